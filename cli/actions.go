@@ -0,0 +1,85 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// Helpers for running inside GitHub Actions. Outside Actions (no
+// GITHUB_OUTPUT / GITHUB_STEP_SUMMARY) output goes to stdout instead, so
+// the same commands can be tried locally against a saved event payload.
+
+// readActionEvent reads and parses the event that triggered the workflow.
+// name and path override GITHUB_EVENT_NAME and GITHUB_EVENT_PATH when set.
+func readActionEvent(name, path string) (string, interface{}, error) {
+	if name == "" {
+		name = os.Getenv("GITHUB_EVENT_NAME")
+	}
+	if path == "" {
+		path = os.Getenv("GITHUB_EVENT_PATH")
+	}
+	if name == "" || path == "" {
+		return "", nil, fmt.Errorf("no event to handle: GITHUB_EVENT_NAME and GITHUB_EVENT_PATH are unset (use --event-name and --event)")
+	}
+
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read event payload: %w", err)
+	}
+	event, err := github.ParseWebHook(name, payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("unsupported event '%s': %w", name, err)
+	}
+	return name, event, nil
+}
+
+// setOutput records a step output, using the heredoc form for multi-line
+// values just as the Python scripts did.
+func setOutput(name, value string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		fmt.Printf("%s=%s\n", name, value)
+		return nil
+	}
+	var line string
+	if strings.Contains(value, "\n") {
+		delim := "EOF_" + randomHex(8)
+		line = fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delim, strings.TrimRight(value, "\n"), delim)
+	} else {
+		line = fmt.Sprintf("%s=%s\n", name, value)
+	}
+	return appendFile(path, line)
+}
+
+// writeStepSummary appends markdown to the job's step summary.
+func writeStepSummary(markdown string) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		fmt.Print(markdown)
+		return nil
+	}
+	return appendFile(path, markdown)
+}
+
+func appendFile(path, text string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// activityLevels are the heatmap's shades, from no matches to the busiest
+// days.
+var activityLevels = []string{"·", "░", "▒", "▓", "█"}
+
+// activityColours are the SVG equivalents of activityLevels, the colours
+// of GitHub's contribution graph.
+var activityColours = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// activityCalendar counts matches per day over the year ending on to.
+// Days run from the Sunday of the week 52 weeks before to, so the calendar
+// starts at the top of a column like GitHub's.
+type activityCalendar struct {
+	Start, End time.Time
+	Counts     map[string]int
+	Total, Max int
+}
+
+// newActivityCalendar counts the matches played on each day up to end.
+// With player set, only that player's matches count.
+func newActivityCalendar(matches []Match, player string, end time.Time) activityCalendar {
+	start := end.AddDate(0, 0, -364)
+	start = start.AddDate(0, 0, -int(start.Weekday()))
+	cal := activityCalendar{Start: start, End: end, Counts: make(map[string]int)}
+	first, last := start.Format("2006-01-02"), end.Format("2006-01-02")
+	for _, m := range matches {
+		if m.Date < first || m.Date > last {
+			continue
+		}
+		if player != "" && !containsString(matchPlayers(m), player) {
+			continue
+		}
+		cal.Counts[m.Date]++
+		cal.Total++
+		cal.Max = max(cal.Max, cal.Counts[m.Date])
+	}
+	return cal
+}
+
+// level is the shade of a day with n matches, scaled to the busiest day.
+func (c activityCalendar) level(n int) int {
+	if n == 0 || c.Max == 0 {
+		return 0
+	}
+	return 1 + (n-1)*(len(activityLevels)-2)/max(c.Max-1, 1)
+}
+
+// days calls fn for every day in the calendar with its week column.
+func (c activityCalendar) days(fn func(day time.Time, week int)) {
+	for d := c.Start; !d.After(c.End); d = d.AddDate(0, 0, 1) {
+		fn(d, int(d.Sub(c.Start).Hours()/24)/7)
+	}
+}
+
+// weeks is the number of columns in the calendar.
+func (c activityCalendar) weeks() int {
+	return int(c.End.Sub(c.Start).Hours()/24)/7 + 1
+}
+
+// Text draws the calendar for the terminal: a row per weekday, a column
+// per week, with month names above the weeks they start in.
+func (c activityCalendar) Text() string {
+	weeks := c.weeks()
+	grid := make([][]string, 7)
+	for i := range grid {
+		grid[i] = make([]string, weeks)
+		for j := range grid[i] {
+			grid[i][j] = " "
+		}
+	}
+	months := []rune(strings.Repeat(" ", weeks+3))
+	c.days(func(d time.Time, week int) {
+		grid[d.Weekday()][week] = activityLevels[c.level(c.Counts[d.Format("2006-01-02")])]
+		if d.Day() == 1 {
+			copy(months[week:], []rune(d.Month().String()[:3]))
+		}
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "    %s\n", strings.TrimRight(string(months), " "))
+	for i, row := range grid {
+		label := "   "
+		if i%2 == 1 {
+			label = time.Weekday(i).String()[:3]
+		}
+		fmt.Fprintf(&b, "%s %s\n", label, strings.TrimRight(strings.Join(row, ""), " "))
+	}
+	fmt.Fprintf(&b, "\n%d matches from %s to %s.  Less %s More\n", c.Total,
+		c.Start.Format("2 Jan 2006"), c.End.Format("2 Jan 2006"), strings.Join(activityLevels, ""))
+	return b.String()
+}
+
+// SVG draws the calendar as an image for the Pages site, with a tooltip
+// on every day.
+func (c activityCalendar) SVG(title string) string {
+	const cell, gap, left, top = 11, 3, 32, 36
+	width := left + c.weeks()*(cell+gap) + 10
+	height := top + 7*(cell+gap) + 30
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif" font-size="10" fill="#57606a">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<text x="%d" y="14" font-size="13" font-weight="700" fill="#24292f">%s</text>`+"\n", left, html.EscapeString(title))
+	for i := 1; i < 7; i += 2 {
+		fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`+"\n", top+i*(cell+gap)+cell-1, time.Weekday(i).String()[:3])
+	}
+	c.days(func(d time.Time, week int) {
+		x, y := left+week*(cell+gap), top+int(d.Weekday())*(cell+gap)
+		if d.Day() == 1 {
+			fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", x, top-6, d.Month().String()[:3])
+		}
+		date := d.Format("2006-01-02")
+		n := c.Counts[date]
+		plural := "es"
+		if n == 1 {
+			plural = ""
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%d match%s on %s</title></rect>`+"\n",
+			x, y, cell, cell, activityColours[c.level(n)], n, plural, date)
+	})
+	legend := top + 7*(cell+gap) + 16
+	fmt.Fprintf(&b, `<text x="%d" y="%d">%d matches in the last year</text>`+"\n", left, legend, c.Total)
+	x := width - 10 - len(activityColours)*(cell+gap) - 30
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">Less</text>`+"\n", x-4, legend)
+	for i, colour := range activityColours {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"/>`+"\n", x+i*(cell+gap), legend-cell+1, cell, cell, colour)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d">More</text>`+"\n", x+len(activityColours)*(cell+gap)+2, legend)
+	b.WriteString("</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// aliasesPath is the alias map, aliases.yml: old handle -> current handle.
+// It lets renamed players keep one identity across historical match files,
+// which still carry the old handle. scripts/elo_utils.py reads the same file.
+func aliasesPath() string {
+	return filepath.Join(leagueDir(), "aliases.yml")
+}
+
+// loadAliases reads aliases.yml. A missing file yields an empty map.
+func loadAliases() (map[string]string, error) {
+	data, err := os.ReadFile(aliasesPath())
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw := make(map[string]string)
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid aliases.yml: %w", err)
+	}
+	aliases := make(map[string]string, len(raw))
+	for from, to := range raw {
+		aliases[normalizePlayer(from)] = normalizePlayer(to)
+	}
+	return aliases, nil
+}
+
+func saveAliases(aliases map[string]string) error {
+	return writeYAMLFile(aliasesPath(), aliases)
+}
+
+// resolveAlias follows the alias chain for a normalized handle to the
+// player's current handle. Cycles are cut off rather than looping forever.
+func resolveAlias(aliases map[string]string, handle string) string {
+	for i := 0; i < len(aliases); i++ {
+		next, ok := aliases[handle]
+		if !ok || next == handle {
+			break
+		}
+		handle = next
+	}
+	return handle
+}
+
+// addAlias points from (and anything that already resolved to it) at to.
+func addAlias(aliases map[string]string, from, to string) {
+	for old, cur := range aliases {
+		if cur == from {
+			aliases[old] = to
+		}
+	}
+	aliases[from] = to
+	delete(aliases, to)
+}
+
+// MergeRecord is one entry in merges.yml, the audit trail of identities
+// folded together with "tennis player merge".
+type MergeRecord struct {
+	From    string `yaml:"from"`
+	To      string `yaml:"to"`
+	Date    string `yaml:"date"`
+	Reason  string `yaml:"reason,omitempty"`
+	Singles int    `yaml:"singles_matches"`
+	Doubles int    `yaml:"doubles_matches"`
+}
+
+func mergesPath() string {
+	return filepath.Join(leagueDir(), "merges.yml")
+}
+
+// loadMergeRecords reads merges.yml. A missing file yields no records.
+func loadMergeRecords() ([]MergeRecord, error) {
+	var records []MergeRecord
+	data, err := os.ReadFile(mergesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid merges.yml: %w", err)
+	}
+	return records, nil
+}
+
+// appendMergeRecord adds a record to merges.yml, creating it if needed.
+func appendMergeRecord(rec MergeRecord) error {
+	records, err := loadMergeRecords()
+	if err != nil {
+		return err
+	}
+	return writeYAMLFile(mergesPath(), append(records, rec))
+}
+
+// countMatchesWith returns how many matches involve the given handle.
+func countMatchesWith(matches []Match, handle string) int {
+	n := 0
+	for _, m := range matches {
+		if matchInvolves(m, handle) {
+			n++
+		}
+	}
+	return n
+}
+
+func matchInvolves(m Match, handle string) bool {
+	for _, side := range [][]string{m.Players, m.Team1, m.Team2} {
+		for _, p := range side {
+			if p == handle {
+				return true
+			}
+		}
+	}
+	return false
+}
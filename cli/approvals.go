@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+const (
+	// approveCommand lets a player approve by commenting on the match
+	// issue or pull request, e.g. when they can't review because they
+	// aren't a collaborator.
+	approveCommand = "/approve"
+
+	approvalsContext = "tennis/approvals"
+)
+
+var approvalsMarker = commentMarker("approvals")
+
+// matchApprovals is who has approved a match issue's result.
+type matchApprovals struct {
+	Issue       int
+	PullRequest *github.PullRequest // nil until the workflow opens it
+	Reporter    string
+	Players     []string
+	Approved    map[string]bool
+}
+
+// Pending returns the players, other than the reporter, yet to approve.
+// Under the league's "any" approval rule one approval is enough, and
+// under "none" no one is waited on.
+func (a *matchApprovals) Pending() []string {
+	var pending []string
+	approved := false
+	for _, p := range a.Players {
+		if p == "" || p == a.Reporter {
+			continue
+		}
+		if a.Approved[p] {
+			approved = true
+		} else {
+			pending = append(pending, "@"+p)
+		}
+	}
+	switch rules.approvals() {
+	case approvalsNone:
+		return nil
+	case approvalsAny:
+		if approved {
+			return nil
+		}
+	}
+	return pending
+}
+
+// Disputed returns the players, other than the reporter, whose latest say
+// on the match was to request changes.
+func (a *matchApprovals) Disputed() []string {
+	var disputed []string
+	for _, p := range a.Players {
+		if approved, said := a.Approved[p]; said && !approved && p != a.Reporter {
+			disputed = append(disputed, "@"+p)
+		}
+	}
+	return disputed
+}
+
+// Complete reports whether every player has approved.
+func (a *matchApprovals) Complete() bool {
+	return len(a.Players) > 0 && len(a.Pending()) == 0
+}
+
+// matchIssueForBranch returns the issue number of a match/issue-<n> branch.
+func matchIssueForBranch(ref string) (int, bool) {
+	var n int
+	if _, err := fmt.Sscanf(ref, "match/issue-%d", &n); err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// loadMatchApprovals collects the approvals for a match issue.
+func loadMatchApprovals(ctx context.Context, client *github.Client, number int) (*matchApprovals, error) {
+	issue, _, err := client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch #%d: %w", number, err)
+	}
+	kind, _, ok := matchIssueKind(issue)
+	if !ok || kind == "conflict" {
+		return nil, fmt.Errorf("#%d is not a singles or doubles match issue", number)
+	}
+	pr, err := matchPullRequest(ctx, client, number)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &matchApprovals{
+		Issue:       number,
+		PullRequest: pr,
+		Reporter:    normalizePlayer(issue.GetUser().GetLogin()),
+		Players:     parseMatchIssue(kind, issue.GetBody()).Players,
+	}
+	prNumber := 0
+	if pr != nil {
+		prNumber = pr.GetNumber()
+	}
+	if a.Approved, err = playerApprovals(ctx, client, number, prNumber); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// playerApprovals returns whether each user's latest say on a match is an
+// approval: an approving review of the pull request, or an /approve
+// comment on the issue or pull request. A later review requesting changes
+// (or a dismissal) withdraws it. pr may be 0 if there's no PR yet.
+func playerApprovals(ctx context.Context, client *github.Client, issue, pr int) (map[string]bool, error) {
+	type signal struct {
+		at       time.Time
+		approved bool
+	}
+	latest := make(map[string]signal)
+	record := func(login string, at time.Time, approved bool) {
+		p := normalizePlayer(login)
+		if s, ok := latest[p]; !ok || !at.Before(s.at) {
+			latest[p] = signal{at, approved}
+		}
+	}
+
+	var reviews []*github.PullRequestReview
+	var issueComments, prComments []*github.IssueComment
+	fetches := []func(context.Context) error{
+		func(ctx context.Context) (err error) {
+			issueComments, err = listComments(ctx, client, issue)
+			return err
+		},
+	}
+	if pr != 0 {
+		fetches = append(fetches,
+			func(ctx context.Context) (err error) {
+				reviews, err = listReviews(ctx, client, pr)
+				return err
+			},
+			func(ctx context.Context) (err error) {
+				prComments, err = listComments(ctx, client, pr)
+				return err
+			},
+		)
+	}
+	if err := githubapi.All(ctx, fetches...); err != nil {
+		return nil, err
+	}
+
+	for _, r := range reviews {
+		// Comments don't change an earlier approval or rejection.
+		if r.GetState() == "COMMENTED" {
+			continue
+		}
+		record(r.GetUser().GetLogin(), r.GetSubmittedAt().Time, r.GetState() == "APPROVED")
+	}
+	for _, c := range append(issueComments, prComments...) {
+		if strings.EqualFold(strings.TrimSpace(c.GetBody()), approveCommand) {
+			record(c.GetUser().GetLogin(), c.GetCreatedAt().Time, true)
+		}
+	}
+
+	approved := make(map[string]bool)
+	for p, s := range latest {
+		approved[p] = s.approved
+	}
+	return approved, nil
+}
+
+// approvalsComment renders the status comment kept on the match issue.
+func approvalsComment(a *matchApprovals) string {
+	var b strings.Builder
+	b.WriteString(approvalsMarker + "\n### Match approvals\n\n| Player | Status |\n|---|---|\n")
+	approved := 0
+	for _, p := range a.Players {
+		if p == "" {
+			continue
+		}
+		status := "⏳ waiting"
+		switch {
+		case p == a.Reporter:
+			status = "📝 reported the match"
+			approved++
+		case a.Approved[p]:
+			status = "✅ approved"
+			approved++
+		}
+		fmt.Fprintf(&b, "| @%s | %s |\n", p, status)
+	}
+	b.WriteString("\n")
+
+	if a.Complete() {
+		switch rules.approvals() {
+		case approvalsAll:
+			b.WriteString("All players have approved.")
+		case approvalsAny:
+			b.WriteString("A player has approved, as the league's rules require.")
+		default:
+			b.WriteString("The league's rules don't require approval.")
+		}
+		if a.PullRequest != nil {
+			fmt.Fprintf(&b, " #%d is ready to merge.", a.PullRequest.GetNumber())
+		}
+		b.WriteString("\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d of %d players have approved. ", approved, len(a.Players))
+	if a.PullRequest != nil {
+		fmt.Fprintf(&b, "Approve pull request #%d, or comment `%s` here.\n", a.PullRequest.GetNumber(), approveCommand)
+	} else {
+		fmt.Fprintf(&b, "Comment `%s` here to approve.\n", approveCommand)
+	}
+	return b.String()
+}
+
+// updateApprovals brings a match issue's status comment, approved label
+// and the pull request's approvals check up to date.
+func updateApprovals(ctx context.Context, client *github.Client, number int) (*matchApprovals, error) {
+	a, err := loadMatchApprovals(ctx, client, number)
+	if err != nil {
+		return nil, err
+	}
+	if err := upsertComment(ctx, client, number, approvalsMarker, approvalsComment(a)); err != nil {
+		return nil, err
+	}
+	if err := setApprovedLabel(ctx, client, number, a.Complete()); err != nil {
+		return nil, err
+	}
+	if a.PullRequest != nil && a.PullRequest.GetState() == "open" {
+		if err := setApprovalsStatus(ctx, client, a); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func setApprovedLabel(ctx context.Context, client *github.Client, number int, approved bool) error {
+	issue, _, err := client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return fmt.Errorf("failed to fetch #%d: %w", number, err)
+	}
+	if hasLabel(issue, labelNames.Approved) == approved {
+		return nil
+	}
+	if dryRun {
+		if approved {
+			fmt.Printf("[dry-run] would label #%d %s\n", number, labelNames.Approved)
+		} else {
+			fmt.Printf("[dry-run] would remove %s from #%d\n", labelNames.Approved, number)
+		}
+		return nil
+	}
+	if approved {
+		_, _, err = client.Issues.AddLabelsToIssue(ctx, owner, repo, number, []string{labelNames.Approved})
+	} else {
+		_, err = client.Issues.RemoveLabelForIssue(ctx, owner, repo, number, labelNames.Approved)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update the %s label on #%d: %w", labelNames.Approved, number, err)
+	}
+	return nil
+}
+
+func setApprovalsStatus(ctx context.Context, client *github.Client, a *matchApprovals) error {
+	c := checkResult{
+		SHA:     a.PullRequest.GetHead().GetSHA(),
+		Name:    approvalsContext,
+		State:   "in_progress",
+		Title:   "Waiting on " + strings.Join(a.Pending(), ", "),
+		Summary: approvalsComment(a),
+	}
+	if a.Complete() {
+		c.State, c.Title = "success", "All players approved"
+	}
+	if err := publishCheck(ctx, client, c); err != nil {
+		return fmt.Errorf("failed to publish the approvals check on PR #%d: %v", a.PullRequest.GetNumber(), err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package main
+
+import "embed"
+
+// assets holds the files "tennis init" installs in a new league repository.
+//
+//go:embed assets
+var assets embed.FS
+
+func mustAsset(name string) string {
+	data, err := assets.ReadFile("assets/" + name)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
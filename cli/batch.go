@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// batch runs the items of a bulk command under its failure policy:
+// stop at the first failure (--fail-fast, the default) or carry on with
+// the rest (--continue-on-error). Either way it records every item's
+// outcome, for a --summary report and a retry file listing the items that
+// failed or never ran. Ctrl-C or --timeout stops the run between items,
+// leaving the rest for the retry file.
+type batch struct {
+	Command   string      `json:"command"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Skipped   int         `json:"skipped"`
+	NotRun    int         `json:"not_run"`
+	Items     []batchItem `json:"items"`
+
+	ctx             context.Context // the command's; the run stops once it's done
+	firstErr        error
+	continueOnError bool
+	summary         string
+	retryFile       string
+	only            map[string]bool // from --retry; nil runs every item
+}
+
+// batchItem is one item's outcome: ok, failed, skipped or not_run.
+type batchItem struct {
+	Item   string `json:"item"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// skipItem is returned by a batch step to record the item as skipped,
+// with the reason, rather than failed.
+type skipItem string
+
+func (s skipItem) Error() string { return string(s) }
+
+// addBatchFlags adds the failure policy flags to a bulk command.
+func addBatchFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("fail-fast", false, "Stop at the first item that fails (the default)")
+	cmd.Flags().Bool("continue-on-error", false, "Carry on past items that fail and report them at the end")
+	cmd.Flags().String("summary", "", "Write a JSON summary of every item's outcome to this file (- for stdout)")
+	cmd.Flags().String("retry-file", "", "Where to list the items that failed or never ran (default .tennis/retry-<command>.txt)")
+	cmd.Flags().String("retry", "", "Only run the items listed in this retry file")
+	addThrottleFlag(cmd)
+}
+
+// addThrottleFlag paces a bulk command's writes to GitHub so it finishes
+// without tripping GitHub's secondary rate limits; see githubapi.Throttle.
+func addThrottleFlag(cmd *cobra.Command) {
+	cmd.Flags().Float64("writes-per-second", 1, "Most writes to GitHub a second; slowed further if GitHub's secondary rate limit is hit anyway")
+}
+
+// newBatch reads a bulk command's failure policy flags. The retry file
+// lists one item per line, as the command names them (e.g. #42).
+func newBatch(cmd *cobra.Command) (*batch, error) {
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	summary, _ := cmd.Flags().GetString("summary")
+	retryFile, _ := cmd.Flags().GetString("retry-file")
+	retry, _ := cmd.Flags().GetString("retry")
+	if failFast && continueOnError {
+		return nil, invalidf("--fail-fast and --continue-on-error can't be used together")
+	}
+
+	name := strings.ReplaceAll(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "), " ", "-")
+	if retryFile == "" {
+		retryFile = filepath.Join(leagueDir(), ".tennis", "retry-"+name+".txt")
+	}
+	b := &batch{Command: cmd.CommandPath(), Items: []batchItem{}, ctx: cmd.Context(), continueOnError: continueOnError, summary: summary, retryFile: retryFile}
+	if retry != "" {
+		f, err := os.Open(retry)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		b.only = make(map[string]bool)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if item := strings.TrimSpace(scanner.Text()); item != "" {
+				b.only[item] = true
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Wants reports whether item is to be run: always, unless --retry named
+// a file that doesn't list it.
+func (b *batch) Wants(item string) bool {
+	return b.only == nil || b.only[item]
+}
+
+// Run calls step for each item in turn. A failed item stops the run
+// unless --continue-on-error was given; the items after it are recorded
+// as not run, as are all those left when the command is interrupted.
+func (b *batch) Run(items []string, step func(i int) error) {
+	stopped := false
+	for i, item := range items {
+		if !stopped && b.ctx.Err() != nil {
+			stopped = true
+			if b.firstErr == nil {
+				b.firstErr = context.Cause(b.ctx)
+			}
+		}
+		if stopped {
+			b.Items = append(b.Items, batchItem{Item: item, Status: "not_run"})
+			b.NotRun++
+			continue
+		}
+		err := step(i)
+		var skip skipItem
+		switch {
+		case err == nil:
+			b.Items = append(b.Items, batchItem{Item: item, Status: "ok"})
+			b.Succeeded++
+		case errors.As(err, &skip):
+			b.Items = append(b.Items, batchItem{Item: item, Status: "skipped", Error: string(skip)})
+			b.Skipped++
+		default:
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", item, err)
+			b.Items = append(b.Items, batchItem{Item: item, Status: "failed", Error: err.Error()})
+			b.Failed++
+			if b.firstErr == nil {
+				b.firstErr = err
+			}
+			stopped = !b.continueOnError
+		}
+	}
+}
+
+// Finish writes the summary and the retry file, and returns an error if
+// any item failed or never ran. A clean run removes an old retry file.
+func (b *batch) Finish() error {
+	if b.summary != "" {
+		data, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return err
+		}
+		if b.summary == "-" {
+			fmt.Println(string(data))
+		} else if err := os.WriteFile(b.summary, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if b.Failed == 0 && b.NotRun == 0 {
+		if err := os.Remove(b.retryFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	var retry strings.Builder
+	for _, item := range b.Items {
+		if item.Status == "failed" || item.Status == "not_run" {
+			fmt.Fprintln(&retry, item.Item)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(b.retryFile), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(b.retryFile, []byte(retry.String()), 0o644); err != nil {
+		return err
+	}
+	return fmt.Errorf("%d items failed, %d never ran and %d succeeded; rerun the rest with --retry %s (first failure: %w)", b.Failed, b.NotRun, b.Succeeded, b.retryFile, b.firstErr)
+}
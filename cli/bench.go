@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// benchResult is the cost of one stage of the engine, averaged over runs.
+type benchResult struct {
+	Stage        string        `json:"stage"`
+	Runs         int           `json:"runs"`
+	PerRun       time.Duration `json:"ns_per_run"`
+	AllocsPerRun uint64        `json:"allocs_per_run"`
+	BytesPerRun  uint64        `json:"bytes_per_run"`
+}
+
+// measure runs fn runs times and averages its wall time and allocations.
+func measure(stage string, runs int, fn func() error) (benchResult, error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < runs; i++ {
+		if err := fn(); err != nil {
+			return benchResult{}, fmt.Errorf("%s: %w", stage, err)
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	n := uint64(runs)
+	return benchResult{
+		Stage:        stage,
+		Runs:         runs,
+		PerRun:       elapsed / time.Duration(runs),
+		AllocsPerRun: (after.Mallocs - before.Mallocs) / n,
+		BytesPerRun:  (after.TotalAlloc - before.TotalAlloc) / n,
+	}, nil
+}
+
+// writeSyntheticMatches writes n match files under dir, a doublesShare of
+// them doubles, between players drawn from a pool of the given size. Match
+// files are named and laid out as the issue-to-PR workflow writes them.
+func writeSyntheticMatches(dir string, n, players int, doublesShare float64, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	pool := make([]string, players)
+	for i := range pool {
+		pool[i] = fmt.Sprintf("player%03d", i+1)
+	}
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 1; i <= n; i++ {
+		if rng.Intn(3) == 0 {
+			day = day.AddDate(0, 0, 1)
+		}
+		m := Match{Date: day.Format("2006-01-02"), SourceIssue: i}
+		picked := rng.Perm(players)
+		sub := "singles-matches"
+		if rng.Float64() < doublesShare && players >= 4 {
+			sub = "doubles-matches"
+			m.Team1 = []string{pool[picked[0]], pool[picked[1]]}
+			m.Team2 = []string{pool[picked[2]], pool[picked[3]]}
+		} else {
+			m.Players = []string{pool[picked[0]], pool[picked[1]]}
+		}
+		m.Sets = syntheticSets(rng)
+
+		path := filepath.Join(dir, sub, fmt.Sprintf("%s-%d.yml", m.Date, i))
+		if err := writeYAMLFile(path, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syntheticSets returns a plausible best-of-three score.
+func syntheticSets(rng *rand.Rand) [][]int {
+	set := func(side1Wins bool) []int {
+		loser := rng.Intn(5)
+		winner := 6
+		if rng.Intn(6) == 0 {
+			winner, loser = 7, 5+rng.Intn(2)
+		}
+		if side1Wins {
+			return []int{winner, loser}
+		}
+		return []int{loser, winner}
+	}
+	var sets [][]int
+	var w1, w2 int
+	for w1 < 2 && w2 < 2 {
+		side1 := rng.Intn(2) == 0
+		if side1 {
+			w1++
+		} else {
+			w2++
+		}
+		sets = append(sets, set(side1))
+	}
+	return sets
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BoxLeague is one period (a calendar month) of a box league, stored under
+// boxes/<YYYY-MM>.yml. Boxes are ordered strongest first. Next holds the
+// player order for the following period once promotion and relegation
+// have been applied.
+type BoxLeague struct {
+	Period string   `yaml:"period"`
+	Status string   `yaml:"status"`
+	Size   int      `yaml:"size"`
+	Boxes  []Box    `yaml:"boxes"`
+	Next   []string `yaml:"next,omitempty"`
+}
+
+// Box is a group of players who all play each other during the period.
+type Box struct {
+	Players  []string     `yaml:"players"`
+	Fixtures []BoxFixture `yaml:"fixtures"`
+}
+
+// BoxFixture is one intra-box pairing and its fixture issue.
+type BoxFixture struct {
+	Players [2]string `yaml:"players,flow"`
+	Issue   int       `yaml:"issue,omitempty"`
+}
+
+// missingFixtures reports whether any pairing has no fixture issue yet, as
+// after a "boxes create" that failed part way.
+func (b *BoxLeague) missingFixtures() bool {
+	for _, box := range b.Boxes {
+		for _, f := range box.Fixtures {
+			if f.Issue == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// BoxStanding is a player's record within their box (or division) for the
+// period.
+type BoxStanding struct {
+	Player      string
+	Played      int
+	Won         int
+	Lost        int
+	SetsWon     int
+	SetsLost    int
+	GamesWon    int
+	GamesLost   int
+	orderInDraw int
+}
+
+const (
+	boxesOpen   = "open"
+	boxesClosed = "closed"
+)
+
+func boxesPath(period string) string {
+	return filepath.Join(leagueDir(), "boxes", period+".yml")
+}
+
+func loadBoxLeague(period string) (*BoxLeague, error) {
+	data, err := os.ReadFile(boxesPath(period))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no box league for %s (looked in %s)", period, boxesPath(period))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b BoxLeague
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid box league file %s: %v", boxesPath(period), err)
+	}
+
+	// Follow renames so boxes keep matching recorded results.
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	for i := range b.Boxes {
+		for j, p := range b.Boxes[i].Players {
+			b.Boxes[i].Players[j] = resolveAlias(aliases, p)
+		}
+		for j := range b.Boxes[i].Fixtures {
+			f := &b.Boxes[i].Fixtures[j]
+			f.Players[0], f.Players[1] = resolveAlias(aliases, f.Players[0]), resolveAlias(aliases, f.Players[1])
+		}
+	}
+	for i, p := range b.Next {
+		b.Next[i] = resolveAlias(aliases, p)
+	}
+	return &b, nil
+}
+
+func saveBoxLeague(b *BoxLeague) error {
+	return writeYAMLFile(boxesPath(b.Period), b)
+}
+
+// Label returns the per-period issue label.
+func (b *BoxLeague) Label() string {
+	return "box:" + b.Period
+}
+
+// groupIntoBoxes splits ordered players into boxes of the given size. A
+// final box that would hold a single player is merged into the one above.
+func groupIntoBoxes(players []string, size int) []Box {
+	var boxes []Box
+	for i := 0; i < len(players); i += size {
+		end := i + size
+		if end > len(players) {
+			end = len(players)
+		}
+		boxes = append(boxes, Box{Players: append([]string{}, players[i:end]...)})
+	}
+	if n := len(boxes); n > 1 && len(boxes[n-1].Players) == 1 {
+		boxes[n-2].Players = append(boxes[n-2].Players, boxes[n-1].Players...)
+		boxes = boxes[:n-1]
+	}
+	for i := range boxes {
+		ps := boxes[i].Players
+		for a := 0; a < len(ps); a++ {
+			for c := a + 1; c < len(ps); c++ {
+				boxes[i].Fixtures = append(boxes[i].Fixtures, BoxFixture{Players: [2]string{ps[a], ps[c]}})
+			}
+		}
+	}
+	return boxes
+}
+
+// boxStandings computes each box's table from singles matches played
+// between box members during the period.
+func boxStandings(b *BoxLeague, matches []Match) [][]BoxStanding {
+	var period []Match
+	for _, m := range matches {
+		if strings.HasPrefix(m.Date, b.Period) {
+			period = append(period, m)
+		}
+	}
+	tables := make([][]BoxStanding, len(b.Boxes))
+	for i, box := range b.Boxes {
+		tables[i] = standingsTable(box.Players, period)
+	}
+	return tables
+}
+
+// standingsTable ranks a group of players on the singles matches they
+// played against each other: by matches won, then set difference, then
+// game difference, then the order they were listed in.
+func standingsTable(players []string, matches []Match) []BoxStanding {
+	index := make(map[string]int)
+	rows := make([]BoxStanding, len(players))
+	for j, p := range players {
+		index[p] = j
+		rows[j] = BoxStanding{Player: p, orderInDraw: j}
+	}
+
+	for _, m := range matches {
+		if len(m.Players) != 2 {
+			continue
+		}
+		a, okA := index[m.Players[0]]
+		c, okC := index[m.Players[1]]
+		if !okA || !okC {
+			continue
+		}
+		winner := matchWinner(m)
+		if winner == 0 {
+			continue
+		}
+		rows[a].Played++
+		rows[c].Played++
+		if winner == 1 {
+			rows[a].Won++
+			rows[c].Lost++
+		} else {
+			rows[c].Won++
+			rows[a].Lost++
+		}
+		for _, s := range m.Sets {
+			if len(s) != 2 {
+				continue
+			}
+			rows[a].GamesWon += s[0]
+			rows[a].GamesLost += s[1]
+			rows[c].GamesWon += s[1]
+			rows[c].GamesLost += s[0]
+			switch {
+			case s[0] > s[1]:
+				rows[a].SetsWon++
+				rows[c].SetsLost++
+			case s[1] > s[0]:
+				rows[c].SetsWon++
+				rows[a].SetsLost++
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(x, y int) bool {
+		rx, ry := rows[x], rows[y]
+		if rx.Won != ry.Won {
+			return rx.Won > ry.Won
+		}
+		if dx, dy := rx.SetsWon-rx.SetsLost, ry.SetsWon-ry.SetsLost; dx != dy {
+			return dx > dy
+		}
+		if dx, dy := rx.GamesWon-rx.GamesLost, ry.GamesWon-ry.GamesLost; dx != dy {
+			return dx > dy
+		}
+		return rx.orderInDraw < ry.orderInDraw
+	})
+	return rows
+}
+
+// promoteAndRelegate swaps the bottom `moves` players of each box with the
+// top `moves` of the box below and returns the resulting player order for
+// the next period.
+func promoteAndRelegate(tables [][]BoxStanding, moves int) []string {
+	var next []string
+	for _, o := range swapAdjacent(tables, moves) {
+		next = append(next, o...)
+	}
+	return next
+}
+
+// swapAdjacent returns each group's players, in standings order, after the
+// bottom `moves` of every group swap with the top `moves` of the group
+// below. Fewer move when a group has fewer than 2×moves players.
+func swapAdjacent(tables [][]BoxStanding, moves int) [][]string {
+	order := make([][]string, len(tables))
+	for i, rows := range tables {
+		for _, r := range rows {
+			order[i] = append(order[i], r.Player)
+		}
+	}
+
+	for i := 0; i+1 < len(order); i++ {
+		upper, lower := order[i], order[i+1]
+		k := moves
+		if k > len(upper)/2 {
+			k = len(upper) / 2
+		}
+		if k > len(lower)/2 {
+			k = len(lower) / 2
+		}
+		if k == 0 {
+			continue
+		}
+		relegated := append([]string{}, upper[len(upper)-k:]...)
+		promoted := append([]string{}, lower[:k]...)
+		order[i] = append(upper[:len(upper)-k:len(upper)-k], promoted...)
+		order[i+1] = append(relegated, lower[k:]...)
+	}
+	return order
+}
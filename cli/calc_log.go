@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+)
+
+// setCalc is how one set of a match moved ratings: the sides' ratings
+// going in (team averages in doubles), the winners' expected score, the
+// K-factor and the points the winners gained and the losers lost.
+type setCalc struct {
+	Set          []int
+	Winners      []string
+	Losers       []string
+	WinnerRating float64
+	LoserRating  float64
+	Expected     float64
+	K            float64
+	Change       float64
+}
+
+// rateMatch applies m to ratings, as replaySingles and replayDoubles do,
+// and returns the calculation for each rated set. Tied and malformed sets,
+// and matches without two sides, aren't rated, nor are matches the rankings
+// freeze holds back. A penalized forfeit rates no sets; its penalty is
+// applied all the same.
+func rateMatch(m Match, ratings map[string]float64) []setCalc {
+	if frozen(m) {
+		return nil
+	}
+	side1, side2 := m.Team1, m.Team2
+	if m.IsDoubles() {
+		if len(side1) != 2 || len(side2) != 2 {
+			return nil
+		}
+	} else {
+		if len(m.Players) != 2 {
+			return nil
+		}
+		side1, side2 = m.Players[:1], m.Players[1:]
+	}
+
+	var calcs []setCalc
+	for _, s := range m.scoredSets() {
+		if len(s) != 2 || s[0] == s[1] {
+			continue
+		}
+		c := setCalc{Set: s, Winners: side1, Losers: side2}
+		if s[1] > s[0] {
+			c.Winners, c.Losers = side2, side1
+		}
+		c.WinnerRating, c.LoserRating = sideRating(ratings, c.Winners), sideRating(ratings, c.Losers)
+		c.Expected = expectedScore(c.WinnerRating, c.LoserRating)
+		c.K = elo.setK(m, s)
+		c.Change = setChange(m, s, c.WinnerRating, c.LoserRating, m.IsDoubles())
+		for _, p := range c.Winners {
+			ratings[p] = rating(ratings, p) + c.Change
+		}
+		for _, p := range c.Losers {
+			ratings[p] = rating(ratings, p) - c.Change
+		}
+		calcs = append(calcs, c)
+	}
+	chargeForfeit(m, ratings)
+	return calcs
+}
+
+// sideRating is a side's rating: the player's, or the team's average.
+func sideRating(ratings map[string]float64, side []string) float64 {
+	total := 0.0
+	for _, p := range side {
+		total += rating(ratings, p)
+	}
+	return total / float64(len(side))
+}
+
+// calcRecord is one player's line of a calculation log: how one match
+// moved their rating, with every input needed to check it by hand.
+type calcRecord struct {
+	Match        int       `json:"match"` // position in the replay, from 1
+	Type         string    `json:"type"`
+	File         string    `json:"file"`
+	Date         string    `json:"date"`
+	Issue        int       `json:"issue,omitempty"`
+	Repo         string    `json:"repo,omitempty"`
+	Player       string    `json:"player"`
+	Partner      string    `json:"partner,omitempty"`
+	Opponents    []string  `json:"opponents"`
+	RatingBefore float64   `json:"rating_before"`
+	Sets         []calcSet `json:"sets"`
+	RatingAfter  float64   `json:"rating_after"`
+}
+
+// calcSet is one set of a calcRecord from the player's side.
+type calcSet struct {
+	Games          []int   `json:"games"`           // the player's side first
+	Rating         float64 `json:"rating"`          // the player's side going in
+	OpponentRating float64 `json:"opponent_rating"` // the other side going in
+	Expected       float64 `json:"expected"`        // the player's side's expected score
+	K              float64 `json:"k"`
+	Change         float64 `json:"change"` // points to the player
+}
+
+// calculationLog replays matches from the seeded ratings and returns one
+// record per match per player, in replay order. Ratings are unrounded, so
+// replaying the log reproduces the engine's numbers exactly.
+func calculationLog(kind string, matches []Match, seed map[string]float64) []calcRecord {
+	ratings := make(map[string]float64)
+	seedRatings(ratings, seed)
+	var out []calcRecord
+	for i, m := range matches {
+		before := make(map[string]float64)
+		for _, p := range matchPlayers(m) {
+			before[p] = rating(ratings, p)
+		}
+		calcs := rateMatch(m, ratings)
+		if calcs == nil {
+			continue
+		}
+		side1, side2 := m.Team1, m.Team2
+		if !m.IsDoubles() {
+			side1, side2 = m.Players[:1], m.Players[1:]
+		}
+		for n, side := range [][]string{side1, side2} {
+			theirs := side2
+			if n == 1 {
+				theirs = side1
+			}
+			for _, p := range side {
+				r := calcRecord{
+					Match: i + 1, Type: kind, File: kind + "-matches/" + filepath.Base(m.File),
+					Date: m.Date, Issue: m.SourceIssue, Repo: m.Repo,
+					Player: p, Opponents: theirs,
+					RatingBefore: before[p], RatingAfter: rating(ratings, p), Sets: []calcSet{},
+				}
+				for _, q := range side {
+					if q != p {
+						r.Partner = q
+					}
+				}
+				for _, c := range calcs {
+					cs := calcSet{Games: c.Set, Rating: c.WinnerRating, OpponentRating: c.LoserRating,
+						Expected: c.Expected, K: c.K, Change: c.Change}
+					if n == 1 {
+						cs.Games = []int{c.Set[1], c.Set[0]}
+					}
+					if !containsString(c.Winners, p) {
+						cs.Rating, cs.OpponentRating = c.LoserRating, c.WinnerRating
+						cs.Expected, cs.Change = 1-c.Expected, -c.Change
+					}
+					r.Sets = append(r.Sets, cs)
+				}
+				out = append(out, r)
+			}
+		}
+	}
+	return out
+}
+
+// encodeCalculationLog encodes records as JSON Lines, one record a line.
+func encodeCalculationLog(records []calcRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// calcLogAssetName is the name of the calculation log saved with a day's
+// snapshot. It isn't a .json file, so it isn't listed as a snapshot.
+func calcLogAssetName(date string) string {
+	return date + ".calc.jsonl"
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// categoryRegex is what a competition category looks like in players.yml,
+// e.g. open, women or veterans-45.
+var categoryRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// normalizeCategory lower-cases a category name and checks it.
+func normalizeCategory(c string) (string, error) {
+	c = strings.ToLower(strings.TrimSpace(c))
+	if !categoryRegex.MatchString(c) {
+		return "", invalidf("invalid category '%s'. Use letters, digits and dashes, like veterans-45", c)
+	}
+	return c, nil
+}
+
+// InCategory reports whether the player competes in category c.
+func (p RosterPlayer) InCategory(c string) bool {
+	return containsString(p.Categories, c)
+}
+
+// Category returns the roster of the players in category c, an error if
+// there are none.
+func (r *Roster) Category(c string) (*Roster, error) {
+	members := &Roster{}
+	for _, p := range r.Players {
+		if p.InCategory(c) {
+			members.Players = append(members.Players, p)
+		}
+	}
+	if len(members.Players) == 0 {
+		return nil, fmt.Errorf("no players are in the %s category (use `tennis player category`)", c)
+	}
+	return members, nil
+}
+
+// categoryMatches returns the matches every player of which is on the
+// category's roster: the matches of that competition.
+func categoryMatches(matches []Match, members *Roster) []Match {
+	var out []Match
+	for _, m := range matches {
+		in := true
+		for _, p := range matchPlayers(m) {
+			if members.Find(p) == nil {
+				in = false
+				break
+			}
+		}
+		if in {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// forCategory narrows a roster and matches to the category given by a
+// command's --category flag, if any, returning the category too.
+func forCategory(category string, roster *Roster, matches ...*[]Match) (string, *Roster, error) {
+	if category == "" {
+		return "", roster, nil
+	}
+	c, err := normalizeCategory(category)
+	if err != nil {
+		return "", nil, err
+	}
+	members, err := roster.Category(c)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, m := range matches {
+		*m = categoryMatches(*m, members)
+	}
+	return c, members, nil
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// challongeAPI is the Challonge v1 REST API. Requests authenticate with
+// the account's API key, read from CHALLONGE_API_KEY.
+const challongeAPI = "https://api.challonge.com/v1"
+
+// challongeSlugRegex matches the URL slugs Challonge accepts.
+var challongeSlugRegex = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// challongeTournament is the part of a Challonge tournament the league
+// uses: its participants (named by GitHub handle) and matches.
+type challongeTournament struct {
+	ID             int    `json:"id"`
+	URL            string `json:"url"`
+	Name           string `json:"name"`
+	TournamentType string `json:"tournament_type"`
+	FullURL        string `json:"full_challonge_url"`
+	CreatedAt      string `json:"created_at"`
+	StartedAt      string `json:"started_at"`
+	Participants   []struct {
+		Participant challongeParticipant `json:"participant"`
+	} `json:"participants"`
+	Matches []struct {
+		Match challongeMatch `json:"match"`
+	} `json:"matches"`
+}
+
+type challongeParticipant struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Seed int    `json:"seed"`
+}
+
+// challongeMatch is a bracket match. ScoresCsv lists set scores from
+// player 1's side, e.g. "6-3,4-6,7-5".
+type challongeMatch struct {
+	ID          int    `json:"id"`
+	Round       int    `json:"round"`
+	State       string `json:"state"`
+	Player1ID   int    `json:"player1_id"`
+	Player2ID   int    `json:"player2_id"`
+	WinnerID    int    `json:"winner_id"`
+	ScoresCsv   string `json:"scores_csv"`
+	CompletedAt string `json:"completed_at"`
+}
+
+func challongeKey() (string, error) {
+	key := os.Getenv("CHALLONGE_API_KEY")
+	if key == "" {
+		return "", fmt.Errorf("CHALLONGE_API_KEY is not set (find your key at https://challonge.com/settings/developer)")
+	}
+	return key, nil
+}
+
+// challongeRequest calls the Challonge API and decodes the JSON reply
+// into out. form carries the parameters of POST requests.
+func challongeRequest(ctx context.Context, method, path string, form url.Values, out any) error {
+	key, err := challongeKey()
+	if err != nil {
+		return err
+	}
+	if form == nil {
+		form = url.Values{}
+	}
+	form.Set("api_key", key)
+
+	endpoint := challongeAPI + path
+	var body io.Reader
+	if method == http.MethodGet {
+		endpoint += "?" + form.Encode()
+	} else {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Challonge request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the Challonge response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(data, &e) == nil && len(e.Errors) > 0 {
+			return fmt.Errorf("Challonge: %s (HTTP %d)", strings.Join(e.Errors, "; "), resp.StatusCode)
+		}
+		return fmt.Errorf("Challonge returned HTTP %d for %s", resp.StatusCode, path)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid Challonge response: %w", err)
+	}
+	return nil
+}
+
+// fetchChallonge loads a tournament with its participants and matches.
+// id is the tournament's numeric id or URL slug ("subdomain-slug" for
+// tournaments hosted on an organization's subdomain).
+func fetchChallonge(ctx context.Context, id string) (*challongeTournament, error) {
+	var reply struct {
+		Tournament challongeTournament `json:"tournament"`
+	}
+	form := url.Values{"include_participants": {"1"}, "include_matches": {"1"}}
+	if err := challongeRequest(ctx, http.MethodGet, "/tournaments/"+url.PathEscape(id)+".json", form, &reply); err != nil {
+		return nil, err
+	}
+	return &reply.Tournament, nil
+}
+
+// seeds returns the participants' handles in seed order.
+func (c *challongeTournament) seeds() []string {
+	ps := make([]challongeParticipant, 0, len(c.Participants))
+	for _, p := range c.Participants {
+		ps = append(ps, p.Participant)
+	}
+	sort.SliceStable(ps, func(i, j int) bool { return ps[i].Seed < ps[j].Seed })
+	seeds := make([]string, len(ps))
+	for i, p := range ps {
+		seeds[i] = normalizePlayer(p.Name)
+	}
+	return seeds
+}
+
+// handles maps participant ids to their handles.
+func (c *challongeTournament) handles() map[int]string {
+	names := make(map[int]string)
+	for _, p := range c.Participants {
+		names[p.Participant.ID] = normalizePlayer(p.Participant.Name)
+	}
+	return names
+}
+
+// completed returns the decided matches in the order they were played.
+func (c *challongeTournament) completed() []challongeMatch {
+	var done []challongeMatch
+	for _, m := range c.Matches {
+		if m.Match.State == "complete" && m.Match.WinnerID != 0 {
+			done = append(done, m.Match)
+		}
+	}
+	sort.SliceStable(done, func(i, j int) bool {
+		if done[i].Round != done[j].Round {
+			return done[i].Round < done[j].Round
+		}
+		return done[i].CompletedAt < done[j].CompletedAt
+	})
+	return done
+}
+
+// challongeResult turns a completed Challonge match into the players and
+// sets of a singles match issue, winner first.
+func challongeResult(m challongeMatch, handles map[int]string) ([]string, []string, error) {
+	p1, p2 := handles[m.Player1ID], handles[m.Player2ID]
+	if p1 == "" || p2 == "" {
+		return nil, nil, fmt.Errorf("match %d has an unknown participant", m.ID)
+	}
+	if m.ScoresCsv == "" {
+		return nil, nil, fmt.Errorf("match %d (@%s vs @%s) has no set scores", m.ID, p1, p2)
+	}
+	flip := m.WinnerID == m.Player2ID
+	var sets []string
+	for _, s := range strings.Split(m.ScoresCsv, ",") {
+		parts := strings.SplitN(strings.TrimSpace(s), "-", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("match %d has an unreadable score %q", m.ID, m.ScoresCsv)
+		}
+		g1, err1 := strconv.Atoi(parts[0])
+		g2, err2 := strconv.Atoi(parts[1])
+		if err1 != nil || err2 != nil || g1 < 0 || g2 < 0 {
+			return nil, nil, fmt.Errorf("match %d has an unreadable score %q", m.ID, m.ScoresCsv)
+		}
+		if flip {
+			g1, g2 = g2, g1
+		}
+		sets = append(sets, fmt.Sprintf("%d-%d", g1, g2))
+	}
+	if flip {
+		p1, p2 = p2, p1
+	}
+	return []string{"@" + p1, "@" + p2}, sets, nil
+}
+
+// challongeDate returns the date part of a Challonge timestamp, or today.
+func challongeDate(ts string) string {
+	if t, err := time.Parse(time.RFC3339, ts); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return time.Now().Format("2006-01-02")
+}
+
+// challongeImport is a completed Challonge match prepared for import: the
+// match issue it becomes, or why it can't be imported.
+type challongeImport struct {
+	match     challongeMatch
+	completed time.Time
+	players   []string
+	sets      []string
+	date      string
+	err       error // the match is skipped
+}
+
+// prepareChallongeImports parses and validates matches, with up to workers
+// at a time, and returns them in the order they were completed (by round
+// where Challonge gives no time), the order their issues are opened in.
+// Handles in validated have already been checked on GitHub.
+func prepareChallongeImports(ctx context.Context, matches []challongeMatch, handles map[int]string, validated map[string]bool, workers int) ([]*challongeImport, error) {
+	imports := make([]*challongeImport, len(matches))
+	for i, m := range matches {
+		imports[i] = &challongeImport{match: m, date: challongeDate(m.CompletedAt)}
+		imports[i].completed, _ = time.Parse(time.RFC3339, m.CompletedAt)
+	}
+	err := githubapi.ForEach(ctx, workers, imports, func(ctx context.Context, im *challongeImport) error {
+		im.players, im.sets, im.err = challongeResult(im.match, handles)
+		if im.err != nil {
+			return nil
+		}
+		if err := breaksRules("singles", im.date, im.sets); err != nil {
+			im.err = fmt.Errorf("match %d (%s vs %s): %w", im.match.ID, im.players[0], im.players[1], err)
+			return nil
+		}
+		var unchecked []string
+		for _, p := range im.players {
+			if !validated[strings.TrimPrefix(p, "@")] {
+				unchecked = append(unchecked, p)
+			}
+		}
+		// A missing user is the match's problem; anything else (a
+		// cancelled command or an API outage) stops the import.
+		if err := validateHandles(ctx, unchecked); err != nil {
+			if !githubapi.IsNotFound(err) {
+				return err
+			}
+			im.err = fmt.Errorf("match %d: %w", im.match.ID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(imports, func(i, j int) bool {
+		a, b := imports[i], imports[j]
+		if !a.completed.Equal(b.completed) && !a.completed.IsZero() && !b.completed.IsZero() {
+			return a.completed.Before(b.completed)
+		}
+		return a.match.Round < b.match.Round
+	})
+	return imports, nil
+}
+
+// openChallongeImport opens the match issue for a prepared match, trying
+// again up to retries times if it fails. Retrying can't open a second
+// issue, since createSinglesIssue never opens one for a result already
+// reported.
+func openChallongeImport(ctx context.Context, im *challongeImport, retries int) error {
+	for attempt := 0; ; attempt++ {
+		err := createSinglesIssue(ctx, im.players, im.sets, im.date, "", "")
+		if err == nil || attempt >= retries || ctx.Err() != nil {
+			return err
+		}
+		wait := time.Duration(2<<attempt) * time.Second
+		fmt.Fprintf(os.Stderr, "⚠️  match %d: %v; retrying in %s\n", im.match.ID, err, wait)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// pushToChallonge creates a single-elimination Challonge tournament for
+// the draw, with the players in seed order, and returns its page.
+func pushToChallonge(ctx context.Context, t *Tournament, slug string) (string, error) {
+	var reply struct {
+		Tournament challongeTournament `json:"tournament"`
+	}
+	form := url.Values{
+		"tournament[name]":            {t.Name},
+		"tournament[url]":             {slug},
+		"tournament[tournament_type]": {"single elimination"},
+	}
+	if err := challongeRequest(ctx, http.MethodPost, "/tournaments.json", form, &reply); err != nil {
+		return "", err
+	}
+
+	participants := url.Values{}
+	for i, p := range t.Players {
+		participants.Add("participants[][name]", "@"+p)
+		participants.Add("participants[][seed]", strconv.Itoa(i+1))
+	}
+	path := "/tournaments/" + url.PathEscape(slug) + "/participants/bulk_add.json"
+	if err := challongeRequest(ctx, http.MethodPost, path, participants, nil); err != nil {
+		return "", fmt.Errorf("created the Challonge tournament but failed to add players: %w", err)
+	}
+	return reply.Tournament.FullURL, nil
+}
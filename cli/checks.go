@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// checkStates maps the states "checks set" accepts to a check run status
+// and conclusion, and to the commit status state used when check runs
+// can't be created.
+var checkStates = map[string]struct {
+	status, conclusion, commitState string
+}{
+	"queued":          {"queued", "", "pending"},
+	"pending":         {"queued", "", "pending"},
+	"in_progress":     {"in_progress", "", "pending"},
+	"success":         {"completed", "success", "success"},
+	"failure":         {"completed", "failure", "failure"},
+	"neutral":         {"completed", "neutral", "success"},
+	"skipped":         {"completed", "skipped", "success"},
+	"cancelled":       {"completed", "cancelled", "error"},
+	"timed_out":       {"completed", "timed_out", "error"},
+	"action_required": {"completed", "action_required", "failure"},
+}
+
+func checkStateNames() string {
+	var names []string
+	for s := range checkStates {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// checkResult is one check to publish on a commit.
+type checkResult struct {
+	SHA        string
+	Name       string
+	State      string // a key of checkStates
+	Title      string
+	Summary    string
+	DetailsURL string
+}
+
+// publishCheck publishes c as a check run. Check runs can only be created
+// with a GitHub App token (such as Actions' GITHUB_TOKEN); with any other
+// token it falls back to a commit status under the same name, which
+// branch protection treats the same way. Honors --dry-run.
+func publishCheck(ctx context.Context, client *github.Client, c checkResult) error {
+	st, ok := checkStates[c.State]
+	if !ok {
+		return fmt.Errorf("unknown check state '%s'. Use one of: %s", c.State, checkStateNames())
+	}
+	if dryRun {
+		fmt.Printf("[dry-run] would set check %s on %.7s to %s: %s\n", c.Name, c.SHA, c.State, c.Title)
+		return nil
+	}
+
+	opts := github.CreateCheckRunOptions{
+		Name:    c.Name,
+		HeadSHA: c.SHA,
+		Status:  github.String(st.status),
+	}
+	if st.conclusion != "" {
+		opts.Conclusion = github.String(st.conclusion)
+	}
+	if c.DetailsURL != "" {
+		opts.DetailsURL = github.String(c.DetailsURL)
+	}
+	if c.Title != "" || c.Summary != "" {
+		title := c.Title
+		if title == "" {
+			title = c.Name
+		}
+		opts.Output = &github.CheckRunOutput{Title: github.String(title), Summary: github.String(c.Summary)}
+	}
+
+	_, _, err := client.Checks.CreateCheckRun(ctx, owner, repo, opts)
+	if err == nil {
+		return nil
+	}
+	if !githubapi.IsForbidden(err) {
+		return fmt.Errorf("failed to create check run %s: %w", c.Name, err)
+	}
+
+	// Not an App token: publish a commit status instead.
+	description := c.Title
+	if len(description) > 140 {
+		description = description[:137] + "..."
+	}
+	status := &github.RepoStatus{
+		State:       github.String(st.commitState),
+		Context:     github.String(c.Name),
+		Description: github.String(description),
+	}
+	if c.DetailsURL != "" {
+		status.TargetURL = github.String(c.DetailsURL)
+	}
+	if _, _, err := client.Repositories.CreateStatus(ctx, owner, repo, c.SHA, status); err != nil {
+		return fmt.Errorf("failed to set status %s: %w", c.Name, err)
+	}
+	return nil
+}
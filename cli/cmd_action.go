@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var actionCmd = &cobra.Command{
+	Use:   "action",
+	Short: "Handle the GitHub Actions event that triggered the workflow",
+	Long: `Read the event payload from GITHUB_EVENT_PATH and handle it, writing step
+outputs to GITHUB_OUTPUT and a report to the step summary. This is the
+entrypoint the league's workflows call in place of the Python scripts.
+
+Supported events:
+  issues          a match issue is parsed and validated (replaces
+                  scripts/parse_singles_issue.py and parse_doubles_issue.py)
+  issue_comment   the comment's issue or pull request is reported, and a
+                  match issue is re-validated
+
+Outputs (match issues): handled, kind, label (the league's label for the
+kind, from label_names), issue_number, validation_failed, error_message,
+date, player1..player4, match_file, match_yaml.
+Outputs (comments): comment_id, comment_author, is_pull_request.
+
+Outside Actions, pass --event and --event-name to replay a saved payload;
+outputs are then printed.
+
+Examples:
+  tennis action
+  tennis action --event-name issues --event payload.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		eventName, _ := cmd.Flags().GetString("event-name")
+		eventPath, _ := cmd.Flags().GetString("event")
+		eventName, event, err := readActionEvent(eventName, eventPath)
+		if err != nil {
+			return err
+		}
+
+		switch e := event.(type) {
+		case *github.IssuesEvent:
+			return handleMatchIssueEvent(cmd.Context(), e.GetIssue())
+		case *github.IssueCommentEvent:
+			return handleIssueCommentEvent(cmd.Context(), e)
+		}
+		if err := setOutput("handled", "false"); err != nil {
+			return err
+		}
+		fmt.Printf("Nothing to do for %s events\n", eventName)
+		return nil
+	},
+}
+
+// handleMatchIssueEvent parses and validates a match issue and writes the
+// outputs the issue-to-PR workflow uses to create the match file and PR.
+// A validation failure is reported through the outputs, not the exit
+// status, so the workflow can comment on the issue.
+func handleMatchIssueEvent(ctx context.Context, issue *github.Issue) error {
+	kind, _, ok := matchIssueKind(issue)
+	if !ok || kind == "conflict" {
+		if err := setOutput("handled", "false"); err != nil {
+			return err
+		}
+		return writeStepSummary(fmt.Sprintf("#%d is not a match issue; nothing to do.\n", issue.GetNumber()))
+	}
+
+	m := parseMatchIssue(kind, issue.GetBody())
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	for _, side := range [][]string{m.Players, m.Team1, m.Team2} {
+		for i, p := range side {
+			side[i] = resolveAlias(aliases, p)
+		}
+	}
+
+	label := labelNames.Singles
+	if kind == "doubles" {
+		label = labelNames.Doubles
+	}
+	outputs := [][2]string{
+		{"handled", "true"},
+		{"kind", kind},
+		{"label", label},
+		{"issue_number", strconv.Itoa(issue.GetNumber())},
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "### %s match issue #%d\n\n", strings.ToUpper(kind[:1])+kind[1:], issue.GetNumber())
+
+	if problems := m.validate(); len(problems) > 0 {
+		var lines []string
+		for _, p := range problems {
+			lines = append(lines, "- "+p.Message)
+		}
+		outputs = append(outputs,
+			[2]string{"validation_failed", "true"},
+			[2]string{"error_message", strings.Join(lines, "\n")})
+		fmt.Fprintf(&summary, "❌ The issue can't be recorded:\n\n%s\n", strings.Join(lines, "\n"))
+	} else {
+		match := m.Match()
+		match.SourceIssue = issue.GetNumber()
+		if handicap.Enabled() {
+			if err := recordHandicap(ctx, kind, &match); err != nil {
+				return err
+			}
+		}
+		data, err := marshalYAML(match)
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs,
+			[2]string{"validation_failed", "false"},
+			[2]string{"date", m.Date})
+		for i, p := range m.Players {
+			outputs = append(outputs, [2]string{fmt.Sprintf("player%d", i+1), p})
+		}
+		outputs = append(outputs,
+			[2]string{"match_file", filepath.ToSlash(filepath.Join(kind+"-matches", fmt.Sprintf("%s-%d.yml", m.Date, issue.GetNumber())))},
+			[2]string{"match_yaml", string(data)})
+
+		var sets []string
+		for _, s := range m.Sets {
+			sets = append(sets, fmt.Sprintf("%d-%d", s[0], s[1]))
+		}
+		fmt.Fprintf(&summary, "✅ Valid match on %s: @%s, sets %s\n",
+			m.Date, strings.Join(m.Players, ", @"), strings.Join(sets, ", "))
+		if len(match.Forfeit) > 0 {
+			fmt.Fprintf(&summary, "\nForfeit: @%s didn't turn up\n", strings.Join(match.Forfeit, " & @"))
+		}
+		if h := match.Handicap; h != nil {
+			var adjusted []string
+			for _, s := range match.HandicapSets {
+				adjusted = append(adjusted, fmt.Sprintf("%d-%d", s[0], s[1]))
+			}
+			receiver := match.Players
+			if match.IsDoubles() {
+				receiver = [][]string{match.Team1, match.Team2}[h.Side-1]
+			} else {
+				receiver = receiver[h.Side-1 : h.Side]
+			}
+			fmt.Fprintf(&summary, "\nHandicap: @%s started every set %d games up (rating gap %.1f), so the handicap result is %s\n",
+				strings.Join(receiver, " & @"), h.Games, h.RatingGap, strings.Join(adjusted, ", "))
+		}
+	}
+
+	for _, o := range outputs {
+		if err := setOutput(o[0], o[1]); err != nil {
+			return err
+		}
+	}
+	return writeStepSummary(summary.String())
+}
+
+// recordHandicap adds the head start the lower-rated side of a match had,
+// on the ratings of every other match recorded so far, to its match file.
+func recordHandicap(ctx context.Context, kind string, m *Match) error {
+	load, compute := loadSinglesMatches, computeSinglesRatings
+	if kind == "doubles" {
+		load, compute = loadDoublesMatches, computeDoublesRatings
+	}
+	matches, err := load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load matches: %w", err)
+	}
+	var others []Match
+	for _, o := range matches {
+		if o.SourceIssue != m.SourceIssue || o.Repo != "" {
+			others = append(others, o)
+		}
+	}
+	applyHandicap(m, compute(others))
+	return nil
+}
+
+// handleIssueCommentEvent reports who commented where. Comments on a match
+// issue also re-validate it, so a workflow can answer "fixed it" comments.
+func handleIssueCommentEvent(ctx context.Context, e *github.IssueCommentEvent) error {
+	issue := e.GetIssue()
+	outputs := [][2]string{
+		{"comment_id", strconv.FormatInt(e.GetComment().GetID(), 10)},
+		{"comment_author", normalizePlayer(e.GetComment().GetUser().GetLogin())},
+		{"is_pull_request", strconv.FormatBool(issue.IsPullRequest())},
+	}
+	for _, o := range outputs {
+		if err := setOutput(o[0], o[1]); err != nil {
+			return err
+		}
+	}
+	if issue.IsPullRequest() {
+		if err := setOutput("issue_number", strconv.Itoa(issue.GetNumber())); err != nil {
+			return err
+		}
+		return writeStepSummary(fmt.Sprintf("Comment by @%s on pull request #%d\n",
+			e.GetComment().GetUser().GetLogin(), issue.GetNumber()))
+	}
+	return handleMatchIssueEvent(ctx, issue)
+}
+
+func init() {
+	actionCmd.Flags().String("event", "", "Event payload file (defaults to $GITHUB_EVENT_PATH)")
+	actionCmd.Flags().String("event-name", "", "Event name (defaults to $GITHUB_EVENT_NAME)")
+
+	rootCmd.AddCommand(actionCmd)
+}
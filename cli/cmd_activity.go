@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var activityCmd = &cobra.Command{
+	Use:   "activity [@player]",
+	Short: "Show a heatmap of matches played per day over the past year",
+	Long: `Show a calendar heatmap of the singles and doubles matches played each day
+over the past year, like GitHub's contribution graph. With a player, only
+their matches count.
+
+--format svg draws the calendar as an image for the Pages site.
+
+Examples:
+  tennis activity
+  tennis activity @player_one
+  tennis activity --to 2025-12-31
+  tennis activity --format svg --out site/activity.svg`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		to, _ := cmd.Flags().GetString("to")
+
+		if format != "text" && format != "svg" {
+			return invalidf("invalid format '%s'. Use text or svg", format)
+		}
+		end := time.Now()
+		if to != "" {
+			var err error
+			if end, err = time.Parse("2006-01-02", to); err != nil {
+				return invalidf("invalid date '%s'. Use YYYY-MM-DD", to)
+			}
+		}
+		end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+
+		player := ""
+		if len(args) == 1 {
+			aliases, err := loadAliases()
+			if err != nil {
+				return err
+			}
+			player = resolveAlias(aliases, normalizePlayer(args[0]))
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		cal := newActivityCalendar(append(singles, doubles...), player, end)
+		title := "League activity"
+		if player != "" {
+			title = "@" + player + "'s activity"
+		}
+		text := title + "\n\n" + cal.Text()
+		if format == "svg" {
+			text = cal.SVG(title)
+		}
+		if out == "" {
+			fmt.Print(text)
+			return nil
+		}
+		if err := os.WriteFile(out, []byte(text), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Activity written to %s\n", out)
+		return nil
+	},
+}
+
+func init() {
+	activityCmd.Flags().String("format", "text", "Output format: text or svg")
+	activityCmd.Flags().StringP("out", "o", "", "Write to a file instead of stdout")
+	activityCmd.Flags().String("to", "", "Last day of the calendar (YYYY-MM-DD), defaults to today")
+	rootCmd.AddCommand(activityCmd)
+}
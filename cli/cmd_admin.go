@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Repository maintenance for league admins",
+}
+
+// issueFilter narrows the issues a bulk admin command touches.
+type issueFilter struct {
+	State  string
+	Author string
+	Since  time.Time // created on or after
+	Until  time.Time // created before
+	Title  string    // case-insensitive substring
+}
+
+// parseIssueFilter parses --filter key=value pairs.
+func parseIssueFilter(pairs []string) (issueFilter, error) {
+	f := issueFilter{State: "all"}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return f, fmt.Errorf("invalid filter '%s'. Use key=value", pair)
+		}
+		switch key {
+		case "state":
+			if value != "open" && value != "closed" && value != "all" {
+				return f, fmt.Errorf("invalid state '%s'. Use open, closed or all", value)
+			}
+			f.State = value
+		case "author":
+			f.Author = normalizePlayer(value)
+		case "since", "until":
+			t, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s date '%s'. Use YYYY-MM-DD", key, value)
+			}
+			if key == "since" {
+				f.Since = t
+			} else {
+				f.Until = t
+			}
+		case "title":
+			f.Title = strings.ToLower(value)
+		default:
+			return f, fmt.Errorf("unknown filter '%s'. Use state, author, since, until or title", key)
+		}
+	}
+	return f, nil
+}
+
+func (f issueFilter) matches(issue *github.Issue) bool {
+	created := issue.GetCreatedAt().Time
+	switch {
+	case f.Author != "" && normalizePlayer(issue.GetUser().GetLogin()) != f.Author:
+		return false
+	case !f.Since.IsZero() && created.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !created.Before(f.Until):
+		return false
+	case f.Title != "" && !strings.Contains(strings.ToLower(issue.GetTitle()), f.Title):
+		return false
+	}
+	return true
+}
+
+var adminRelabelCmd = &cobra.Command{
+	Use:   "relabel",
+	Short: "Move issues from one label to another in bulk",
+	Long: `Migrate a label across historical issues and pull requests: every item
+labelled --from gets --to and loses --from (unless --keep). The --to label is
+created, copying --from's colour, if it doesn't exist yet.
+
+Results are paged through 100 at a time. Writes are paced to at most
+--writes-per-second (default 1) with some jitter, and slowed down further
+if GitHub answers with a secondary rate limit anyway; --delay adds a pause
+between issues on top. When GitHub's rate limit runs low the command waits
+for it to reset.
+
+By default the command stops at the first issue it can't relabel;
+--continue-on-error carries on and reports the failures at the end. Either
+way the issues left over are listed in a retry file, and --retry reruns
+just those. --summary writes every issue's outcome as JSON.
+
+Filters (repeatable --filter key=value):
+  state=open|closed|all   issue state (default all)
+  author=<handle>         opened by this user
+  since=YYYY-MM-DD        created on or after
+  until=YYYY-MM-DD        created before
+  title=<text>            title contains text
+
+Examples:
+  tennis admin relabel --from new-singles-match --to match:singles --dry-run
+  tennis admin relabel --from new-doubles-match --to match:doubles --filter state=closed --filter since=2025-01-01
+  tennis admin relabel --from new-singles-match --to match:singles --continue-on-error --summary relabel.json
+  tennis admin relabel --from new-singles-match --to match:singles --retry .tennis/retry-admin-relabel.txt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		filters, _ := cmd.Flags().GetStringArray("filter")
+		keep, _ := cmd.Flags().GetBool("keep")
+		delay, _ := cmd.Flags().GetDuration("delay")
+
+		if from == "" || to == "" {
+			return invalidf("both --from and --to are required")
+		}
+		if from == to {
+			return invalidf("--from and --to are the same label")
+		}
+		filter, err := parseIssueFilter(filters)
+		if err != nil {
+			return err
+		}
+		b, err := newBatch(cmd)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+
+		listed, err := listLabelledIssues(ctx, client, from, filter)
+		if err != nil {
+			return err
+		}
+		var issues []*github.Issue
+		var items []string
+		for _, issue := range listed {
+			if item := fmt.Sprintf("#%d", issue.GetNumber()); b.Wants(item) {
+				issues = append(issues, issue)
+				items = append(items, item)
+			}
+		}
+		if len(issues) == 0 {
+			fmt.Printf("No issues labelled %s match the filters\n", from)
+			return nil
+		}
+
+		action := fmt.Sprintf("%s → %s", from, to)
+		if keep {
+			action = fmt.Sprintf("+%s (keeping %s)", to, from)
+		}
+		if dryRun {
+			fmt.Printf("[dry-run] would relabel %d issues in %s/%s: %s\n", len(issues), owner, repo, action)
+			for _, issue := range issues {
+				fmt.Printf("  #%-5d %-6s %s\n", issue.GetNumber(), issue.GetState(), issue.GetTitle())
+			}
+			return nil
+		}
+		if err := confirm("Relabel %d issues in %s/%s (%s)?", len(issues), owner, repo, action); err != nil {
+			return err
+		}
+
+		if err := ensureLabel(ctx, client, to, from); err != nil {
+			return err
+		}
+
+		p := newProgress("Relabelling", len(issues))
+		b.Run(items, func(i int) error {
+			n := issues[i].GetNumber()
+			defer p.Add(1)
+			defer time.Sleep(delay)
+			if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, n, []string{to}); err != nil {
+				return fmt.Errorf("failed to label #%d: %w", n, err)
+			}
+			if !keep {
+				if _, err := client.Issues.RemoveLabelForIssue(ctx, owner, repo, n, from); err != nil {
+					return fmt.Errorf("failed to remove %s from #%d: %w", from, n, err)
+				}
+			}
+			p.Printf("[%d/%d] #%d %s\n", i+1, len(issues), n, action)
+			return nil
+		})
+		p.Finish()
+		if err := b.Finish(); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Relabelled %d issues\n", b.Succeeded)
+		return nil
+	},
+}
+
+// listLabelledIssues pages through every issue and pull request carrying
+// label and returns those matching the filter, oldest first.
+func listLabelledIssues(ctx context.Context, client *github.Client, label string, filter issueFilter) ([]*github.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:     filter.State,
+		Labels:    []string{label},
+		Sort:      "created",
+		Direction: "asc",
+	}
+	if !filter.Since.IsZero() {
+		// Narrows the listing server-side; "since" is by update time, so
+		// creation dates are still checked by the filter.
+		opts.Since = filter.Since
+	}
+
+	all, err := githubapi.ListAll(ctx, "list issues", func(ctx context.Context, page github.ListOptions) ([]*github.Issue, *github.Response, error) {
+		o := *opts
+		o.ListOptions = page
+		return client.Issues.ListByRepo(ctx, owner, repo, &o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var issues []*github.Issue
+	for _, issue := range all {
+		if filter.matches(issue) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// ensureLabel creates label if it's missing, copying like's colour and
+// description.
+func ensureLabel(ctx context.Context, client *github.Client, label, like string) error {
+	_, _, err := client.Issues.GetLabel(ctx, owner, repo, label)
+	if err == nil {
+		return nil
+	}
+	if !githubapi.IsNotFound(err) {
+		return fmt.Errorf("failed to look up label %s: %w", label, err)
+	}
+
+	newLabel := &github.Label{Name: github.String(label)}
+	if src, _, err := client.Issues.GetLabel(ctx, owner, repo, like); err == nil {
+		newLabel.Color = src.Color
+		newLabel.Description = src.Description
+	}
+	if _, _, err := client.Issues.CreateLabel(ctx, owner, repo, newLabel); err != nil {
+		return fmt.Errorf("failed to create label %s: %w", label, err)
+	}
+	fmt.Printf("Created label %s\n", label)
+	return nil
+}
+
+var adminLabelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "Manage repository labels",
+}
+
+var adminLabelsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile repository labels with .tennis.yml",
+	Long: `Make the repository's labels match the labels list in .tennis.yml
+(or the CLI's default labels if the list is absent): missing labels are
+created and labels whose colour or description differ are updated. Labels
+that aren't declared are only deleted with --prune.
+
+A diff is printed first; --dry-run stops there.
+
+Examples:
+  tennis admin labels sync --dry-run
+  tennis admin labels sync --prune`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prune, _ := cmd.Flags().GetBool("prune")
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		declared := cfg.LabelSet()
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+
+		current, err := listLabels(ctx, client)
+		if err != nil {
+			return err
+		}
+		changes := diffLabels(declared, current, prune)
+
+		if len(changes) == 0 {
+			fmt.Printf("✅ Labels in %s/%s already match %s\n", owner, repo, configPath())
+			return nil
+		}
+		deletes := 0
+		for _, c := range changes {
+			fmt.Println(c.String())
+			if c.Op == "delete" {
+				deletes++
+			}
+		}
+		if dryRun {
+			return nil
+		}
+		if deletes > 0 {
+			if err := confirm("Delete %d labels from %s/%s?", deletes, owner, repo); err != nil {
+				return err
+			}
+		}
+
+		for _, c := range changes {
+			if err := c.apply(ctx, client); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("\n✅ %d label changes applied\n", countApplied(changes))
+		return nil
+	},
+}
+
+// labelChange is one step in reconciling repository labels.
+type labelChange struct {
+	Op   string // "create", "update", "delete" or "extra" (undeclared, kept)
+	Want leagueLabel
+	Have *github.Label
+}
+
+func (c labelChange) String() string {
+	switch c.Op {
+	case "create":
+		return fmt.Sprintf("+ %-24s #%s  %s", c.Want.Name, c.Want.Color, c.Want.Description)
+	case "update":
+		var diffs []string
+		if c.Have.GetName() != c.Want.Name {
+			diffs = append(diffs, fmt.Sprintf("name %s → %s", c.Have.GetName(), c.Want.Name))
+		}
+		if !strings.EqualFold(c.Have.GetColor(), c.Want.Color) {
+			diffs = append(diffs, fmt.Sprintf("color #%s → #%s", c.Have.GetColor(), c.Want.Color))
+		}
+		if c.Have.GetDescription() != c.Want.Description {
+			diffs = append(diffs, fmt.Sprintf("description %q → %q", c.Have.GetDescription(), c.Want.Description))
+		}
+		return fmt.Sprintf("~ %-24s %s", c.Want.Name, strings.Join(diffs, ", "))
+	case "delete":
+		return fmt.Sprintf("- %s", c.Have.GetName())
+	}
+	return fmt.Sprintf("  %-24s not declared (kept; use --prune to delete)", c.Have.GetName())
+}
+
+func (c labelChange) apply(ctx context.Context, client *github.Client) error {
+	var err error
+	switch c.Op {
+	case "create":
+		_, _, err = client.Issues.CreateLabel(ctx, owner, repo, &github.Label{
+			Name:        github.String(c.Want.Name),
+			Color:       github.String(c.Want.Color),
+			Description: github.String(c.Want.Description),
+		})
+	case "update":
+		_, _, err = client.Issues.EditLabel(ctx, owner, repo, c.Have.GetName(), &github.Label{
+			Name:        github.String(c.Want.Name),
+			Color:       github.String(c.Want.Color),
+			Description: github.String(c.Want.Description),
+		})
+	case "delete":
+		_, err = client.Issues.DeleteLabel(ctx, owner, repo, c.Have.GetName())
+	}
+	if err != nil {
+		name := c.Want.Name
+		if name == "" {
+			name = c.Have.GetName()
+		}
+		return fmt.Errorf("failed to %s label %s: %w", c.Op, name, err)
+	}
+	return nil
+}
+
+func countApplied(changes []labelChange) int {
+	n := 0
+	for _, c := range changes {
+		if c.Op != "extra" {
+			n++
+		}
+	}
+	return n
+}
+
+// diffLabels works out the changes that turn current into declared. Names
+// are matched case-insensitively, as GitHub does.
+func diffLabels(declared []leagueLabel, current []*github.Label, prune bool) []labelChange {
+	byName := make(map[string]*github.Label)
+	for _, l := range current {
+		byName[strings.ToLower(l.GetName())] = l
+	}
+
+	var changes []labelChange
+	seen := make(map[string]bool)
+	for _, want := range declared {
+		key := strings.ToLower(want.Name)
+		seen[key] = true
+		have, ok := byName[key]
+		switch {
+		case !ok:
+			changes = append(changes, labelChange{Op: "create", Want: want})
+		case have.GetName() != want.Name || !strings.EqualFold(have.GetColor(), want.Color) || have.GetDescription() != want.Description:
+			changes = append(changes, labelChange{Op: "update", Want: want, Have: have})
+		}
+	}
+	for _, have := range current {
+		if seen[strings.ToLower(have.GetName())] {
+			continue
+		}
+		op := "extra"
+		if prune {
+			op = "delete"
+		}
+		changes = append(changes, labelChange{Op: op, Have: have})
+	}
+	return changes
+}
+
+// listLabels returns every label in the repository.
+func listLabels(ctx context.Context, client *github.Client) ([]*github.Label, error) {
+	return githubapi.ListAll(ctx, "list labels", func(ctx context.Context, page github.ListOptions) ([]*github.Label, *github.Response, error) {
+		return client.Issues.ListLabels(ctx, owner, repo, &page)
+	})
+}
+
+var adminTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage the match issue forms",
+}
+
+var adminTemplatesSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Regenerate the match issue forms from the CLI's field definitions",
+	Long: `Write .github/ISSUE_TEMPLATE/singles-match.yml and doubles-match.yml in
+the league checkout from the same field definitions "tennis match" uses to
+build issue bodies, so issues from the web form and the CLI are identical.
+
+With --check nothing is written; the command fails if any form is out of
+date, for use in CI.
+
+Examples:
+  tennis admin templates sync
+  tennis admin templates sync --check`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		check, _ := cmd.Flags().GetBool("check")
+
+		stale := 0
+		for _, form := range matchForms {
+			path := filepath.Join(leagueDir(), formPath(form))
+			current, err := os.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			want := form.YAML()
+			if string(current) == want {
+				fmt.Printf("✅ %s is up to date\n", formPath(form))
+				continue
+			}
+			stale++
+			if check {
+				fmt.Printf("❌ %s is out of date\n", formPath(form))
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, []byte(want), 0o644); err != nil {
+				return err
+			}
+			fmt.Printf("✏️  Wrote %s\n", formPath(form))
+		}
+
+		if check && stale > 0 {
+			return fmt.Errorf("%d issue forms are out of date; run `tennis admin templates sync`", stale)
+		}
+		if stale > 0 {
+			fmt.Println("Commit the updated issue forms to publish them")
+		}
+		return nil
+	},
+}
+
+// formPath is where an issue form lives in a league repository.
+func formPath(form issueForm) string {
+	return ".github/ISSUE_TEMPLATE/" + form.File
+}
+
+func init() {
+	adminRelabelCmd.Flags().String("from", "", "Label to migrate away from")
+	adminRelabelCmd.Flags().String("to", "", "Label to migrate to")
+	adminRelabelCmd.Flags().StringArray("filter", nil, "Only relabel matching issues (key=value, repeatable)")
+	adminRelabelCmd.Flags().Bool("keep", false, "Add --to without removing --from")
+	adminRelabelCmd.Flags().Duration("delay", 0, "Extra pause between issues, on top of --writes-per-second")
+	adminRelabelCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the issues that would be relabelled without changing them")
+	addBatchFlags(adminRelabelCmd)
+
+	adminLabelsSyncCmd.Flags().Bool("prune", false, "Delete labels that aren't declared")
+	adminLabelsSyncCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the diff without changing labels")
+	addThrottleFlag(adminLabelsSyncCmd)
+
+	adminTemplatesSyncCmd.Flags().Bool("check", false, "Fail if the issue forms are out of date instead of writing them")
+
+	adminLabelsCmd.AddCommand(adminLabelsSyncCmd)
+	adminTemplatesCmd.AddCommand(adminTemplatesSyncCmd)
+	adminCmd.AddCommand(adminRelabelCmd)
+	adminCmd.AddCommand(adminLabelsCmd)
+	adminCmd.AddCommand(adminTemplatesCmd)
+	rootCmd.AddCommand(adminCmd)
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// auditFinding is one problem found by "tennis audit".
+type auditFinding struct {
+	Issue    int    `json:"issue"`
+	URL      string `json:"url"`
+	Kind     string `json:"kind,omitempty"`
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// auditReport is the machine-readable output of "tennis audit".
+type auditReport struct {
+	Repo     string         `json:"repo"`
+	Scanned  int            `json:"scanned"`
+	Findings []auditFinding `json:"findings"`
+}
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Check every match issue for data problems",
+	Long: `Scan every match issue in the repository, parse it the way the
+issue-to-PR workflow does, and report problems: malformed bodies, bad scores,
+missing winners, self-matches, conflicting or missing labels, and matches
+whose pull request was never created, rejected, or is still awaiting
+approval. Merged matches are also checked against the local match files.
+
+--format jsonl streams the findings instead, one JSON object per line,
+checking each page of issues as it is fetched, so large leagues can pipe
+them into other tools (jq, grep, a database) without waiting for the scan.
+
+Examples:
+  tennis audit
+  tennis audit --format json --out audit.json
+  tennis audit --format jsonl | jq -r 'select(.severity == "error") | .url'
+  tennis audit --state open`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state, _ := cmd.Flags().GetString("state")
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+
+		if state != "open" && state != "closed" && state != "all" {
+			return invalidf("unknown state '%s'. Use open, closed or all", state)
+		}
+		if format != "text" && format != "json" && format != "jsonl" {
+			return invalidf("unknown format '%s'. Use text, json or jsonl", format)
+		}
+
+		w := io.Writer(os.Stdout)
+		if out != "" {
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		if format == "jsonl" {
+			return streamAudit(cmd.Context(), state, w)
+		}
+
+		report, err := runAudit(cmd.Context(), state)
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		}
+		printAuditReport(w, report)
+		return nil
+	},
+}
+
+// runAudit fetches match issues and pull requests and checks each issue.
+func runAudit(ctx context.Context, state string) (*auditReport, error) {
+	client := getGitHubClient()
+
+	var issues []*github.Issue
+	var prs map[int]*github.PullRequest
+	err := githubapi.All(ctx,
+		func(ctx context.Context) (err error) {
+			issues, err = listMatchIssues(ctx, client, state)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			prs, err = listMatchPullRequests(ctx, client)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := recordedIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &auditReport{Repo: owner + "/" + repo, Scanned: len(issues), Findings: []auditFinding{}}
+	for _, issue := range issues {
+		report.Findings = append(report.Findings, auditIssue(issue, prs[issue.GetNumber()], recorded, aliases)...)
+	}
+	return report, nil
+}
+
+// streamAudit writes the findings to w as JSON Lines, one per line,
+// checking each page of match issues as it arrives.
+func streamAudit(ctx context.Context, state string, w io.Writer) error {
+	client := getGitHubClient()
+	prs, err := listMatchPullRequests(ctx, client)
+	if err != nil {
+		return err
+	}
+	recorded, err := recordedIssues(ctx)
+	if err != nil {
+		return err
+	}
+	aliases, err := loadAliases()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	return eachMatchIssuePage(ctx, client, state, func(issues []*github.Issue) error {
+		for _, issue := range issues {
+			for _, f := range auditIssue(issue, prs[issue.GetNumber()], recorded, aliases) {
+				if err := enc.Encode(f); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// matchIssueKind classifies an issue by its labels, falling back to the
+// title "tennis match" gives it. ok is false for non-match issues.
+func matchIssueKind(issue *github.Issue) (kind string, labelled bool, ok bool) {
+	var singles, doubles bool
+	for _, l := range issue.Labels {
+		switch l.GetName() {
+		case labelNames.Singles:
+			singles = true
+		case labelNames.Doubles:
+			doubles = true
+		}
+	}
+	switch {
+	case singles && doubles:
+		return "conflict", true, true
+	case singles:
+		return "singles", true, true
+	case doubles:
+		return "doubles", true, true
+	case strings.HasPrefix(issue.GetTitle(), "Singles Match:"):
+		return "singles", false, true
+	case strings.HasPrefix(issue.GetTitle(), "Doubles Match:"):
+		return "doubles", false, true
+	}
+	return "", false, false
+}
+
+// listMatchIssues returns every match issue (see matchIssueKind) in the
+// given state, oldest first.
+func listMatchIssues(ctx context.Context, client *github.Client, state string) ([]*github.Issue, error) {
+	issues, err := githubapi.ListAll(ctx, "list issues", issuePages(client, state))
+	if err != nil {
+		return nil, err
+	}
+	return onlyMatchIssues(issues), nil
+}
+
+// eachMatchIssuePage is listMatchIssues a page at a time, for streaming.
+func eachMatchIssuePage(ctx context.Context, client *github.Client, state string, page func([]*github.Issue) error) error {
+	return githubapi.ListPages(ctx, "list issues", issuePages(client, state), func(issues []*github.Issue) error {
+		return page(onlyMatchIssues(issues))
+	})
+}
+
+// issuePages lists the repository's issues in the given state, oldest
+// first, a page at a time.
+func issuePages(client *github.Client, state string) func(ctx context.Context, page github.ListOptions) ([]*github.Issue, *github.Response, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:     state,
+		Sort:      "created",
+		Direction: "asc",
+	}
+	return func(ctx context.Context, page github.ListOptions) ([]*github.Issue, *github.Response, error) {
+		o := *opts
+		o.ListOptions = page
+		return client.Issues.ListByRepo(ctx, owner, repo, &o)
+	}
+}
+
+// onlyMatchIssues drops pull requests and non-match issues.
+func onlyMatchIssues(issues []*github.Issue) []*github.Issue {
+	var matches []*github.Issue
+	for _, issue := range issues {
+		if issue.IsPullRequest() {
+			continue
+		}
+		if _, _, ok := matchIssueKind(issue); ok {
+			matches = append(matches, issue)
+		}
+	}
+	return matches
+}
+
+// listMatchPullRequests maps issue numbers to the pull request the
+// issue-to-PR workflow opened for them (branch match/issue-<n>).
+func listMatchPullRequests(ctx context.Context, client *github.Client) (map[int]*github.PullRequest, error) {
+	all, err := githubapi.ListAll(ctx, "list pull requests", func(ctx context.Context, page github.ListOptions) ([]*github.PullRequest, *github.Response, error) {
+		return client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{State: "all", ListOptions: page})
+	})
+	if err != nil {
+		return nil, err
+	}
+	prs := make(map[int]*github.PullRequest)
+	for _, pr := range all {
+		n, ok := matchIssueForBranch(pr.GetHead().GetRef())
+		if !ok {
+			continue
+		}
+		// Keep the most useful PR if a branch was reused: merged, then open.
+		if prev, ok := prs[n]; ok && (prev.MergedAt != nil || prev.GetState() == "open") {
+			continue
+		}
+		prs[n] = pr
+	}
+	return prs, nil
+}
+
+// recordedIssues returns the source issues of every local match file.
+func recordedIssues(ctx context.Context) (map[int]Match, error) {
+	recorded := make(map[int]Match)
+	for _, load := range []func(context.Context) ([]Match, error){loadSinglesMatches, loadDoublesMatches} {
+		matches, err := load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load matches: %w", err)
+		}
+		for _, m := range matches {
+			if m.SourceIssue != 0 && m.Repo == "" {
+				recorded[m.SourceIssue] = m
+			}
+		}
+	}
+	return recorded, nil
+}
+
+// auditIssue checks one match issue.
+func auditIssue(issue *github.Issue, pr *github.PullRequest, recorded map[int]Match, aliases map[string]string) []auditFinding {
+	kind, labelled, _ := matchIssueKind(issue)
+	var findings []auditFinding
+	add := func(severity, code, message string) {
+		findings = append(findings, auditFinding{
+			Issue:    issue.GetNumber(),
+			URL:      issue.GetHTMLURL(),
+			Kind:     kind,
+			Severity: severity,
+			Code:     code,
+			Message:  message,
+		})
+	}
+
+	if kind == "conflict" {
+		add(severityError, "conflicting_labels", fmt.Sprintf("labelled both %s and %s", labelNames.Singles, labelNames.Doubles))
+		return findings
+	}
+	if !labelled {
+		label := labelNames.Singles
+		if kind == "doubles" {
+			label = labelNames.Doubles
+		}
+		add(severityError, "missing_label", fmt.Sprintf("looks like a %s match but has no %s label, so no workflow ran", kind, label))
+	}
+
+	parsed := parseMatchIssue(kind, issue.GetBody())
+	problems := parsed.validate()
+	for _, p := range problems {
+		add(severityError, p.Code, p.Message)
+	}
+
+	_, isRecorded := recorded[issue.GetNumber()]
+	switch {
+	case pr == nil:
+		if !isRecorded && len(problems) == 0 && labelled {
+			add(severityError, "no_pull_request", "the issue is valid but no match pull request was created")
+		}
+	case pr.MergedAt != nil:
+		if !isRecorded {
+			add(severityWarning, "missing_match_file", fmt.Sprintf("PR #%d was merged but no local match file has source_issue %d (is your checkout up to date?)", pr.GetNumber(), issue.GetNumber()))
+		}
+	case pr.GetState() == "closed":
+		add(severityWarning, "pr_rejected", fmt.Sprintf("PR #%d was closed without merging, so the match is not recorded", pr.GetNumber()))
+	default:
+		add(severityWarning, "awaiting_approval", fmt.Sprintf("PR #%d is still open and awaiting approval", pr.GetNumber()))
+	}
+
+	// Match files are read with renamed and merged players resolved
+	// (aliases.yml), so the issue's players must be too.
+	reported := parsed.Match()
+	for _, side := range [][]string{reported.Players, reported.Team1, reported.Team2} {
+		for i, p := range side {
+			side[i] = resolveAlias(aliases, p)
+		}
+	}
+	if m, ok := recorded[issue.GetNumber()]; ok && len(problems) == 0 && !sameMatch(m, reported) {
+		add(severityWarning, "file_mismatch", "the recorded match file no longer agrees with the issue body (was the issue edited after merging?)")
+	}
+	return findings
+}
+
+// sameMatch reports whether two matches have the same date, players and sets.
+func sameMatch(a, b Match) bool {
+	return a.Date == b.Date &&
+		fmt.Sprint(a.Players, a.Team1, a.Team2, a.Sets) == fmt.Sprint(b.Players, b.Team1, b.Team2, b.Sets)
+}
+
+func printAuditReport(w io.Writer, report *auditReport) {
+	if len(report.Findings) == 0 {
+		fmt.Fprintf(w, "✅ %d match issues in %s, no problems found\n", report.Scanned, report.Repo)
+		return
+	}
+
+	findings := append([]auditFinding{}, report.Findings...)
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Issue < findings[j].Issue })
+
+	var errors, warnings int
+	for _, f := range findings {
+		icon := "⚠️ "
+		if f.Severity == severityError {
+			icon = "❌"
+			errors++
+		} else {
+			warnings++
+		}
+		fmt.Fprintf(w, "%s #%-5d %-20s %s\n", icon, f.Issue, f.Code, f.Message)
+	}
+	fmt.Fprintf(w, "\n%d match issues in %s: %d errors, %d warnings\n", report.Scanned, report.Repo, errors, warnings)
+}
+
+func init() {
+	auditCmd.Flags().String("state", "all", "Issue state to scan: open, closed or all")
+	auditCmd.Flags().String("format", "text", "Output format: text, json or jsonl (one finding per line, streamed)")
+	auditCmd.Flags().String("out", "", "Write the report to a file instead of stdout")
+
+	rootCmd.AddCommand(auditCmd)
+}
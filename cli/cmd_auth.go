@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Save, check or remove the GitHub token the CLI uses",
+	Long: `Save a GitHub token for the CLI so it doesn't need GITHUB_TOKEN or --token.
+
+Tokens are kept in the OS keychain: the login Keychain on macOS, the Secret
+Service (GNOME Keyring or KWallet, via secret-tool) on Linux and the
+Credential Manager on Windows. Where no keychain is available the token is
+saved to an AES-encrypted file in the user's config directory instead.`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Save a GitHub token",
+	Long: `Check a GitHub token and save it. The token is read from standard input,
+so it stays out of your shell history.
+
+The encrypted file fallback uses a key bound to this machine and user, or
+$TENNIS_TOKEN_PASSPHRASE when it is set (it must then be set for every
+command). --insecure-store saves the token as plain text instead, e.g. for
+containers with no keychain and no stable machine ID.
+
+Examples:
+  tennis auth login
+  gh auth token | tennis auth login --with-token
+  tennis auth login --with-token --insecure-store < token.txt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		withToken, _ := cmd.Flags().GetBool("with-token")
+		insecure, _ := cmd.Flags().GetBool("insecure-store")
+
+		if !withToken {
+			fmt.Print("Paste a GitHub token (classic with repo scope, or fine-grained with issues and pull requests access): ")
+		}
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("no token given")
+		}
+		newToken := strings.TrimSpace(line)
+		if newToken == "" {
+			return fmt.Errorf("no token given")
+		}
+
+		user, _, err := githubapi.New(newToken, githubapi.Options{Trace: traceOut, Record: recordDir, Replay: replayDir}).Users.Get(cmd.Context(), "")
+		if err != nil {
+			if githubapi.IsUnauthorized(err) {
+				return fmt.Errorf("the GitHub token is invalid or expired")
+			}
+			return fmt.Errorf("failed to check the token: %w", err)
+		}
+
+		stores := tokenStores()
+		saved := -1
+		for i, s := range stores {
+			if insecure != (s.Name() == "plaintext file") {
+				continue
+			}
+			if err := s.Set(newToken); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				continue
+			}
+			saved = i
+			break
+		}
+		if saved < 0 {
+			return fmt.Errorf("could not save the token")
+		}
+		// Drop tokens saved earlier elsewhere so they can't shadow this one.
+		for i, s := range stores {
+			if i != saved {
+				if err := s.Delete(); err != nil {
+					fmt.Printf("⚠️  failed to remove the token from the %s: %v\n", s.Name(), err)
+				}
+			}
+		}
+		fmt.Printf("✅ Logged in as @%s; token saved to the %s\n", user.GetLogin(), stores[saved].Name())
+		return nil
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the saved GitHub token",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, from := storedToken()
+		for _, s := range tokenStores() {
+			if err := s.Delete(); err != nil {
+				return fmt.Errorf("failed to remove the token from the %s: %v", s.Name(), err)
+			}
+		}
+		if from == "" {
+			fmt.Println("No saved token")
+			return nil
+		}
+		fmt.Printf("✅ Token removed from the %s\n", from)
+		return nil
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which GitHub token the CLI uses",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if tokenSource == "" {
+			fmt.Println("Not logged in. Run `tennis auth login` or set GITHUB_TOKEN.")
+			return nil
+		}
+		user, _, err := getGitHubClient().Users.Get(cmd.Context(), "")
+		if err != nil {
+			if githubapi.IsUnauthorized(err) {
+				return fmt.Errorf("the token from %s is invalid or expired", tokenSource)
+			}
+			return fmt.Errorf("failed to check the token from %s: %w", tokenSource, err)
+		}
+		fmt.Printf("Logged in as @%s with the token from %s\n", user.GetLogin(), tokenSource)
+		return nil
+	},
+}
+
+func init() {
+	authLoginCmd.Flags().Bool("with-token", false, "Read the token from standard input without prompting")
+	authLoginCmd.Flags().Bool("insecure-store", false, "Save the token in a plaintext file instead of the keychain")
+
+	authCmd.AddCommand(authLoginCmd, authLogoutCmd, authStatusCmd)
+	rootCmd.AddCommand(authCmd)
+}
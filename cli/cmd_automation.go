@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var automationCmd = &cobra.Command{
+	Use:   "automation",
+	Short: "Steps run by the league's GitHub Actions workflows",
+}
+
+var requestReviewsCmd = &cobra.Command{
+	Use:   "request-reviews <pr-number>",
+	Short: "Ask a match pull request's players to approve it",
+	Long: `Request reviews on a match pull request from the players in the match,
+replacing scripts/request_reviews.py.
+
+Players are read from the match issue the PR was opened for (branch
+match/issue-<n>), or given with --players. Collaborators are requested as
+reviewers; players who aren't collaborators can't be, so a comment explains
+why. Re-running never repeats a request or the comment.
+
+Examples:
+  tennis automation request-reviews 57
+  tennis automation request-reviews 57 --players @player_one,@player_two`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		players, _ := cmd.Flags().GetStringSlice("players")
+
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			return invalidf("invalid pull request number '%s'", args[0])
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+
+		pr, _, err := client.PullRequests.Get(ctx, owner, repo, number)
+		if err != nil {
+			return fmt.Errorf("failed to fetch PR #%d: %w", number, err)
+		}
+
+		if len(players) == 0 {
+			if players, err = matchPlayersForPR(ctx, client, pr); err != nil {
+				return err
+			}
+		}
+		for i, p := range players {
+			players[i] = normalizePlayer(p)
+		}
+
+		return requestMatchReviews(ctx, client, pr, players)
+	},
+}
+
+// matchPlayersForPR reads the players from the match issue a PR was opened
+// for, found from its match/issue-<n> branch.
+func matchPlayersForPR(ctx context.Context, client *github.Client, pr *github.PullRequest) ([]string, error) {
+	n, ok := matchIssueForBranch(pr.GetHead().GetRef())
+	if !ok {
+		return nil, fmt.Errorf("PR #%d is not a match pull request (branch %s); pass --players", pr.GetNumber(), pr.GetHead().GetRef())
+	}
+	issue, _, err := client.Issues.Get(ctx, owner, repo, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch match issue #%d: %w", n, err)
+	}
+	kind, _, isMatch := matchIssueKind(issue)
+	if !isMatch || kind == "conflict" {
+		return nil, fmt.Errorf("#%d is not a singles or doubles match issue; pass --players", n)
+	}
+	players := parseMatchIssue(kind, issue.GetBody()).Players
+	if len(players) == 0 {
+		return nil, fmt.Errorf("no players found in match issue #%d; pass --players", n)
+	}
+	return players, nil
+}
+
+// requestMatchReviews requests reviews from the collaborators among the
+// players and explains, once, why the others weren't asked.
+func requestMatchReviews(ctx context.Context, client *github.Client, pr *github.PullRequest, players []string) error {
+	requested := make(map[string]bool)
+	for _, u := range pr.RequestedReviewers {
+		requested[normalizePlayer(u.GetLogin())] = true
+	}
+	author := normalizePlayer(pr.GetUser().GetLogin())
+
+	var reviewers, outsiders []string
+	for _, p := range players {
+		if p == "" || p == author {
+			continue
+		}
+		isCollab, _, err := client.Repositories.IsCollaborator(ctx, owner, repo, p)
+		if err != nil {
+			return fmt.Errorf("failed to check whether @%s is a collaborator: %w", p, err)
+		}
+		switch {
+		case !isCollab:
+			outsiders = append(outsiders, p)
+		case !requested[p]:
+			reviewers = append(reviewers, p)
+		}
+	}
+
+	if len(reviewers) > 0 {
+		if dryRun {
+			fmt.Printf("[dry-run] would request reviews on PR #%d from @%s\n", pr.GetNumber(), strings.Join(reviewers, ", @"))
+		} else {
+			if _, _, err := client.PullRequests.RequestReviewers(ctx, owner, repo, pr.GetNumber(), github.ReviewersRequest{Reviewers: reviewers}); err != nil {
+				return fmt.Errorf("failed to request reviewers on PR #%d: %v", pr.GetNumber(), err)
+			}
+			fmt.Printf("Requested reviews on PR #%d from @%s\n", pr.GetNumber(), strings.Join(reviewers, ", @"))
+		}
+	}
+
+	if len(outsiders) > 0 {
+		var mentions []string
+		for _, p := range outsiders {
+			mentions = append(mentions, "@"+p)
+		}
+		body := fmt.Sprintf("Heads up: %s cannot be requested as reviewers because they are not collaborators. "+
+			"Please add them as collaborators if you want them to provide Approve reviews on future PRs.",
+			strings.Join(mentions, ", "))
+		posted, err := commentOnce(ctx, client, pr.GetNumber(), body, "non-collaborators")
+		if err != nil {
+			return err
+		}
+		if posted && !dryRun {
+			fmt.Printf("Commented on PR #%d about non-collaborators %s\n", pr.GetNumber(), strings.Join(mentions, ", "))
+		}
+	}
+	return nil
+}
+
+func init() {
+	requestReviewsCmd.Flags().StringSlice("players", nil, "Players to ask (defaults to the players in the match issue)")
+	requestReviewsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the requests without making them")
+
+	automationCmd.AddCommand(requestReviewsCmd)
+	rootCmd.AddCommand(automationCmd)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var availabilityCmd = &cobra.Command{
+	Use:   "availability",
+	Short: "Mark players away and back",
+}
+
+var availabilityAwayCmd = &cobra.Command{
+	Use:   "away [@handle]",
+	Short: "Mark a player away until a date",
+	Long: `Mark a player away, e.g. on holiday or injured, from --from (default
+today) up to --until, when they're back. While they're away:
+
+  - matchmaking, box leagues and tournament seeding skip them
+  - "bot nudge" doesn't remind them of matches waiting on their approval
+  - their days away don't count towards "bot expire" and "bot escalate"
+    for matches waiting on them, or towards inactivity decay
+
+The away period is saved in players.yml; commit it afterwards. The player
+is the owner of the GitHub token unless a handle is given.
+
+Examples:
+  tennis availability away --until 2025-03-01
+  tennis availability away @player_two --from 2025-07-14 --until 2025-08-04`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		until, _ := cmd.Flags().GetString("until")
+		if until == "" {
+			return invalidf("--until is required")
+		}
+		if from == "" {
+			from = time.Now().Format("2006-01-02")
+		}
+		for _, d := range []string{from, until} {
+			if !isValidDate(d) {
+				return invalidf("invalid date '%s'. Use YYYY-MM-DD format", d)
+			}
+		}
+		if until <= from {
+			return invalidf("--until (%s) must be after --from (%s)", until, from)
+		}
+
+		roster, p, err := availabilityPlayer(cmd, args)
+		if err != nil {
+			return err
+		}
+		p.Away = &awayPeriod{From: from, Until: until}
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+		fmt.Printf("✅ @%s is away from %s, back on %s. Commit %s\n", p.Handle, from, until, rosterPath())
+		return nil
+	},
+}
+
+var availabilityBackCmd = &cobra.Command{
+	Use:   "back [@handle]",
+	Short: "Mark an away player back",
+	Long: `End a player's away period early, or cancel one that hasn't started. The
+days they were away still don't count towards inactivity decay.
+
+Examples:
+  tennis availability back
+  tennis availability back @player_two`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		roster, p, err := availabilityPlayer(cmd, args)
+		if err != nil {
+			return err
+		}
+		today := time.Now().Format("2006-01-02")
+		if p.Away == nil || p.Away.Until <= today {
+			return fmt.Errorf("@%s isn't away", p.Handle)
+		}
+		if p.Away.From >= today {
+			p.Away = nil
+		} else {
+			p.Away.Until = today
+		}
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+		fmt.Printf("✅ @%s is back. Commit %s\n", p.Handle, rosterPath())
+		return nil
+	},
+}
+
+// availabilityPlayer returns the roster and the entry of the player named
+// in args, or of the token's owner.
+func availabilityPlayer(cmd *cobra.Command, args []string) (*Roster, *RosterPlayer, error) {
+	var handle string
+	if len(args) == 1 {
+		if handle = normalizePlayer(args[0]); handle == "" {
+			return nil, nil, invalidf("empty player handle")
+		}
+	} else {
+		user, _, err := getGitHubClient().Users.Get(cmd.Context(), "")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to look up the token's user (give a handle): %w", err)
+		}
+		handle = normalizePlayer(user.GetLogin())
+	}
+	roster, err := loadRoster()
+	if err != nil {
+		return nil, nil, err
+	}
+	p := roster.Find(handle)
+	if p == nil {
+		return nil, nil, fmt.Errorf("@%s is not on the roster", handle)
+	}
+	return roster, p, nil
+}
+
+func init() {
+	availabilityAwayCmd.Flags().String("from", "", "First day away (YYYY-MM-DD), defaults to today")
+	availabilityAwayCmd.Flags().String("until", "", "Day the player is back (YYYY-MM-DD)")
+
+	availabilityCmd.AddCommand(availabilityAwayCmd)
+	availabilityCmd.AddCommand(availabilityBackCmd)
+	rootCmd.AddCommand(availabilityCmd)
+}
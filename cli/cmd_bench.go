@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure the ranking engine on synthetic match data",
+	Long: `Generate synthetic matches and time each stage of the ranking engine on
+them, reporting the average time, allocations and bytes allocated per run:
+
+  parse         load and normalize every match file
+  ratings       replay the singles and doubles Elo ratings
+  leaderboards  compute the singles and doubles leaderboards
+  json          encode the leaderboards as the rankings.json the Pages
+                site publishes
+  incremental   bring a rankings compute snapshot up to date after one
+                new match
+
+The Pages site's HTML and player pages (scripts/build_pages.py and
+scripts/build_player_pages.py) aren't built or timed.
+
+Nothing touches the league checkout or GitHub. Run it before and after an
+engine change, with the same --seed, to spot performance regressions.
+
+Examples:
+  tennis bench
+  tennis bench --matches 20000 --players 200 --runs 3
+  tennis bench --json > bench.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, _ := cmd.Flags().GetInt("matches")
+		players, _ := cmd.Flags().GetInt("players")
+		doublesShare, _ := cmd.Flags().GetFloat64("doubles")
+		runs, _ := cmd.Flags().GetInt("runs")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		out, _ := cmd.Flags().GetString("out")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		if n < 1 || runs < 1 {
+			return invalidf("--matches and --runs must be at least 1")
+		}
+		if players < 2 {
+			return invalidf("--players must be at least 2")
+		}
+		if doublesShare < 0 || doublesShare > 1 {
+			return invalidf("--doubles must be between 0 and 1")
+		}
+
+		dir := out
+		if dir == "" {
+			tmp, err := os.MkdirTemp("", "tennis-bench-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(tmp)
+			dir = tmp
+		}
+		if err := writeSyntheticMatches(dir, n, players, doublesShare, seed); err != nil {
+			return fmt.Errorf("failed to generate matches: %w", err)
+		}
+
+		// Every stage reads from the synthetic league.
+		prevDir := dataDir
+		dataDir = dir
+		defer func() { dataDir = prevDir }()
+
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return err
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return err
+		}
+
+		var results []benchResult
+		stage := func(name string, fn func() error) error {
+			r, err := measure(name, runs, fn)
+			if err != nil {
+				return err
+			}
+			results = append(results, r)
+			return nil
+		}
+
+		err = stage("parse", func() error {
+			if _, err := loadSinglesMatches(ctx); err != nil {
+				return err
+			}
+			_, err := loadDoublesMatches(ctx)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		err = stage("ratings", func() error {
+			computeSinglesRatings(singles)
+			computeDoublesRatings(doubles)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		var singlesBoard, doublesBoard []LeaderboardRow
+		today := time.Now().Format("2006-01-02")
+		err = stage("leaderboards", func() error {
+			singlesBoard = singlesLeaderboard(singles, nil, today)
+			doublesBoard = doublesLeaderboard(doubles, nil, today)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = stage("json", func() error {
+			_, err := json.MarshalIndent(publishedRankings{Singles: singlesBoard, DoublesIndividual: doublesBoard}, "", "  ")
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(singles) > 1 {
+			// A snapshot covering all but the newest match, as saved by the
+			// previous "rankings compute".
+			var base replayState
+			base.advance(singles[:len(singles)-1], replaySingles, tallySingles, nil)
+			saved, err := json.Marshal(base)
+			if err != nil {
+				return err
+			}
+			err = stage("incremental", func() error {
+				var st replayState
+				if err := json.Unmarshal(saved, &st); err != nil {
+					return err
+				}
+				st.advance(singles, replaySingles, tallySingles, nil)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if asJSON {
+			data, err := json.MarshalIndent(map[string]interface{}{
+				"matches": n,
+				"singles": len(singles),
+				"doubles": len(doubles),
+				"players": players,
+				"seed":    seed,
+				"results": results,
+			}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("%d matches (%d singles, %d doubles) between %d players, %d runs per stage\n\n",
+			n, len(singles), len(doubles), players, runs)
+		fmt.Printf("  %-14s %12s %12s %12s\n", "Stage", "Time/run", "Allocs/run", "Bytes/run")
+		for _, r := range results {
+			fmt.Printf("  %-14s %12s %12d %12d\n", r.Stage, r.PerRun.Round(time.Microsecond), r.AllocsPerRun, r.BytesPerRun)
+		}
+		return nil
+	},
+}
+
+func init() {
+	benchCmd.Flags().Int("matches", 5000, "Number of synthetic matches to generate")
+	benchCmd.Flags().Int("players", 60, "Number of synthetic players")
+	benchCmd.Flags().Float64("doubles", 0.3, "Share of the matches that are doubles (0-1)")
+	benchCmd.Flags().Int("runs", 5, "Runs per stage to average over")
+	benchCmd.Flags().Int64("seed", 1, "Random seed, so runs are comparable")
+	benchCmd.Flags().String("out", "", "Keep the generated match files in this directory (default: a temporary directory)")
+	benchCmd.Flags().Bool("json", false, "Print the results as JSON")
+
+	rootCmd.AddCommand(benchCmd)
+}
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var botCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "Bots that react to activity on match issues",
+}
+
+var botApprovalsCmd = &cobra.Command{
+	Use:   "approvals [issue]",
+	Short: "Track which players have approved a match",
+	Long: `Keep a match issue's approval status up to date:
+
+  - a single status comment on the issue listing who has approved, edited
+    in place on every run
+  - the players-approved label (label_names.approved in .tennis.yml),
+    applied once the match is approved
+    and removed if an approval is withdrawn
+  - a ` + approvalsContext + ` check run on the match pull request, so
+    it can be made a required check
+
+A player approves by approving the match pull request or by commenting
+` + approveCommand + ` on the issue or pull request; the reporter counts as approved.
+
+With an issue number, that issue is updated. Without one, the GitHub
+Actions event (issue_comment, issues, pull_request or
+pull_request_review) is read from GITHUB_EVENT_PATH. With --serve, the
+bot runs as a webhook server handling the same events.
+
+Examples:
+  tennis bot approvals 42
+  tennis bot approvals
+  tennis bot approvals --serve :8080 --secret "$WEBHOOK_SECRET"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serve, _ := cmd.Flags().GetString("serve")
+		secret, _ := cmd.Flags().GetString("secret")
+		ctx := cmd.Context()
+		client := getGitHubClient()
+
+		if serve != "" {
+			if secret == "" {
+				secret = os.Getenv("TENNIS_WEBHOOK_SECRET")
+			}
+			return serveApprovals(client, serve, secret)
+		}
+
+		var number int
+		if len(args) == 1 {
+			n, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+			if err != nil {
+				return invalidf("invalid issue number '%s'", args[0])
+			}
+			number = n
+		} else {
+			eventName, _ := cmd.Flags().GetString("event-name")
+			eventPath, _ := cmd.Flags().GetString("event")
+			_, event, err := readActionEvent(eventName, eventPath)
+			if err != nil {
+				return err
+			}
+			n, ok, err := approvalIssueForEvent(ctx, client, event)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Not a match issue or pull request; nothing to do")
+				return setOutput("approved", "false")
+			}
+			number = n
+		}
+
+		a, err := updateApprovals(ctx, client, number)
+		if err != nil {
+			return err
+		}
+		if a.Complete() {
+			fmt.Printf("#%d: all players have approved\n", number)
+		} else {
+			fmt.Printf("#%d: waiting on %s\n", number, strings.Join(a.Pending(), ", "))
+		}
+		if len(args) == 0 {
+			return setOutput("approved", strconv.FormatBool(a.Complete()))
+		}
+		return nil
+	},
+}
+
+// approvalIssueForEvent returns the match issue an event concerns, if any.
+func approvalIssueForEvent(ctx context.Context, client *github.Client, event interface{}) (int, bool, error) {
+	switch e := event.(type) {
+	case *github.IssuesEvent:
+		return matchIssueNumber(e.GetIssue())
+	case *github.IssueCommentEvent:
+		if !e.GetIssue().IsPullRequest() {
+			return matchIssueNumber(e.GetIssue())
+		}
+		pr, _, err := client.PullRequests.Get(ctx, owner, repo, e.GetIssue().GetNumber())
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to fetch PR #%d: %v", e.GetIssue().GetNumber(), err)
+		}
+		n, ok := matchIssueForBranch(pr.GetHead().GetRef())
+		return n, ok, nil
+	case *github.PullRequestEvent:
+		n, ok := matchIssueForBranch(e.GetPullRequest().GetHead().GetRef())
+		return n, ok, nil
+	case *github.PullRequestReviewEvent:
+		n, ok := matchIssueForBranch(e.GetPullRequest().GetHead().GetRef())
+		return n, ok, nil
+	}
+	return 0, false, nil
+}
+
+func matchIssueNumber(issue *github.Issue) (int, bool, error) {
+	kind, _, ok := matchIssueKind(issue)
+	if !ok || kind == "conflict" {
+		return 0, false, nil
+	}
+	return issue.GetNumber(), true, nil
+}
+
+// serveApprovals runs the approvals bot as a webhook receiver. Deliveries
+// are checked against secret when one is set.
+func serveApprovals(client *github.Client, addr, secret string) error {
+	if secret == "" {
+		log.Printf("warning: no webhook secret set; deliveries are not verified")
+	}
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		payload, err := github.ValidatePayload(r, []byte(secret))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		event, err := github.ParseWebHook(github.WebHookType(r), payload)
+		if err != nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		ctx := r.Context()
+		number, ok, err := approvalIssueForEvent(ctx, client, event)
+		if err == nil && ok {
+			_, err = updateApprovals(ctx, client, number)
+		}
+		if err != nil {
+			log.Printf("%s delivery %s: %v", github.WebHookType(r), github.DeliveryID(r), err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		log.Printf("updated approvals for #%d", number)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Printf("approvals bot listening on %s", addr)
+	return http.ListenAndServe(addr, nil)
+}
+
+var botExpireCmd = &cobra.Command{
+	Use:   "expire",
+	Short: "Close match issues left unapproved for too long",
+	Long: `Close open match issues that still lack the approvals the league's rules
+require --days days after they were reported, unless they have been
+escalated to the admins ("bot escalate"). Each one is labelled expired
+(label_names.expired in .tennis.yml) and closed as not planned. A comment
+mentions the players, and the pull request is closed unmerged, so rankings
+never wait on a match stuck in limbo. If the result stands, the players
+can report it again.
+
+Run it on a schedule: the Expire Matches workflow runs it daily. It asks
+for confirmation unless --yes is given, and takes the same failure policy
+flags as "admin relabel".
+
+Examples:
+  tennis bot expire --dry-run
+  tennis bot expire --days 21 --yes --continue-on-error`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		if days < 1 {
+			return invalidf("--days must be at least 1")
+		}
+		b, err := newBatch(cmd)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		all, err := staleMatchIssues(ctx, client, days)
+		if err != nil {
+			return err
+		}
+		var stale []*github.Issue
+		for _, issue := range all {
+			if b.Wants(fmt.Sprintf("#%d", issue.GetNumber())) {
+				stale = append(stale, issue)
+			}
+		}
+		if len(stale) == 0 {
+			fmt.Printf("✅ No match issues open for more than %d days\n", days)
+			return nil
+		}
+
+		if err := confirm("Check %d match issues open for more than %d days and expire the unapproved ones?", len(stale), days); err != nil {
+			return err
+		}
+		if !dryRun {
+			if err := ensureLabel(ctx, client, labelNames.Expired, labelNames.NeedsCorrection); err != nil {
+				return err
+			}
+		}
+
+		items := make([]string, len(stale))
+		for i, issue := range stale {
+			items[i] = fmt.Sprintf("#%d", issue.GetNumber())
+		}
+		b.Run(items, func(i int) error {
+			return expireMatch(ctx, client, stale[i], days)
+		})
+		if err := b.Finish(); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %d matches expired, %d approved, recorded or escalated\n", b.Succeeded, b.Skipped)
+		return nil
+	},
+}
+
+// staleMatchIssues returns the open match issues reported more than days
+// ago, by issue number.
+func staleMatchIssues(ctx context.Context, client *github.Client, days int) ([]*github.Issue, error) {
+	cutoff := time.Now().AddDate(0, 0, -days)
+	var stale []*github.Issue
+	for _, label := range []string{labelNames.Singles, labelNames.Doubles} {
+		issues, err := listLabelledIssues(ctx, client, label, issueFilter{State: "open", Until: cutoff})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() {
+				stale = append(stale, issue)
+			}
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].GetNumber() < stale[j].GetNumber() })
+	return stale, nil
+}
+
+// waitingDays returns how many days a match issue has waited on its pending
+// players, leaving out the days one of them was away: their timer pauses.
+func waitingDays(issue *github.Issue, pending []string) int {
+	reported, now := issue.GetCreatedAt().Time, time.Now()
+	away := 0
+	for _, p := range pending {
+		if d := awayPeriods[strings.TrimPrefix(p, "@")].Days(reported, now); d > away {
+			away = d
+		}
+	}
+	return int(now.Sub(reported).Hours()/24) - away
+}
+
+var botNudgeCmd = &cobra.Command{
+	Use:   "nudge [@player]",
+	Short: "Remind players of matches waiting on their approval",
+	Long: `Post a reminder on every open match issue that has waited more than --days
+days for a player's approval. The comment mentions the player, so GitHub
+notifies them. Each player is reminded once per match, so the bot can run on
+a schedule without repeating itself. Players away ("tennis availability
+away") aren't reminded, and their days away don't count. With a player, only their pending
+approvals are nudged.
+
+The Expire Matches workflow runs it daily, before "bot expire".
+
+Examples:
+  tennis bot nudge --dry-run
+  tennis bot nudge @player_two --days 2`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		if days < 1 {
+			return invalidf("--days must be at least 1")
+		}
+		only := ""
+		if len(args) == 1 {
+			if only = normalizePlayer(args[0]); only == "" {
+				return invalidf("empty player handle")
+			}
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		stale, err := staleMatchIssues(ctx, client, days)
+		if err != nil {
+			return err
+		}
+		nudged := 0
+		for _, issue := range stale {
+			n, err := nudgeMatch(ctx, client, issue, only, days)
+			if err != nil {
+				return err
+			}
+			nudged += n
+		}
+		fmt.Printf("✅ %d reminders posted\n", nudged)
+		return nil
+	},
+}
+
+// nudgeMatch reminds each player an issue has waited days on (or only that
+// player, if set) who isn't away and hasn't been reminded yet, and returns
+// how many reminders it posted.
+func nudgeMatch(ctx context.Context, client *github.Client, issue *github.Issue, only string, days int) (int, error) {
+	number := issue.GetNumber()
+	a, err := loadMatchApprovals(ctx, client, number)
+	if err != nil {
+		return 0, err
+	}
+	if a.PullRequest != nil && a.PullRequest.MergedAt != nil {
+		return 0, nil
+	}
+	how := fmt.Sprintf("comment `%s` here", approveCommand)
+	if a.PullRequest != nil {
+		how = fmt.Sprintf("approve pull request #%d or comment `%s` here", a.PullRequest.GetNumber(), approveCommand)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	nudged := 0
+	for _, p := range a.Pending() {
+		if only != "" && p != "@"+only {
+			continue
+		}
+		if waitingDays(issue, []string{p}) < days || awayPeriods[strings.TrimPrefix(p, "@")].On(today) {
+			continue
+		}
+		comment := fmt.Sprintf("👋 Hi %s, this match was reported on %s and is still waiting for your approval. If the result is right, please %s. If something's wrong, let @%s know in a comment so it can be corrected.", p, issue.GetCreatedAt().Format("January 2"), how, a.Reporter)
+		posted, err := commentOnce(ctx, client, number, comment, "nudge:"+strings.TrimPrefix(p, "@"))
+		if err != nil {
+			return nudged, err
+		}
+		if posted {
+			fmt.Printf("👋 #%d: reminded %s\n", number, p)
+			nudged++
+		}
+	}
+	return nudged, nil
+}
+
+var botEscalateCmd = &cobra.Command{
+	Use:   "escalate",
+	Short: "Hand disputed or stalled matches to the league admins",
+	Long: `Escalate open match issues that are still disputed (a player requested
+changes on the pull request) or short of approvals --days days after they
+were reported. Each one is labelled needs-admin (label_names.needs_admin in
+.tennis.yml) and assigned to the league admins (admins in .tennis.yml), and
+a comment mentions them. Admins see escalated matches in
+"tennis inbox --admin". "bot expire" leaves escalated matches open.
+
+Issues already escalated are skipped, so the bot can run on a schedule. The
+Expire Matches workflow runs it daily.
+
+Examples:
+  tennis bot escalate --dry-run
+  tennis bot escalate --days 5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		if days < 1 {
+			return invalidf("--days must be at least 1")
+		}
+		if len(leagueAdmins) == 0 {
+			fmt.Fprintln(os.Stderr, "warning: no admins in .tennis.yml; escalated matches will be labelled but not assigned")
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		stale, err := staleMatchIssues(ctx, client, days)
+		if err != nil {
+			return err
+		}
+		escalated := 0
+		for _, issue := range stale {
+			ok, err := escalateMatch(ctx, client, issue, days)
+			if err != nil {
+				return err
+			}
+			if ok {
+				escalated++
+			}
+		}
+		fmt.Printf("✅ %d matches escalated\n", escalated)
+		return nil
+	},
+}
+
+// expireMatch closes one match issue that has waited days for approval,
+// unless it has since been approved or recorded, or escalated to the
+// admins.
+func expireMatch(ctx context.Context, client *github.Client, issue *github.Issue, days int) error {
+	number := issue.GetNumber()
+	if hasLabel(issue, labelNames.NeedsAdmin) {
+		return skipItem("escalated to the admins")
+	}
+	a, err := loadMatchApprovals(ctx, client, number)
+	if err != nil {
+		return err
+	}
+	if a.Complete() {
+		return skipItem("approved")
+	}
+	if waitingDays(issue, a.Pending()) < days {
+		return skipItem("timer paused while a player was away")
+	}
+	pr := 0
+	if a.PullRequest != nil {
+		if a.PullRequest.MergedAt != nil {
+			return skipItem("already recorded")
+		}
+		if a.PullRequest.GetState() == "open" {
+			pr = a.PullRequest.GetNumber()
+		}
+	}
+
+	var mentions []string
+	for _, p := range a.Players {
+		if p != "" {
+			mentions = append(mentions, "@"+p)
+		}
+	}
+	pending := strings.Join(a.Pending(), ", ")
+	fmt.Printf("⌛ #%d has waited more than %d days on %s\n", number, days, pending)
+	if !dryRun {
+		if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, number, []string{labelNames.Expired}); err != nil {
+			return fmt.Errorf("failed to label #%d: %w", number, err)
+		}
+	}
+	comment := fmt.Sprintf("⌛ This match waited more than %d days for approval from %s, so it has been closed without being recorded.\n\n%s: if the result stands, please report it again and approve it promptly.", days, pending, strings.Join(mentions, " "))
+	return closeUnrecordedMatch(ctx, client, number, pr, "expired", comment)
+}
+
+func init() {
+	botExpireCmd.Flags().Int("days", 14, "Expire match issues still unapproved this many days after they were reported")
+	botExpireCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the matches that would expire without closing them")
+	addBatchFlags(botExpireCmd)
+	botCmd.AddCommand(botExpireCmd)
+
+	botNudgeCmd.Flags().Int("days", 3, "Remind players of matches waiting on them for more than this many days")
+	botNudgeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the reminders without posting them")
+	botCmd.AddCommand(botNudgeCmd)
+
+	botEscalateCmd.Flags().Int("days", 7, "Escalate matches still disputed or unapproved this many days after they were reported")
+	botEscalateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the matches that would be escalated without changing them")
+	botCmd.AddCommand(botEscalateCmd)
+
+	botApprovalsCmd.Flags().String("serve", "", "Run as a webhook server on this address (e.g. :8080)")
+	botApprovalsCmd.Flags().String("secret", "", "Webhook secret for --serve (defaults to $TENNIS_WEBHOOK_SECRET)")
+	botApprovalsCmd.Flags().String("event", "", "Event payload file (defaults to $GITHUB_EVENT_PATH)")
+	botApprovalsCmd.Flags().String("event-name", "", "Event name (defaults to $GITHUB_EVENT_NAME)")
+	botApprovalsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the updates without making them")
+
+	botCmd.AddCommand(botApprovalsCmd)
+	rootCmd.AddCommand(botCmd)
+}
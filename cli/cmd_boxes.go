@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var boxesCmd = &cobra.Command{
+	Use:   "boxes",
+	Short: "Run a monthly box league",
+	Long: `Run a monthly box league: players are grouped into small boxes by rating,
+play everyone in their box during the month, and the top and bottom finishers
+move up and down a box for the next month.
+
+Each period is stored in boxes/<YYYY-MM>.yml in the league checkout.`,
+}
+
+var boxesCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Group players into boxes and create the month's fixtures",
+	Long: `Group active players into boxes and open a fixture issue for every
+intra-box pairing.
+
+If the previous month's box league has been closed, its promotion and
+relegation order is used; newcomers are slotted in by rating. Otherwise
+players are grouped by current singles rating.
+
+The boxes are saved after each fixture issue is opened. If opening one
+fails, running the command again for the same month opens only the
+fixtures still missing.
+
+Examples:
+  tennis boxes create --size 4
+  tennis boxes create --size 5 --month 2025-03 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		size, _ := cmd.Flags().GetInt("size")
+		month, _ := cmd.Flags().GetString("month")
+
+		start, err := parseMonth(month)
+		if err != nil {
+			return err
+		}
+		period := start.Format("2006-01")
+
+		if size < 2 {
+			return fmt.Errorf("box size must be at least 2")
+		}
+		ctx := cmd.Context()
+		if _, err := os.Stat(boxesPath(period)); err == nil {
+			// A run that failed part way saved the boxes; open the fixtures
+			// it didn't get to.
+			b, err := loadBoxLeague(period)
+			if err != nil {
+				return err
+			}
+			if !b.missingFixtures() {
+				return fmt.Errorf("box league for %s already exists", period)
+			}
+			fmt.Printf("Opening the remaining fixtures for %s\n", period)
+			return openBoxFixtures(ctx, b, start)
+		}
+
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		players, err := activePlayers(matches, start)
+		if err != nil {
+			return err
+		}
+		if len(players) < 2 {
+			return fmt.Errorf("need at least 2 active players for a box league, found %d", len(players))
+		}
+
+		order := boxOrder(players, computeSinglesRatings(matches), start)
+		b := &BoxLeague{
+			Period: period,
+			Status: boxesOpen,
+			Size:   size,
+			Boxes:  groupIntoBoxes(order, size),
+		}
+
+		// The boxes are saved before any fixture is opened, and again after
+		// each one, so a failure part way through leaves no issue unrecorded.
+		if !dryRun {
+			if err := saveBoxLeague(b); err != nil {
+				return fmt.Errorf("failed to save box league: %w", err)
+			}
+		}
+		if err := openBoxFixtures(ctx, b, start); err != nil {
+			return fmt.Errorf("%w (the boxes are saved; run `tennis boxes create --month %s` again to open the remaining fixtures)", err, period)
+		}
+		if dryRun {
+			return nil
+		}
+
+		fmt.Printf("✅ %d boxes created for %s\n", len(b.Boxes), period)
+		fmt.Printf("Saved to %s — commit it to share the boxes\n", boxesPath(period))
+		return nil
+	},
+}
+
+// openBoxFixtures opens a fixture issue for every pairing in the boxes that
+// doesn't have one yet, saving the box league after each so no issue is
+// ever opened twice.
+func openBoxFixtures(ctx context.Context, b *BoxLeague, start time.Time) error {
+	for i := range b.Boxes {
+		for j, f := range b.Boxes[i].Fixtures {
+			if f.Issue != 0 {
+				continue
+			}
+			p1, p2 := "@"+f.Players[0], "@"+f.Players[1]
+			title := fmt.Sprintf("Box %d (%s): %s vs %s", i+1, b.Period, p1, p2)
+			body := fmt.Sprintf(`### Box league
+Box %d, %s
+
+### Players
+%s, %s
+
+Play this match during %s, then record the result with `+"`tennis match singles`"+` or the singles match issue form.`,
+				i+1, b.Period, p1, p2, start.Format("January 2006"))
+
+			number, err := openIssue(ctx, title, body, []string{labelNames.BoxLeague, b.Label()})
+			if err != nil {
+				return err
+			}
+			b.Boxes[i].Fixtures[j].Issue = number
+			if dryRun {
+				continue
+			}
+			if err := saveBoxLeague(b); err != nil {
+				return fmt.Errorf("failed to save box league after opening #%d: %w", number, err)
+			}
+		}
+	}
+	return nil
+}
+
+var boxesStandingsCmd = &cobra.Command{
+	Use:   "standings",
+	Short: "Show each box's table for the month",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		month, _ := cmd.Flags().GetString("month")
+
+		start, err := parseMonth(month)
+		if err != nil {
+			return err
+		}
+		b, err := loadBoxLeague(start.Format("2006-01"))
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		for i, rows := range boxStandings(b, matches) {
+			fmt.Printf("Box %d\n", i+1)
+			fmt.Printf("  %-20s %3s %3s %3s %7s %7s\n", "Player", "P", "W", "L", "Sets", "Games")
+			for _, r := range rows {
+				fmt.Printf("  %-20s %3d %3d %3d %7s %7s\n", "@"+r.Player, r.Played, r.Won, r.Lost,
+					fmt.Sprintf("%d-%d", r.SetsWon, r.SetsLost), fmt.Sprintf("%d-%d", r.GamesWon, r.GamesLost))
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var boxesCloseCmd = &cobra.Command{
+	Use:   "close",
+	Short: "End the month and compute promotion and relegation",
+	Long: `Close a box league period: the top --moves players of each box are
+promoted and the bottom --moves relegated. The resulting order is saved and
+used by the next "boxes create".`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		month, _ := cmd.Flags().GetString("month")
+		moves, _ := cmd.Flags().GetInt("moves")
+
+		start, err := parseMonth(month)
+		if err != nil {
+			return err
+		}
+		b, err := loadBoxLeague(start.Format("2006-01"))
+		if err != nil {
+			return err
+		}
+		if b.Status == boxesClosed {
+			return fmt.Errorf("box league for %s is already closed", b.Period)
+		}
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		tables := boxStandings(b, matches)
+		b.Next = promoteAndRelegate(tables, moves)
+		b.Status = boxesClosed
+
+		boxOf := make(map[string]int)
+		for i, box := range b.Boxes {
+			for _, p := range box.Players {
+				boxOf[p] = i
+			}
+		}
+		for i, next := range groupIntoBoxes(b.Next, b.Size) {
+			for _, p := range next.Players {
+				switch {
+				case boxOf[p] > i:
+					fmt.Printf("⬆️  @%s promoted to box %d\n", p, i+1)
+				case boxOf[p] < i:
+					fmt.Printf("⬇️  @%s relegated to box %d\n", p, i+1)
+				}
+			}
+		}
+
+		if dryRun {
+			return nil
+		}
+		if err := confirm("Close the box league for %s?", b.Period); err != nil {
+			return err
+		}
+		if err := saveBoxLeague(b); err != nil {
+			return fmt.Errorf("failed to save box league: %w", err)
+		}
+		fmt.Printf("✅ Box league for %s closed\n", b.Period)
+		return nil
+	},
+}
+
+var monthRegex = regexp.MustCompile(`^\d{4}-\d{2}$`)
+
+// parseMonth parses YYYY-MM, defaulting to the current month.
+func parseMonth(month string) (time.Time, error) {
+	if month == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	if !monthRegex.MatchString(month) {
+		return time.Time{}, fmt.Errorf("invalid month '%s'. Use YYYY-MM", month)
+	}
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month '%s'. Use YYYY-MM", month)
+	}
+	return t, nil
+}
+
+// boxOrder orders players for grouping: the previous period's closing order
+// where available, then everyone else by rating.
+func boxOrder(players []string, ratings map[string]float64, start time.Time) []string {
+	active := make(map[string]bool)
+	for _, p := range players {
+		active[p] = true
+	}
+
+	var order []string
+	placed := make(map[string]bool)
+	if prev, err := loadBoxLeague(start.AddDate(0, -1, 0).Format("2006-01")); err == nil && prev.Status == boxesClosed {
+		for _, p := range prev.Next {
+			if active[p] && !placed[p] {
+				order = append(order, p)
+				placed[p] = true
+			}
+		}
+	}
+
+	var rest []string
+	for _, p := range players {
+		if !placed[p] {
+			rest = append(rest, p)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		return rating(ratings, rest[i]) > rating(ratings, rest[j])
+	})
+	return append(order, rest...)
+}
+
+func init() {
+	boxesCreateCmd.Flags().Int("size", 4, "Players per box")
+	boxesCreateCmd.Flags().String("month", "", "Month to schedule (YYYY-MM), defaults to the current month")
+	boxesStandingsCmd.Flags().String("month", "", "Month to show (YYYY-MM), defaults to the current month")
+	boxesCloseCmd.Flags().String("month", "", "Month to close (YYYY-MM), defaults to the current month")
+	boxesCloseCmd.Flags().Int("moves", 1, "Players promoted and relegated between adjacent boxes")
+
+	boxesCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print what would happen without creating issues or saving files")
+
+	boxesCmd.AddCommand(boxesCreateCmd)
+	boxesCmd.AddCommand(boxesStandingsCmd)
+	boxesCmd.AddCommand(boxesCloseCmd)
+	rootCmd.AddCommand(boxesCmd)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var checksCmd = &cobra.Command{
+	Use:   "checks",
+	Short: "Publish check results on commits",
+}
+
+var checksSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a check run on a commit",
+	Long: `Publish a check run on a commit, e.g. the approval gate on a match pull
+request, so branch protection can require it.
+
+States: ` + checkStateNames() + `
+
+Check runs need a GitHub App token, such as the GITHUB_TOKEN of a workflow
+run. With a personal token a commit status with the same name is set
+instead.
+
+Examples:
+  tennis checks set --sha 1a2b3c4 --name match-approval --state success
+  tennis checks set --name match-approval --state in_progress --title "Waiting on @player_two"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := checkResult{}
+		c.SHA, _ = cmd.Flags().GetString("sha")
+		c.Name, _ = cmd.Flags().GetString("name")
+		c.State, _ = cmd.Flags().GetString("state")
+		c.Title, _ = cmd.Flags().GetString("title")
+		c.Summary, _ = cmd.Flags().GetString("summary")
+		c.DetailsURL, _ = cmd.Flags().GetString("details-url")
+
+		if c.SHA == "" {
+			c.SHA = os.Getenv("GITHUB_SHA")
+		}
+		if c.SHA == "" {
+			return invalidf("--sha is required outside GitHub Actions")
+		}
+		if c.Name == "" {
+			return invalidf("--name is required")
+		}
+
+		if err := publishCheck(cmd.Context(), getGitHubClient(), c); err != nil {
+			return err
+		}
+		if !dryRun {
+			fmt.Printf("✅ %s on %.7s: %s\n", c.Name, c.SHA, c.State)
+		}
+		return nil
+	},
+}
+
+func init() {
+	checksSetCmd.Flags().String("sha", "", "Commit to set the check on (defaults to $GITHUB_SHA)")
+	checksSetCmd.Flags().String("name", "", "Check name, e.g. match-approval")
+	checksSetCmd.Flags().String("state", "success", "Check state")
+	checksSetCmd.Flags().String("title", "", "One-line result shown next to the check")
+	checksSetCmd.Flags().String("summary", "", "Markdown summary shown on the check's page")
+	checksSetCmd.Flags().String("details-url", "", "Link for the check's Details button")
+	checksSetCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the check without publishing it")
+
+	checksCmd.AddCommand(checksSetCmd)
+	rootCmd.AddCommand(checksCmd)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var divisionsCmd = &cobra.Command{
+	Use:   "divisions",
+	Short: "Run divisions with promotion and relegation",
+	Long: `Run the league in divisions. Each player's division is kept in the
+roster (players.yml), 1 being the top. Players are ranked within their
+division on the singles matches they play against each other, and at the
+end of each period "divisions rotate" promotes and relegates them.`,
+}
+
+var divisionsAssignCmd = &cobra.Command{
+	Use:   "assign <@handle> <division>",
+	Short: "Put a player in a division (0 removes them)",
+	Long: `Put a player in a division, 1 being the top. Division 0 takes them out
+of the divisions.
+
+Examples:
+  tennis divisions assign @player_one 1
+  tennis divisions assign @player_two 2`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		division, err := strconv.Atoi(args[1])
+		if err != nil || division < 0 {
+			return invalidf("invalid division '%s'. Use 1 for the top division, or 0 for none", args[1])
+		}
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		p := roster.Find(args[0])
+		if p == nil {
+			return fmt.Errorf("@%s is not on the roster (add them with `tennis player add`)", normalizePlayer(args[0]))
+		}
+		p.Division = division
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+		if division == 0 {
+			fmt.Printf("✅ @%s is no longer in a division\n", p.Handle)
+		} else {
+			fmt.Printf("✅ @%s is in division %d\n", p.Handle, division)
+		}
+		return nil
+	},
+}
+
+var divisionsStandingsCmd = &cobra.Command{
+	Use:   "standings",
+	Short: "Show each division's table for the period",
+	Long: `Show each division's table. The period runs from the day after the last
+rotation (or the first match) to today, unless --from and --to are given.
+
+Examples:
+  tennis divisions standings
+  tennis divisions standings --from 2025-04-01 --to 2025-06-30`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to, err := divisionPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		divisions, members := divisionMembers(roster)
+		if len(divisions) == 0 {
+			return fmt.Errorf("no players are in a division (use `tennis divisions assign`)")
+		}
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		fmt.Printf("Division standings, %s\n\n", periodLabel(from, to))
+		for i, rows := range divisionStandings(divisions, members, matches, from, to) {
+			fmt.Printf("Division %d\n", divisions[i])
+			fmt.Printf("  %-20s %3s %3s %3s %7s %7s\n", "Player", "P", "W", "L", "Sets", "Games")
+			for _, r := range rows {
+				fmt.Printf("  %-20s %3d %3d %3d %7s %7s\n", "@"+r.Player, r.Played, r.Won, r.Lost,
+					fmt.Sprintf("%d-%d", r.SetsWon, r.SetsLost), fmt.Sprintf("%d-%d", r.GamesWon, r.GamesLost))
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var divisionsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "End the period and promote and relegate players",
+	Long: `End a division period: the top --moves players of each division are
+promoted and the bottom --moves relegated to the division below, on the
+period's standings. The new divisions are saved in players.yml and the
+rotation is recorded in divisions.yml, where the next period starts.
+
+Examples:
+  tennis divisions rotate --dry-run
+  tennis divisions rotate --moves 2 --to 2025-06-30`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		moves, _ := cmd.Flags().GetInt("moves")
+		if moves < 1 {
+			return invalidf("--moves must be at least 1")
+		}
+		from, to, err := divisionPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		divisions, members := divisionMembers(roster)
+		if len(divisions) < 2 {
+			return fmt.Errorf("need at least 2 divisions to promote and relegate, found %d", len(divisions))
+		}
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		tables := divisionStandings(divisions, members, matches, from, to)
+		made := rotateDivisions(roster, divisions, tables, moves)
+		fmt.Printf("Promotion and relegation on %s\n", periodLabel(from, to))
+		for _, m := range made {
+			if m.To < m.From {
+				fmt.Printf("⬆️  @%s promoted to division %d\n", m.Player, m.To)
+			} else {
+				fmt.Printf("⬇️  @%s relegated to division %d\n", m.Player, m.To)
+			}
+		}
+
+		if dryRun {
+			return nil
+		}
+		if err := confirm("Move these %d players?", len(made)); err != nil {
+			return err
+		}
+		rotations, err := loadDivisionRotations()
+		if err != nil {
+			return err
+		}
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+		rotations = append(rotations, DivisionRotation{Date: time.Now().Format("2006-01-02"), From: from, To: to, Moves: made})
+		if err := writeYAMLFile(divisionsPath(), rotations); err != nil {
+			return fmt.Errorf("failed to save divisions.yml: %w", err)
+		}
+		fmt.Printf("✅ %d players moved. Commit %s and %s\n", len(made), rosterPath(), divisionsPath())
+		return nil
+	},
+}
+
+// divisionPeriod returns the period set by --from and --to. --from
+// defaults to the day after the last rotation's period (or the start of
+// the match data), --to to today.
+func divisionPeriod(cmd *cobra.Command) (string, string, error) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	if to == "" {
+		to = time.Now().Format("2006-01-02")
+	}
+	explicit := from != ""
+	if !explicit {
+		rotations, err := loadDivisionRotations()
+		if err != nil {
+			return "", "", err
+		}
+		if n := len(rotations); n > 0 {
+			last, err := time.Parse("2006-01-02", rotations[n-1].To)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid divisions.yml: bad date %q", rotations[n-1].To)
+			}
+			from = last.AddDate(0, 0, 1).Format("2006-01-02")
+		}
+	}
+	for _, d := range []string{from, to} {
+		if d != "" && !isValidDate(d) {
+			return "", "", fmt.Errorf("invalid date '%s'. Use YYYY-MM-DD format", d)
+		}
+	}
+	if from > to {
+		if !explicit {
+			return "", "", fmt.Errorf("the next period starts on %s, the day after the last rotation's; use --from to choose another", from)
+		}
+		return "", "", fmt.Errorf("the period starts (%s) after it ends (%s)", from, to)
+	}
+	return from, to, nil
+}
+
+func periodLabel(from, to string) string {
+	if from == "" {
+		return "matches up to " + to
+	}
+	return from + " to " + to
+}
+
+func init() {
+	for _, c := range []*cobra.Command{divisionsStandingsCmd, divisionsRotateCmd} {
+		c.Flags().String("from", "", "First day of the period (YYYY-MM-DD), defaults to the day after the last rotation")
+		c.Flags().String("to", "", "Last day of the period (YYYY-MM-DD), defaults to today")
+	}
+	divisionsRotateCmd.Flags().Int("moves", 1, "Players promoted and relegated between adjacent divisions")
+	divisionsRotateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the moves without saving players.yml or divisions.yml")
+
+	divisionsCmd.AddCommand(divisionsAssignCmd, divisionsStandingsCmd, divisionsRotateCmd)
+	rootCmd.AddCommand(divisionsCmd)
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// requiredWorkflows are the workflows a league can't run without: turning
+// match issues into pull requests, and rebuilding the site.
+var requiredWorkflows = []string{"issue-to-pr.yml", "rebuild-rankings.yml"}
+
+// maxClockSkew is how far the local clock may drift from GitHub's before
+// doctor reports it; match dates default to the local day.
+const maxClockSkew = time.Minute
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose CLI setup problems",
+	Long: `Check everything the CLI and the league's workflows rely on, and print a
+fix for each problem found:
+
+  - the token is valid, and what it can do (see "tennis doctor permissions")
+  - the repository is reachable
+  - the labels in .tennis.yml exist in the repository
+  - the issue-to-PR and rebuild-rankings workflows exist and are enabled
+  - GitHub Pages is enabled and deployed by GitHub Actions
+  - the local clock agrees with GitHub's
+
+Examples:
+  tennis doctor`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		client := getGitHubClient()
+
+		checks := doctorChecks(ctx, client)
+		failed := 0
+		for _, c := range checks {
+			if c.OK {
+				fmt.Printf("✅ %s: %s\n", c.Name, c.Detail)
+				continue
+			}
+			failed++
+			fmt.Printf("❌ %s: %s\n   → %s\n", c.Name, c.Detail, c.Fix)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+		}
+		fmt.Println("\nEverything looks good")
+		return nil
+	},
+}
+
+// doctorCheck is the outcome of one doctor check, with the fix to apply
+// when it failed.
+type doctorCheck struct {
+	Name   string
+	OK     bool
+	Detail string
+	Fix    string
+}
+
+// doctorChecks runs the checks in order. The token and repository are
+// checked first; the rest are skipped when either fails, since they'd only
+// fail the same way.
+func doctorChecks(ctx context.Context, client *github.Client) []doctorCheck {
+	var checks []doctorCheck
+	if token == "" {
+		return append(checks, doctorCheck{Name: "Token", Detail: "no GitHub token found",
+			Fix: "run `tennis auth login`, set GITHUB_TOKEN, or run `gh auth login`"})
+	}
+	user, resp, err := client.Users.Get(ctx, "")
+	if err != nil {
+		c := doctorCheck{Name: "Token", Detail: fmt.Sprintf("failed to authenticate: %v", err),
+			Fix: "check your network connection and that api.github.com is reachable"}
+		if githubapi.IsUnauthorized(err) {
+			c.Detail = fmt.Sprintf("the token from %s is invalid or expired", tokenSource)
+			c.Fix = "create a new token at https://github.com/settings/tokens and run `tennis auth login`"
+		}
+		return append(checks, c)
+	}
+	scopes, classic := tokenScopes(resp)
+	detail := fmt.Sprintf("@%s, from %s", user.GetLogin(), tokenSource)
+	if classic {
+		detail += fmt.Sprintf(", scopes %s", strings.Join(scopes, ", "))
+	}
+	checks = append(checks, doctorCheck{Name: "Token", OK: true, Detail: detail})
+
+	checks = append(checks, clockCheck(resp))
+
+	r, _, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return append(checks, doctorCheck{Name: "Repository", Detail: fmt.Sprintf("cannot access %s/%s: %v", owner, repo, err),
+			Fix: "check --owner/--repo (or the origin remote), and that the token has access to the repository"})
+	}
+	checks = append(checks, doctorCheck{Name: "Repository", OK: true, Detail: r.GetFullName()})
+
+	for _, p := range permissionChecks(ctx, client, r, scopes, classic, r.GetHasPages()) {
+		c := doctorCheck{Name: p.Name, OK: p.OK, Detail: "allowed"}
+		if !p.OK {
+			c.Detail, c.Fix = "not allowed", "needs "+p.Missing
+		}
+		checks = append(checks, c)
+	}
+
+	checks = append(checks, labelsCheck(ctx, client), workflowsCheck(ctx, client), pagesCheck(ctx, client))
+	return checks
+}
+
+// clockCheck compares the local clock with the Date of a GitHub response.
+func clockCheck(resp *github.Response) doctorCheck {
+	c := doctorCheck{Name: "Clock", OK: true}
+	server, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		c.Detail = "GitHub sent no Date header to compare with"
+		return c
+	}
+	skew := time.Since(server).Round(time.Second)
+	c.Detail = fmt.Sprintf("%s from GitHub's", skew)
+	if skew.Abs() > maxClockSkew {
+		c.OK = false
+		c.Detail = fmt.Sprintf("the local clock is %s off GitHub's, so match dates may default to the wrong day", skew)
+		c.Fix = "sync the system clock with a time server (e.g. `timedatectl set-ntp true`, or turn on automatic date and time)"
+	}
+	return c
+}
+
+// labelsCheck reports labels declared in .tennis.yml that are missing or
+// differ in the repository.
+func labelsCheck(ctx context.Context, client *github.Client) doctorCheck {
+	c := doctorCheck{Name: "Labels", Fix: "run `tennis admin labels sync`"}
+	cfg, err := loadConfig()
+	if err != nil {
+		c.Detail, c.Fix = err.Error(), "fix "+configPath()
+		return c
+	}
+	current, err := listLabels(ctx, client)
+	if err != nil {
+		c.Detail = err.Error()
+		return c
+	}
+	var missing, differ []string
+	for _, ch := range diffLabels(cfg.LabelSet(), current, false) {
+		switch ch.Op {
+		case "create":
+			missing = append(missing, ch.Want.Name)
+		case "update":
+			differ = append(differ, ch.Want.Name)
+		}
+	}
+	switch {
+	case len(missing) > 0:
+		c.Detail = "missing " + strings.Join(missing, ", ")
+	case len(differ) > 0:
+		c.Detail = "colour or description differs for " + strings.Join(differ, ", ")
+	default:
+		c.OK, c.Detail, c.Fix = true, fmt.Sprintf("all %d present", len(cfg.LabelSet())), ""
+	}
+	return c
+}
+
+// workflowsCheck reports required workflows that are missing or disabled.
+func workflowsCheck(ctx context.Context, client *github.Client) doctorCheck {
+	c := doctorCheck{Name: "Workflows"}
+	workflows, _, err := client.Actions.ListWorkflows(ctx, owner, repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		c.Detail, c.Fix = fmt.Sprintf("failed to list workflows: %v", err), "enable GitHub Actions in the repository settings"
+		return c
+	}
+	var missing, disabled []string
+	for _, want := range requiredWorkflows {
+		found := false
+		for _, w := range workflows.Workflows {
+			if path.Base(w.GetPath()) != want {
+				continue
+			}
+			found = true
+			if w.GetState() != "active" {
+				disabled = append(disabled, want)
+			}
+		}
+		if !found {
+			missing = append(missing, want)
+		}
+	}
+	switch {
+	case len(missing) > 0:
+		c.Detail = "missing " + strings.Join(missing, ", ")
+		c.Fix = "copy them into .github/workflows from the tennis template repository (`tennis init` installs rebuild-rankings.yml)"
+	case len(disabled) > 0:
+		c.Detail = "disabled " + strings.Join(disabled, ", ")
+		c.Fix = "enable them in the repository's Actions tab"
+	default:
+		c.OK, c.Detail = true, strings.Join(requiredWorkflows, ", ")+" active"
+	}
+	return c
+}
+
+// pagesCheck reports whether GitHub Pages is enabled and built by GitHub
+// Actions, as the rebuild-rankings workflow deploys it.
+func pagesCheck(ctx context.Context, client *github.Client) doctorCheck {
+	c := doctorCheck{Name: "GitHub Pages"}
+	pages, _, err := client.Repositories.GetPagesInfo(ctx, owner, repo)
+	switch {
+	case githubapi.IsNotFound(err):
+		c.Detail = "not enabled"
+		c.Fix = "run `tennis init`, or set Settings → Pages → Source to GitHub Actions"
+	case err != nil:
+		c.Detail = fmt.Sprintf("failed to read the Pages settings: %v", err)
+		c.Fix = "run `tennis doctor permissions --pages`"
+	case pages.GetBuildType() != "workflow":
+		c.Detail = fmt.Sprintf("built from a branch (%s), so the rankings workflow's deploys are ignored", pages.GetBuildType())
+		c.Fix = "run `tennis init`, or set Settings → Pages → Source to GitHub Actions"
+	default:
+		c.OK, c.Detail = true, pages.GetHTMLURL()
+	}
+	return c
+}
+
+// permissionCheck is one capability the CLI needs from the token.
+type permissionCheck struct {
+	Name    string
+	OK      bool
+	Missing string // what to grant when !OK
+}
+
+var doctorPermissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Check the token can do everything the CLI needs",
+	Long: `Verify the GitHub token can create issues, add labels, dispatch
+workflows and (if the repository uses it) manage GitHub Pages, and report
+exactly which scope or repository permission is missing, before a command
+fails half-way through.
+
+Classic tokens are checked against their OAuth scopes. Fine-grained tokens
+and GITHUB_TOKEN don't report scopes, so they are checked against your
+repository role and by read-only probes of the relevant APIs.
+
+Examples:
+  tennis doctor permissions
+  tennis doctor permissions --pages`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pages, _ := cmd.Flags().GetBool("pages")
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+
+		user, resp, err := client.Users.Get(ctx, "")
+		if err != nil {
+			if githubapi.IsUnauthorized(err) {
+				return fmt.Errorf("the GitHub token is invalid or expired")
+			}
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+		scopes, classic := tokenScopes(resp)
+
+		r, _, err := client.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			return fmt.Errorf("cannot access %s/%s with this token: %w", owner, repo, err)
+		}
+		if !pages {
+			pages = r.GetHasPages()
+		}
+
+		fmt.Printf("Token for @%s on %s/%s\n", user.GetLogin(), owner, repo)
+		if classic {
+			fmt.Printf("Scopes: %s\n", strings.Join(scopes, ", "))
+		} else {
+			fmt.Println("Scopes: not reported (fine-grained token or GITHUB_TOKEN)")
+		}
+		fmt.Println()
+
+		checks := permissionChecks(ctx, client, r, scopes, classic, pages)
+		failed := 0
+		for _, c := range checks {
+			if c.OK {
+				fmt.Printf("✅ %s\n", c.Name)
+				continue
+			}
+			failed++
+			fmt.Printf("❌ %s — missing %s\n", c.Name, c.Missing)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d permission checks failed", failed, len(checks))
+		}
+		fmt.Println("\nAll permissions look good")
+		return nil
+	},
+}
+
+// tokenScopes returns the scopes of a classic token from the X-OAuth-Scopes
+// header. classic is false when the header is absent.
+func tokenScopes(resp *github.Response) (scopes []string, classic bool) {
+	header, ok := resp.Header["X-Oauth-Scopes"]
+	if !ok {
+		return nil, false
+	}
+	for _, s := range strings.Split(strings.Join(header, ","), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, true
+}
+
+func hasScope(scopes []string, want ...string) bool {
+	for _, s := range scopes {
+		for _, w := range want {
+			if s == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// permissionChecks works out which capabilities the token has. Nothing is
+// created or changed: probes only read.
+func permissionChecks(ctx context.Context, client *github.Client, r *github.Repository, scopes []string, classic, pages bool) []permissionCheck {
+	perms := r.GetPermissions()
+	// Classic tokens need repo for private repositories; public_repo is enough otherwise.
+	repoScope := "public_repo"
+	if r.GetPrivate() {
+		repoScope = "repo"
+	}
+	scopeOK := func(want ...string) bool { return !classic || hasScope(scopes, want...) }
+	var checks []permissionCheck
+
+	issues := permissionCheck{Name: "Create issues", OK: true}
+	switch {
+	case !r.GetHasIssues():
+		issues = permissionCheck{Name: "Create issues", Missing: "Issues turned on in the repository settings"}
+	case !scopeOK("repo", repoScope):
+		issues = permissionCheck{Name: "Create issues", Missing: fmt.Sprintf("the %s token scope", repoScope)}
+	case r.GetPrivate() && !perms["pull"]:
+		issues = permissionCheck{Name: "Create issues", Missing: "read access to the repository"}
+	case !classic:
+		if _, _, err := client.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{ListOptions: github.ListOptions{PerPage: 1}}); err != nil {
+			issues = permissionCheck{Name: "Create issues", Missing: "the Issues: read and write token permission"}
+		}
+	}
+	checks = append(checks, issues)
+
+	labels := permissionCheck{Name: "Add labels to issues", OK: true}
+	switch {
+	case !scopeOK("repo", repoScope):
+		labels = permissionCheck{Name: "Add labels to issues", Missing: fmt.Sprintf("the %s token scope", repoScope)}
+	case !perms["triage"] && !perms["push"] && !perms["admin"]:
+		labels = permissionCheck{Name: "Add labels to issues", Missing: "the triage (or write) role on the repository"}
+	}
+	checks = append(checks, labels)
+
+	dispatch := permissionCheck{Name: "Dispatch workflows", OK: true}
+	switch {
+	case !scopeOK("repo", repoScope):
+		dispatch = permissionCheck{Name: "Dispatch workflows", Missing: fmt.Sprintf("the %s token scope", repoScope)}
+	case !perms["push"] && !perms["admin"]:
+		dispatch = permissionCheck{Name: "Dispatch workflows", Missing: "the write role on the repository"}
+	case !classic:
+		if _, _, err := client.Actions.ListWorkflows(ctx, owner, repo, nil); err != nil {
+			dispatch = permissionCheck{Name: "Dispatch workflows", Missing: "the Actions: read and write token permission"}
+		}
+	}
+	checks = append(checks, dispatch)
+
+	if pages {
+		p := permissionCheck{Name: "Manage GitHub Pages", OK: true}
+		switch {
+		case !scopeOK("repo", repoScope):
+			p = permissionCheck{Name: "Manage GitHub Pages", Missing: fmt.Sprintf("the %s token scope", repoScope)}
+		case !perms["admin"] && !perms["maintain"]:
+			p = permissionCheck{Name: "Manage GitHub Pages", Missing: "the admin or maintain role on the repository"}
+		default:
+			if _, _, err := client.Repositories.GetPagesInfo(ctx, owner, repo); err != nil && !githubapi.IsNotFound(err) {
+				p = permissionCheck{Name: "Manage GitHub Pages", Missing: "the Pages: read and write token permission"}
+			}
+		}
+		checks = append(checks, p)
+	}
+	return checks
+}
+
+func init() {
+	doctorPermissionsCmd.Flags().Bool("pages", false, "Also check GitHub Pages access (on by default when the repository has Pages enabled)")
+
+	doctorCmd.AddCommand(doctorPermissionsCmd)
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// playerDataSchema identifies the layout of "tennis export my-data"
+// bundles. Bump it when a field changes meaning or goes away.
+const playerDataSchema = "tennis-player-data/v1"
+
+// playerDataExport is everything the league's data files hold about one
+// player, in a portable JSON form.
+type playerDataExport struct {
+	Schema    string            `json:"schema"`
+	Generated string            `json:"generated"`
+	League    string            `json:"league"`
+	Player    string            `json:"player"`
+	Aliases   []string          `json:"aliases,omitempty"`
+	Pseudonym string            `json:"pseudonym,omitempty"`
+	Roster    *playerDataRoster `json:"roster,omitempty"`
+	Ratings   playerDataRatings `json:"ratings"`
+	Matches   []playerDataMatch `json:"matches"`
+	Merges    []playerDataMerge `json:"merges,omitempty"`
+}
+
+type playerDataRoster struct {
+	Name       string          `json:"name,omitempty"`
+	Joined     string          `json:"joined,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Division   int             `json:"division,omitempty"`
+	Categories []string        `json:"categories,omitempty"`
+	Profile    *playerProfile  `json:"profile,omitempty"`
+	Away       *awayPeriod     `json:"away,omitempty"`
+	Imported   *importedRating `json:"imported,omitempty"`
+}
+
+// playerDataRatings are the player's current ratings, as on the
+// leaderboard (after inactivity decay) if they're listed there, if they've
+// played or imported one.
+type playerDataRatings struct {
+	Singles *float64 `json:"singles,omitempty"`
+	Doubles *float64 `json:"doubles,omitempty"`
+}
+
+// playerDataMatch is one match from the player's side: their partner and
+// opponents, the sets with their games first, and their rating before and
+// after it.
+type playerDataMatch struct {
+	Date         string   `json:"date"`
+	Type         string   `json:"type"`
+	Partner      string   `json:"partner,omitempty"`
+	Opponents    []string `json:"opponents"`
+	Sets         [][]int  `json:"sets"`
+	Result       string   `json:"result"`
+	RatingBefore float64  `json:"rating_before"`
+	RatingAfter  float64  `json:"rating_after"`
+	Issue        int      `json:"issue,omitempty"`
+	Repo         string   `json:"repo,omitempty"`
+}
+
+type playerDataMerge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Date   string `json:"date"`
+	Reason string `json:"reason,omitempty"`
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export league data",
+}
+
+var exportMyDataCmd = &cobra.Command{
+	Use:   "my-data",
+	Short: "Export everything the league stores about you",
+	Long: `Export everything the league's data files hold about you as one JSON
+bundle: your roster entry (name, join date, status, division, profile,
+away period and any imported rating), your current ratings, every match you
+played with your rating before and after it, your old handles and any
+merges. Match issues and pull requests live on GitHub and aren't included.
+
+The bundle's layout is named by its "schema" field (tennis-player-data/v1)
+so other tools can read it. Ratings are in the league's Elo points, and
+every match's sets list your games first.
+
+The player is the owner of the GitHub token unless --player is given, e.g.
+by an admin answering a request.
+
+Examples:
+  tennis export my-data --out my-data.json
+  tennis export my-data --player @player_one`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		player, _ := cmd.Flags().GetString("player")
+		out, _ := cmd.Flags().GetString("out")
+
+		if player == "" {
+			user, _, err := getGitHubClient().Users.Get(cmd.Context(), "")
+			if err != nil {
+				return fmt.Errorf("failed to look up the token's user (use --player): %w", err)
+			}
+			player = user.GetLogin()
+		}
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		player = resolveAlias(aliases, normalizePlayer(player))
+
+		data, err := collectPlayerData(cmd.Context(), player, aliases)
+		if err != nil {
+			return err
+		}
+		if data.Roster == nil && len(data.Matches) == 0 && data.Ratings == (playerDataRatings{}) {
+			return fmt.Errorf("the league holds no data about @%s", player)
+		}
+
+		w := io.Writer(os.Stdout)
+		if out != "" {
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(data); err != nil {
+			return err
+		}
+		if out != "" {
+			fmt.Printf("✅ Exported @%s's data (%d matches) to %s\n", player, len(data.Matches), out)
+		}
+		return nil
+	},
+}
+
+// collectPlayerData gathers the export bundle for a player from the
+// league's data files.
+func collectPlayerData(ctx context.Context, player string, aliases map[string]string) (*playerDataExport, error) {
+	data := &playerDataExport{
+		Schema:    playerDataSchema,
+		Generated: time.Now().UTC().Format(time.RFC3339),
+		League:    owner + "/" + repo,
+		Player:    player,
+		Pseudonym: pseudonyms[player],
+		Matches:   []playerDataMatch{},
+	}
+	for old := range aliases {
+		if old != player && resolveAlias(aliases, old) == player {
+			data.Aliases = append(data.Aliases, old)
+		}
+	}
+	sort.Strings(data.Aliases)
+
+	roster, err := loadRoster()
+	if err != nil {
+		return nil, err
+	}
+	if p := roster.Find(player); p != nil {
+		data.Roster = &playerDataRoster{
+			Name: p.Name, Joined: p.Joined, Status: p.Status, Division: p.Division, Categories: p.Categories,
+			Profile: p.Profile, Away: p.Away, Imported: p.Imported,
+		}
+	}
+
+	singles, err := loadSinglesMatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load matches: %w", err)
+	}
+	doubles, err := loadDoublesMatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load matches: %w", err)
+	}
+	today := time.Now().Format("2006-01-02")
+	for _, kind := range []struct {
+		name    string
+		matches []Match
+		board   []LeaderboardRow
+		replay  func(map[string]float64, []Match)
+		current **float64
+	}{
+		{"singles", singles, singlesLeaderboard(singles, roster, today), replaySingles, &data.Ratings.Singles},
+		{"doubles", doubles, doublesLeaderboard(doubles, roster, today), replayDoubles, &data.Ratings.Doubles},
+	} {
+		played := playerMatches(player, kind.name, kind.matches, kind.replay)
+		if len(played) > 0 {
+			r := played[len(played)-1].RatingAfter
+			*kind.current = &r
+		}
+		for _, r := range kind.board {
+			if r.Player == player {
+				r := roundRating(r.Rating)
+				*kind.current = &r
+			}
+		}
+		data.Matches = append(data.Matches, played...)
+	}
+	sort.SliceStable(data.Matches, func(i, j int) bool { return data.Matches[i].Date < data.Matches[j].Date })
+
+	merges, err := loadMergeRecords()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range merges {
+		if resolveAlias(aliases, m.To) == player {
+			data.Merges = append(data.Merges, playerDataMerge{From: m.From, To: m.To, Date: m.Date, Reason: m.Reason})
+		}
+	}
+	return data, nil
+}
+
+// playerMatches replays matches one at a time and returns the ones player
+// played, from their side, with their rating either side of each.
+func playerMatches(player, kind string, matches []Match, replay func(map[string]float64, []Match)) []playerDataMatch {
+	ratings := make(map[string]float64)
+	replay(ratings, nil) // seeds imported ratings
+	var out []playerDataMatch
+	for i, m := range matches {
+		side1, side2 := m.Team1, m.Team2
+		if !m.IsDoubles() {
+			if len(m.Players) != 2 {
+				continue
+			}
+			side1, side2 = m.Players[:1], m.Players[1:]
+		}
+		mine, theirs, flip := side1, side2, false
+		if !containsString(side1, player) {
+			mine, theirs, flip = side2, side1, true
+		}
+		if !containsString(mine, player) {
+			replay(ratings, matches[i:i+1])
+			continue
+		}
+
+		before := rating(ratings, player)
+		replay(ratings, matches[i:i+1])
+		pm := playerDataMatch{
+			Date:         m.Date,
+			Type:         kind,
+			Opponents:    theirs,
+			Result:       "D",
+			RatingBefore: roundRating(before),
+			RatingAfter:  roundRating(rating(ratings, player)),
+			Issue:        m.SourceIssue,
+			Repo:         m.Repo,
+		}
+		for _, p := range mine {
+			if p != player {
+				pm.Partner = p
+			}
+		}
+		for _, s := range m.Sets {
+			if len(s) != 2 {
+				continue
+			}
+			if flip {
+				s = []int{s[1], s[0]}
+			}
+			pm.Sets = append(pm.Sets, s)
+		}
+		switch w := matchWinner(m); {
+		case w == 1 && !flip, w == 2 && flip:
+			pm.Result = "W"
+		case w != 0:
+			pm.Result = "L"
+		}
+		out = append(out, pm)
+	}
+	return out
+}
+
+// roundRating rounds a rating to one decimal place, as rankings.json does.
+func roundRating(r float64) float64 {
+	return math.Round(r*10) / 10
+}
+
+func init() {
+	exportMyDataCmd.Flags().String("player", "", "Player whose data to export (defaults to the token's user)")
+	exportMyDataCmd.Flags().StringP("out", "o", "", "Write to a file instead of stdout")
+
+	exportCmd.AddCommand(exportMyDataCmd)
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+// standingFixture is a match played at the same time every week, defined
+// by an open issue labelled labelNames.StandingFixture. Closing the issue
+// cancels it.
+type standingFixture struct {
+	Number  int        // the defining issue, 0 for a one-off fixture
+	Sides   [][]string // one handle per side in singles, two in doubles
+	Day     time.Weekday
+	Time    string // HH:MM
+	Repeats string // "weekly"
+}
+
+// kind is "singles" or "doubles".
+func (f standingFixture) kind() string {
+	if len(f.Sides[0]) == 2 {
+		return "doubles"
+	}
+	return "singles"
+}
+
+// players formats the sides as the issue forms do: "@a, @b" for singles
+// and "@a, @b || @c, @d" for doubles.
+func (f standingFixture) players() string {
+	if f.kind() == "singles" {
+		return f.Sides[0][0] + ", " + f.Sides[1][0]
+	}
+	return strings.Join(f.Sides[0], ", ") + " || " + strings.Join(f.Sides[1], ", ")
+}
+
+// versus formats the sides for an issue title.
+func (f standingFixture) versus() string {
+	return strings.Join(f.Sides[0], " & ") + " vs " + strings.Join(f.Sides[1], " & ")
+}
+
+// body is the standing fixture issue body, read back by parseStandingFixture.
+func (f standingFixture) body() string {
+	return fmt.Sprintf(`### Players
+%s
+
+### Day
+%s
+
+### Time
+%s
+
+### Repeats
+%s
+
+A scheduling issue is opened for this fixture every week. Close this issue to cancel it.`,
+		f.players(), f.Day, f.Time, f.Repeats)
+}
+
+// parseStandingFixture reads a standing fixture back from its issue body.
+func parseStandingFixture(number int, body string) (standingFixture, error) {
+	sections := issueSections(body)
+	field := func(keyword string) string {
+		return strings.TrimSpace(strings.SplitN(section(sections, keyword), "\n", 2)[0])
+	}
+	sides, err := parseSides(field("players"))
+	if err != nil {
+		return standingFixture{}, fmt.Errorf("#%d: %w", number, err)
+	}
+	day, err := parseWeekday(field("day"))
+	if err != nil {
+		return standingFixture{}, fmt.Errorf("#%d: %w", number, err)
+	}
+	timeOfDay, err := parseTimeOfDay(field("time"))
+	if err != nil {
+		return standingFixture{}, fmt.Errorf("#%d: %w", number, err)
+	}
+	repeats := strings.ToLower(field("repeats"))
+	if repeats != "weekly" {
+		return standingFixture{}, fmt.Errorf("#%d: unsupported repeat %q (only weekly)", number, repeats)
+	}
+	return standingFixture{Number: number, Sides: sides, Day: day, Time: timeOfDay, Repeats: repeats}, nil
+}
+
+// parseSides parses "@a,@b" (singles) or "@a,@b||@c,@d" (doubles).
+func parseSides(s string) ([][]string, error) {
+	parts := strings.Split(s, "||")
+	if len(parts) == 1 {
+		handles := splitHandles(s)
+		if len(handles) != 2 {
+			return nil, fmt.Errorf("exactly 2 players required for a singles fixture")
+		}
+		return [][]string{{"@" + handles[0]}, {"@" + handles[1]}}, nil
+	}
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("exactly 2 teams required for a doubles fixture (separated by ||)")
+	}
+	var sides [][]string
+	for _, part := range parts {
+		handles := splitHandles(part)
+		if len(handles) != 2 {
+			return nil, fmt.Errorf("each team must have exactly 2 players")
+		}
+		sides = append(sides, []string{"@" + handles[0], "@" + handles[1]})
+	}
+	return sides, nil
+}
+
+// parseWeekday parses a day name such as "thursday" or "Thu".
+func parseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if len(s) >= 3 && strings.HasPrefix(name, s) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day '%s'. Use a day of the week, e.g. thursday", s)
+}
+
+// fixtureKey identifies the scheduling issue for a standing fixture in a
+// given week, so the weekly run opens it only once.
+func fixtureKey(number int, week string) string {
+	return fmt.Sprintf("fixture-%d-%s", number, week)
+}
+
+// scheduleFixture opens the scheduling issue for a fixture in the ISO week
+// starting on start.
+func scheduleFixture(ctx context.Context, f standingFixture, week string, start time.Time) (int, error) {
+	offset := (int(f.Day) + 6) % 7 // days after Monday
+	date := start.AddDate(0, 0, offset).Format("2006-01-02")
+
+	title := fmt.Sprintf("Fixture: %s (%s %s)", f.versus(), date, f.Time)
+	body := fmt.Sprintf(`### Fixture week
+%s
+
+### Match date
+%s (%s)
+
+### Match time
+%s
+
+### Players
+%s
+`, week, date, f.Day, f.Time, f.players())
+	record := fmt.Sprintf("tennis match %s --date %s --time %s", f.kind(), date, f.Time)
+	if f.Number != 0 {
+		body += fmt.Sprintf("\nScheduled by standing fixture #%d.\n", f.Number)
+	}
+	body += fmt.Sprintf("\nPlay your match, then record the result with `%s` or the %s match issue form.", record, f.kind())
+	if f.Number != 0 {
+		body += "\n\n" + commentMarker(fixtureKey(f.Number, week))
+	}
+	return openIssue(ctx, title, body, []string{labelNames.Challenge})
+}
+
+// listStandingFixtures returns the league's open standing fixtures. Issues
+// that can't be read are reported and skipped.
+func listStandingFixtures(ctx context.Context, client *github.Client) ([]standingFixture, error) {
+	issues, err := listLabelledIssues(ctx, client, labelNames.StandingFixture, issueFilter{State: "open"})
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []standingFixture
+	for _, issue := range issues {
+		if issue.IsPullRequest() {
+			continue
+		}
+		f, err := parseStandingFixture(issue.GetNumber(), issue.GetBody())
+		if err != nil {
+			fmt.Printf("⚠️  Skipping standing fixture %v\n", err)
+			continue
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// currentWeek returns the ISO week for the --week flag, defaulting to the
+// current week, and the Monday it starts on.
+func currentWeek(week string) (string, time.Time, error) {
+	if week == "" {
+		y, w := time.Now().ISOWeek()
+		week = fmt.Sprintf("%d-W%02d", y, w)
+	}
+	start, err := parseISOWeek(week)
+	return week, start, err
+}
+
+var fixtureCmd = &cobra.Command{
+	Use:   "fixture",
+	Short: "Schedule fixtures at a set day and time",
+	Long: `Schedule fixtures at a set day and time, once or every week.
+
+A standing fixture (--recur weekly) is an open issue labelled
+standing-fixture. Each week "tennis fixture run", run by the Standing
+Fixtures workflow, opens a scheduling issue for it, until the standing
+fixture is cancelled.`,
+}
+
+var fixtureCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Schedule a fixture, or a standing fixture with --recur weekly",
+	Long: `Schedule a fixture on a day and time.
+
+Without --recur, a scheduling issue is opened for the fixture in one week
+(the current week unless --week is set). With --recur weekly, a standing
+fixture issue is opened instead, and a scheduling issue follows every week
+until it is cancelled.
+
+Examples:
+  tennis fixture create --players "@player_one,@player_two" --day thursday --time 18:30
+  tennis fixture create --players "@player_one,@player_two" --day thursday --time 18:30 --recur weekly
+  tennis fixture create --teams "@player_one,@player_two||@player_three,@player_four" --day sat --time 09:00 --recur weekly`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		players, _ := cmd.Flags().GetString("players")
+		teams, _ := cmd.Flags().GetString("teams")
+		dayFlag, _ := cmd.Flags().GetString("day")
+		timeFlag, _ := cmd.Flags().GetString("time")
+		recur, _ := cmd.Flags().GetString("recur")
+		week, _ := cmd.Flags().GetString("week")
+
+		if (players == "") == (teams == "") {
+			return fmt.Errorf("give either --players (singles) or --teams (doubles)")
+		}
+		if players != "" && strings.Contains(players, "||") {
+			return fmt.Errorf("use --teams for doubles fixtures")
+		}
+		if dayFlag == "" || timeFlag == "" {
+			return invalidf("--day and --time are required")
+		}
+		sides, err := parseSides(players + teams)
+		if err != nil {
+			return err
+		}
+		day, err := parseWeekday(dayFlag)
+		if err != nil {
+			return err
+		}
+		timeOfDay, err := parseTimeOfDay(timeFlag)
+		if err != nil {
+			return err
+		}
+		f := standingFixture{Sides: sides, Day: day, Time: timeOfDay}
+
+		var handles []string
+		for _, side := range sides {
+			handles = append(handles, side...)
+		}
+		if err := validateHandles(cmd.Context(), handles); err != nil {
+			return err
+		}
+
+		switch recur {
+		case "":
+			week, start, err := currentWeek(week)
+			if err != nil {
+				return err
+			}
+			number, err := scheduleFixture(cmd.Context(), f, week, start)
+			if err != nil || dryRun {
+				return err
+			}
+			return showQR(fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, number))
+		case "weekly":
+			if week != "" {
+				return invalidf("--week only applies to one-off fixtures")
+			}
+			f.Repeats = recur
+			title := fmt.Sprintf("Standing fixture: %s (%ss at %s)", f.versus(), f.Day, f.Time)
+			number, err := openIssue(cmd.Context(), title, f.body(), []string{labelNames.StandingFixture})
+			if err != nil {
+				return err
+			}
+			if !dryRun {
+				fmt.Printf("✅ Standing fixture #%d created; it is scheduled every week by \"tennis fixture run\"\n", number)
+			}
+			return nil
+		default:
+			return invalidf("invalid --recur '%s'. Only weekly is supported", recur)
+		}
+	},
+}
+
+var fixtureRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Open this week's scheduling issues for standing fixtures",
+	Long: `Open a scheduling issue for every open standing fixture in a week (the
+current week unless --week is set). Fixtures already scheduled that week
+are skipped, so the command is safe to re-run.
+
+Examples:
+  tennis fixture run
+  tennis fixture run --week 2025-W07 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		week, _ := cmd.Flags().GetString("week")
+		week, start, err := currentWeek(week)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		fixtures, err := listStandingFixtures(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		// Scheduling issues carry a marker for their fixture and week, and
+		// are opened on or after the Monday before the week starts.
+		scheduled, err := listLabelledIssues(ctx, client, labelNames.Challenge, issueFilter{State: "all", Since: start.AddDate(0, 0, -7)})
+		if err != nil {
+			return err
+		}
+
+		created := 0
+		for _, f := range fixtures {
+			marker := commentMarker(fixtureKey(f.Number, week))
+			done := false
+			for _, issue := range scheduled {
+				if strings.Contains(issue.GetBody(), marker) {
+					fmt.Printf("#%d is already scheduled for %s (#%d)\n", f.Number, week, issue.GetNumber())
+					done = true
+					break
+				}
+			}
+			if done {
+				continue
+			}
+			if _, err := scheduleFixture(ctx, f, week, start); err != nil {
+				return err
+			}
+			created++
+		}
+
+		if !dryRun {
+			fmt.Printf("✅ %d of %d standing fixtures scheduled for %s\n", created, len(fixtures), week)
+		}
+		return nil
+	},
+}
+
+var fixtureListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List standing fixtures",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fixtures, err := listStandingFixtures(cmd.Context(), getGitHubClient())
+		if err != nil {
+			return err
+		}
+		if len(fixtures) == 0 {
+			fmt.Println("No standing fixtures")
+			return nil
+		}
+		for _, f := range fixtures {
+			fmt.Printf("#%-5d %-9s %s  %s (%s)\n", f.Number, f.Day, f.Time, f.versus(), f.Repeats)
+		}
+		return nil
+	},
+}
+
+var fixtureCancelCmd = &cobra.Command{
+	Use:   "cancel <issue-number>",
+	Short: "Cancel a standing fixture",
+	Long: `Cancel a standing fixture by closing its issue. Scheduling issues that
+are already open are left for the players to close.
+
+Examples:
+  tennis fixture cancel 42`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			return invalidf("invalid issue number '%s'", args[0])
+		}
+		if !dryRun {
+			issue, _, err := getGitHubClient().Issues.Get(cmd.Context(), owner, repo, number)
+			if err != nil {
+				return fmt.Errorf("failed to fetch #%d: %w", number, err)
+			}
+			labelled := false
+			for _, l := range issue.Labels {
+				labelled = labelled || l.GetName() == labelNames.StandingFixture
+			}
+			if !labelled {
+				return fmt.Errorf("#%d is not a standing fixture", number)
+			}
+		}
+		if err := confirm("Cancel standing fixture #%d?", number); err != nil {
+			return err
+		}
+		if err := closeIssue(cmd.Context(), number, "Standing fixture cancelled; no more scheduling issues will be opened for it."); err != nil {
+			return err
+		}
+		if !dryRun {
+			fmt.Printf("✅ Standing fixture #%d cancelled\n", number)
+		}
+		return nil
+	},
+}
+
+func init() {
+	fixtureCreateCmd.Flags().StringP("players", "p", "", "Singles players (comma-separated)")
+	fixtureCreateCmd.Flags().StringP("teams", "t", "", "Doubles teams (team1||team2, players comma-separated)")
+	fixtureCreateCmd.Flags().String("day", "", "Day of the week the fixture is played, e.g. thursday")
+	fixtureCreateCmd.Flags().String("time", "", "Start time (24-hour HH:MM)")
+	fixtureCreateCmd.Flags().String("recur", "", "Repeat the fixture: weekly")
+	fixtureCreateCmd.Flags().String("week", "", "ISO week of a one-off fixture, e.g. 2025-W07 (defaults to the current week)")
+	fixtureCreateCmd.Flags().BoolVar(&noValidate, "no-validate", false, "Skip checking that player handles exist on GitHub")
+	fixtureCreateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the issue that would be created without creating it")
+	fixtureCreateCmd.Flags().BoolVar(&qrTerminal, "qr", false, "Print a QR code of a one-off fixture's issue link, for the opponent to scan")
+	fixtureCreateCmd.Flags().StringVar(&qrOut, "qr-out", "", "Write a QR code of a one-off fixture's issue link to this PNG file")
+
+	fixtureRunCmd.Flags().String("week", "", "ISO week to schedule, e.g. 2025-W07 (defaults to the current week)")
+	fixtureRunCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the issues that would be created without creating them")
+
+	fixtureCancelCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print what would be done without closing the issue")
+
+	fixtureCmd.AddCommand(fixtureCreateCmd, fixtureRunCmd, fixtureListCmd, fixtureCancelCmd)
+	rootCmd.AddCommand(fixtureCmd)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var handicapCmd = &cobra.Command{
+	Use:   "handicap <@player1> <@player2> | <@p1> <@p2> <@p3> <@p4>",
+	Short: "Show the head start a pairing plays with",
+	Long: `Show the head start the lower-rated side gets in a match between two
+players, or between two doubles teams (the first two handles against the
+last two), under the league's handicap rules (the handicap section of
+.tennis.yml) and the current ratings.
+
+The same head start is recorded in the match file when the match is
+reported, with the sets as played and with the head start added.
+
+Examples:
+  tennis handicap @player_one @player_two
+  tennis handicap @player_one @player_two @player_three @player_four`,
+	Args: cobra.RangeArgs(2, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 3 {
+			return invalidf("give two players, or four for doubles")
+		}
+		if !handicap.Enabled() {
+			return fmt.Errorf("the league doesn't play handicaps (set handicap.games_per in .tennis.yml)")
+		}
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		var players []string
+		for _, a := range args {
+			players = append(players, resolveAlias(aliases, normalizePlayer(a)))
+		}
+
+		m := Match{Players: players, Sets: [][]int{{0, 0}}}
+		load, compute := loadSinglesMatches, computeSinglesRatings
+		if len(players) == 4 {
+			m = Match{Team1: players[:2], Team2: players[2:], Sets: [][]int{{0, 0}}}
+			load, compute = loadDoublesMatches, computeDoublesRatings
+		}
+		matches, err := load(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		ratings := compute(matches)
+		side1, side2 := players[:len(players)/2], players[len(players)/2:]
+		names := func(side []string) string {
+			return "@" + strings.Join(side, " & @")
+		}
+
+		fmt.Printf("%s (%.1f) vs %s (%.1f)\n", names(side1), sideRating(ratings, side1), names(side2), sideRating(ratings, side2))
+		fmt.Printf("Handicap: %s\n", handicap)
+		applyHandicap(&m, ratings)
+		if m.Handicap == nil {
+			fmt.Println("No head start: the ratings are too close")
+			return nil
+		}
+		receiver, verb := side1, "starts"
+		if m.Handicap.Side == 2 {
+			receiver = side2
+		}
+		if len(receiver) > 1 {
+			verb = "start"
+		}
+		fmt.Printf("%s %s every set %d games up\n", names(receiver), verb, m.Handicap.Games)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(handicapCmd)
+}
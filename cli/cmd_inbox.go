@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "List the match issues that need your attention",
+	Long: `List the open match issues waiting on you:
+
+  waiting on your approval   results another player reported with you in them
+  needs your correction      matches you reported that were flagged
+                             needs-correction
+
+With --admin, list what's waiting on the league admins instead: matches
+escalated by "bot escalate" (needs-admin), with who they're assigned to, and
+every match waiting on its reporter's correction.
+
+The player is the owner of the GitHub token unless --player is given.
+
+Examples:
+  tennis inbox
+  tennis inbox --player @player_one
+  tennis inbox --admin`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		player, _ := cmd.Flags().GetString("player")
+		admin, _ := cmd.Flags().GetBool("admin")
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		issues, err := listMatchIssues(ctx, client, "open")
+		if err != nil {
+			return err
+		}
+
+		if admin {
+			fmt.Println("Escalated to the admins")
+			escalated := 0
+			for _, issue := range issues {
+				if !hasLabel(issue, labelNames.NeedsAdmin) {
+					continue
+				}
+				var assignees []string
+				for _, a := range issue.Assignees {
+					assignees = append(assignees, "@"+a.GetLogin())
+				}
+				assigned := "unassigned"
+				if len(assignees) > 0 {
+					assigned = "assigned to " + strings.Join(assignees, ", ")
+				}
+				fmt.Printf("  #%-5d %s (reported by @%s %s, %s)\n", issue.GetNumber(), issue.GetTitle(),
+					normalizePlayer(issue.GetUser().GetLogin()), daysAgo(issue.GetCreatedAt().Time), assigned)
+				escalated++
+			}
+			if escalated == 0 {
+				fmt.Println("  Nothing escalated")
+			}
+			fmt.Println("\nNeeds correction")
+			printCorrections(issues, "")
+			return nil
+		}
+
+		if player == "" {
+			user, _, err := client.Users.Get(ctx, "")
+			if err != nil {
+				return fmt.Errorf("failed to look up the token's user (use --player): %w", err)
+			}
+			player = user.GetLogin()
+		}
+		player = normalizePlayer(player)
+
+		fmt.Printf("Inbox for @%s\n", player)
+		fmt.Println("\nWaiting on your approval")
+		waiting, err := approvalsPendingFor(ctx, client, player)
+		if err != nil {
+			return err
+		}
+		if len(waiting) == 0 {
+			fmt.Println("  Nothing to approve")
+		}
+		for _, w := range waiting {
+			fmt.Printf("  #%-5d %s (reported by @%s %s)\n", w.Issue.GetNumber(), w.Issue.GetTitle(), w.Reporter, daysAgo(w.Issue.GetCreatedAt().Time))
+		}
+		fmt.Println("\nNeeds your correction")
+		printCorrections(issues, player)
+		return nil
+	},
+}
+
+// printCorrections lists the issues labelled needs-correction, only those
+// reported by reporter if it's set.
+func printCorrections(issues []*github.Issue, reporter string) {
+	found := 0
+	for _, issue := range issues {
+		by := normalizePlayer(issue.GetUser().GetLogin())
+		if !hasLabel(issue, labelNames.NeedsCorrection) || (reporter != "" && by != reporter) {
+			continue
+		}
+		if reporter != "" {
+			fmt.Printf("  #%-5d %s (%s)\n", issue.GetNumber(), issue.GetTitle(), daysAgo(issue.GetCreatedAt().Time))
+		} else {
+			fmt.Printf("  #%-5d %s (reported by @%s %s)\n", issue.GetNumber(), issue.GetTitle(), by, daysAgo(issue.GetCreatedAt().Time))
+		}
+		found++
+	}
+	if found == 0 {
+		fmt.Println("  Nothing to correct")
+	}
+}
+
+// daysAgo describes how long ago t was, in days.
+func daysAgo(t time.Time) string {
+	switch days := int(time.Since(t).Hours() / 24); days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
+
+func init() {
+	inboxCmd.Flags().String("player", "", "Player whose inbox to show (defaults to the token's user)")
+	inboxCmd.Flags().Bool("admin", false, "Show the league admins' inbox: escalated matches and pending corrections")
+
+	rootCmd.AddCommand(inboxCmd)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// repoFile is a file "tennis init" installs. Managed files are kept in
+// step with the CLI by --force; league data files are only ever created.
+type repoFile struct {
+	Path    string
+	Content string
+	Managed bool
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up a repository as a tennis league",
+	Long: `Set up a fresh repository as a tennis league, through the GitHub API:
+
+  - the match, fixture and correction labels
+  - the singles and doubles match issue forms
+  - the rankings workflow, which builds the leaderboard with the ranking
+    scripts from stonehenge-collective/tennis
+  - GitHub Pages, deployed from that workflow
+  - an empty roster (players.yml) and a default .tennis.yml
+
+Anything that already exists is left alone, so init is safe to re-run.
+With --force, issue forms and workflows that differ from the CLI's copies
+are updated; players.yml and .tennis.yml are never overwritten.
+
+Examples:
+  tennis init --owner my-club --repo tennis-league --dry-run
+  tennis init --name "Thursday Night Tennis"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		force, _ := cmd.Flags().GetBool("force")
+		if name == "" {
+			name = repo
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+
+		fmt.Printf("Setting up %s/%s as a tennis league\n\n", owner, repo)
+
+		for _, l := range defaultLabels() {
+			if err := initLabel(ctx, client, l); err != nil {
+				return err
+			}
+		}
+
+		var files []repoFile
+		for _, form := range matchForms {
+			files = append(files, repoFile{formPath(form), form.YAML(), true})
+		}
+		files = append(files,
+			repoFile{".github/workflows/rebuild-rankings.yml", mustAsset("rebuild-rankings.yml"), true},
+			repoFile{"players.yml", "[]\n", false},
+			repoFile{".tennis.yml", defaultConfig(name), false},
+		)
+		for _, f := range files {
+			if err := initFile(ctx, client, f, force); err != nil {
+				return err
+			}
+		}
+
+		if err := initPages(ctx, client); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return nil
+		}
+		fmt.Println("\n✅ League ready. Add players with `tennis player add` and record matches from the issue forms.")
+		return nil
+	},
+}
+
+func initLabel(ctx context.Context, client *github.Client, l leagueLabel) error {
+	_, _, err := client.Issues.GetLabel(ctx, owner, repo, l.Name)
+	if err == nil {
+		fmt.Printf("  label %-28s exists\n", l.Name)
+		return nil
+	}
+	if !githubapi.IsNotFound(err) {
+		return fmt.Errorf("failed to look up label %s: %w", l.Name, err)
+	}
+	if dryRun {
+		fmt.Printf("  label %-28s would be created\n", l.Name)
+		return nil
+	}
+	if _, _, err := client.Issues.CreateLabel(ctx, owner, repo, &github.Label{
+		Name:        github.String(l.Name),
+		Color:       github.String(l.Color),
+		Description: github.String(l.Description),
+	}); err != nil {
+		return fmt.Errorf("failed to create label %s: %w", l.Name, err)
+	}
+	fmt.Printf("  label %-28s created\n", l.Name)
+	return nil
+}
+
+func initFile(ctx context.Context, client *github.Client, f repoFile, force bool) error {
+	existing, _, _, err := client.Repositories.GetContents(ctx, owner, repo, f.Path, nil)
+	if err != nil && !githubapi.IsNotFound(err) {
+		return fmt.Errorf("failed to look up %s: %w", f.Path, err)
+	}
+
+	opts := &github.RepositoryContentFileOptions{
+		Message: github.String(fmt.Sprintf("chore: add %s (tennis init)", f.Path)),
+		Content: []byte(f.Content),
+	}
+	status := "created"
+	if existing != nil {
+		current, err := existing.GetContent()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Path, err)
+		}
+		if current == f.Content || !f.Managed || !force {
+			fmt.Printf("  file  %-28s exists\n", f.Path)
+			return nil
+		}
+		opts.Message = github.String(fmt.Sprintf("chore: update %s (tennis init)", f.Path))
+		opts.SHA = existing.SHA
+		status = "updated"
+	}
+
+	if dryRun {
+		fmt.Printf("  file  %-28s would be %s\n", f.Path, status)
+		return nil
+	}
+	if existing != nil {
+		_, _, err = client.Repositories.UpdateFile(ctx, owner, repo, f.Path, opts)
+	} else {
+		_, _, err = client.Repositories.CreateFile(ctx, owner, repo, f.Path, opts)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.Path, err)
+	}
+	fmt.Printf("  file  %-28s %s\n", f.Path, status)
+	return nil
+}
+
+// initPages enables GitHub Pages, deployed by the rankings workflow.
+func initPages(ctx context.Context, client *github.Client) error {
+	pages, _, err := client.Repositories.GetPagesInfo(ctx, owner, repo)
+	switch {
+	case err == nil && pages.GetBuildType() == "workflow":
+		fmt.Printf("  pages %-28s enabled\n", "GitHub Actions")
+		return nil
+	case err == nil:
+		if dryRun {
+			fmt.Printf("  pages %-28s would switch to GitHub Actions\n", pages.GetBuildType())
+			return nil
+		}
+		if _, err := client.Repositories.UpdatePages(ctx, owner, repo, &github.PagesUpdate{BuildType: github.String("workflow")}); err != nil {
+			return fmt.Errorf("failed to configure Pages: %w", err)
+		}
+		fmt.Printf("  pages %-28s switched to GitHub Actions\n", pages.GetBuildType())
+		return nil
+	case !githubapi.IsNotFound(err):
+		return fmt.Errorf("failed to look up Pages: %w", err)
+	}
+
+	if dryRun {
+		fmt.Printf("  pages %-28s would be enabled\n", "GitHub Actions")
+		return nil
+	}
+	if _, _, err := client.Repositories.EnablePages(ctx, owner, repo, &github.Pages{BuildType: github.String("workflow")}); err != nil {
+		return fmt.Errorf("failed to enable Pages: %w", err)
+	}
+	fmt.Printf("  pages %-28s enabled\n", "GitHub Actions")
+	return nil
+}
+
+func init() {
+	initCmd.Flags().String("name", "", "League name for .tennis.yml (defaults to the repository name)")
+	initCmd.Flags().Bool("force", false, "Update issue forms and workflows that differ from the CLI's copies")
+	initCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be set up without changing the repository")
+
+	rootCmd.AddCommand(initCmd)
+}
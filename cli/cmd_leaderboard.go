@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var leaderboardCmd = &cobra.Command{
+	Use:   "leaderboard",
+	Short: "Print the leaderboards, as text or markdown to paste",
+	Long: `Print the singles and doubles leaderboards as they stand today, with each
+ranked player's movement since the last saved leaderboard snapshot (or
+since --since; see "tennis rankings snapshot").
+
+With --output markdown, the leaderboards are GitHub-flavored markdown
+tables, ready to paste into the README, a discussion or a chat: movement is
+shown as 🔼 up, 🔽 down, ➖ unchanged and 🆕 newly ranked, players appear
+under their pseudonyms if they have one, and unranked players are counted
+under the table instead of listed.
+
+Unlike "tennis rankings compute", it replays every match and leaves the
+rankings snapshot alone.
+
+Examples:
+  tennis leaderboard
+  tennis leaderboard --output markdown --top 10 | pbcopy
+  tennis leaderboard --output markdown --kind singles --since 2025-01-01
+  tennis leaderboard --category veterans --output markdown`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		kind, _ := cmd.Flags().GetString("kind")
+		since, _ := cmd.Flags().GetString("since")
+		top, _ := cmd.Flags().GetInt("top")
+		release, _ := cmd.Flags().GetBool("release")
+		category, _ := cmd.Flags().GetString("category")
+
+		if output != "text" && output != "markdown" {
+			return invalidf("invalid --output '%s'. Use text or markdown", output)
+		}
+		if kind != "both" && kind != "singles" && kind != "doubles" {
+			return invalidf("invalid --kind '%s'. Use singles, doubles or both", kind)
+		}
+		if since != "" && !isValidDate(since) {
+			return invalidf("invalid --since '%s'. Use YYYY-MM-DD format", since)
+		}
+		if top < 0 {
+			return invalidf("--top must be 0 (everyone) or more")
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		if category, roster, err = forCategory(category, roster, &singles, &doubles); err != nil {
+			return err
+		}
+
+		today := time.Now().Format("2006-01-02")
+		// A category's boards rank fewer players than the saved snapshots,
+		// so movement against them would be meaningless.
+		var prev *leaderboardSnapshot
+		if category == "" {
+			if since == "" {
+				dates, err := listLeaderboardSnapshots(ctx, release)
+				if err != nil {
+					return err
+				}
+				for _, d := range dates {
+					if d < today {
+						since = d
+					}
+				}
+			}
+			if since != "" {
+				if prev, err = loadLeaderboardSnapshot(ctx, since, release); err != nil {
+					return err
+				}
+			}
+		} else if since != "" {
+			return invalidf("--since can't be used with --category: snapshots hold the whole league's leaderboards")
+		}
+
+		type board struct {
+			title      string
+			rows, prev []LeaderboardRow
+		}
+		var boards []board
+		if kind != "doubles" {
+			b := board{title: "Singles", rows: singlesLeaderboard(singles, roster, today)}
+			if prev != nil {
+				b.prev = prev.Singles
+			}
+			boards = append(boards, b)
+		}
+		if kind != "singles" {
+			b := board{title: "Doubles", rows: doublesLeaderboard(doubles, roster, today)}
+			if prev != nil {
+				b.prev = prev.DoublesIndividual
+			}
+			boards = append(boards, b)
+		}
+
+		heading := "Leaderboards on " + today
+		if category != "" {
+			heading = fmt.Sprintf("%s leaderboards on %s", category, today)
+		}
+		if prev != nil {
+			heading += fmt.Sprintf(" (movement since %s)", prev.Date)
+		}
+		for i, b := range boards {
+			var moves map[string]int
+			if prev != nil {
+				moves = rankMovement(b.prev, b.rows)
+			}
+			if output == "markdown" {
+				if i == 0 {
+					fmt.Printf("_%s_\n", heading)
+				}
+				fmt.Println()
+				writeLeaderboardMarkdown(os.Stdout, b.title, b.rows, moves, top)
+				continue
+			}
+			if i == 0 {
+				fmt.Println(heading)
+			}
+			fmt.Println()
+			printLeaderboardMovement(b.title, topRows(b.rows, top), moves, nil)
+		}
+		return nil
+	},
+}
+
+// topRows is the first n ranked rows of a board, or the whole board if n
+// is 0.
+func topRows(board []LeaderboardRow, n int) []LeaderboardRow {
+	if n == 0 {
+		return board
+	}
+	var rows []LeaderboardRow
+	for _, r := range board {
+		if !r.Unranked && len(rows) < n {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// writeLeaderboardMarkdown writes a board's top ranked players (all of
+// them if top is 0) as a GitHub-flavored markdown table under a heading,
+// with a movement column if moves isn't nil. The ranked players left off
+// and the unranked ones are counted under the table rather than listed.
+func writeLeaderboardMarkdown(w io.Writer, title string, board []LeaderboardRow, moves map[string]int, top int) {
+	hasTier := false
+	for _, r := range board {
+		hasTier = hasTier || r.Tier != ""
+	}
+	cols := []string{"Rank"}
+	align := []string{"---:"}
+	if moves != nil {
+		cols, align = append(cols, ""), append(align, ":---:")
+	}
+	cols, align = append(cols, "Player", "Rating"), append(align, "---", "---:")
+	if rankingPoints.Enabled() {
+		cols, align = append(cols, "Points"), append(align, "---:")
+	}
+	cols, align = append(cols, "Sets", "Games"), append(align, "---:", "---:")
+	if hasTier {
+		cols, align = append(cols, "Tier"), append(align, "---")
+	}
+
+	fmt.Fprintf(w, "## %s\n\n", title)
+	unranked, omitted, shown := 0, 0, 0
+	for _, r := range board {
+		if r.Unranked {
+			unranked++
+			continue
+		}
+		if top > 0 && shown == top {
+			omitted++
+			continue
+		}
+		if shown == 0 {
+			fmt.Fprintf(w, "| %s |\n| %s |\n", strings.Join(cols, " | "), strings.Join(align, " | "))
+		}
+		row := []string{fmt.Sprint(r.Rank)}
+		if moves != nil {
+			row = append(row, movementEmoji(moves, r.Player))
+		}
+		row = append(row, markdownCell(publicName(r.Player)), fmt.Sprintf("%.1f", r.Rating))
+		if rankingPoints.Enabled() {
+			row = append(row, fmt.Sprintf("%.1f", r.Points))
+		}
+		row = append(row, fmt.Sprintf("%d-%d", r.SetWins, r.SetLosses), fmt.Sprintf("%d-%d", r.GameWins, r.GameLosses))
+		if hasTier {
+			row = append(row, markdownCell(r.Tier))
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+		shown++
+	}
+	if shown == 0 && omitted == 0 {
+		fmt.Fprintln(w, "No ranked players yet.")
+	}
+
+	var notes []string
+	if omitted > 0 {
+		notes = append(notes, fmt.Sprintf("%d more not shown", omitted))
+	}
+	if unranked > 0 {
+		notes = append(notes, fmt.Sprintf("%d unranked (%s needed)", unranked, qualification))
+	}
+	if len(notes) > 0 {
+		fmt.Fprintf(w, "\n_%s._\n", strings.Join(notes, "; "))
+	}
+}
+
+// movementEmoji is movementArrow in emoji, which render the same in a
+// README, a discussion and a chat app: 🆕 for a player not ranked before.
+func movementEmoji(moves map[string]int, player string) string {
+	move, ok := moves[player]
+	switch {
+	case !ok:
+		return "🆕"
+	case move > 0:
+		return fmt.Sprintf("🔼 %d", move)
+	case move < 0:
+		return fmt.Sprintf("🔽 %d", -move)
+	}
+	return "➖"
+}
+
+// markdownCell escapes the characters that would break a table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+func init() {
+	leaderboardCmd.Flags().StringP("output", "o", "text", "Output format: text, or markdown for tables to paste")
+	leaderboardCmd.Flags().String("kind", "both", "Leaderboards to print: singles, doubles or both")
+	leaderboardCmd.Flags().String("since", "", "Show movement since the snapshot saved on this day (YYYY-MM-DD), defaults to the latest before today")
+	leaderboardCmd.Flags().Int("top", 0, "Show only the top N ranked players (0 for everyone)")
+	leaderboardCmd.Flags().Bool("release", false, "Read snapshots from the GitHub Release instead of the league checkout")
+	leaderboardCmd.Flags().String("category", "", "Rank only the players in this category")
+
+	rootCmd.AddCommand(leaderboardCmd)
+}
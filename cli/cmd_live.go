@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var liveCmd = &cobra.Command{
+	Use:   "live <match-issue>",
+	Short: "Score a match point by point, with a live score on its issue",
+	Long: `Score a match as it's played. Enter each point, game or set as it
+happens; a "live score" comment on the match issue is kept up to date, so
+anyone following the issue can watch. When the match is won, the sets are
+written into the issue, which the workflow then records as usual.
+
+Enter, one or more per line:
+  1, 2       a point to the first or second side, as listed on the issue
+  g1, g2     a whole game
+  6-3        a finished set, first side's games first, to catch up
+  u, undo    take back the last entry
+  end        end the match now (e.g. a retirement) and write the score so far
+  q, quit    stop scoring; run the command again to carry on
+
+Games are called 0, 15, 30, 40 and Ad; a set's tiebreak is first to 7 and
+a match tiebreak first to 10, both by two. The format follows the league's
+rules (best_of and scoring), or --best-of, --games and --match-tiebreak.
+
+The live comment holds everything entered, so scoring carries on where it
+left off after quitting, a dropped connection or Ctrl-C, even from another
+machine. --restart throws it away and starts again from 0-0.
+
+Examples:
+  tennis live 42
+  tennis live 42 --best-of 1 --games 4
+  tennis live 42 --restart`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || number < 1 {
+			return invalidf("invalid match issue '%s'. Use its number, like 42", args[0])
+		}
+		restart, _ := cmd.Flags().GetBool("restart")
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		issue, _, err := client.Issues.Get(ctx, owner, repo, number)
+		if err != nil {
+			return fmt.Errorf("failed to get issue #%d: %w", number, err)
+		}
+		kind, _, ok := matchIssueKind(issue)
+		if !ok || kind == "conflict" {
+			return invalidf("#%d isn't a singles or doubles match issue", number)
+		}
+		if issue.GetState() != "open" {
+			return invalidf("#%d is closed", number)
+		}
+		m := parseMatchIssue(kind, issue.GetBody())
+		names, err := liveSides(m)
+		if err != nil {
+			return fmt.Errorf("#%d: %w", number, err)
+		}
+
+		if err := loadRepoRules(ctx); err != nil {
+			return err
+		}
+		format := liveFormatFor(rules)
+		if cmd.Flags().Changed("best-of") {
+			format.BestOf, _ = cmd.Flags().GetInt("best-of")
+		}
+		if cmd.Flags().Changed("games") {
+			format.Games, _ = cmd.Flags().GetInt("games")
+		}
+		if cmd.Flags().Changed("match-tiebreak") {
+			format.MatchTiebreak, _ = cmd.Flags().GetBool("match-tiebreak")
+		}
+		if format.BestOf != 1 && format.BestOf != 3 && format.BestOf != 5 {
+			return invalidf("--best-of must be 1, 3 or 5")
+		}
+		if format.Games < 1 {
+			return invalidf("--games must be at least 1")
+		}
+
+		live := &liveComment{number: number}
+		score := &liveScore{Format: format}
+		if err := live.find(ctx, client); err != nil {
+			return err
+		}
+		switch {
+		case live.id == 0 || restart:
+		case live.final:
+			return invalidf("#%d was already scored live to the end; use --restart to score it again", number)
+		default:
+			if score, err = replayLive(live.format, live.log); err != nil {
+				return err
+			}
+			fmt.Printf("Carrying on from the live score on #%d (%s)\n", number, score.Format)
+		}
+
+		fmt.Printf("Scoring #%d: %s vs %s, %s\n", number, names[0], names[1], score.Format)
+		fmt.Println(`Enter 1 or 2 for a point, g1 or g2 for a game, 6-3 for a set, undo, end or quit ("help" for more)`)
+		fmt.Println(score.Text(names))
+		if err := live.update(ctx, client, score, names); err != nil {
+			return err
+		}
+
+		lines := make(chan string)
+		go func() {
+			defer close(lines)
+			in := bufio.NewScanner(os.Stdin)
+			for in.Scan() {
+				lines <- in.Text()
+			}
+		}()
+		for score.Winner == 0 {
+			fmt.Print("> ")
+			var line string
+			select {
+			case <-ctx.Done():
+				fmt.Println()
+				fmt.Fprintf(os.Stderr, "Stopped; run \"tennis live %d\" to carry on\n", number)
+				return ctx.Err()
+			case l, ok := <-lines:
+				if !ok {
+					fmt.Println()
+					fmt.Printf("Stopped; run \"tennis live %d\" to carry on\n", number)
+					return nil
+				}
+				line = l
+			}
+
+			ended := false
+			for _, entry := range strings.Fields(strings.ToLower(line)) {
+				switch entry {
+				case "q", "quit":
+					fmt.Printf("Stopped; run \"tennis live %d\" to carry on\n", number)
+					return nil
+				case "?", "h", "help":
+					fmt.Println("1, 2: point  g1, g2: game  6-3: set  u, undo: take back the last entry  end: finish now  q, quit: stop")
+				case "u", "undo":
+					if len(score.Log) == 0 {
+						fmt.Println("Nothing to undo")
+						continue
+					}
+					if score, err = replayLive(score.Format, score.Log[:len(score.Log)-1]); err != nil {
+						return err
+					}
+				case "end":
+					ended = true
+				default:
+					if err := score.apply(entry); err != nil {
+						fmt.Printf("%s: %v\n", entry, err)
+					}
+				}
+			}
+			fmt.Println(score.Text(names))
+			if ended {
+				if len(score.FinalSets()) == 0 {
+					fmt.Println("No games played yet, so there's no score to write")
+					continue
+				}
+				fmt.Println("⚠️  Ending the match before anyone won it; the score so far is written")
+				break
+			}
+			if score.Winner == 0 {
+				if err := live.update(ctx, client, score, names); err != nil {
+					return err
+				}
+			}
+		}
+
+		sets := score.FinalSets()
+		live.final = true
+		if err := live.update(ctx, client, score, names); err != nil {
+			return err
+		}
+		if err := writeLiveSets(ctx, client, issue, kind, sets); err != nil {
+			return err
+		}
+		if score.Winner != 0 {
+			fmt.Printf("🏆 %s won %s\n", names[score.Winner-1], strings.Join(sets, ", "))
+		}
+		if !dryRun {
+			fmt.Printf("✅ Sets %s written into #%d\n", strings.Join(sets, ", "), number)
+		}
+		return nil
+	},
+}
+
+// liveSides names the sides of a match issue for the scoreboard: "@a" in
+// singles, "@a & @b" in doubles.
+func liveSides(m MatchIssue) ([2]string, error) {
+	at := func(handles []string) string {
+		var out []string
+		for _, h := range handles {
+			out = append(out, "@"+h)
+		}
+		return strings.Join(out, " & ")
+	}
+	if m.Kind == "doubles" {
+		if len(m.Team1) != 2 || len(m.Team2) != 2 {
+			return [2]string{}, fmt.Errorf("the issue doesn't list two teams of two")
+		}
+		return [2]string{at(m.Team1), at(m.Team2)}, nil
+	}
+	if len(m.Players) != 2 {
+		return [2]string{}, fmt.Errorf("the issue doesn't list two players")
+	}
+	return [2]string{at(m.Players[:1]), at(m.Players[1:])}, nil
+}
+
+const liveMarkerKey = "live"
+
+// liveLogRegex finds the log kept in a live score comment, e.g.
+// <!-- tennis:live-log best_of=3 games=6 match_tiebreak=false final=false entries=1,2,g1 -->.
+var liveLogRegex = regexp.MustCompile(`<!-- tennis:live-log ([^>]*) -->`)
+
+// liveComment is the live score comment on a match issue.
+type liveComment struct {
+	number int
+	id     int64 // 0 until the comment exists
+	body   string
+	format liveFormat
+	log    []string
+	final  bool
+}
+
+// find looks for the issue's live score comment and reads its log.
+func (c *liveComment) find(ctx context.Context, client *github.Client) error {
+	comments, err := listComments(ctx, client, c.number)
+	if err != nil {
+		return err
+	}
+	marker := commentMarker(liveMarkerKey)
+	for _, comment := range comments {
+		if !strings.Contains(comment.GetBody(), marker) {
+			continue
+		}
+		c.id, c.body = comment.GetID(), comment.GetBody()
+		g := liveLogRegex.FindStringSubmatch(c.body)
+		if g == nil {
+			return fmt.Errorf("the live score comment on #%d has lost its log; use --restart", c.number)
+		}
+		for _, field := range strings.Fields(g[1]) {
+			key, value, _ := strings.Cut(field, "=")
+			switch key {
+			case "best_of":
+				c.format.BestOf, _ = strconv.Atoi(value)
+			case "games":
+				c.format.Games, _ = strconv.Atoi(value)
+			case "match_tiebreak":
+				c.format.MatchTiebreak = value == "true"
+			case "final":
+				c.final = value == "true"
+			case "entries":
+				if value != "" {
+					c.log = strings.Split(value, ",")
+				}
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// update posts the score to the live comment, creating it the first time.
+// Under --dry-run nothing is posted.
+func (c *liveComment) update(ctx context.Context, client *github.Client, score *liveScore, names [2]string) error {
+	var b strings.Builder
+	switch {
+	case !c.final:
+		b.WriteString("### 🎾 Live score\n\n")
+	case score.Winner != 0:
+		fmt.Fprintf(&b, "### 🏆 Final score: %s won\n\n", names[score.Winner-1])
+	default:
+		b.WriteString("### 🎾 Final score (ended early)\n\n")
+	}
+	b.WriteString(score.Markdown(names))
+	if c.final {
+		fmt.Fprintf(&b, "\n\nThe sets were written into the issue at %s.", time.Now().UTC().Format("15:04 UTC"))
+	} else {
+		fmt.Fprintf(&b, "\n\n_Updated %s by `tennis live`; %s._", time.Now().UTC().Format("15:04:05 UTC"), score.Format)
+	}
+	fmt.Fprintf(&b, "\n\n%s\n<!-- tennis:live-log best_of=%d games=%d match_tiebreak=%t final=%t entries=%s -->",
+		commentMarker(liveMarkerKey), score.Format.BestOf, score.Format.Games, score.Format.MatchTiebreak, c.final, strings.Join(score.Log, ","))
+	body := b.String()
+
+	if dryRun || body == c.body {
+		return nil
+	}
+	if c.id != 0 {
+		if _, _, err := client.Issues.EditComment(ctx, owner, repo, c.id, &github.IssueComment{Body: &body}); err != nil {
+			return fmt.Errorf("failed to update the live score on #%d: %w", c.number, err)
+		}
+	} else {
+		comment, _, err := client.Issues.CreateComment(ctx, owner, repo, c.number, &github.IssueComment{Body: &body})
+		if err != nil {
+			return fmt.Errorf("failed to post the live score on #%d: %w", c.number, err)
+		}
+		c.id = comment.GetID()
+	}
+	c.body = body
+	return nil
+}
+
+// issueSetsSectionRegex finds the sets in a match issue body: the heading
+// line, then everything up to the next section.
+var issueSetsSectionRegex = regexp.MustCompile(`(?s)(### Sets[^\n]*\n).*?(\n###|\z)`)
+
+// writeLiveSets replaces the sets in a match issue's body, and the result's
+// fingerprint if it carries one, leaving the rest of the body as it was.
+func writeLiveSets(ctx context.Context, client *github.Client, issue *github.Issue, kind string, sets []string) error {
+	body := strings.ReplaceAll(issue.GetBody(), "\r\n", "\n")
+	replacement := "${1}\n" + strings.Join(sets, "\n") + "\n${2}"
+	if issueSetsSectionRegex.MatchString(body) {
+		body = issueSetsSectionRegex.ReplaceAllString(body, replacement)
+	} else {
+		body += "\n\n### Sets (one line per set, player1's games first)\n\n" + strings.Join(sets, "\n")
+	}
+	if fingerprintRegex.MatchString(body) {
+		fp := matchFingerprint(parseMatchIssue(kind, body).Match())
+		body = fingerprintRegex.ReplaceAllString(body, commentMarker("match:"+fp))
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] would write the sets into #%d:\n\n%s\n", issue.GetNumber(), body)
+		return nil
+	}
+	if _, _, err := client.Issues.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{Body: &body}); err != nil {
+		return fmt.Errorf("failed to write the sets into #%d: %w", issue.GetNumber(), err)
+	}
+	return nil
+}
+
+func init() {
+	liveCmd.Flags().Int("best-of", 3, "Sets in the match: 1, 3 or 5 (defaults to rules.best_of)")
+	liveCmd.Flags().Int("games", 6, "Games to win a set, e.g. 4 for short sets (defaults to the league's scoring rules)")
+	liveCmd.Flags().Bool("match-tiebreak", false, "Play a deciding set as a first-to-10 tiebreak (defaults to the league's scoring rules)")
+	liveCmd.Flags().Bool("restart", false, "Discard the live score on the issue and start again from 0-0")
+	liveCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Score without posting the live comment or editing the issue")
+
+	rootCmd.AddCommand(liveCmd)
+}
@@ -8,7 +8,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/go-github/v67/github"
 	"github.com/spf13/cobra"
 )
 
@@ -37,6 +36,8 @@ var singlesMatchCmd = &cobra.Command{
 Examples:
   tennis match singles --players "@player_one,@player_two" --sets "6-3,4-6,6-4" --date "2025-01-15"
   tennis match singles -p "@player_one,@player_two" -s "6-3,4-6,6-4" -d "2025-01-15"
+  tennis match singles -p "@player_one,@player_two" -s "6-3,6-4" --time 18:30
+  tennis match singles -p "@player_one,@player_two" -s "6-3,6-4" --surface clay
 
 If date is not provided, today's date will be used.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -58,7 +59,22 @@ If date is not provided, today's date will be used.`,
 
 		// Validate date format
 		if !isValidDate(date) {
-			return fmt.Errorf("invalid date format. Use YYYY-MM-DD")
+			return invalidf("invalid date format. Use YYYY-MM-DD")
+		}
+
+		timeOfDay, _ := cmd.Flags().GetString("time")
+		if timeOfDay != "" {
+			var err error
+			if timeOfDay, err = parseTimeOfDay(timeOfDay); err != nil {
+				return err
+			}
+		}
+		surface, _ := cmd.Flags().GetString("surface")
+		if surface != "" {
+			var err error
+			if surface, err = parseSurface(surface); err != nil {
+				return err
+			}
 		}
 
 		// Parse players
@@ -73,7 +89,7 @@ If date is not provided, today's date will be used.`,
 		// Parse and validate sets
 		setsList, err := parseSets(sets)
 		if err != nil {
-			return fmt.Errorf("invalid sets format: %v", err)
+			return invalidf("invalid sets format: %v", err)
 		}
 
 		// Warn if the first-listed player did not win more sets
@@ -81,13 +97,17 @@ If date is not provided, today's date will be used.`,
 			return err
 		}
 
+		if err := checkRules(cmd.Context(), "singles", date, setsList); err != nil {
+			return err
+		}
+
 		// Verify the handles exist on GitHub (unless skipped)
-		if err := validateHandles(playerList); err != nil {
+		if err := validateHandles(cmd.Context(), playerList); err != nil {
 			return err
 		}
 
 		// Create issue
-		return createSinglesIssue(playerList, setsList, date)
+		return createSinglesIssue(cmd.Context(), playerList, setsList, date, timeOfDay, surface)
 	},
 }
 
@@ -99,6 +119,7 @@ var doublesMatchCmd = &cobra.Command{
 Examples:
   tennis match doubles --teams "@player_one,@player_two||@player_three,@player_four" --sets "6-3,4-6,6-4" --date "2025-01-15"
   tennis match doubles -t "@player_one,@player_two||@player_three,@player_four" -s "6-3,4-6,6-4" -d "2025-01-15"
+  tennis match doubles -t "@player_one,@player_two||@player_three,@player_four" -s "6-3,6-4" --time 18:30
 
 If date is not provided, today's date will be used.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -120,7 +141,22 @@ If date is not provided, today's date will be used.`,
 
 		// Validate date format
 		if !isValidDate(date) {
-			return fmt.Errorf("invalid date format. Use YYYY-MM-DD")
+			return invalidf("invalid date format. Use YYYY-MM-DD")
+		}
+
+		timeOfDay, _ := cmd.Flags().GetString("time")
+		if timeOfDay != "" {
+			var err error
+			if timeOfDay, err = parseTimeOfDay(timeOfDay); err != nil {
+				return err
+			}
+		}
+		surface, _ := cmd.Flags().GetString("surface")
+		if surface != "" {
+			var err error
+			if surface, err = parseSurface(surface); err != nil {
+				return err
+			}
 		}
 
 		// Parse teams
@@ -144,17 +180,21 @@ If date is not provided, today's date will be used.`,
 		// Parse and validate sets
 		setsList, err := parseSets(sets)
 		if err != nil {
-			return fmt.Errorf("invalid sets format: %v", err)
+			return invalidf("invalid sets format: %v", err)
+		}
+
+		if err := checkRules(cmd.Context(), "doubles", date, setsList); err != nil {
+			return err
 		}
 
 		// Verify the handles exist on GitHub (unless skipped)
 		allPlayers := append(append([]string{}, teamList[0]...), teamList[1]...)
-		if err := validateHandles(allPlayers); err != nil {
+		if err := validateHandles(cmd.Context(), allPlayers); err != nil {
 			return err
 		}
 
 		// Create issue
-		return createDoublesIssue(teamList, setsList, date)
+		return createDoublesIssue(cmd.Context(), teamList, setsList, date, timeOfDay, surface)
 	},
 }
 
@@ -214,108 +254,163 @@ func checkWinnerFirst(player1, player2 string, sets []string) error {
 	return nil
 }
 
+// checkRules holds a match to the league's rules (see leagueRules), as
+// the repository's .tennis.yml sets them, before its issue is created.
+func checkRules(ctx context.Context, kind, date string, sets []string) error {
+	if err := loadRepoRules(ctx); err != nil {
+		return err
+	}
+	return breaksRules(kind, date, sets)
+}
+
+// breaksRules holds a match to the rules already loaded, so it's safe to
+// call concurrently.
+func breaksRules(kind, date string, sets []string) error {
+	m := Match{Date: date}
+	for _, s := range sets {
+		score, _ := parseSetScore(s)
+		m.Sets = append(m.Sets, score)
+	}
+	var broken []string
+	for _, p := range rules.problems(kind, m) {
+		broken = append(broken, p.Message)
+	}
+	if len(broken) > 0 {
+		return fmt.Errorf("the match breaks the league rules: %s", strings.Join(broken, "; "))
+	}
+	return nil
+}
+
 // validateHandles checks that each @handle resolves to a real GitHub user,
 // surfacing typos before an issue is created. Skipped when --no-validate is set.
-func validateHandles(handles []string) error {
+func validateHandles(ctx context.Context, handles []string) error {
 	if noValidate || dryRun {
 		return nil
 	}
-	ctx := context.Background()
 	client := getGitHubClient()
 	for _, h := range handles {
 		login := strings.TrimPrefix(strings.TrimSpace(h), "@")
 		if login == "" {
-			return fmt.Errorf("empty player handle")
+			return invalidf("empty player handle")
 		}
 		if _, _, err := client.Users.Get(ctx, login); err != nil {
-			return fmt.Errorf("GitHub user '@%s' not found (use --no-validate to skip this check): %v", login, err)
+			return fmt.Errorf("GitHub user '@%s' not found (use --no-validate to skip this check): %w", login, err)
 		}
 	}
 	return nil
 }
 
-func createSinglesIssue(players []string, sets []string, date string) error {
-	title := fmt.Sprintf("Singles Match: %s vs %s (%s)", players[0], players[1], date)
+// singlesIssueBody formats a singles match issue body exactly as the
+// singles issue form would, with the match time, if any, after it.
+func singlesIssueBody(date, timeOfDay string, players []string, sets []string) string {
+	return withMatchTime(singlesForm.Body(date, players[0]+", "+players[1], strings.Join(sets, "\n")), timeOfDay)
+}
 
-	body := fmt.Sprintf(`### Match date (YYYY-MM-DD)
-%s
+// doublesIssueBody formats a doubles match issue body exactly as the
+// doubles issue form would, with the match time, if any, after it.
+func doublesIssueBody(date, timeOfDay string, teams [][]string, sets []string) string {
+	return withMatchTime(doublesForm.Body(date, strings.Join(teams[0], ", ")+" || "+strings.Join(teams[1], ", "), strings.Join(sets, "\n")), timeOfDay)
+}
 
-### Players (winner first, comma-separated @handles)
-%s, %s
+// withMatchTime appends the optional match time section. The issue forms
+// don't ask for it, and the workflow records it in the match file.
+func withMatchTime(body, timeOfDay string) string {
+	if timeOfDay == "" {
+		return body
+	}
+	return body + "\n\n### Match time (HH:MM)\n\n" + timeOfDay
+}
 
-### Sets (one line per set, winner’s games first)
-%s`, date, players[0], players[1], strings.Join(sets, "\n"))
+// surfaces are the court surfaces a match can be recorded as played on.
+var surfaces = []string{"hard", "clay", "grass", "carpet"}
 
-	issueRequest := &github.IssueRequest{
-		Title:  &title,
-		Body:   &body,
-		Labels: &[]string{"new-singles-match"},
+// parseSurface checks a court surface, case-insensitively, and returns it
+// in lower case.
+func parseSurface(s string) (string, error) {
+	surface := strings.ToLower(strings.TrimSpace(s))
+	if !containsString(surfaces, surface) {
+		return "", invalidf("invalid surface '%s'. Use one of %s", s, strings.Join(surfaces, ", "))
 	}
+	return surface, nil
+}
 
-	if dryRun {
-		printDryRun(title, body, issueRequest.GetLabels())
-		return nil
+// withSurface appends the optional surface section. Like the match time,
+// the issue forms don't ask for it.
+func withSurface(body, surface string) string {
+	if surface == "" {
+		return body
 	}
+	return body + "\n\n### Surface\n\n" + surface
+}
 
-	ctx := context.Background()
-	client := getGitHubClient()
+// parseTimeOfDay parses a 24-hour time such as 18:30 or 9:15 and returns
+// it as HH:MM.
+func parseTimeOfDay(s string) (string, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return "", invalidf("invalid time '%s'. Use 24-hour HH:MM, e.g. 18:30", s)
+	}
+	return t.Format("15:04"), nil
+}
 
-	fmt.Printf("Creating singles match issue...\n")
-	fmt.Printf("Title: %s\n", title)
+func createSinglesIssue(ctx context.Context, players []string, sets []string, date, timeOfDay, surface string) error {
+	title := fmt.Sprintf("Singles Match: %s vs %s (%s)", players[0], players[1], date)
 
-	issue, _, err := client.Issues.Create(ctx, owner, repo, issueRequest)
-	if err != nil {
-		return fmt.Errorf("failed to create issue: %v", err)
+	body := withSurface(singlesIssueBody(date, timeOfDay, players, sets), surface)
+
+	if !dryRun {
+		fmt.Printf("Creating singles match issue...\n")
+		fmt.Printf("Title: %s\n", title)
+	}
+
+	issue, created, err := openMatchIssueOnce(ctx, "singles", title, body, labelNames.Singles)
+	if err != nil || dryRun {
+		return err
+	}
+	if !created {
+		fmt.Printf("ℹ️  This result was already reported, so no issue was created\n")
+		fmt.Printf("Issue #%d: %s\n", issue.GetNumber(), issue.GetHTMLURL())
+		printMatchID(issue)
+		return showQR(issue.GetHTMLURL())
 	}
 
 	fmt.Printf("✅ Singles match issue created successfully!\n")
 	fmt.Printf("Issue #%d: %s\n", *issue.Number, *issue.HTMLURL)
+	printMatchID(issue)
 
-	return nil
+	return showQR(issue.GetHTMLURL())
 }
 
-func createDoublesIssue(teams [][]string, sets []string, date string) error {
+func createDoublesIssue(ctx context.Context, teams [][]string, sets []string, date, timeOfDay, surface string) error {
 	// Format teams for display
 	team1Str := fmt.Sprintf("%s, %s", teams[0][0], teams[0][1])
 	team2Str := fmt.Sprintf("%s, %s", teams[1][0], teams[1][1])
 
 	title := fmt.Sprintf("Doubles Match: (%s) vs (%s) (%s)", team1Str, team2Str, date)
 
-	body := fmt.Sprintf(`### Match date (YYYY-MM-DD)
-%s
+	body := withSurface(doublesIssueBody(date, timeOfDay, teams, sets), surface)
 
-### Teams (winner first, comma-separated @handles)
-%s || %s
-
-### Sets (one line per set, winner’s games first)
-%s`, date, team1Str, team2Str, strings.Join(sets, "\n"))
-
-	issueRequest := &github.IssueRequest{
-		Title:  &title,
-		Body:   &body,
-		Labels: &[]string{"new-doubles-match"},
+	if !dryRun {
+		fmt.Printf("Creating doubles match issue...\n")
+		fmt.Printf("Title: %s\n", title)
 	}
 
-	if dryRun {
-		printDryRun(title, body, issueRequest.GetLabels())
-		return nil
+	issue, created, err := openMatchIssueOnce(ctx, "doubles", title, body, labelNames.Doubles)
+	if err != nil || dryRun {
+		return err
 	}
-
-	ctx := context.Background()
-	client := getGitHubClient()
-
-	fmt.Printf("Creating doubles match issue...\n")
-	fmt.Printf("Title: %s\n", title)
-
-	issue, _, err := client.Issues.Create(ctx, owner, repo, issueRequest)
-	if err != nil {
-		return fmt.Errorf("failed to create issue: %v", err)
+	if !created {
+		fmt.Printf("ℹ️  This result was already reported, so no issue was created\n")
+		fmt.Printf("Issue #%d: %s\n", issue.GetNumber(), issue.GetHTMLURL())
+		printMatchID(issue)
+		return showQR(issue.GetHTMLURL())
 	}
 
 	fmt.Printf("✅ Doubles match issue created successfully!\n")
 	fmt.Printf("Issue #%d: %s\n", *issue.Number, *issue.HTMLURL)
+	printMatchID(issue)
 
-	return nil
+	return showQR(issue.GetHTMLURL())
 }
 
 func init() {
@@ -323,15 +418,21 @@ func init() {
 	singlesMatchCmd.Flags().StringP("players", "p", "", "Players separated by comma (winner first): @player_one,@player_two")
 	singlesMatchCmd.Flags().StringP("sets", "s", "", "Sets separated by comma: 6-3,4-6,6-4")
 	singlesMatchCmd.Flags().StringP("date", "d", "", "Match date (YYYY-MM-DD), defaults to today")
+	singlesMatchCmd.Flags().String("time", "", "Time the match was played (24-hour HH:MM), recorded with the result")
+	singlesMatchCmd.Flags().String("surface", "", "Court surface the match was played on: hard, clay, grass or carpet")
 
 	// Doubles command flags
 	doublesMatchCmd.Flags().StringP("teams", "t", "", "Teams separated by || : @player_one,@player_two||@player_three,@player_four")
 	doublesMatchCmd.Flags().StringP("sets", "s", "", "Sets separated by comma: 6-3,4-6,6-4")
 	doublesMatchCmd.Flags().StringP("date", "d", "", "Match date (YYYY-MM-DD), defaults to today")
+	doublesMatchCmd.Flags().String("time", "", "Time the match was played (24-hour HH:MM), recorded with the result")
+	doublesMatchCmd.Flags().String("surface", "", "Court surface the match was played on: hard, clay, grass or carpet")
 
 	// Shared flags for both match subcommands
 	matchCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the issue that would be created without creating it")
 	matchCmd.PersistentFlags().BoolVar(&noValidate, "no-validate", false, "Skip checking that player handles exist on GitHub")
+	matchCmd.PersistentFlags().BoolVar(&qrTerminal, "qr", false, "Print a QR code of the issue link, for the opponent to scan and approve")
+	matchCmd.PersistentFlags().StringVar(&qrOut, "qr-out", "", "Write a QR code of the issue link to this PNG file")
 
 	matchCmd.AddCommand(singlesMatchCmd)
 	matchCmd.AddCommand(doublesMatchCmd)
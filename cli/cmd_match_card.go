@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var matchCardCmd = &cobra.Command{
+	Use:   "card <issue>",
+	Short: "Draw a recorded match's scorecard image",
+	Long: `Draw the scorecard of a recorded match as a 1200x630 SVG image: the
+players, set scores, date and each player's rating change. It is the card
+the Pages site publishes as the match page's OpenGraph preview.
+
+Examples:
+  tennis match card 123 --out match-123.svg
+  tennis match card "#123" > card.svg`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := cmd.Flags().GetString("out")
+
+		issue, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			return invalidf("invalid issue number '%s'", args[0])
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		m, changes, ok := ratingChanges(singles, issue, replaySingles)
+		if !ok {
+			doubles, err := loadDoublesMatches(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to load matches: %w", err)
+			}
+			if m, changes, ok = ratingChanges(doubles, issue, replayDoubles); !ok {
+				return fmt.Errorf("no recorded match from issue #%d", issue)
+			}
+		}
+
+		svg := scorecardSVG(m, changes)
+		if out == "" {
+			fmt.Print(svg)
+			return nil
+		}
+		if err := os.WriteFile(out, []byte(svg), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Scorecard written to %s\n", out)
+		return nil
+	},
+}
+
+func init() {
+	matchCardCmd.Flags().StringP("out", "o", "", "Write to a file instead of stdout")
+	matchCmd.AddCommand(matchCardCmd)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var matchForfeitCmd = &cobra.Command{
+	Use:   "forfeit",
+	Short: "Record a no-show as a forfeit",
+	Long: `Create a match issue recording a forfeit: a match one side didn't turn
+up for. List the side that turned up first and the side that didn't
+second, as with --players or --teams for a result.
+
+A forfeit isn't a walkover: the absent side is charged with a no-show,
+which counts against their reliability in "tennis stats player". It's
+recorded with the default score (6-0 in every set needed to win, or 4-0
+in a league of short sets) and goes through approval like any other
+match.
+
+How the rankings treat it is up to the league (rules.forfeit in
+.tennis.yml): by default the default win is rated like any other result;
+with result: penalty nothing is rated and each absent player loses the
+penalty in rating points. Either way the win counts in the box, team and
+season standings.
+
+Examples:
+  tennis match forfeit --players "@player_one,@player_two" --date 2025-01-15
+  tennis match forfeit --teams "@player_one,@player_two||@player_three,@player_four"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		players, _ := cmd.Flags().GetString("players")
+		teams, _ := cmd.Flags().GetString("teams")
+		date, _ := cmd.Flags().GetString("date")
+
+		if (players == "") == (teams == "") {
+			return invalidf("give either --players (singles) or --teams (doubles)")
+		}
+		if date == "" {
+			date = time.Now().Format("2006-01-02")
+		}
+		if !isValidDate(date) {
+			return invalidf("invalid date format. Use YYYY-MM-DD")
+		}
+
+		kind, sides := "singles", [][]string{}
+		if players != "" {
+			list := strings.Split(players, ",")
+			if len(list) != 2 {
+				return fmt.Errorf("exactly 2 players required for a singles forfeit")
+			}
+			for _, p := range list {
+				sides = append(sides, []string{strings.TrimSpace(p)})
+			}
+		} else {
+			kind = "doubles"
+			parts := strings.Split(teams, "||")
+			if len(parts) != 2 {
+				return fmt.Errorf("exactly 2 teams required for a doubles forfeit (separated by ||)")
+			}
+			for _, team := range parts {
+				list := strings.Split(strings.TrimSpace(team), ",")
+				if len(list) != 2 {
+					return fmt.Errorf("each team must have exactly 2 players")
+				}
+				for i, p := range list {
+					list[i] = strings.TrimSpace(p)
+				}
+				sides = append(sides, list)
+			}
+		}
+
+		if err := loadRepoRules(cmd.Context()); err != nil {
+			return err
+		}
+		var sets []string
+		for _, s := range forfeitSets(rules) {
+			sets = append(sets, fmt.Sprintf("%d-%d", s[0], s[1]))
+		}
+		if err := checkRules(cmd.Context(), kind, date, sets); err != nil {
+			return err
+		}
+		if err := validateHandles(cmd.Context(), append(append([]string{}, sides[0]...), sides[1]...)); err != nil {
+			return err
+		}
+		return createForfeitIssue(cmd.Context(), kind, sides, sets, date)
+	},
+}
+
+// createForfeitIssue opens a match issue for a forfeit, in the layout of
+// the kind's issue form with the forfeit section naming the second side.
+func createForfeitIssue(ctx context.Context, kind string, sides [][]string, sets []string, date string) error {
+	var title, body, label string
+	if kind == "doubles" {
+		title = fmt.Sprintf("Doubles Match: (%s) vs (%s) (%s, forfeit)", strings.Join(sides[0], ", "), strings.Join(sides[1], ", "), date)
+		body, label = doublesIssueBody(date, "", sides, sets), labelNames.Doubles
+	} else {
+		title = fmt.Sprintf("Singles Match: %s vs %s (%s, forfeit)", sides[0][0], sides[1][0], date)
+		body, label = singlesIssueBody(date, "", []string{sides[0][0], sides[1][0]}, sets), labelNames.Singles
+	}
+	body = withForfeit(body, sides[1])
+
+	if !dryRun {
+		fmt.Printf("Creating %s forfeit issue...\n", kind)
+		fmt.Printf("Title: %s\n", title)
+	}
+	issue, created, err := openMatchIssueOnce(ctx, kind, title, body, label)
+	if err != nil || dryRun {
+		return err
+	}
+	if !created {
+		fmt.Printf("ℹ️  This forfeit was already reported, so no issue was created\n")
+		fmt.Printf("Issue #%d: %s\n", issue.GetNumber(), issue.GetHTMLURL())
+		printMatchID(issue)
+		return showQR(issue.GetHTMLURL())
+	}
+	fmt.Printf("✅ Forfeit issue created: %s didn't turn up\n", strings.Join(sides[1], " & "))
+	fmt.Printf("Issue #%d: %s\n", issue.GetNumber(), issue.GetHTMLURL())
+	printMatchID(issue)
+	return showQR(issue.GetHTMLURL())
+}
+
+func init() {
+	matchForfeitCmd.Flags().StringP("players", "p", "", "Singles players separated by comma (the one who turned up first): @player_one,@player_two")
+	matchForfeitCmd.Flags().StringP("teams", "t", "", "Doubles teams separated by || (the team that turned up first)")
+	matchForfeitCmd.Flags().StringP("date", "d", "", "Date the match was due (YYYY-MM-DD), defaults to today")
+
+	matchCmd.AddCommand(matchForfeitCmd)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var matchUndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Void the match you just reported",
+	Long: `Void the match issue you reported most recently, e.g. after a typo in
+the score. Its issue is closed as not planned and its pull request closed
+unmerged, each with a comment recording who voided it and when, so the
+match is never recorded.
+
+A match can only be undone by its reporter, within the league's undo window
+(rules.undo_window in .tennis.yml, default 15m) and before any other player
+has approved it. After that, ask an admin to close it or report a
+correction.
+
+Examples:
+  tennis match undo
+  tennis match undo --issue 42 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, _ := cmd.Flags().GetInt("issue")
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		if err := loadRepoRules(ctx); err != nil {
+			return err
+		}
+		user, _, err := client.Users.Get(ctx, "")
+		if err != nil {
+			return fmt.Errorf("failed to look up the authenticated user: %w", err)
+		}
+		me := normalizePlayer(user.GetLogin())
+
+		var issue *github.Issue
+		if number != 0 {
+			if issue, _, err = client.Issues.Get(ctx, owner, repo, number); err != nil {
+				return fmt.Errorf("failed to fetch #%d: %w", number, err)
+			}
+			if _, _, ok := matchIssueKind(issue); !ok {
+				return fmt.Errorf("#%d is not a match issue", number)
+			}
+		} else if issue, err = latestMatchIssue(ctx, client, user.GetLogin()); err != nil {
+			return err
+		}
+		number = issue.GetNumber()
+
+		if reporter := normalizePlayer(issue.GetUser().GetLogin()); reporter != me {
+			return fmt.Errorf("#%d was reported by @%s; only the reporter can undo a match", number, reporter)
+		}
+		if issue.GetState() != "open" {
+			return fmt.Errorf("#%d is already closed", number)
+		}
+		age := time.Since(issue.GetCreatedAt().Time)
+		if window := rules.undoWindow(); age > window {
+			return fmt.Errorf("#%d was reported %s ago, after the %s undo window; ask an admin to close it", number, age.Round(time.Minute), window)
+		}
+
+		pr, err := matchPullRequest(ctx, client, number)
+		if err != nil {
+			return err
+		}
+		prNumber := 0
+		if pr != nil {
+			if pr.MergedAt != nil {
+				return fmt.Errorf("#%d has already been recorded (PR #%d merged)", number, pr.GetNumber())
+			}
+			if pr.GetState() == "open" {
+				prNumber = pr.GetNumber()
+			}
+		}
+		approved, err := playerApprovals(ctx, client, number, prNumber)
+		if err != nil {
+			return err
+		}
+		for p, ok := range approved {
+			if ok && p != me {
+				return fmt.Errorf("@%s has already approved #%d; ask an admin to close it", p, number)
+			}
+		}
+
+		fmt.Printf("Voiding #%d: %s\n", number, issue.GetTitle())
+		if err := confirm("Void match #%d?", number); err != nil {
+			return err
+		}
+		comment := fmt.Sprintf("↩️ Match voided by @%s with `tennis match undo`, %s after it was reported and before any other player approved it. It won't be recorded.", me, age.Round(time.Second))
+		if err := closeUnrecordedMatch(ctx, client, number, prNumber, "voided", comment); err != nil {
+			return err
+		}
+		if !dryRun {
+			fmt.Printf("✅ Match #%d voided\n", number)
+		}
+		return nil
+	},
+}
+
+// latestMatchIssue returns the match issue login opened most recently.
+func latestMatchIssue(ctx context.Context, client *github.Client, login string) (*github.Issue, error) {
+	issues, _, err := client.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{
+		Creator:     login,
+		State:       "all",
+		Sort:        "created",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 30},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list your issues: %w", err)
+	}
+	for _, issue := range issues {
+		if kind, _, ok := matchIssueKind(issue); ok && kind != "conflict" && !issue.IsPullRequest() {
+			return issue, nil
+		}
+	}
+	return nil, fmt.Errorf("you haven't reported any matches in %s/%s recently", owner, repo)
+}
+
+// closeUnrecordedMatch closes a match issue as not planned with comment,
+// and its open pull request (pr, or 0) unmerged, noting the match was how
+// (e.g. voided).
+func closeUnrecordedMatch(ctx context.Context, client *github.Client, issue, pr int, how, comment string) error {
+	if dryRun {
+		fmt.Printf("[dry-run] would close #%d as not planned: %s\n", issue, comment)
+		if pr != 0 {
+			fmt.Printf("[dry-run] would close PR #%d unmerged\n", pr)
+		}
+		return nil
+	}
+
+	if _, err := commentOnce(ctx, client, issue, comment, how); err != nil {
+		return err
+	}
+	closed, notPlanned := "closed", "not_planned"
+	if _, _, err := client.Issues.Edit(ctx, owner, repo, issue, &github.IssueRequest{State: &closed, StateReason: &notPlanned}); err != nil {
+		return fmt.Errorf("failed to close #%d: %w", issue, err)
+	}
+	if pr == 0 {
+		return nil
+	}
+	if _, err := commentOnce(ctx, client, pr, fmt.Sprintf("Match #%d was %s, so this pull request is closed without recording it.", issue, how), how); err != nil {
+		return err
+	}
+	if _, _, err := client.PullRequests.Edit(ctx, owner, repo, pr, &github.PullRequest{State: &closed}); err != nil {
+		return fmt.Errorf("failed to close PR #%d: %w", pr, err)
+	}
+	return nil
+}
+
+func init() {
+	matchUndoCmd.Flags().Int("issue", 0, "Undo this match issue instead of your most recent one")
+	matchUndoCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be voided without changing anything")
+
+	matchCmd.AddCommand(matchUndoCmd)
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var matchmakeCmd = &cobra.Command{
+	Use:   "matchmake",
+	Short: "Pair active players into weekly fixtures",
+	Long: `Pair all active players into balanced singles fixtures for a week,
+create a challenge issue for each pairing, and post a summary issue.
+
+Players are paired with the closest-rated opponent they haven't played
+recently. With an odd number of players the lowest-rated unpaired player
+gets a bye.
+
+Active players are the active players on the roster (players.yml), or, if
+there is no roster, everyone who has played a singles match in the last 12
+weeks.
+
+With --category, only the players in that category are paired, on their
+ratings from the matches they played against each other, so each of the
+league's competitions can be matchmade separately.
+
+Examples:
+  tennis matchmake --week 2025-W07
+  tennis matchmake --week 2025-W07 --avoid-weeks 6 --dry-run
+  tennis matchmake --time 18:30
+  tennis matchmake --week 2025-W07 --category veterans`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		week, _ := cmd.Flags().GetString("week")
+		avoidWeeks, _ := cmd.Flags().GetInt("avoid-weeks")
+		timeOfDay, _ := cmd.Flags().GetString("time")
+		category, _ := cmd.Flags().GetString("category")
+
+		if week == "" {
+			y, w := time.Now().ISOWeek()
+			week = fmt.Sprintf("%d-W%02d", y, w)
+		}
+		start, err := parseISOWeek(week)
+		if err != nil {
+			return err
+		}
+		if timeOfDay != "" {
+			if timeOfDay, err = parseTimeOfDay(timeOfDay); err != nil {
+				return err
+			}
+		}
+
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		recent := recentOpponents(matches, start.AddDate(0, 0, -7*avoidWeeks), start)
+		var players []string
+		if category == "" {
+			if players, err = activePlayers(matches, start); err != nil {
+				return err
+			}
+		} else {
+			roster, err := loadRoster()
+			if err != nil {
+				return err
+			}
+			if category, roster, err = forCategory(category, roster, &matches); err != nil {
+				return err
+			}
+			players = roster.AvailableHandles(start.Format("2006-01-02"))
+		}
+		if len(players) < 2 {
+			return fmt.Errorf("need at least 2 active players to matchmake, found %d", len(players))
+		}
+
+		ratings := computeSinglesRatings(matches)
+		pairs, bye := pairPlayers(players, ratings, recent)
+
+		return createFixtures(cmd.Context(), week, category, start, timeOfDay, pairs, bye, ratings)
+	},
+}
+
+var isoWeekRegex = regexp.MustCompile(`^(\d{4})-W(\d{2})$`)
+
+// parseISOWeek parses an ISO week such as "2025-W07" and returns the
+// Monday it starts on.
+func parseISOWeek(week string) (time.Time, error) {
+	m := isoWeekRegex.FindStringSubmatch(week)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("invalid week '%s'. Use format like '2025-W07'", week)
+	}
+	year, _ := strconv.Atoi(m[1])
+	w, _ := strconv.Atoi(m[2])
+
+	// January 4th is always in ISO week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7 // days since Monday
+	monday := jan4.AddDate(0, 0, -offset+(w-1)*7)
+
+	if y, got := monday.ISOWeek(); y != year || got != w {
+		return time.Time{}, fmt.Errorf("invalid week '%s': %d has no week %d", week, year, w)
+	}
+	return monday, nil
+}
+
+// activePlayers returns the active players on the league roster who
+// aren't away on the given date, falling back to everyone who played a
+// singles match in the 12 weeks before it when there is no roster.
+func activePlayers(matches []Match, before time.Time) ([]string, error) {
+	roster, err := loadRoster()
+	if err != nil {
+		return nil, err
+	}
+	if len(roster.Players) > 0 {
+		return roster.AvailableHandles(before.Format("2006-01-02")), nil
+	}
+
+	since := before.AddDate(0, 0, -7*12).Format("2006-01-02")
+	until := before.Format("2006-01-02")
+	seen := make(map[string]bool)
+	var players []string
+	for _, m := range matches {
+		if m.Date < since || m.Date >= until {
+			continue
+		}
+		for _, p := range m.Players {
+			if !seen[p] {
+				seen[p] = true
+				players = append(players, p)
+			}
+		}
+	}
+	return players, nil
+}
+
+// recentOpponents returns the set of player pairs who played a singles
+// match in [from, to), keyed by pairKey.
+func recentOpponents(matches []Match, from, to time.Time) map[string]bool {
+	lo, hi := from.Format("2006-01-02"), to.Format("2006-01-02")
+	recent := make(map[string]bool)
+	for _, m := range matches {
+		if len(m.Players) == 2 && m.Date >= lo && m.Date < hi {
+			recent[pairKey(m.Players[0], m.Players[1])] = true
+		}
+	}
+	return recent
+}
+
+// pairKey is an order-independent key for two players.
+func pairKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "|" + b
+}
+
+// pairPlayers greedily pairs players, strongest first, each with the
+// closest-rated remaining opponent they haven't recently played. If every
+// remaining opponent is a recent one, the closest-rated is used anyway.
+// Returns the pairs and the player left with a bye ("" if none).
+func pairPlayers(players []string, ratings map[string]float64, recent map[string]bool) ([][2]string, string) {
+	pool := append([]string{}, players...)
+	sort.SliceStable(pool, func(i, j int) bool {
+		return rating(ratings, pool[i]) > rating(ratings, pool[j])
+	})
+
+	var pairs [][2]string
+	for len(pool) > 1 {
+		p := pool[0]
+		rest := pool[1:]
+
+		best := -1
+		fallback := -1
+		for i, q := range rest {
+			diff := math.Abs(rating(ratings, p) - rating(ratings, q))
+			if fallback == -1 || diff < math.Abs(rating(ratings, p)-rating(ratings, rest[fallback])) {
+				fallback = i
+			}
+			if recent[pairKey(p, q)] {
+				continue
+			}
+			if best == -1 || diff < math.Abs(rating(ratings, p)-rating(ratings, rest[best])) {
+				best = i
+			}
+		}
+		if best == -1 {
+			best = fallback
+		}
+
+		pairs = append(pairs, [2]string{p, rest[best]})
+		pool = append(append([]string{}, rest[:best]...), rest[best+1:]...)
+	}
+
+	bye := ""
+	if len(pool) == 1 {
+		bye = pool[0]
+	}
+	return pairs, bye
+}
+
+// createFixtures opens one challenge issue per pairing and a summary issue
+// linking them all. A non-empty timeOfDay is the suggested start time, and
+// a non-empty category the competition the fixtures are for.
+func createFixtures(ctx context.Context, week, category string, start time.Time, timeOfDay string, pairs [][2]string, bye string, ratings map[string]float64) error {
+	end := start.AddDate(0, 0, 6)
+	span := fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	label, fixtures := week, "Weekly fixtures"
+	if category != "" {
+		label, fixtures = week+", "+category, "Weekly "+category+" fixtures"
+	}
+
+	var lines []string
+	for _, pair := range pairs {
+		p1, p2 := "@"+pair[0], "@"+pair[1]
+		title := fmt.Sprintf("Challenge: %s vs %s (%s)", p1, p2, label)
+		body := fmt.Sprintf(`### Fixture week
+%s (%s)
+
+### Players
+%s (%.0f), %s (%.0f)
+`, week, span, p1, rating(ratings, pair[0]), p2, rating(ratings, pair[1]))
+		if category != "" {
+			body += fmt.Sprintf("\n### Category\n%s\n", category)
+		}
+		if timeOfDay != "" {
+			body += fmt.Sprintf("\n### Match time\n%s\n", timeOfDay)
+		}
+		body += "\nPlay your match this week, then record the result with `tennis match singles` or the singles match issue form."
+
+		number, err := openIssue(ctx, title, body, []string{labelNames.Challenge})
+		if err != nil {
+			return err
+		}
+		if number == 0 {
+			lines = append(lines, fmt.Sprintf("- %s vs %s", p1, p2))
+		} else {
+			lines = append(lines, fmt.Sprintf("- %s vs %s — #%d", p1, p2, number))
+		}
+	}
+
+	if bye != "" {
+		lines = append(lines, fmt.Sprintf("- @%s has a bye this week", bye))
+	}
+
+	title := fmt.Sprintf("%s: %s", fixtures, week)
+	body := fmt.Sprintf("Fixtures for %s (%s):\n\n%s", week, span, strings.Join(lines, "\n"))
+	number, err := openIssue(ctx, title, body, []string{labelNames.Matchmaking})
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		fmt.Printf("✅ %d fixtures created for %s (summary #%d)\n", len(pairs), week, number)
+	}
+	return nil
+}
+
+func init() {
+	matchmakeCmd.Flags().String("week", "", "ISO week to schedule, e.g. 2025-W07 (defaults to the current week)")
+	matchmakeCmd.Flags().Int("avoid-weeks", 4, "Avoid pairing players who met within this many weeks")
+	matchmakeCmd.Flags().String("category", "", "Pair only the players in this category")
+	matchmakeCmd.Flags().String("time", "", "Suggested start time for every fixture (24-hour HH:MM)")
+	matchmakeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the issues that would be created without creating them")
+
+	rootCmd.AddCommand(matchmakeCmd)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// matchIDRegex matches a match ID, or any longer prefix of a fingerprint.
+var matchIDRegex = regexp.MustCompile(`^[0-9a-f]{6,16}$`)
+
+var permalinkCmd = &cobra.Command{
+	Use:   "permalink <id>",
+	Short: "Print the issue a match ID refers to",
+	Long: `Print the link to the issue of the match with this ID. A match's ID is
+the start of its result's fingerprint, a hash of the players, date and
+sets, printed when its issue is opened (e.g. "Match ID: 3f9a0c12"). It
+doesn't depend on the issue number, so it keeps referring to the match
+after the issues move to another repository.
+
+The recorded match files in the league checkout are searched first, then
+the match issues on GitHub, so matches not yet recorded are found too. Any
+prefix of the fingerprint of at least 6 characters will do, as long as only
+one match starts with it.
+
+Examples:
+  tennis permalink 3f9a0c12
+  open "$(tennis permalink 3f9a0c12)"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := strings.ToLower(strings.TrimSpace(args[0]))
+		if !matchIDRegex.MatchString(id) {
+			return invalidf("invalid match ID '%s'. Use the ID printed when the match was reported, like 3f9a0c12", args[0])
+		}
+
+		// Fingerprint to issue links; one match can have a file and an issue.
+		found := make(map[string][]string)
+		add := func(fp, link string) {
+			if strings.HasPrefix(fp, id) && !containsString(found[fp], link) {
+				found[fp] = append(found[fp], link)
+			}
+		}
+		for _, dir := range []string{"singles-matches", "doubles-matches"} {
+			files, err := filepath.Glob(filepath.Join(leagueDir(), dir, "*.yml"))
+			if err != nil {
+				return err
+			}
+			for _, fn := range files {
+				data, err := os.ReadFile(fn)
+				if err != nil {
+					return err
+				}
+				// Handles as recorded, not renamed by aliases, as the
+				// fingerprint was taken.
+				m, err := parseMatchFile(fn, data, nil)
+				if err != nil || m.SourceIssue == 0 {
+					continue
+				}
+				source := owner + "/" + repo
+				if m.Repo != "" {
+					source = m.Repo
+				}
+				add(matchFingerprint(m), fmt.Sprintf("https://github.com/%s/issues/%d", source, m.SourceIssue))
+			}
+		}
+
+		if len(found) == 0 {
+			ctx := cmd.Context()
+			client := getGitHubClient()
+			for _, label := range []string{labelNames.Singles, labelNames.Doubles} {
+				issues, err := listLabelledIssues(ctx, client, label, issueFilter{State: "all"})
+				if err != nil {
+					return err
+				}
+				// Issues closed as not planned were reported again, if at all.
+				for _, issue := range issues {
+					if !issue.IsPullRequest() && issue.GetStateReason() != "not_planned" {
+						add(issueFingerprint(issue), issue.GetHTMLURL())
+					}
+				}
+			}
+		}
+
+		switch len(found) {
+		case 0:
+			return fmt.Errorf("no match with ID %s in %s/%s", id, owner, repo)
+		case 1:
+			for _, links := range found {
+				for _, link := range links {
+					fmt.Println(link)
+				}
+			}
+			return nil
+		}
+		var ids []string
+		for fp := range found {
+			ids = append(ids, fp)
+		}
+		sort.Strings(ids)
+		return invalidf("match ID %s is ambiguous: it starts %s; give more of it", id, strings.Join(ids, ", "))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(permalinkCmd)
+}
@@ -0,0 +1,662 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var playerCmd = &cobra.Command{
+	Use:   "player",
+	Short: "Manage the league roster",
+	Long: `Manage the league roster in players.yml.
+
+The roster is the source of truth for who is in the league: matchmaking,
+box leagues, seeding and the leaderboard use it instead of inferring players
+from match issues. Edit it with these commands, then commit players.yml.`,
+}
+
+var playerAddCmd = &cobra.Command{
+	Use:   "add <@handle>",
+	Short: "Add a player to the roster",
+	Long: `Add a player to the roster.
+
+Examples:
+  tennis player add @player_one --name "Player One"
+  tennis player add @player_two --joined 2025-01-15
+  tennis player add @player_three --division 2
+  tennis player add @player_four --category open --category women`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		joined, _ := cmd.Flags().GetString("joined")
+		division, _ := cmd.Flags().GetInt("division")
+		categories, _ := cmd.Flags().GetStringSlice("category")
+
+		handle := normalizePlayer(args[0])
+		if handle == "" {
+			return invalidf("empty player handle")
+		}
+		if joined == "" {
+			joined = time.Now().Format("2006-01-02")
+		}
+		if !isValidDate(joined) {
+			return invalidf("invalid join date. Use YYYY-MM-DD")
+		}
+		if division < 0 {
+			return invalidf("invalid division %d. Use 1 for the top division", division)
+		}
+		for i, c := range categories {
+			normalized, err := normalizeCategory(c)
+			if err != nil {
+				return err
+			}
+			categories[i] = normalized
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		if roster.Find(handle) != nil {
+			return fmt.Errorf("@%s is already on the roster", handle)
+		}
+
+		if err := validateHandles(cmd.Context(), []string{handle}); err != nil {
+			return err
+		}
+
+		roster.Players = append(roster.Players, RosterPlayer{
+			Handle:     handle,
+			Name:       name,
+			Joined:     joined,
+			Status:     playerActive,
+			Division:   division,
+			Categories: categories,
+		})
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+
+		fmt.Printf("✅ Added @%s to %s\n", handle, rosterPath())
+		return nil
+	},
+}
+
+var playerCategoryCmd = &cobra.Command{
+	Use:   "category <@handle> <category>...",
+	Short: "Put a player in competition categories",
+	Long: `Add a player to categories, or take them out with --remove. Categories
+let one league run several competitions at once, such as open, women and
+veterans: "rankings compute", "standings export" and "matchmake" take
+--category to rank or pair only that category's players, counting only the
+matches they played against each other.
+
+Categories are saved in players.yml; commit it afterwards.
+
+Examples:
+  tennis player category @player_one open veterans
+  tennis player category @player_one veterans --remove`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		remove, _ := cmd.Flags().GetBool("remove")
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		p := roster.Find(args[0])
+		if p == nil {
+			return fmt.Errorf("@%s is not on the roster", normalizePlayer(args[0]))
+		}
+
+		for _, arg := range args[1:] {
+			c, err := normalizeCategory(arg)
+			if err != nil {
+				return err
+			}
+			switch {
+			case remove && p.InCategory(c):
+				var kept []string
+				for _, have := range p.Categories {
+					if have != c {
+						kept = append(kept, have)
+					}
+				}
+				p.Categories = kept
+			case !remove && !p.InCategory(c):
+				p.Categories = append(p.Categories, c)
+			}
+		}
+		sort.Strings(p.Categories)
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+
+		if len(p.Categories) == 0 {
+			fmt.Printf("✅ @%s is in no categories\n", p.Handle)
+			return nil
+		}
+		fmt.Printf("✅ @%s is in %s\n", p.Handle, strings.Join(p.Categories, ", "))
+		return nil
+	},
+}
+
+var playerRemoveCmd = &cobra.Command{
+	Use:   "remove <@handle>",
+	Short: "Remove a player from the roster",
+	Long: `Remove a player from the roster. Their recorded matches are kept and
+still count towards their opponents' ratings.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+
+		handle := normalizePlayer(args[0])
+		if !roster.Remove(handle) {
+			return fmt.Errorf("@%s is not on the roster", handle)
+		}
+		if err := confirm("Remove @%s from the roster?", handle); err != nil {
+			return err
+		}
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+
+		fmt.Printf("✅ Removed @%s from %s\n", handle, rosterPath())
+		return nil
+	},
+}
+
+var playerDeactivateCmd = &cobra.Command{
+	Use:   "deactivate <@handle>",
+	Short: "Mark a player as retired or inactive",
+	Long: `Mark a player inactive. Inactive players drop off the public leaderboard
+and out of matchmaking, box leagues and seeding, but their historical matches
+still count towards everyone's ratings. Use "player activate" to bring them
+back.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPlayerStatus(args[0], playerInactive)
+	},
+}
+
+var playerActivateCmd = &cobra.Command{
+	Use:   "activate <@handle>",
+	Short: "Return an inactive player to the league",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setPlayerStatus(args[0], playerActive)
+	},
+}
+
+// setPlayerStatus updates a rostered player's status and saves the roster.
+func setPlayerStatus(handle, status string) error {
+	roster, err := loadRoster()
+	if err != nil {
+		return err
+	}
+
+	p := roster.Find(handle)
+	if p == nil {
+		return fmt.Errorf("@%s is not on the roster", normalizePlayer(handle))
+	}
+	if p.Status == status {
+		return fmt.Errorf("@%s is already %s", p.Handle, status)
+	}
+	p.Status = status
+
+	if err := saveRoster(roster); err != nil {
+		return fmt.Errorf("failed to save roster: %w", err)
+	}
+	fmt.Printf("✅ @%s is now %s\n", p.Handle, status)
+	return nil
+}
+
+var playerRenameCmd = &cobra.Command{
+	Use:   "rename <@old> <@new>",
+	Short: "Move a player to a new GitHub handle",
+	Long: `Rename a player after a GitHub handle change.
+
+The roster entry moves to the new handle and the old handle is recorded in
+aliases.yml, so historical match issues and files that still use the old
+handle count towards the same player in ratings, tournaments and box leagues.
+Commit players.yml and aliases.yml afterwards.
+
+Example:
+  tennis player rename @old_handle @new_handle`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to := normalizePlayer(args[0]), normalizePlayer(args[1])
+		if from == "" || to == "" {
+			return invalidf("empty player handle")
+		}
+		if from == to {
+			return fmt.Errorf("old and new handles are the same")
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		p := roster.Find(from)
+		if p == nil {
+			return fmt.Errorf("@%s is not on the roster", from)
+		}
+		if roster.Find(to) != nil {
+			return fmt.Errorf("@%s is already on the roster", to)
+		}
+
+		if err := validateHandles(cmd.Context(), []string{to}); err != nil {
+			return err
+		}
+
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		p.Handle = to
+		addAlias(aliases, from, to)
+
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+		if err := saveAliases(aliases); err != nil {
+			return fmt.Errorf("failed to save aliases: %w", err)
+		}
+
+		fmt.Printf("✅ Renamed @%s to @%s\n", from, to)
+		fmt.Printf("Matches recorded as @%s now count for @%s (%s)\n", from, to, aliasesPath())
+		return nil
+	},
+}
+
+var playerMergeCmd = &cobra.Command{
+	Use:   "merge <@duplicate> <@real>",
+	Short: "Fold a misspelled or duplicate handle into a real player",
+	Long: `Merge two identities, e.g. when a match was recorded under a misspelled
+handle and the typo now shows up on the leaderboard as its own player.
+
+The duplicate handle is aliased to the real one in aliases.yml, so every match
+recorded under it counts for the real player, and it is dropped from the
+roster. The merge is logged in merges.yml. Commit players.yml, aliases.yml
+and merges.yml afterwards.
+
+Example:
+  tennis player merge @plyer_one @player_one --reason "typo in issue #42"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reason, _ := cmd.Flags().GetString("reason")
+
+		from, to := normalizePlayer(args[0]), normalizePlayer(args[1])
+		if from == "" || to == "" {
+			return invalidf("empty player handle")
+		}
+
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		if resolveAlias(aliases, to) != to {
+			return fmt.Errorf("@%s was renamed or merged into @%s; merge into that handle instead", to, resolveAlias(aliases, to))
+		}
+		if resolveAlias(aliases, from) == to || from == to {
+			return fmt.Errorf("@%s is already the same player as @%s", from, to)
+		}
+
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		for _, m := range append(singles, doubles...) {
+			if matchInvolves(m, from) && matchInvolves(m, to) {
+				return fmt.Errorf("@%s and @%s appear in the same match (issue #%d), so they are different players", from, to, m.SourceIssue)
+			}
+		}
+		rec := MergeRecord{
+			From:    from,
+			To:      to,
+			Date:    time.Now().Format("2006-01-02"),
+			Reason:  reason,
+			Singles: countMatchesWith(singles, from),
+			Doubles: countMatchesWith(doubles, from),
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		if dup := roster.Find(from); dup != nil {
+			if roster.Find(to) == nil {
+				// The real player was never rostered: keep the entry under the real handle.
+				dup.Handle = to
+			} else {
+				roster.Remove(from)
+			}
+		}
+		addAlias(aliases, from, to)
+
+		fmt.Printf("Merging @%s into @%s: %d singles and %d doubles matches reassigned\n",
+			from, to, rec.Singles, rec.Doubles)
+		if dryRun {
+			return nil
+		}
+		if err := confirm("Merge @%s into @%s?", from, to); err != nil {
+			return err
+		}
+
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+		if err := saveAliases(aliases); err != nil {
+			return fmt.Errorf("failed to save aliases: %w", err)
+		}
+		if err := appendMergeRecord(rec); err != nil {
+			return fmt.Errorf("failed to record merge: %w", err)
+		}
+		fmt.Printf("✅ Merged @%s into @%s (logged in %s)\n", from, to, mergesPath())
+		return nil
+	},
+}
+
+var playerForgetCmd = &cobra.Command{
+	Use:   "forget <@handle>",
+	Short: "Erase a departing player's personal data",
+	Long: `Erase a departing player's personal data, e.g. when they ask to be
+forgotten. Their roster entry (name, profile and the rest) is deleted, and
+their handle, including any old handles in aliases.yml, is replaced with an
+anonymous placeholder such as former-player-1 in every data file: match
+files, merges, divisions, box leagues, tournaments, rankings snapshots and
+.tennis.yml. Their matches stay, under the placeholder, so their opponents'
+ratings and records don't change.
+
+The Pages site is rebuilt from these files once they're committed. Exports
+made earlier, such as season archives, standings exports and release
+assets, need regenerating; match issues and pull requests on GitHub aren't
+changed.
+
+Examples:
+  tennis player forget @player_one --dry-run
+  tennis player forget @player_one`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		handle := resolveAlias(aliases, normalizePlayer(args[0]))
+		if handle == "" {
+			return invalidf("empty player handle")
+		}
+		handles := []string{handle}
+		for old := range aliases {
+			if old != handle && resolveAlias(aliases, old) == handle {
+				handles = append(handles, old)
+			}
+		}
+		sort.Strings(handles[1:])
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		matches := append(singles, doubles...)
+		placeholder := forgetPlaceholder(func(h string) bool {
+			_, aliased := aliases[h]
+			return roster.Find(h) != nil || aliased || countMatchesWith(matches, h) > 0
+		})
+
+		s := newScrubber(handles, placeholder)
+		files, err := forgetFiles()
+		if err != nil {
+			return err
+		}
+		var scrub []string
+		for _, f := range files {
+			found, err := s.File(f, false)
+			if err != nil {
+				return err
+			}
+			if found {
+				scrub = append(scrub, f)
+			}
+		}
+		rostered := roster.Remove(handle)
+		if !rostered && len(scrub) == 0 {
+			return fmt.Errorf("@%s isn't on the roster or in any data file", handle)
+		}
+
+		fmt.Printf("Forgetting @%s as %s:\n", strings.Join(handles, ", @"), placeholder)
+		if rostered {
+			fmt.Printf("  delete their entry in %s\n", rosterPath())
+		}
+		if len(handles) > 1 {
+			fmt.Printf("  remove their aliases from %s\n", aliasesPath())
+		}
+		for _, f := range scrub {
+			fmt.Printf("  scrub %s\n", f)
+		}
+		if dryRun {
+			return nil
+		}
+		if err := confirm("Forget @%s? This can't be undone", handle); err != nil {
+			return err
+		}
+
+		if rostered {
+			if err := saveRoster(roster); err != nil {
+				return fmt.Errorf("failed to save roster: %w", err)
+			}
+		}
+		if len(handles) > 1 {
+			for _, h := range handles {
+				delete(aliases, h)
+			}
+			if err := saveAliases(aliases); err != nil {
+				return fmt.Errorf("failed to save aliases: %w", err)
+			}
+		}
+		for _, f := range scrub {
+			if _, err := s.File(f, true); err != nil {
+				return fmt.Errorf("failed to scrub %s: %w", f, err)
+			}
+		}
+		fmt.Printf("✅ @%s forgotten; their matches are now recorded as %s\n", handle, placeholder)
+		fmt.Println("Commit the changes so the Pages site is rebuilt, and regenerate any earlier exports")
+		return nil
+	},
+}
+
+var playerImportRatingCmd = &cobra.Command{
+	Use:   "import-rating <@handle>",
+	Short: "Start a newcomer from their rating in another league",
+	Long: `Start a newcomer from their standing in another tennis league instead of
+the initial rating. Their ratings are read from the other league's
+published rankings (its GitHub Pages site, or --url) and carried over as
+their distance from that league's initial rating.
+
+The imported ratings are provisional: they are saved in players.yml as the
+player's baseline, with the league and date they came from, and the
+player's matches here move them like any other rating. Only rostered
+players with no matches here yet can import one.
+
+Examples:
+  tennis player import-rating @player_one --from other-club/tennis
+  tennis player import-rating @player_one --from other-club/tennis --url https://tennis.other-club.org/`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		url, _ := cmd.Flags().GetString("url")
+
+		if from == "" {
+			return invalidf("--from is required")
+		}
+		o, name, ok := strings.Cut(from, "/")
+		if !ok || o == "" || name == "" || strings.Contains(name, "/") {
+			return invalidf("--from must be the other league's repository as owner/name")
+		}
+		if strings.EqualFold(from, owner+"/"+repo) {
+			return invalidf("--from is this league's repository")
+		}
+
+		handle := normalizePlayer(args[0])
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		p := roster.Find(handle)
+		if p == nil {
+			return fmt.Errorf("@%s is not on the roster; add them first with tennis player add", handle)
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		if formGuide(singles)[handle]+formGuide(doubles)[handle] != "" {
+			return fmt.Errorf("@%s already has recorded matches here; only newcomers can import a rating", handle)
+		}
+
+		if url == "" {
+			url = repoPagesURL(ctx, o, name)
+		}
+		url = strings.TrimSuffix(url, "/") + "/"
+		published, err := fetchPublishedRankings(ctx, url)
+		if err != nil {
+			return err
+		}
+		// Carry the standing over relative to each league's starting point.
+		theirInitial := defaultElo.InitialRating
+		if published.Elo != nil && published.Elo.InitialRating > 0 {
+			theirInitial = published.Elo.InitialRating
+		}
+		imported := &importedRating{From: from, Date: time.Now().Format("2006-01-02")}
+		for _, kind := range []struct {
+			rows []LeaderboardRow
+			to   *float64
+		}{{published.Singles, &imported.Singles}, {published.DoublesIndividual, &imported.Doubles}} {
+			for _, r := range kind.rows {
+				if normalizePlayer(r.Player) == handle {
+					*kind.to = math.Round(max(1, elo.InitialRating+r.Rating-theirInitial)*10) / 10
+				}
+			}
+		}
+		if imported.Singles == 0 && imported.Doubles == 0 {
+			return fmt.Errorf("@%s is not on %s's published leaderboard (%s)", handle, from, url)
+		}
+
+		var starts []string
+		if imported.Singles > 0 {
+			starts = append(starts, fmt.Sprintf("%.1f in singles", imported.Singles))
+		}
+		if imported.Doubles > 0 {
+			starts = append(starts, fmt.Sprintf("%.1f in doubles", imported.Doubles))
+		}
+		if dryRun {
+			fmt.Printf("[dry-run] would start @%s at %s, imported from %s\n", handle, strings.Join(starts, " and "), from)
+			return nil
+		}
+		p.Imported = imported
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+		fmt.Printf("✅ @%s starts at %s, imported from %s. Commit %s.\n", handle, strings.Join(starts, " and "), from, rosterPath())
+		return nil
+	},
+}
+
+var playerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List players on the roster",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		all, _ := cmd.Flags().GetBool("all")
+
+		if len(roster.Players) == 0 {
+			fmt.Printf("No players on the roster (%s)\n", rosterPath())
+			return nil
+		}
+
+		divisions, _ := divisionMembers(roster)
+		fmt.Printf("%-22s %-24s %-10s %-8s", "Handle", "Name", "Joined", "Status")
+		if len(divisions) > 0 {
+			fmt.Print(" Division")
+		}
+		fmt.Println()
+		for _, p := range roster.Players {
+			if !all && !p.IsActive() {
+				continue
+			}
+			fmt.Printf("%-22s %-24s %-10s %-8s", "@"+p.Handle, p.Name, p.Joined, p.Status)
+			if len(divisions) > 0 && p.Division > 0 {
+				fmt.Printf(" %d", p.Division)
+			}
+			if len(p.Categories) > 0 {
+				fmt.Printf("  [%s]", strings.Join(p.Categories, ", "))
+			}
+			if p.Imported != nil {
+				fmt.Printf("  (rating imported from %s)", p.Imported.From)
+			}
+			if today := time.Now().Format("2006-01-02"); p.Away != nil && p.Away.Until > today {
+				fmt.Printf("  (away %s to %s)", p.Away.From, p.Away.Until)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+func init() {
+	playerAddCmd.Flags().String("name", "", "Display name")
+	playerAddCmd.Flags().String("joined", "", "Join date (YYYY-MM-DD), defaults to today")
+	playerAddCmd.Flags().Int("division", 0, "Division to put the player in (1 is the top)")
+	playerAddCmd.Flags().StringSlice("category", nil, "Category the player competes in (repeatable)")
+	playerCategoryCmd.Flags().Bool("remove", false, "Take the player out of the categories")
+	playerListCmd.Flags().Bool("all", false, "Include inactive players")
+	playerMergeCmd.Flags().String("reason", "", "Why the identities are being merged, kept in merges.yml")
+	playerMergeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be merged without saving files")
+	playerForgetCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List what would be erased without changing any files")
+	playerImportRatingCmd.Flags().String("from", "", "The other league's repository, as owner/name")
+	playerImportRatingCmd.Flags().String("url", "", "The other league's Pages site, if not the repository's default")
+	playerImportRatingCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the imported ratings without saving them")
+	playerAddCmd.Flags().BoolVar(&noValidate, "no-validate", false, "Skip checking that the handle exists on GitHub")
+	playerRenameCmd.Flags().BoolVar(&noValidate, "no-validate", false, "Skip checking that the new handle exists on GitHub")
+
+	playerCmd.AddCommand(playerAddCmd)
+	playerCmd.AddCommand(playerRemoveCmd)
+	playerCmd.AddCommand(playerCategoryCmd)
+	playerCmd.AddCommand(playerDeactivateCmd)
+	playerCmd.AddCommand(playerActivateCmd)
+	playerCmd.AddCommand(playerRenameCmd)
+	playerCmd.AddCommand(playerMergeCmd)
+	playerCmd.AddCommand(playerForgetCmd)
+	playerCmd.AddCommand(playerImportRatingCmd)
+	playerCmd.AddCommand(playerListCmd)
+	rootCmd.AddCommand(playerCmd)
+}
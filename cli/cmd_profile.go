@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Show and edit player profiles",
+}
+
+var profileShowCmd = &cobra.Command{
+	Use:   "show <@handle>",
+	Short: "Show a player's profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		p := roster.Find(args[0])
+		if p == nil {
+			return fmt.Errorf("@%s is not on the roster", normalizePlayer(args[0]))
+		}
+
+		fmt.Printf("@%s (%s)\n", p.Handle, p.DisplayName())
+		if p.Profile == nil {
+			fmt.Println("No profile yet. Add one with tennis profile set")
+			return nil
+		}
+		for _, field := range []struct{ name, value string }{
+			{"Plays", p.Profile.Hand},
+			{"NTRP", ntrpString(p.Profile.NTRP)},
+			{"Club", p.Profile.Club},
+		} {
+			if field.value != "" {
+				fmt.Printf("  %-6s %s\n", field.name, field.value)
+			}
+		}
+		return nil
+	},
+}
+
+var profileSetCmd = &cobra.Command{
+	Use:   "set <@handle>",
+	Short: "Set fields of a player's profile",
+	Long: `Set a player's profile: the hand they play with, their NTRP self-rating
+and their club. Only the fields given change; an empty value clears one.
+The profile is saved in players.yml (commit it afterwards) and shown by
+"tennis stats player" and on the player's page of the Pages site.
+
+Examples:
+  tennis profile set @player_one --hand left --ntrp 4.0 --club "Riverside"
+  tennis profile set @player_one --club ""`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		if !flags.Changed("hand") && !flags.Changed("ntrp") && !flags.Changed("club") {
+			return invalidf("nothing to set; use --hand, --ntrp or --club")
+		}
+		hand, _ := flags.GetString("hand")
+		ntrp, _ := flags.GetFloat64("ntrp")
+		club, _ := flags.GetString("club")
+		if hand != "" && hand != "left" && hand != "right" {
+			return invalidf("invalid --hand '%s'. Use left or right", hand)
+		}
+		if ntrp != 0 && (ntrp < 1 || ntrp > 7 || math.Mod(ntrp*2, 1) != 0) {
+			return invalidf("invalid --ntrp %v. Use 1.0 to 7.0 in steps of 0.5", ntrp)
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		p := roster.Find(args[0])
+		if p == nil {
+			return fmt.Errorf("@%s is not on the roster", normalizePlayer(args[0]))
+		}
+		profile := playerProfile{}
+		if p.Profile != nil {
+			profile = *p.Profile
+		}
+		if flags.Changed("hand") {
+			profile.Hand = hand
+		}
+		if flags.Changed("ntrp") {
+			profile.NTRP = ntrp
+		}
+		if flags.Changed("club") {
+			profile.Club = club
+		}
+		p.Profile = &profile
+		if profile == (playerProfile{}) {
+			p.Profile = nil
+		}
+
+		if err := saveRoster(roster); err != nil {
+			return fmt.Errorf("failed to save roster: %w", err)
+		}
+		if summary := p.Profile.String(); summary != "" {
+			fmt.Printf("✅ @%s: %s. Commit %s\n", p.Handle, summary, rosterPath())
+		} else {
+			fmt.Printf("✅ @%s's profile cleared. Commit %s\n", p.Handle, rosterPath())
+		}
+		return nil
+	},
+}
+
+// ntrpString formats an NTRP rating, or "" for none.
+func ntrpString(ntrp float64) string {
+	if ntrp == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", ntrp)
+}
+
+func init() {
+	profileSetCmd.Flags().String("hand", "", "Playing hand: left or right")
+	profileSetCmd.Flags().Float64("ntrp", 0, "NTRP self-rating, 1.0 to 7.0 (0 clears it)")
+	profileSetCmd.Flags().String("club", "", "Home club")
+
+	profileCmd.AddCommand(profileShowCmd)
+	profileCmd.AddCommand(profileSetCmd)
+	rootCmd.AddCommand(profileCmd)
+}
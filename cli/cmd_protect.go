@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+// protectionSetting is one setting compared by "admin protect".
+type protectionSetting struct {
+	Name    string
+	Current string
+	Want    string
+	OK      bool
+}
+
+var adminProtectCmd = &cobra.Command{
+	Use:   "protect",
+	Short: "Configure branch protection for the match-approval flow",
+	Long: `Audit and configure the protection the match-approval flow relies on.
+Match results land as pull requests that the players approve, so the data
+branch must:
+
+  - require the given number of approving reviews before merging
+  - dismiss approvals when new commits are pushed, so an edited match file
+    is re-approved
+  - require the given status checks (--checks) to pass
+  - reject force pushes
+  - and GitHub Actions must be allowed to create and approve pull requests
+
+The current settings are compared with these and any gaps fixed. With
+--audit nothing is changed: the command fails if any setting is off.
+
+Examples:
+  tennis admin protect --branch main --require-approvals 2
+  tennis admin protect --audit
+  tennis admin protect --checks pytest,issue-forms --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		branch, _ := cmd.Flags().GetString("branch")
+		approvals, _ := cmd.Flags().GetInt("require-approvals")
+		checks, _ := cmd.Flags().GetStringSlice("checks")
+		audit, _ := cmd.Flags().GetBool("audit")
+
+		if approvals < 1 || approvals > 6 {
+			return invalidf("--require-approvals must be between 1 and 6")
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+
+		protection, _, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+		if err != nil && !errors.Is(err, github.ErrBranchNotProtected) {
+			return fmt.Errorf("failed to read protection for %s: %w", branch, err)
+		}
+		actions, _, err := client.Repositories.GetDefaultWorkflowPermissions(ctx, owner, repo)
+		if err != nil {
+			return fmt.Errorf("failed to read Actions permissions: %w", err)
+		}
+
+		settings := compareProtection(protection, actions, approvals, checks)
+		fmt.Printf("Branch protection for %s/%s@%s\n\n", owner, repo, branch)
+		fmt.Printf("   %-34s %-22s %s\n", "Setting", "Current", "Wanted")
+		failing := 0
+		for _, s := range settings {
+			icon := "✅"
+			if !s.OK {
+				icon = "❌"
+				failing++
+			}
+			fmt.Printf("%s %-34s %-22s %s\n", icon, s.Name, s.Current, s.Want)
+		}
+		fmt.Println()
+
+		switch {
+		case failing == 0:
+			fmt.Println("✅ Branch protection is configured for match approvals")
+			return nil
+		case audit:
+			return fmt.Errorf("%d protection settings need changing; run `tennis admin protect` to fix them", failing)
+		case dryRun:
+			fmt.Printf("[dry-run] would update %d settings\n", failing)
+			return nil
+		}
+
+		if _, _, err := client.Repositories.UpdateBranchProtection(ctx, owner, repo, branch,
+			protectionRequest(protection, approvals, checks)); err != nil {
+			return fmt.Errorf("failed to update protection for %s: %w", branch, err)
+		}
+		if !actions.GetCanApprovePullRequestReviews() {
+			actions.CanApprovePullRequestReviews = github.Bool(true)
+			if _, _, err := client.Repositories.EditDefaultWorkflowPermissions(ctx, owner, repo, *actions); err != nil {
+				return fmt.Errorf("failed to allow Actions to approve pull requests: %w", err)
+			}
+		}
+		fmt.Printf("✅ Updated %d settings\n", failing)
+		return nil
+	},
+}
+
+// compareProtection lists the current and wanted value of each setting.
+// protection is nil for an unprotected branch.
+func compareProtection(protection *github.Protection, actions *github.DefaultWorkflowPermissionRepository, approvals int, checks []string) []protectionSetting {
+	var reviews *github.PullRequestReviewsEnforcement
+	var status *github.RequiredStatusChecks
+	forcePush := false
+	if protection != nil {
+		reviews = protection.RequiredPullRequestReviews
+		status = protection.RequiredStatusChecks
+		forcePush = protection.AllowForcePushes != nil && protection.AllowForcePushes.Enabled
+	}
+
+	haveApprovals, dismissStale := 0, false
+	if reviews != nil {
+		haveApprovals = reviews.RequiredApprovingReviewCount
+		dismissStale = reviews.DismissStaleReviews
+	}
+	haveChecks := requiredContexts(status)
+
+	var missing []string
+	for _, c := range checks {
+		if !containsFold(haveChecks, c) {
+			missing = append(missing, c)
+		}
+	}
+
+	return []protectionSetting{
+		{"Branch protected", yesNo(protection != nil), "yes", protection != nil},
+		{"Required approving reviews", strconv.Itoa(haveApprovals), fmt.Sprintf("at least %d", approvals), haveApprovals >= approvals},
+		{"Dismiss stale approvals", yesNo(dismissStale), "yes", dismissStale},
+		{"Required status checks", listOrNone(haveChecks), listOrNone(checks), len(missing) == 0},
+		{"Force pushes allowed", yesNo(forcePush), "no", !forcePush},
+		{"Actions can approve pull requests", yesNo(actions.GetCanApprovePullRequestReviews()), "yes", actions.GetCanApprovePullRequestReviews()},
+	}
+}
+
+// protectionRequest builds the protection to apply, keeping existing
+// stricter settings (more approvals, extra checks, admin enforcement).
+func protectionRequest(current *github.Protection, approvals int, checks []string) *github.ProtectionRequest {
+	contexts := append([]string{}, checks...)
+	strict := false
+	enforceAdmins := false
+	reviews := &github.PullRequestReviewsEnforcementRequest{
+		DismissStaleReviews:          true,
+		RequiredApprovingReviewCount: approvals,
+	}
+	if current != nil {
+		for _, c := range requiredContexts(current.RequiredStatusChecks) {
+			if !containsFold(contexts, c) {
+				contexts = append(contexts, c)
+			}
+		}
+		if current.RequiredStatusChecks != nil {
+			strict = current.RequiredStatusChecks.Strict
+		}
+		if current.EnforceAdmins != nil {
+			enforceAdmins = current.EnforceAdmins.Enabled
+		}
+		if r := current.RequiredPullRequestReviews; r != nil {
+			reviews.RequireCodeOwnerReviews = r.RequireCodeOwnerReviews
+			if r.RequiredApprovingReviewCount > approvals {
+				reviews.RequiredApprovingReviewCount = r.RequiredApprovingReviewCount
+			}
+		}
+	}
+	sort.Strings(contexts)
+
+	return &github.ProtectionRequest{
+		RequiredStatusChecks:       &github.RequiredStatusChecks{Strict: strict, Contexts: &contexts},
+		RequiredPullRequestReviews: reviews,
+		EnforceAdmins:              enforceAdmins,
+		AllowForcePushes:           github.Bool(false),
+	}
+}
+
+func requiredContexts(status *github.RequiredStatusChecks) []string {
+	if status == nil {
+		return nil
+	}
+	var contexts []string
+	if status.Contexts != nil {
+		contexts = append(contexts, *status.Contexts...)
+	}
+	if status.Checks != nil {
+		for _, c := range *status.Checks {
+			if !containsFold(contexts, c.Context) {
+				contexts = append(contexts, c.Context)
+			}
+		}
+	}
+	return contexts
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func listOrNone(list []string) string {
+	if len(list) == 0 {
+		return "none"
+	}
+	return strings.Join(list, ", ")
+}
+
+func init() {
+	adminProtectCmd.Flags().String("branch", "main", "Branch match pull requests merge into")
+	adminProtectCmd.Flags().Int("require-approvals", 1, "Approving reviews required before a match can be merged")
+	adminProtectCmd.Flags().StringSlice("checks", []string{"pytest"}, "Status checks that must pass (comma-separated)")
+	adminProtectCmd.Flags().Bool("audit", false, "Only report the current settings; fail if any need changing")
+	adminProtectCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without updating settings")
+
+	adminCmd.AddCommand(adminProtectCmd)
+}
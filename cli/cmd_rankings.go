@@ -0,0 +1,461 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var rankingsCmd = &cobra.Command{
+	Use:   "rankings",
+	Short: "Compute the league's leaderboards",
+}
+
+var rankingsComputeCmd = &cobra.Command{
+	Use:   "compute",
+	Short: "Compute the singles and doubles leaderboards",
+	Long: `Compute the singles and doubles leaderboards from the match files in the
+league checkout, as the Pages site publishes them.
+
+Ratings and records are saved to a snapshot (.tennis/rankings-snapshot.json
+by default) together with the last match file replayed, so the next run
+only replays matches recorded since. If an already replayed match file is
+edited or removed, or one is added before the last replayed file, the
+snapshot is rebuilt from scratch, as it is when the Elo parameters, the
+rating formula, handicap scoring or the imported ratings change. The
+snapshot is a cache and can be deleted at any time.
+
+With --form, each player's last 5 results and a sparkline of their rating
+over their last 10 matches follow their row.
+
+With --category, the leaderboards rank only the players in that category
+(see "tennis player category"), on the matches they played against each
+other. Category leaderboards are replayed in full and not saved to the
+snapshot.
+
+Examples:
+  tennis rankings compute
+  tennis rankings compute --json > rankings.json
+  tennis rankings compute --full
+  tennis rankings compute --form
+  tennis rankings compute --category veterans`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshotPath, _ := cmd.Flags().GetString("snapshot")
+		full, _ := cmd.Flags().GetBool("full")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		form, _ := cmd.Flags().GetBool("form")
+		category, _ := cmd.Flags().GetString("category")
+
+		if snapshotPath == "" {
+			snapshotPath = defaultSnapshotPath()
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		category, roster, err = forCategory(category, roster, &singles, &doubles)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Elo: %s\n", elo)
+		today := time.Now().Format("2006-01-02")
+		if freeze.Active(today) {
+			fmt.Fprintf(os.Stderr, "Rankings frozen until %s: %d singles and %d doubles matches held back\n", freeze.Until, heldBack(singles), heldBack(doubles))
+		}
+		rankings := publishedRankings{
+			Generated: time.Now().UTC().Format("2006-01-02 15:04:05 UTC"),
+			Category:  category,
+			Elo:       &elo,
+		}
+		if category != "" {
+			fmt.Fprintf(os.Stderr, "Replayed %d singles and %d doubles matches in the %s category\n", len(singles), len(doubles), category)
+			rankings.Singles = singlesLeaderboard(singles, roster, today)
+			rankings.DoublesIndividual = doublesLeaderboard(doubles, roster, today)
+		} else if rankings.Singles, rankings.DoublesIndividual, err = computeFromSnapshot(snapshotPath, full, roster, singles, doubles, today); err != nil {
+			return err
+		}
+
+		if asJSON {
+			data, err := json.MarshalIndent(rankings, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		var singlesForm, doublesForm map[string]string
+		if form {
+			singlesForm, doublesForm = leaderboardForm(singles, replaySingles), leaderboardForm(doubles, replayDoubles)
+		}
+		singlesTitle, doublesTitle := "Singles", "Doubles"
+		if category != "" {
+			singlesTitle, doublesTitle = "Singles ("+category+")", "Doubles ("+category+")"
+		}
+		fmt.Println()
+		printLeaderboardMovement(singlesTitle, rankings.Singles, nil, singlesForm)
+		fmt.Println()
+		printLeaderboardMovement(doublesTitle, rankings.DoublesIndividual, nil, doublesForm)
+		if len(leagueRepos) > 0 {
+			fmt.Printf("\nMatches from %s\n", strings.Join(matchSources(append(append([]Match{}, singles...), doubles...)), ", "))
+		}
+		return nil
+	},
+}
+
+// computeFromSnapshot brings the rankings snapshot at path up to date with
+// the singles and doubles matches, replaying only those recorded since
+// unless full is set, and returns the leaderboards on asOf.
+func computeFromSnapshot(path string, full bool, roster *Roster, singles, doubles []Match, asOf string) ([]LeaderboardRow, []LeaderboardRow, error) {
+	snap := newRankingsSnapshot()
+	if !full {
+		snap = loadRankingsSnapshot(path)
+	}
+	for _, kind := range []struct {
+		name    string
+		state   *replayState
+		matches []Match
+		replay  func(map[string]float64, []Match)
+		tally   func(map[string]*LeaderboardRow, []Match)
+	}{
+		{"singles", &snap.Singles, singles, replaySingles, tallySingles},
+		{"doubles", &snap.Doubles, doubles, replayDoubles, tallyDoubles},
+	} {
+		n, rebuilt := kind.state.advance(kind.matches, kind.replay, kind.tally, newProgress("Replaying "+kind.name+" matches", 0))
+		switch {
+		case rebuilt:
+			fmt.Fprintf(os.Stderr, "Match history changed; replayed all %d %s matches\n", n, kind.name)
+		case n == len(kind.matches):
+			fmt.Fprintf(os.Stderr, "Replayed %d %s matches\n", n, kind.name)
+		default:
+			fmt.Fprintf(os.Stderr, "Replayed %d new %s matches (%d from snapshot)\n", n, kind.name, len(kind.matches)-n)
+		}
+	}
+
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "[dry-run] would save snapshot to %s\n", path)
+	} else if err := saveRankingsSnapshot(path, snap); err != nil {
+		return nil, nil, fmt.Errorf("failed to save the rankings snapshot: %w", err)
+	}
+	return leaderboardOn(snap.Singles.Records, snap.Singles.Ratings, singles, roster, asOf),
+		leaderboardOn(snap.Doubles.Records, snap.Doubles.Ratings, doubles, roster, asOf), nil
+}
+
+var rankingsSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and view leaderboards as they stood on a day",
+	Long: `Leaderboard snapshots are the singles and doubles leaderboards as they
+stood on a day, saved as rankings-snapshots/<date>.json in the league
+checkout (commit them to share them), or with --release as assets of the
+` + snapshotsReleaseTag + ` GitHub Release. Past leaderboards and rank
+movement are read from them instead of replaying the match history.`,
+}
+
+var rankingsSnapshotSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save the leaderboards as a snapshot",
+	Long: `Save the leaderboards as they stood at the end of a day, counting only
+matches dated on or before it.
+
+With --calc-log, the calculation behind the ratings is saved next to the
+snapshot as <date>.calc.jsonl: one JSON record per match per player, in
+replay order, with the ratings going in, each set's expected score,
+K-factor and change, and the rating after. A snapshot's rating is the last
+record's rating_after less the row's decay, so anyone can reproduce and
+audit it.
+
+Examples:
+  tennis rankings snapshot save
+  tennis rankings snapshot save --date 2025-06-30
+  tennis rankings snapshot save --release --calc-log`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, _ := cmd.Flags().GetString("date")
+		release, _ := cmd.Flags().GetBool("release")
+		force, _ := cmd.Flags().GetBool("force")
+		calcLog, _ := cmd.Flags().GetBool("calc-log")
+
+		if date == "" {
+			date = time.Now().Format("2006-01-02")
+		}
+		if !isValidDate(date) {
+			return invalidf("invalid date '%s'. Use YYYY-MM-DD format", date)
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		snap := &leaderboardSnapshot{
+			Date:              date,
+			Generated:         time.Now().UTC().Format("2006-01-02 15:04:05 UTC"),
+			Commit:            gitHead(),
+			Singles:           singlesLeaderboard(playedBy(singles, date), roster, date),
+			DoublesIndividual: doublesLeaderboard(playedBy(doubles, date), roster, date),
+			Elo:               &elo,
+		}
+		where, err := saveLeaderboardSnapshot(cmd.Context(), snap, release, force)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			fmt.Printf("[dry-run] would save the %s snapshot to %s\n", date, where)
+		} else {
+			fmt.Printf("✅ Saved the %s snapshot to %s\n", date, where)
+		}
+		if !calcLog {
+			return nil
+		}
+
+		records := append(calculationLog("singles", playedBy(singles, date), importedSingles),
+			calculationLog("doubles", playedBy(doubles, date), importedDoubles)...)
+		data, err := encodeCalculationLog(records)
+		if err != nil {
+			return err
+		}
+		if where, err = saveSnapshotAsset(cmd.Context(), calcLogAssetName(date), data, release, force); err != nil {
+			return err
+		}
+		if dryRun {
+			fmt.Printf("[dry-run] would save the calculation log (%d records) to %s\n", len(records), where)
+			return nil
+		}
+		fmt.Printf("✅ Saved the calculation log (%d records) to %s\n", len(records), where)
+		return nil
+	},
+}
+
+var rankingsSnapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved snapshots",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		release, _ := cmd.Flags().GetBool("release")
+
+		dates, err := listLeaderboardSnapshots(cmd.Context(), release)
+		if err != nil {
+			return err
+		}
+		if len(dates) == 0 {
+			if release {
+				fmt.Printf("No snapshots in release %s\n", snapshotsReleaseTag)
+			} else {
+				fmt.Printf("No snapshots in %s\n", snapshotsDir())
+			}
+			return nil
+		}
+		for _, d := range dates {
+			fmt.Println(d)
+		}
+		return nil
+	},
+}
+
+var rankingsSnapshotShowCmd = &cobra.Command{
+	Use:   "show <date>",
+	Short: "Show a saved snapshot",
+	Long: `Show the leaderboards saved for a day, with each player's movement since
+the snapshot before it (or since --compare).
+
+Examples:
+  tennis rankings snapshot show 2025-06-30
+  tennis rankings snapshot show 2025-06-30 --compare 2025-01-01
+  tennis rankings snapshot show 2025-06-30 --release --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date := args[0]
+		compare, _ := cmd.Flags().GetString("compare")
+		release, _ := cmd.Flags().GetBool("release")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		ctx := cmd.Context()
+
+		if !isValidDate(date) {
+			return invalidf("invalid date '%s'. Use YYYY-MM-DD format", date)
+		}
+		snap, err := loadLeaderboardSnapshot(ctx, date, release)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			data, err := json.MarshalIndent(snap, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		if compare == "" {
+			dates, err := listLeaderboardSnapshots(ctx, release)
+			if err != nil {
+				return err
+			}
+			for _, d := range dates {
+				if d < date {
+					compare = d
+				}
+			}
+		}
+		var prev *leaderboardSnapshot
+		if compare != "" {
+			if prev, err = loadLeaderboardSnapshot(ctx, compare, release); err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("Leaderboards on %s", snap.Date)
+		if prev != nil {
+			fmt.Printf(" (movement since %s)", prev.Date)
+		}
+		fmt.Println()
+		fmt.Println()
+		if prev != nil {
+			printLeaderboardMovement("Singles", snap.Singles, rankMovement(prev.Singles, snap.Singles), nil)
+			fmt.Println()
+			printLeaderboardMovement("Doubles", snap.DoublesIndividual, rankMovement(prev.DoublesIndividual, snap.DoublesIndividual), nil)
+			printTierChanges("Singles", tierChanges(prev.Singles, snap.Singles))
+			printTierChanges("Doubles", tierChanges(prev.DoublesIndividual, snap.DoublesIndividual))
+			return nil
+		}
+		printLeaderboard("Singles", snap.Singles)
+		fmt.Println()
+		printLeaderboard("Doubles", snap.DoublesIndividual)
+		return nil
+	},
+}
+
+// printTierChanges lists a board's tier changes, if it has any.
+func printTierChanges(title string, changes []string) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Printf("\n%s tier changes\n", title)
+	for _, c := range changes {
+		fmt.Println("  " + c)
+	}
+}
+
+func printLeaderboard(title string, board []LeaderboardRow) {
+	printLeaderboardMovement(title, board, nil, nil)
+}
+
+// printLeaderboardMovement prints a board, with an arrow for how far each
+// ranked player has moved when moves is non-nil; players new to the
+// rankings are marked "new". Unranked players follow in their own section.
+// Ratings lowered by inactivity decay note the points lost. A Tier column
+// is shown when the players have tiers, and a Form column when form is
+// non-nil.
+func printLeaderboardMovement(title string, board []LeaderboardRow, moves map[string]int, form map[string]string) {
+	tierWidth := 0
+	for _, r := range board {
+		if r.Tier != "" {
+			tierWidth = max(tierWidth, len(r.Tier), len("Tier"))
+		}
+	}
+	tierCol := func(tier string) string {
+		if tierWidth == 0 {
+			return ""
+		}
+		return fmt.Sprintf("  %-*s", tierWidth, tier)
+	}
+
+	pointsCol := func(p string) string {
+		if !rankingPoints.Enabled() {
+			return ""
+		}
+		return fmt.Sprintf(" %7s", p)
+	}
+
+	formCol := func(f string) string {
+		if form == nil {
+			return ""
+		}
+		return "  " + f
+	}
+
+	fmt.Println(title)
+	fmt.Printf("  %6s %-20s %7s%s %7s %7s%s\n", "Rank", "Player", "Rating", pointsCol("Points"), "Sets", "Games", strings.TrimRight(tierCol("Tier")+formCol("Form"), " "))
+	for i, r := range board {
+		if r.Unranked && (i == 0 || !board[i-1].Unranked) {
+			fmt.Printf("  Unranked (%s needed)\n", qualification)
+		}
+		rank := "-"
+		if !r.Unranked {
+			rank = fmt.Sprint(r.Rank)
+		}
+		if moves != nil && !r.Unranked {
+			arrow := "new"
+			if move, ok := moves[r.Player]; ok {
+				arrow = movementArrow(move)
+			}
+			rank = arrow + " " + rank
+		}
+		idle := ""
+		if r.Decay > 0 {
+			idle = fmt.Sprintf("  (-%.1f inactive)", r.Decay)
+		}
+		fmt.Printf("  %6s %-20s %7.1f%s %7s %7s%s%s%s\n", rank, "@"+r.Player, r.Rating, pointsCol(fmt.Sprintf("%.1f", r.Points)),
+			fmt.Sprintf("%d-%d", r.SetWins, r.SetLosses), fmt.Sprintf("%d-%d", r.GameWins, r.GameLosses), tierCol(r.Tier), formCol(form[r.Player]), idle)
+	}
+}
+
+func init() {
+	rankingsComputeCmd.Flags().String("snapshot", "", "Snapshot file (defaults to .tennis/rankings-snapshot.json in the league checkout)")
+	rankingsComputeCmd.Flags().Bool("full", false, "Ignore the snapshot and replay every match")
+	rankingsComputeCmd.Flags().Bool("json", false, "Print the leaderboards as rankings.json")
+	rankingsComputeCmd.Flags().Bool("form", false, "Show each player's recent results and rating trend")
+	rankingsComputeCmd.Flags().String("category", "", "Rank only the players in this category")
+	rankingsComputeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Compute without saving the snapshot")
+
+	rankingsSnapshotSaveCmd.Flags().String("date", "", "Day to snapshot (YYYY-MM-DD), defaults to today")
+	rankingsSnapshotSaveCmd.Flags().Bool("release", false, "Save as an asset of the "+snapshotsReleaseTag+" release instead of a file")
+	rankingsSnapshotSaveCmd.Flags().Bool("force", false, "Overwrite an existing snapshot for the day")
+	rankingsSnapshotSaveCmd.Flags().Bool("calc-log", false, "Also save the calculation log behind the ratings")
+	rankingsSnapshotSaveCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show where the snapshot would be saved without saving it")
+	rankingsSnapshotListCmd.Flags().Bool("release", false, "List the snapshots in the "+snapshotsReleaseTag+" release")
+	rankingsSnapshotShowCmd.Flags().String("compare", "", "Snapshot date to show movement since (defaults to the previous snapshot)")
+	rankingsSnapshotShowCmd.Flags().Bool("release", false, "Read snapshots from the "+snapshotsReleaseTag+" release")
+	rankingsSnapshotShowCmd.Flags().Bool("json", false, "Print the snapshot as JSON")
+
+	rankingsSnapshotCmd.AddCommand(rankingsSnapshotSaveCmd)
+	rankingsSnapshotCmd.AddCommand(rankingsSnapshotListCmd)
+	rankingsSnapshotCmd.AddCommand(rankingsSnapshotShowCmd)
+	rankingsCmd.AddCommand(rankingsComputeCmd)
+	rankingsCmd.AddCommand(rankingsSnapshotCmd)
+	rootCmd.AddCommand(rankingsCmd)
+}
+
+// movementArrow shows a rank movement: ▲ up, ▼ down, – unchanged.
+func movementArrow(move int) string {
+	switch {
+	case move > 0:
+		return fmt.Sprintf("▲%d", move)
+	case move < 0:
+		return fmt.Sprintf("▼%d", -move)
+	}
+	return "–"
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var rankingsChangelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Write a markdown changelog of rank changes since a date",
+	Long: `Write a markdown changelog of every rank change between the end of the
+day before --since and the end of --until (default today), ready to post
+to a discussion thread or chat.
+
+Each player who moved, was newly ranked or dropped out of the rankings is
+listed with their old and new rank and rating and the matches they played
+in the meantime. A player who moved without playing is listed with the
+players who passed them or whom they passed. Players with a pseudonym
+appear under it.
+
+Examples:
+  tennis rankings changelog --since 2025-06-01
+  tennis rankings changelog --since 2025-06-01 --until 2025-06-30 --kind singles
+  tennis rankings changelog --since 2025-06-01 --out changelog.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, _ := cmd.Flags().GetString("since")
+		until, _ := cmd.Flags().GetString("until")
+		kind, _ := cmd.Flags().GetString("kind")
+		out, _ := cmd.Flags().GetString("out")
+
+		if since == "" {
+			return invalidf("--since is required")
+		}
+		if until == "" {
+			until = time.Now().Format("2006-01-02")
+		}
+		start, err := time.Parse("2006-01-02", since)
+		if err != nil || !isValidDate(until) {
+			return invalidf("invalid date. Use YYYY-MM-DD")
+		}
+		if until < since {
+			return invalidf("--until %s is before --since %s", until, since)
+		}
+		if kind != "all" && kind != "singles" && kind != "doubles" {
+			return invalidf("unknown kind '%s' (use singles, doubles or all)", kind)
+		}
+		before := start.AddDate(0, 0, -1).Format("2006-01-02")
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "# Ranking changes, %s to %s\n", since, until)
+		for _, k := range []struct {
+			name        string
+			load        func(context.Context) ([]Match, error)
+			leaderboard func([]Match, *Roster, string) []LeaderboardRow
+		}{
+			{"singles", loadSinglesMatches, singlesLeaderboard},
+			{"doubles", loadDoublesMatches, doublesLeaderboard},
+		} {
+			if kind != "all" && kind != k.name {
+				continue
+			}
+			matches, err := k.load(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to load matches: %w", err)
+			}
+			var played []Match
+			for _, m := range matches {
+				if m.Date >= since && m.Date <= until {
+					played = append(played, m)
+				}
+			}
+			old := k.leaderboard(playedBy(matches, before), roster, before)
+			now := k.leaderboard(playedBy(matches, until), roster, until)
+			fmt.Fprintf(&b, "\n## %s%s\n\n", strings.ToUpper(k.name[:1]), k.name[1:])
+			writeRankChanges(&b, old, now, played)
+		}
+
+		w := io.Writer(os.Stdout)
+		if out != "" {
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+		if out != "" {
+			fmt.Printf("✅ Changelog written to %s\n", out)
+		}
+		return nil
+	},
+}
+
+// writeRankChanges lists every ranked player whose rank differs between
+// two leaderboards, in their new order, with the matches they played in
+// between, then the players who dropped out of the rankings.
+func writeRankChanges(b *strings.Builder, old, now []LeaderboardRow, played []Match) {
+	oldRows := make(map[string]LeaderboardRow)
+	for _, r := range old {
+		if !r.Unranked {
+			oldRows[r.Player] = r
+		}
+	}
+	nowRanks := make(map[string]int)
+	for _, r := range now {
+		if !r.Unranked {
+			nowRanks[r.Player] = r.Rank
+		}
+	}
+
+	changes := 0
+	for _, r := range now {
+		if r.Unranked {
+			continue
+		}
+		prev, wasRanked := oldRows[r.Player]
+		switch {
+		case !wasRanked:
+			fmt.Fprintf(b, "- **%s** newly ranked at #%d (%.1f)\n", publicName(r.Player), r.Rank, r.Rating)
+		case prev.Rank != r.Rank:
+			arrow := "▲"
+			if r.Rank > prev.Rank {
+				arrow = "▼"
+			}
+			fmt.Fprintf(b, "- **%s** %s%d to #%d (was #%d, %.1f → %.1f)\n", publicName(r.Player), arrow,
+				max(prev.Rank-r.Rank, r.Rank-prev.Rank), r.Rank, prev.Rank, prev.Rating, r.Rating)
+		default:
+			continue
+		}
+		changes++
+
+		var mine []Match
+		for _, m := range played {
+			if containsString(matchPlayers(m), r.Player) {
+				mine = append(mine, m)
+			}
+		}
+		for _, m := range mine {
+			line := fmt.Sprintf("  - %s: %s", m.Date, matchHeadlineWith(m, publicName))
+			if m.SourceIssue != 0 && m.Repo == "" {
+				line += fmt.Sprintf(" (#%d)", m.SourceIssue)
+			}
+			fmt.Fprintln(b, line)
+		}
+		if len(mine) > 0 || !wasRanked {
+			continue
+		}
+		var passedBy, passed []string
+		for p, was := range oldRows {
+			is, ok := nowRanks[p]
+			if !ok || p == r.Player {
+				continue
+			}
+			switch {
+			case was.Rank > prev.Rank && is < r.Rank:
+				passedBy = append(passedBy, publicName(p))
+			case was.Rank < prev.Rank && is > r.Rank:
+				passed = append(passed, publicName(p))
+			}
+		}
+		sort.Strings(passedBy)
+		sort.Strings(passed)
+		reason := "didn't play"
+		if len(passedBy) > 0 {
+			reason += "; passed by " + strings.Join(passedBy, ", ")
+		}
+		if len(passed) > 0 {
+			reason += "; moved past " + strings.Join(passed, ", ")
+		}
+		if len(passedBy)+len(passed) == 0 {
+			reason += "; players above or below joined or left the rankings"
+		}
+		fmt.Fprintf(b, "  - %s\n", reason)
+	}
+
+	for _, r := range old {
+		if _, ok := nowRanks[r.Player]; ok || r.Unranked {
+			continue
+		}
+		fmt.Fprintf(b, "- **%s** dropped out of the rankings (was #%d)\n", publicName(r.Player), r.Rank)
+		changes++
+	}
+	if changes == 0 {
+		b.WriteString("No rank changes.\n")
+	}
+}
+
+func init() {
+	rankingsChangelogCmd.Flags().String("since", "", "First day of the changes (YYYY-MM-DD)")
+	rankingsChangelogCmd.Flags().String("until", "", "Last day of the changes (YYYY-MM-DD), defaults to today")
+	rankingsChangelogCmd.Flags().String("kind", "all", "Leaderboards to cover: singles, doubles or all")
+	rankingsChangelogCmd.Flags().StringP("out", "o", "", "Write to a file instead of stdout")
+	rankingsCmd.AddCommand(rankingsChangelogCmd)
+}
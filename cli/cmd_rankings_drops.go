@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var rankingsUpcomingDropsCmd = &cobra.Command{
+	Use:   "upcoming-drops",
+	Short: "Show the ranking points players are about to lose",
+	Long: `Show the ranking points each player will lose in the coming days, as
+wins older than the league's points window (points.window_days in
+.tennis.yml, 52 weeks unless set) drop off.
+
+Players are listed by the points they stand to lose, most first, with
+each win that expires, the day it does and the points it was worth.
+
+Examples:
+  tennis rankings upcoming-drops
+  tennis rankings upcoming-drops --days 60 --player @player_one`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		player, _ := cmd.Flags().GetString("player")
+
+		if !rankingPoints.Enabled() {
+			return fmt.Errorf("the league doesn't award ranking points (set points.win or points.rounds in .tennis.yml)")
+		}
+		if days < 1 {
+			return invalidf("--days must be at least 1")
+		}
+		if player != "" {
+			aliases, err := loadAliases()
+			if err != nil {
+				return err
+			}
+			player = resolveAlias(aliases, normalizePlayer(player))
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		now := time.Now()
+		today, until := now.Format("2006-01-02"), now.AddDate(0, 0, days).Format("2006-01-02")
+		fmt.Printf("Points dropping off by %s (window: %d days)\n", until, rankingPoints.Window())
+		for _, kind := range []struct {
+			name    string
+			matches []Match
+		}{
+			{"Singles", singles},
+			{"Doubles", doubles},
+		} {
+			current := matchPoints(kind.matches, today)
+			dropping := make(map[string][]pointsAward)
+			for _, a := range pointAwards(kind.matches) {
+				if a.live(today) && a.Expires <= until && (player == "" || a.Player == player) {
+					dropping[a.Player] = append(dropping[a.Player], a)
+				}
+			}
+
+			fmt.Printf("\n%s\n", kind.name)
+			if len(dropping) == 0 {
+				fmt.Printf("  No points drop off in the next %d days\n", days)
+				continue
+			}
+			lost := make(map[string]float64)
+			var players []string
+			for p, awards := range dropping {
+				players = append(players, p)
+				for _, a := range awards {
+					lost[p] += a.Points
+				}
+			}
+			sort.Slice(players, func(i, j int) bool {
+				if lost[players[i]] != lost[players[j]] {
+					return lost[players[i]] > lost[players[j]]
+				}
+				return players[i] < players[j]
+			})
+			for _, p := range players {
+				fmt.Printf("  @%s: %.1f points, losing %.1f\n", p, current[p], lost[p])
+				for _, a := range dropping[p] {
+					what := "beat @" + strings.Join(a.Beaten, " & @")
+					switch a.Round {
+					case "":
+					case "round":
+						what += " in an early round"
+					default:
+						what += " in the " + a.Round
+					}
+					if a.Match.SourceIssue != 0 {
+						what += fmt.Sprintf(" (#%d, %s)", a.Match.SourceIssue, a.Match.Date)
+					} else {
+						what += fmt.Sprintf(" (%s)", a.Match.Date)
+					}
+					fmt.Printf("    %s  -%.1f  %s\n", a.Expires, a.Points, what)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rankingsUpcomingDropsCmd.Flags().Int("days", 28, "How many days ahead to look")
+	rankingsUpcomingDropsCmd.Flags().String("player", "", "Only show this player's drops")
+	rankingsCmd.AddCommand(rankingsUpcomingDropsCmd)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var rankingsExplainCmd = &cobra.Command{
+	Use:   "explain <match-issue>",
+	Short: "Show how a match changed each player's rating",
+	Long: `Show, set by set, how a recorded match changed its players' ratings:
+the ratings going in, each side's expected score, the K-factor with any
+margin weighting, the change it gave, and every player's rating before and
+after the match.
+
+Every match recorded before it is replayed first, so the numbers are the
+ones the leaderboard used. Inactivity decay isn't part of a match and
+isn't shown.
+
+Examples:
+  tennis rankings explain 42
+  tennis rankings explain '#42'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || number < 1 {
+			return invalidf("invalid match issue '%s'. Use its number, like 42", args[0])
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		for _, kind := range []struct {
+			matches []Match
+			replay  func(map[string]float64, []Match)
+		}{{singles, replaySingles}, {doubles, replayDoubles}} {
+			for i, m := range kind.matches {
+				if m.SourceIssue != number || m.Repo != "" {
+					continue
+				}
+				ratings := make(map[string]float64)
+				kind.replay(ratings, kind.matches[:i])
+				explainMatch(m, ratings)
+				return nil
+			}
+		}
+		return fmt.Errorf("no recorded match for issue #%d", number)
+	},
+}
+
+// explainMatch prints how m changes ratings, the ratings of everyone
+// before it, set by set, updating ratings as it goes.
+func explainMatch(m Match, ratings map[string]float64) {
+	side1, side2 := m.Team1, m.Team2
+	kind, label := "Doubles", "Team avg"
+	if !m.IsDoubles() {
+		if len(m.Players) != 2 {
+			fmt.Printf("Match #%d doesn't have two players and isn't rated\n", m.SourceIssue)
+			return
+		}
+		side1, side2 = m.Players[:1], m.Players[1:]
+		kind, label = "Singles", "Rating"
+	}
+	names := func(side []string) string {
+		return "@" + strings.Join(side, " & @")
+	}
+
+	players := append(append([]string{}, side1...), side2...)
+	before := make(map[string]float64)
+	for _, p := range players {
+		before[p] = rating(ratings, p)
+	}
+
+	fmt.Printf("%s match #%d on %s: %s vs %s\n", kind, m.SourceIssue, m.Date, names(side1), names(side2))
+	fmt.Printf("Elo: %s\n", elo)
+	if h := m.Handicap; h != nil && (h.Side == 1 || h.Side == 2) && handicap.Score == scoreHandicap {
+		fmt.Printf("Scored on the handicap result: %s started every set %d games up\n", names([][]string{side1, side2}[h.Side-1]), h.Games)
+	}
+	if m.forfeited() {
+		if rules.Forfeit.penalized() {
+			fmt.Printf("Forfeit: %s didn't turn up, so the result isn't rated and each loses the %g-point penalty\n", names(m.Forfeit), rules.Forfeit.Penalty)
+		} else {
+			fmt.Printf("Forfeit: %s didn't turn up, so the default result is rated\n", names(m.Forfeit))
+		}
+	}
+	calcs := rateMatch(m, ratings)
+	for n, s := range m.scoredSets() {
+		if len(s) != 2 {
+			fmt.Printf("\nSet %d: malformed, ignored\n", n+1)
+			continue
+		}
+		if s[0] == s[1] {
+			fmt.Printf("\nSet %d: %d-%d, tied, ignored\n", n+1, s[0], s[1])
+			continue
+		}
+		c := calcs[0]
+		calcs = calcs[1:]
+		w, l := names(c.Winners), names(c.Losers)
+		fmt.Printf("\nSet %d: %d-%d to %s\n", n+1, s[0], s[1], w)
+		fmt.Printf("  %-9s %s %.1f, %s %.1f\n", label, w, c.WinnerRating, l, c.LoserRating)
+		fmt.Printf("  %-9s %s %.3f, %s %.3f\n", "Expected", w, c.Expected, l, 1-c.Expected)
+		fmt.Printf("  %-9s %s\n", "K", explainK(m, s, c.K))
+		fmt.Printf("  %-9s %s to %s, the opposite to %s\n", "Change", explainChange(s, c.K, c.Expected, c.Change), w, l)
+	}
+
+	fmt.Println("\nRatings")
+	for _, p := range players {
+		after := rating(ratings, p)
+		fmt.Printf("  %-20s %7.1f → %7.1f  (%+.1f)\n", "@"+p, before[p], after, after-before[p])
+	}
+}
+
+// explainK describes the K-factor applied to a set.
+func explainK(m Match, set []int, k float64) string {
+	if k == elo.K {
+		if elo.Margin == marginNone {
+			return fmt.Sprintf("%g", k)
+		}
+		return fmt.Sprintf("%g (a margin of 1 or less isn't weighted)", k)
+	}
+	margin := set[0] - set[1]
+	what := "games"
+	if elo.Margin == marginSets {
+		w1, w2 := 0, 0
+		for _, s := range m.scoredSets() {
+			if len(s) == 2 && s[0] > s[1] {
+				w1++
+			} else if len(s) == 2 && s[1] > s[0] {
+				w2++
+			}
+		}
+		margin, what = w1-w2, "sets"
+	}
+	return fmt.Sprintf("%.1f = %g × (1 + ln %d), weighted by the %s margin", k, elo.K, int(math.Abs(float64(margin))), what)
+}
+
+// explainChange describes the points the winners of a set gained and the
+// losers lost, as setChange worked them out. Rated per game the change can
+// be negative: winners who took fewer games than expected lose points.
+func explainChange(set []int, k, expected, change float64) string {
+	won, lost := set[0], set[1]
+	if lost > won {
+		won, lost = lost, won
+	}
+	switch {
+	case elo.Mode == modeGames:
+		return fmt.Sprintf("%+.1f = %.1f × (%d games won − %.3f × %d games played)", change, k, won, expected, won+lost)
+	case customFormula != nil:
+		return fmt.Sprintf("%+.1f from the league's formula (%s)", change, elo.Formula)
+	}
+	return fmt.Sprintf("%+.1f = %.1f × (1 − %.3f)", change, k, expected)
+}
+
+func init() {
+	rankingsCmd.AddCommand(rankingsExplainCmd)
+}
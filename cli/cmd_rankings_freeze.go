@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var rankingsFreezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Hold ratings still until a date, e.g. during playoffs",
+	Long: `Freeze the rankings from --from (default today) until --until. Matches
+played in the meantime are recorded as usual and count in players'
+records, recent results and history, but don't move ratings or earn
+ranking points. Once the freeze lifts, the day after --until, they're
+replayed in order as if it never happened.
+
+The freeze is saved to rankings-freeze.yml in the league checkout; commit
+it so the Pages site holds its rankings too. Without flags, the current
+freeze is shown. --lift ends it early.
+
+Examples:
+  tennis rankings freeze --until 2025-09-30 --reason "Club championship playoffs"
+  tennis rankings freeze --from 2025-09-01 --until 2025-09-30
+  tennis rankings freeze
+  tennis rankings freeze --lift`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		until, _ := cmd.Flags().GetString("until")
+		reason, _ := cmd.Flags().GetString("reason")
+		lift, _ := cmd.Flags().GetBool("lift")
+
+		today := time.Now().Format("2006-01-02")
+		switch {
+		case lift:
+			if until != "" || from != "" {
+				return invalidf("--lift can't be combined with --from or --until")
+			}
+			if freeze.From == "" {
+				return fmt.Errorf("the rankings aren't frozen")
+			}
+			if dryRun {
+				fmt.Printf("[dry-run] would remove %s\n", freezePath())
+				return nil
+			}
+			if err := os.Remove(freezePath()); err != nil {
+				return fmt.Errorf("failed to lift the freeze: %w", err)
+			}
+			fmt.Printf("✅ Rankings freeze lifted; matches from %s on count towards ratings again\n", freeze.From)
+			fmt.Printf("Commit the removal of %s to lift it on the Pages site\n", freezePath())
+			return nil
+
+		case until == "":
+			if from != "" || reason != "" {
+				return invalidf("--until is required")
+			}
+			if freeze.From == "" {
+				fmt.Println("The rankings aren't frozen")
+				return nil
+			}
+			ctx := cmd.Context()
+			singles, err := loadSinglesMatches(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to load matches: %w", err)
+			}
+			doubles, err := loadDoublesMatches(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to load matches: %w", err)
+			}
+			fmt.Printf("Rankings frozen from %s until %s", freeze.From, freeze.Until)
+			if freeze.Reason != "" {
+				fmt.Printf(" (%s)", freeze.Reason)
+			}
+			fmt.Println()
+			switch {
+			case freeze.Active(today):
+				fmt.Printf("Holding back %d singles and %d doubles matches\n", heldBack(singles), heldBack(doubles))
+			case today < freeze.From:
+				fmt.Println("Not started yet")
+			default:
+				fmt.Println("Lifted; every match counts. Remove it with --lift")
+			}
+			return nil
+		}
+
+		if from == "" {
+			from = today
+		}
+		if !isValidDate(from) || !isValidDate(until) {
+			return invalidf("invalid date. Use YYYY-MM-DD")
+		}
+		if until < from {
+			return invalidf("--until %s is before --from %s", until, from)
+		}
+		if until < today {
+			return invalidf("--until %s has already passed", until)
+		}
+		f := rankingsFreeze{From: from, Until: until, Reason: reason}
+		if dryRun {
+			fmt.Printf("[dry-run] would freeze the rankings from %s until %s in %s\n", from, until, freezePath())
+			return nil
+		}
+		if err := writeYAMLFile(freezePath(), f); err != nil {
+			return fmt.Errorf("failed to save the freeze: %w", err)
+		}
+		fmt.Printf("✅ Rankings frozen from %s until %s\n", from, until)
+		fmt.Printf("Saved to %s — commit it to freeze the Pages site's rankings too\n", freezePath())
+		return nil
+	},
+}
+
+func init() {
+	rankingsFreezeCmd.Flags().String("from", "", "First day of the freeze (YYYY-MM-DD), defaults to today")
+	rankingsFreezeCmd.Flags().String("until", "", "Last day of the freeze (YYYY-MM-DD)")
+	rankingsFreezeCmd.Flags().String("reason", "", "Why the rankings are frozen, e.g. the playoffs")
+	rankingsFreezeCmd.Flags().Bool("lift", false, "End the freeze now")
+	rankingsFreezeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the freeze without saving it")
+	rankingsCmd.AddCommand(rankingsFreezeCmd)
+}
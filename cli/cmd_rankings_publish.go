@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// releaseTagRegex is the tags "rankings publish" accepts.
+var releaseTagRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// publishTopRows is how many rows of each leaderboard a release's notes
+// show; the attached files have them all.
+const publishTopRows = 10
+
+var rankingsPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish the leaderboards as a tagged GitHub Release",
+	Long: `Publish the leaderboards as they stood at the end of --date (default
+today) as a new GitHub Release tagged --release, so the league has an
+immutable, linkable record of its standings.
+
+The release notes show the top of each leaderboard. Attached are:
+  rankings-<date>.json   both leaderboards, as a rankings snapshot
+  singles-<date>.csv     the singles leaderboard (as "standings export")
+  doubles-<date>.csv     the doubles leaderboard
+  report-<year>.md       the season report so far (as "report season")
+
+--release defaults to the ISO week of --date, e.g. v2025-W07. A tag is
+never reused: publishing to an existing release fails.
+
+Examples:
+  tennis rankings publish
+  tennis rankings publish --release v2025-W07
+  tennis rankings publish --release end-of-season-2025 --date 2025-12-31`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tag, _ := cmd.Flags().GetString("release")
+		date, _ := cmd.Flags().GetString("date")
+
+		if date == "" {
+			date = time.Now().Format("2006-01-02")
+		}
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return invalidf("invalid date '%s'. Use YYYY-MM-DD format", date)
+		}
+		if tag == "" {
+			year, week := day.ISOWeek()
+			tag = fmt.Sprintf("v%d-W%02d", year, week)
+		}
+		if !releaseTagRegex.MatchString(tag) {
+			return invalidf("invalid release tag '%s'. Use letters, digits, dots, dashes and underscores", tag)
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		singles, doubles = playedBy(singles, date), playedBy(doubles, date)
+
+		snap := &leaderboardSnapshot{
+			Date:              date,
+			Generated:         time.Now().UTC().Format("2006-01-02 15:04:05 UTC"),
+			Commit:            gitHead(),
+			Singles:           singlesLeaderboard(singles, roster, date),
+			DoublesIndividual: doublesLeaderboard(doubles, roster, date),
+			Elo:               &elo,
+		}
+		assets, err := publishAssets(snap, singles, doubles)
+		if err != nil {
+			return err
+		}
+		release := &github.RepositoryRelease{
+			TagName: github.String(tag),
+			Name:    github.String(fmt.Sprintf("Rankings %s", tag)),
+			Body:    github.String(publishNotes(snap)),
+		}
+		if snap.Commit != "" {
+			release.TargetCommitish = github.String(snap.Commit)
+		}
+
+		if dryRun {
+			fmt.Printf("[dry-run] would create release %s in %s/%s\n", tag, owner, repo)
+			for _, a := range assets {
+				fmt.Printf("Asset: %s (%d bytes)\n", a.name, len(a.data))
+			}
+			fmt.Printf("\n%s", release.GetBody())
+			return nil
+		}
+
+		client := getGitHubClient()
+		if _, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repo, tag); err == nil {
+			return fmt.Errorf("release %s already exists; published rankings aren't replaced (choose another --release)", tag)
+		} else if !githubapi.IsNotFound(err) {
+			return fmt.Errorf("failed to check for release %s: %w", tag, err)
+		}
+		rel, _, err := client.Repositories.CreateRelease(ctx, owner, repo, release)
+		if err != nil {
+			return fmt.Errorf("failed to create release %s: %w", tag, err)
+		}
+		for _, a := range assets {
+			if _, err := uploadReleaseAsset(ctx, client, rel, a.name, a.mediaType, a.data); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("✅ Published the %s leaderboards as release %s\n", date, tag)
+		fmt.Println(rel.GetHTMLURL())
+		return nil
+	},
+}
+
+// releaseAsset is a file to attach to a release.
+type releaseAsset struct {
+	name      string
+	mediaType string
+	data      []byte
+}
+
+// publishAssets renders the files a rankings release carries. The season
+// report is left out if the season has no matches yet.
+func publishAssets(snap *leaderboardSnapshot, singles, doubles []Match) ([]releaseAsset, error) {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	assets := []releaseAsset{{"rankings-" + snap.Date + ".json", "application/json", append(data, '\n')}}
+
+	for _, board := range []struct {
+		kind string
+		rows []LeaderboardRow
+	}{
+		{"singles", snap.Singles},
+		{"doubles", snap.DoublesIndividual},
+	} {
+		var csv bytes.Buffer
+		if err := writeStandings(&csv, "csv", "", "", board.rows); err != nil {
+			return nil, err
+		}
+		assets = append(assets, releaseAsset{board.kind + "-" + snap.Date + ".csv", "text/csv", csv.Bytes()})
+	}
+
+	year := snap.Date[:4]
+	if report, err := seasonReportMarkdown(year, singles, doubles); err == nil {
+		assets = append(assets, releaseAsset{"report-" + year + ".md", "text/markdown", []byte(report)})
+	}
+	return assets, nil
+}
+
+// publishNotes renders a rankings release's notes: the top of each
+// leaderboard, with players under their public handles.
+func publishNotes(snap *leaderboardSnapshot) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Leaderboards as they stood at the end of %s", snap.Date)
+	if snap.Commit != "" {
+		fmt.Fprintf(&b, " (commit %s)", snap.Commit[:min(len(snap.Commit), 7)])
+	}
+	fmt.Fprintf(&b, ". Elo: %s.\n", snap.Elo)
+	for _, board := range []struct {
+		title string
+		rows  []LeaderboardRow
+	}{
+		{"Singles", snap.Singles},
+		{"Doubles", snap.DoublesIndividual},
+	} {
+		fmt.Fprintf(&b, "\n## %s\n\n", board.title)
+		shown := 0
+		for _, r := range board.rows {
+			if r.Unranked || shown == publishTopRows {
+				continue
+			}
+			if shown == 0 {
+				b.WriteString("| Rank | Player | Rating |\n|---:|---|---:|\n")
+			}
+			fmt.Fprintf(&b, "| %d | %s | %.1f |\n", r.Rank, publicName(r.Player), r.Rating)
+			shown++
+		}
+		if shown == 0 {
+			b.WriteString("No ranked players yet.\n")
+		}
+	}
+	b.WriteString("\nThe attached files have the full leaderboards and the season report so far.\n")
+	return b.String()
+}
+
+func init() {
+	rankingsPublishCmd.Flags().String("release", "", "Tag of the release to create (defaults to the ISO week, e.g. v2025-W07)")
+	rankingsPublishCmd.Flags().String("date", "", "Publish the leaderboards as of this day (YYYY-MM-DD), defaults to today")
+	rankingsPublishCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the release without creating it")
+	rankingsCmd.AddCommand(rankingsPublishCmd)
+}
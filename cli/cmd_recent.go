@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "Show the most recently recorded matches",
+	Long: `Show the last recorded singles and doubles matches, newest first, one line
+per match: the date, the result, each player's rating change and the
+match's issue number (with its repository, for matches from the league's
+other repositories). --long also prints links to the issue and the
+match's page on the Pages site.
+
+Examples:
+  tennis recent
+  tennis recent -n 25
+  tennis recent --long`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		long, _ := cmd.Flags().GetBool("long")
+		if limit < 1 {
+			return invalidf("--limit must be at least 1")
+		}
+
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		type recent struct {
+			match   Match
+			changes map[string]float64
+		}
+		var all []recent
+		for _, kind := range []struct {
+			matches []Match
+			replay  func(map[string]float64, []Match)
+		}{{singles, replaySingles}, {doubles, replayDoubles}} {
+			for i, changes := range matchRatingChanges(kind.matches, kind.replay) {
+				all = append(all, recent{kind.matches[i], changes})
+			}
+		}
+		if len(all) == 0 {
+			fmt.Println("No matches recorded yet.")
+			return nil
+		}
+		sort.SliceStable(all, func(i, j int) bool {
+			return all[i].match.Date+all[i].match.Time > all[j].match.Date+all[j].match.Time
+		})
+		all = all[:min(len(all), limit)]
+
+		site := ""
+		if long {
+			site = pagesURL(ctx)
+		}
+		for _, r := range all {
+			m := r.match
+			var deltas []string
+			for _, p := range matchPlayers(m) {
+				deltas = append(deltas, fmt.Sprintf("%s %+.1f", p, r.changes[p]))
+			}
+			source := owner + "/" + repo
+			issue := fmt.Sprintf("#%d", m.SourceIssue)
+			if m.Repo != "" {
+				source, issue = m.Repo, m.Repo+issue
+			}
+			fmt.Printf("%s  %s  (%s)  %s\n", m.Date, matchHeadline(m), strings.Join(deltas, ", "), issue)
+			if long {
+				fmt.Printf("            https://github.com/%s/issues/%d\n", source, m.SourceIssue)
+				if m.Repo == "" {
+					fmt.Printf("            %smatch_%d.html\n", site, m.SourceIssue)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// matchHeadline summarises a match's result, e.g. "@alice beat @bob 6-3
+// 6-4", with the sets from the winner's side.
+func matchHeadline(m Match) string {
+	return matchHeadlineWith(m, func(p string) string { return "@" + p })
+}
+
+// matchHeadlineWith is matchHeadline with each player shown as name gives
+// them, e.g. publicName for pages outside the league.
+func matchHeadlineWith(m Match, name func(string) string) string {
+	sides := [][]string{m.Players[:min(len(m.Players), 1)], m.Players[min(len(m.Players), 1):]}
+	if m.IsDoubles() {
+		sides = [][]string{m.Team1, m.Team2}
+	}
+	names := make([]string, 2)
+	for i, side := range sides {
+		var handles []string
+		for _, p := range side {
+			handles = append(handles, name(p))
+		}
+		names[i] = strings.Join(handles, " & ")
+	}
+	w := matchWinner(m) - 1
+	var sets []string
+	for _, s := range m.Sets {
+		if len(s) != 2 {
+			continue
+		}
+		if w == 1 {
+			s = []int{s[1], s[0]}
+		}
+		sets = append(sets, fmt.Sprintf("%d-%d", s[0], s[1]))
+	}
+	if w < 0 {
+		return fmt.Sprintf("%s vs %s %s", names[0], names[1], strings.Join(sets, " "))
+	}
+	return fmt.Sprintf("%s beat %s %s", names[w], names[1-w], strings.Join(sets, " "))
+}
+
+func init() {
+	recentCmd.Flags().IntP("limit", "n", 10, "Number of matches to show")
+	recentCmd.Flags().Bool("long", false, "Also print links to each match's issue and page")
+	rootCmd.AddCommand(recentCmd)
+}
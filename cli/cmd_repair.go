@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+// formatCodes are audit codes that a reformatted issue body can fix.
+var formatCodes = map[string]bool{
+	"missing_date":       true,
+	"missing_players":    true,
+	"wrong_player_count": true,
+	"missing_sets":       true,
+	"invalid_set":        true,
+}
+
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Fix malformed match issues found by audit",
+	Long: `Repair open match issues with audit errors.
+
+Where the match can be read unambiguously (for example a date written
+2025/8/5, players separated by "vs", or scores like "6–3, 6–4" on one line),
+the issue body is rewritten into the standard format with a comment noting
+the automated fix; editing the issue re-runs the issue-to-PR workflow.
+Unlabelled match issues get their missing label. Anything else (bad dates,
+tied sets, no winner, self-matches) is labelled needs-correction with a
+comment asking the reporter to fix it.
+
+By default repair stops at the first issue it can't update;
+--continue-on-error carries on and reports the failures at the end. The
+issues left over are listed in a retry file for --retry, and --summary
+writes every issue's outcome as JSON.
+
+Examples:
+  tennis repair --dry-run
+  tennis audit --format json --out audit.json && tennis repair --from audit.json
+  tennis repair --issue 42
+  tennis repair --continue-on-error --summary repair.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		only, _ := cmd.Flags().GetInt("issue")
+		b, err := newBatch(cmd)
+		if err != nil {
+			return err
+		}
+
+		var report *auditReport
+		if from != "" {
+			data, err := os.ReadFile(from)
+			if err != nil {
+				return err
+			}
+			report = &auditReport{}
+			if err := json.Unmarshal(data, report); err != nil {
+				return invalidf("invalid audit report %s: %v", from, err)
+			}
+		} else {
+			var err error
+			if report, err = runAudit(cmd.Context(), "open"); err != nil {
+				return err
+			}
+		}
+
+		byIssue := make(map[int][]auditFinding)
+		for _, f := range report.Findings {
+			if f.Severity == severityError && (only == 0 || f.Issue == only) && b.Wants(fmt.Sprintf("#%d", f.Issue)) {
+				byIssue[f.Issue] = append(byIssue[f.Issue], f)
+			}
+		}
+		if len(byIssue) == 0 {
+			fmt.Println("✅ Nothing to repair")
+			return nil
+		}
+
+		numbers := make([]int, 0, len(byIssue))
+		for n := range byIssue {
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+		items := make([]string, len(numbers))
+		for i, n := range numbers {
+			items[i] = fmt.Sprintf("#%d", n)
+		}
+
+		if err := confirm("Repair or flag %d issues in %s/%s?", len(numbers), owner, repo); err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		var fixed, flagged int
+		b.Run(items, func(i int) error {
+			n := numbers[i]
+			issue, _, err := client.Issues.Get(ctx, owner, repo, n)
+			if err != nil {
+				return fmt.Errorf("failed to fetch #%d: %w", n, err)
+			}
+			if issue.GetState() != "open" {
+				fmt.Printf("#%d is closed, skipping\n", n)
+				return skipItem("closed")
+			}
+			ok, err := repairIssue(ctx, client, issue, byIssue[n])
+			if err != nil {
+				return err
+			}
+			if ok {
+				fixed++
+			} else {
+				flagged++
+			}
+			return nil
+		})
+		fmt.Printf("\n%d issues repaired, %d flagged for manual correction\n", fixed, flagged)
+		return b.Finish()
+	},
+}
+
+// repairIssue fixes one issue if it can, otherwise flags it. It reports
+// whether the issue was fixed.
+func repairIssue(ctx context.Context, client *github.Client, issue *github.Issue, findings []auditFinding) (bool, error) {
+	n := issue.GetNumber()
+	kind, labelled, ok := matchIssueKind(issue)
+	if !ok || kind == "conflict" {
+		return false, flagIssue(ctx, client, issue, findings)
+	}
+
+	var fixes []string
+	var body string
+	for _, f := range findings {
+		if f.Code != "missing_label" && !formatCodes[f.Code] {
+			return false, flagIssue(ctx, client, issue, findings)
+		}
+	}
+	if hasFormatFinding(findings) {
+		m, ok := repairMatchIssue(kind, issue.GetBody())
+		if !ok {
+			return false, flagIssue(ctx, client, issue, findings)
+		}
+		body = m.Body()
+		fixes = append(fixes, "rewrote the issue into the standard format")
+	}
+	label := labelNames.Singles
+	if kind == "doubles" {
+		label = labelNames.Doubles
+	}
+	if !labelled {
+		fixes = append(fixes, "added the "+label+" label")
+	}
+
+	fmt.Printf("🔧 #%d: %s\n", n, strings.Join(fixes, ", "))
+	if dryRun {
+		if body != "" {
+			fmt.Printf("%s\n\n", body)
+		}
+		return true, nil
+	}
+
+	if !labelled {
+		if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, n, []string{label}); err != nil {
+			return false, fmt.Errorf("failed to label #%d: %w", n, err)
+		}
+	}
+	if body != "" {
+		if _, _, err := client.Issues.Edit(ctx, owner, repo, n, &github.IssueRequest{Body: &body}); err != nil {
+			return false, fmt.Errorf("failed to update #%d: %w", n, err)
+		}
+	}
+	if hasLabel(issue, labelNames.NeedsCorrection) {
+		if _, err := client.Issues.RemoveLabelForIssue(ctx, owner, repo, n, labelNames.NeedsCorrection); err != nil {
+			return false, fmt.Errorf("failed to unlabel #%d: %w", n, err)
+		}
+	}
+	comment := fmt.Sprintf("🔧 This match issue was repaired automatically: %s. Please check the details are still right and edit the issue if not.", strings.Join(fixes, ", "))
+	if _, err := commentOnce(ctx, client, n, comment, "repaired:"+strings.Join(fixes, ",")); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// flagIssue labels an issue needs-correction and explains what's wrong.
+// Issues already flagged are left alone so reruns don't repeat the comment.
+func flagIssue(ctx context.Context, client *github.Client, issue *github.Issue, findings []auditFinding) error {
+	n := issue.GetNumber()
+	var lines []string
+	for _, f := range findings {
+		lines = append(lines, "- "+f.Message)
+	}
+	fmt.Printf("🚩 #%d needs manual correction: %s\n", n, findings[0].Message)
+	if dryRun || hasLabel(issue, labelNames.NeedsCorrection) {
+		return nil
+	}
+
+	if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, n, []string{labelNames.NeedsCorrection}); err != nil {
+		return fmt.Errorf("failed to label #%d: %w", n, err)
+	}
+	var codes []string
+	for _, f := range findings {
+		codes = append(codes, f.Code)
+	}
+	comment := fmt.Sprintf("This match couldn't be recorded and can't be fixed automatically:\n\n%s\n\nPlease edit the issue to correct it; the bot will re-check it automatically.", strings.Join(lines, "\n"))
+	if _, err := commentOnce(ctx, client, n, comment, labelNames.NeedsCorrection+":"+strings.Join(codes, ",")); err != nil {
+		return err
+	}
+	return nil
+}
+
+func hasFormatFinding(findings []auditFinding) bool {
+	for _, f := range findings {
+		if formatCodes[f.Code] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabel(issue *github.Issue, name string) bool {
+	for _, l := range issue.Labels {
+		if l.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	repairCmd.Flags().String("from", "", "Audit report (tennis audit --format json) to repair from; runs a fresh audit if omitted")
+	repairCmd.Flags().Int("issue", 0, "Only repair this issue number")
+	repairCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the fixes without editing issues")
+	addBatchFlags(repairCmd)
+
+	rootCmd.AddCommand(repairCmd)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Produce printable league reports",
+}
+
+var reportSeasonCmd = &cobra.Command{
+	Use:   "season <year>",
+	Short: "Report on a season: awards, standings and charts",
+	Long: `Report on a season: the season's awards (singles champion, most
+improved, most active, best record among players with at least 5
+matches, and longest winning streak), singles
+and doubles standings with each player's wins above expectation, and charts
+of matches per month and final singles ratings.
+
+Formats:
+  markdown  for the repository or a GitHub issue
+  pdf       for printing and the club noticeboard (requires --out)
+
+Examples:
+  tennis report season 2025
+  tennis report season 2025 --format pdf --out season-2025.pdf`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		year := args[0]
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+
+		if !yearRegex.MatchString(year) {
+			return invalidf("invalid season '%s'. Use a year like 2024", year)
+		}
+		if format != "markdown" && format != "md" && format != "pdf" {
+			return invalidf("unknown format '%s' (use markdown or pdf)", format)
+		}
+		if format == "pdf" && out == "" {
+			return invalidf("--out is required for pdf output")
+		}
+		if y, _ := strconv.Atoi(year); y >= time.Now().Year() {
+			fmt.Fprintf(os.Stderr, "⚠️  The %s season isn't over yet; the report only covers matches so far\n", year)
+		}
+
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		report, err := seasonReportMarkdown(year, singles, doubles)
+		if err != nil {
+			return err
+		}
+
+		if out == "" {
+			_, err := io.WriteString(os.Stdout, report)
+			return err
+		}
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if format == "pdf" {
+			err = writeTextPDF(f, year+" season report", reportPDFLines(report))
+		} else {
+			_, err = io.WriteString(f, report)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Season report written to %s\n", out)
+		return nil
+	},
+}
+
+// reportPDFLines turns a markdown report into plain lines for the PDF,
+// dropping heading marks, emphasis and code fences.
+func reportPDFLines(report string) []string {
+	var lines []string
+	for _, l := range strings.Split(report, "\n") {
+		if strings.HasPrefix(l, "```") {
+			continue
+		}
+		l = strings.TrimLeft(l, "#")
+		lines = append(lines, strings.TrimSpace(strings.ReplaceAll(l, "**", "")))
+	}
+	return lines
+}
+
+func init() {
+	reportSeasonCmd.Flags().StringP("format", "f", "markdown", "Report format: markdown or pdf")
+	reportSeasonCmd.Flags().StringP("out", "o", "", "Write to a file instead of stdout")
+
+	reportCmd.AddCommand(reportSeasonCmd)
+	rootCmd.AddCommand(reportCmd)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var reposCmd = &cobra.Command{
+	Use:   "repos",
+	Short: "Work with the league's other repositories",
+	Long: `A league can be split across several repositories, e.g. one per club or
+location. List the others under repos in .tennis.yml and their matches
+count towards rankings, stats and reports alongside this repository's,
+each tagged with the repository it was recorded in.`,
+}
+
+var reposListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the league's repositories and how many matches each holds",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(leagueRepos) == 0 {
+			fmt.Println("No other repositories are listed under repos in .tennis.yml")
+			return nil
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		fmt.Printf("%-40s %8s %8s\n", "Repository", "Singles", "Doubles")
+		for i, name := range append([]string{owner + "/" + repo}, repoNames()...) {
+			source := ""
+			if i > 0 {
+				source = name
+			}
+			fmt.Printf("%-40s %8d %8d\n", name, countFrom(singles, source), countFrom(doubles, source))
+		}
+		return nil
+	},
+}
+
+var reposPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Copy the other repositories' match files into this checkout",
+	Long: `Copy the match files of every repository listed under repos in .tennis.yml
+into this checkout's singles-matches/ and doubles-matches/, so the Pages
+build and the ranking scripts see the whole league. Each copy is named
+e.g. <date>--<owner>--<repo>-<issue>.yml and records its repository as
+repo:. Files
+pulled before are kept, and the CLI never counts a match twice.
+
+The Rebuild Rankings workflow runs this before building the site; the
+pulled files aren't meant to be committed.
+
+Examples:
+  tennis repos pull
+  tennis repos pull --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(leagueRepos) == 0 {
+			fmt.Println("No other repositories are listed under repos in .tennis.yml")
+			return nil
+		}
+		written := 0
+		for _, dir := range []string{"singles-matches", "doubles-matches"} {
+			files, err := filepath.Glob(filepath.Join(leagueDir(), dir, "*.yml"))
+			if err != nil {
+				return err
+			}
+			var local []Match
+			for _, fn := range files {
+				data, err := os.ReadFile(fn)
+				if err != nil {
+					return err
+				}
+				if m, err := parseMatchFile(fn, data, nil); err == nil {
+					local = append(local, m)
+				}
+			}
+			// Aliases are applied when loading, not in the pulled copies.
+			remote, err := loadRepoMatches(cmd.Context(), dir, nil, local)
+			if err != nil {
+				return err
+			}
+			for _, m := range remote {
+				path := filepath.Join(leagueDir(), dir, filepath.Base(m.File))
+				if dryRun {
+					fmt.Printf("[dry-run] would write %s\n", path)
+					continue
+				}
+				if err := writeYAMLFile(path, m); err != nil {
+					return err
+				}
+				written++
+			}
+		}
+		if !dryRun {
+			fmt.Printf("✅ %d match files pulled from %d repositories\n", written, len(leagueRepos))
+		}
+		return nil
+	},
+}
+
+// repoNames lists the league's other repositories.
+func repoNames() []string {
+	var names []string
+	for _, r := range leagueRepos {
+		names = append(names, r.Repo)
+	}
+	return names
+}
+
+// countFrom counts the matches recorded in a repository ("" for this one).
+func countFrom(matches []Match, source string) int {
+	n := 0
+	for _, m := range matches {
+		if m.Repo == source {
+			n++
+		}
+	}
+	return n
+}
+
+func init() {
+	reposPullCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the files without writing them")
+
+	reposCmd.AddCommand(reposListCmd, reposPullCmd)
+	rootCmd.AddCommand(reposCmd)
+}
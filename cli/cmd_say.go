@@ -0,0 +1,408 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var sayCmd = &cobra.Command{
+	Use:   "say <sentence>",
+	Short: "Report a match result in plain English",
+	Long: `Report a match by describing it in a sentence, e.g. "I beat @bob 6-3 4-6
+10-8 yesterday on clay". The sentence is read into the players, sets,
+date, time and surface; the reading is shown for confirmation, then the
+match issue is opened as "tennis match singles" or "tennis match doubles"
+would open it.
+
+What it understands:
+  players   @handles; "I", "me" and "we" are the owner of the GitHub token
+            (or --player), and "with @x" is a partner on the speaker's side
+  result    "beat", "defeated" or "won against" for a win, "lost to" or
+            "was beaten by" for a loss, or "vs" and the score decides
+  sets      scores like 6-3, 6:3 or 7-6(5), from either side's point of view
+  date      today (the default), yesterday, 3 days ago, (last) monday,
+            15 june, june 15th or 2025-06-15
+  time      at 18:30, or 6:30pm
+  surface   on clay, on grass, on carpet, on hard, or a hard/clay court
+
+Examples:
+  tennis say "I beat @bob 6-3 4-6 10-8 yesterday on clay"
+  tennis say "lost to @carol 4-6 3-6 on monday at 18:30"
+  tennis say "@alice and I beat @bob and @dave 6-4 6-4"
+  tennis say "we lost to @bob and @dave 3-6 6-7(4) with @alice" --yes
+  tennis say "@alice vs @bob 6-2 6-1 on 15 june" --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		player, _ := cmd.Flags().GetString("player")
+		ctx := cmd.Context()
+
+		// The speaker is only looked up if the sentence refers to them.
+		me := func() (string, error) {
+			if player == "" {
+				user, _, err := getGitHubClient().Users.Get(ctx, "")
+				if err != nil {
+					return "", fmt.Errorf("failed to look up who \"I\" is (use --player): %w", err)
+				}
+				player = user.GetLogin()
+			}
+			return normalizePlayer(player), nil
+		}
+		said, err := parseSaid(strings.Join(args, " "), me, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(said)
+		if err := confirm("Report this %s match?", said.Kind); err != nil {
+			return err
+		}
+
+		if err := checkRules(ctx, said.Kind, said.Date, said.Sets); err != nil {
+			return err
+		}
+		if err := validateHandles(ctx, append(append([]string{}, said.Sides[0]...), said.Sides[1]...)); err != nil {
+			return err
+		}
+		if said.Kind == "doubles" {
+			return createDoublesIssue(ctx, said.Sides, said.Sets, said.Date, said.Time, said.Surface)
+		}
+		return createSinglesIssue(ctx, []string{said.Sides[0][0], said.Sides[1][0]}, said.Sets, said.Date, said.Time, said.Surface)
+	},
+}
+
+// saidMatch is a match result read from a sentence.
+type saidMatch struct {
+	Kind    string     // "singles" or "doubles"
+	Sides   [][]string // @handles, the winners first
+	Sets    []string   // e.g. "6-3", the winners' games first
+	Date    string
+	Day     string // how the date was given, e.g. "yesterday", if not as a date
+	Time    string // HH:MM, if given
+	Surface string // if given
+}
+
+// String shows the reading for confirmation.
+func (s saidMatch) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Read as a %s match:\n", s.Kind)
+	fmt.Fprintf(&b, "  Result:  %s beat %s\n", strings.Join(s.Sides[0], " & "), strings.Join(s.Sides[1], " & "))
+	fmt.Fprintf(&b, "  Sets:    %s\n", strings.Join(s.Sets, ", "))
+	date := s.Date
+	if s.Day != "" {
+		date += " (" + s.Day + ")"
+	}
+	fmt.Fprintf(&b, "  Date:    %s\n", date)
+	if s.Time != "" {
+		fmt.Fprintf(&b, "  Time:    %s\n", s.Time)
+	}
+	if s.Surface != "" {
+		fmt.Fprintf(&b, "  Surface: %s\n", s.Surface)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+var (
+	saidPlayerRegex  = regexp.MustCompile(`@[A-Za-z0-9-]+|\b(?i:i|me|myself|we|us)\b`)
+	saidPartnerRegex = regexp.MustCompile(`(?i)\b(?:with|partnering|alongside)\s+(?:my\s+partner\s+)?@([A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)`)
+	saidVerbRegex    = regexp.MustCompile(`(?i)\b(?:(?:was|were|got)\s+beaten\s+by|beaten\s+by|lost\s+(?:to|against|vs\.?)|lose\s+to|fell\s+to|went\s+down\s+to)\b|` +
+		`\b(?:beat|beats|defeated|defeat|defeats|won\s+(?:against|over|vs\.?)|win\s+(?:against|over)|edged(?:\s+out)?|overcame)\b|` +
+		`\b(?:vs\.?|v\.?|versus|against)(?:\s|$)`)
+	saidLossRegex    = regexp.MustCompile(`(?i)beaten|lost|lose|fell|went`)
+	saidWinRegex     = regexp.MustCompile(`(?i)beat|defeat|won|win|edged|overcame`)
+	saidTimeRegex    = regexp.MustCompile(`(?i)\bat\s+(\d{1,2})[:.](\d{2})\b|\b(?:at\s+)?(\d{1,2})(?:[:.](\d{2}))?\s*(am|pm)\b`)
+	saidSurfaceRegex = regexp.MustCompile(`(?i)\b(on\s+(?:the\s+|a\s+)?)?(clay|grass|carpet|hard)([\s-]?courts?)?\b`)
+	saidAgoRegex     = regexp.MustCompile(`(?i)\b(\d+|a|one|two|three|four|five|six|seven)\s+days?\s+ago\b`)
+	saidWeekdayRegex = regexp.MustCompile(`(?i)\b(?:(last|on)\s+)?(monday|tuesday|wednesday|thursday|friday|saturday|sunday|mon|tue|tues|wed|thu|thur|thurs|fri|sat|sun)\b`)
+	saidDayMonth     = regexp.MustCompile(`(?i)\b(?:on\s+)?(?:the\s+)?(\d{1,2})(?:st|nd|rd|th)?(?:\s+of)?\s+(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\b`)
+	saidMonthDay     = regexp.MustCompile(`(?i)\b(?:on\s+)?(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\s+(\d{1,2})(?:st|nd|rd|th)?\b`)
+	saidTodayRegex   = regexp.MustCompile(`(?i)\b(today|tonight|this\s+(?:morning|afternoon|evening)|yesterday)\b`)
+)
+
+// parseSaid reads a sentence describing a match into a result. me returns
+// the speaker's handle, for "I", "me" and "we" and when no one is named
+// before the verb. Each part that's understood is cut from the sentence,
+// so that a date's digits are never read as a score.
+func parseSaid(text string, me func() (string, error), now time.Time) (saidMatch, error) {
+	var said saidMatch
+	rest := " " + strings.Join(strings.Fields(text), " ") + " "
+	cut := func(loc []int) {
+		rest = rest[:loc[0]] + " " + rest[loc[1]:]
+	}
+
+	// Date: at most one way of giving it.
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	var dates []time.Time
+	var days []string
+	if loc := looseDateRegex.FindStringSubmatchIndex(rest); loc != nil {
+		d, err := time.ParseInLocation("2006-1-2", rest[loc[2]:loc[3]]+"-"+rest[loc[4]:loc[5]]+"-"+rest[loc[6]:loc[7]], time.Local)
+		if err != nil {
+			return said, invalidf("'%s' isn't a real date", strings.TrimSpace(rest[loc[0]:loc[1]]))
+		}
+		dates, days = append(dates, d), append(days, "")
+		cut(loc[:2])
+	}
+	for _, re := range []*regexp.Regexp{saidDayMonth, saidMonthDay} {
+		if loc := re.FindStringSubmatchIndex(rest); loc != nil {
+			day, month := rest[loc[2]:loc[3]], rest[loc[4]:loc[5]]
+			if re == saidMonthDay {
+				day, month = month, day
+			}
+			d, err := time.ParseInLocation("2 Jan 2006", day+" "+strings.ToUpper(month[:1])+strings.ToLower(month[1:3])+" "+strconv.Itoa(today.Year()), time.Local)
+			if err != nil {
+				return said, invalidf("'%s' isn't a real date", strings.TrimSpace(rest[loc[0]:loc[1]]))
+			}
+			if d.After(today) {
+				d = d.AddDate(-1, 0, 0) // a date still to come this year was last year's
+			}
+			dates, days = append(dates, d), append(days, strings.TrimSpace(rest[loc[0]:loc[1]]))
+			cut(loc[:2])
+		}
+	}
+	if loc := saidAgoRegex.FindStringSubmatchIndex(rest); loc != nil {
+		n, err := strconv.Atoi(rest[loc[2]:loc[3]])
+		if err != nil {
+			n = map[string]int{"a": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7}[strings.ToLower(rest[loc[2]:loc[3]])]
+		}
+		dates, days = append(dates, today.AddDate(0, 0, -n)), append(days, strings.TrimSpace(rest[loc[0]:loc[1]]))
+		cut(loc[:2])
+	}
+	if loc := saidTodayRegex.FindStringSubmatchIndex(rest); loc != nil {
+		d := today
+		if strings.EqualFold(rest[loc[2]:loc[3]], "yesterday") {
+			d = today.AddDate(0, 0, -1)
+		}
+		dates, days = append(dates, d), append(days, strings.ToLower(rest[loc[2]:loc[3]]))
+		cut(loc[:2])
+	}
+	if loc := saidWeekdayRegex.FindStringSubmatchIndex(rest); loc != nil {
+		wd, err := parseWeekday(rest[loc[4]:loc[5]])
+		if err != nil {
+			return said, err
+		}
+		// The latest such day before today: a match today is "today".
+		back := (int(today.Weekday()) - int(wd) + 7) % 7
+		if back == 0 {
+			back = 7
+		}
+		dates, days = append(dates, today.AddDate(0, 0, -back)), append(days, strings.TrimSpace(rest[loc[0]:loc[1]]))
+		cut(loc[:2])
+	}
+	switch {
+	case len(dates) > 1:
+		return said, invalidf("the sentence gives more than one date (%s); give just one", strings.Join(dateList(dates), ", "))
+	case len(dates) == 1:
+		if dates[0].After(today) {
+			return said, invalidf("%s is in the future", dates[0].Format("2006-01-02"))
+		}
+		said.Date, said.Day = dates[0].Format("2006-01-02"), days[0]
+	default:
+		said.Date, said.Day = today.Format("2006-01-02"), "today"
+	}
+
+	// Time: "at 18:30", or with am or pm, so that "6:3" stays a set.
+	if g := saidTimeRegex.FindStringSubmatchIndex(rest); g != nil {
+		var hour, minute int
+		if g[2] >= 0 {
+			hour, _ = strconv.Atoi(rest[g[2]:g[3]])
+			minute, _ = strconv.Atoi(rest[g[4]:g[5]])
+		} else {
+			hour, _ = strconv.Atoi(rest[g[6]:g[7]])
+			if g[8] >= 0 {
+				minute, _ = strconv.Atoi(rest[g[8]:g[9]])
+			}
+			if hour < 1 || hour > 12 {
+				return said, invalidf("'%s' isn't a time", strings.TrimSpace(rest[g[0]:g[1]]))
+			}
+			hour %= 12
+			if strings.EqualFold(rest[g[10]:g[11]], "pm") {
+				hour += 12
+			}
+		}
+		t, err := parseTimeOfDay(fmt.Sprintf("%02d:%02d", hour, minute))
+		if err != nil {
+			return said, err
+		}
+		said.Time = t
+		cut(g[:2])
+	}
+
+	// Surface: "hard" only as "on hard" or "hard court", as it's also an
+	// adjective.
+	for _, g := range saidSurfaceRegex.FindAllStringSubmatchIndex(rest, -1) {
+		surface := strings.ToLower(rest[g[4]:g[5]])
+		if surface == "hard" && g[2] < 0 && g[6] < 0 {
+			continue
+		}
+		if said.Surface != "" && said.Surface != surface {
+			return said, invalidf("the sentence names two surfaces, %s and %s; give just one", said.Surface, surface)
+		}
+		said.Surface = surface
+	}
+	if said.Surface != "" {
+		rest = saidSurfaceRegex.ReplaceAllStringFunc(rest, func(s string) string {
+			if strings.Contains(strings.ToLower(s), said.Surface) {
+				return " "
+			}
+			return s
+		})
+	}
+
+	// Sets, as written: from the point of view of whoever is named first.
+	var sets [][]int
+	rest = parenRegex.ReplaceAllString(rest, " ")
+	for _, g := range looseSetRegex.FindAllStringSubmatchIndex(rest, -1) {
+		g1, _ := strconv.Atoi(rest[g[2]:g[3]])
+		g2, _ := strconv.Atoi(rest[g[4]:g[5]])
+		sets = append(sets, []int{g1, g2})
+	}
+	rest = looseSetRegex.ReplaceAllString(rest, " ")
+	if len(sets) == 0 {
+		return said, invalidf("no score found; give the sets like 6-3 4-6 10-8")
+	}
+
+	// Players: those before the verb are one side, those after it the
+	// other, and a partner named "with @x" joins the speaker.
+	var partners []string
+	for _, g := range saidPartnerRegex.FindAllStringSubmatch(rest, -1) {
+		partners = append(partners, normalizePlayer(g[1]))
+	}
+	rest = saidPartnerRegex.ReplaceAllString(rest, " ")
+	verb := saidVerbRegex.FindStringIndex(rest)
+	if verb == nil {
+		return said, invalidf("couldn't tell who won; say who beat or lost to whom, e.g. \"I beat @bob 6-3 6-4\"")
+	}
+	won := 0 // the side that won by the verb: 1 for the first named, 2 for the second, 0 if the score decides
+	switch word := rest[verb[0]:verb[1]]; {
+	case saidLossRegex.MatchString(word):
+		won = 2
+	case saidWinRegex.MatchString(word):
+		won = 1
+	}
+	speaker := ""
+	side := func(part string) ([]string, error) {
+		var handles []string
+		for _, tok := range saidPlayerRegex.FindAllString(part, -1) {
+			h := normalizePlayer(tok)
+			if !strings.HasPrefix(tok, "@") {
+				var err error
+				if speaker, err = me(); err != nil {
+					return nil, err
+				}
+				h = speaker
+			}
+			if !containsString(handles, h) {
+				handles = append(handles, h)
+			}
+		}
+		return handles, nil
+	}
+	first, err := side(rest[:verb[0]])
+	if err != nil {
+		return said, err
+	}
+	second, err := side(rest[verb[1]:])
+	if err != nil {
+		return said, err
+	}
+	if len(first) == 0 { // "beat @bob 6-3 6-4"
+		if speaker, err = me(); err != nil {
+			return said, err
+		}
+		first = []string{speaker}
+	}
+	if len(partners) > 0 {
+		speakerSide := &first
+		if containsString(second, speaker) && !containsString(first, speaker) {
+			speakerSide = &second
+		}
+		for _, p := range partners {
+			if !containsString(*speakerSide, p) {
+				*speakerSide = append(*speakerSide, p)
+			}
+		}
+	}
+	for _, p := range first {
+		if containsString(second, p) {
+			return said, invalidf("@%s is on both sides", p)
+		}
+	}
+	switch {
+	case len(first) == 1 && len(second) == 1:
+		said.Kind = "singles"
+	case len(first) == 2 && len(second) == 2:
+		said.Kind = "doubles"
+	default:
+		return said, invalidf("found %s against %s; a match needs one player or two on each side", handleList(first), handleList(second))
+	}
+
+	// Write the winners first, and their games first in every set.
+	wins := 0
+	for _, s := range sets {
+		if s[0] > s[1] {
+			wins++
+		} else if s[1] > s[0] {
+			wins--
+		}
+	}
+	if wins == 0 {
+		return said, invalidf("the sets are split evenly, so the match has no winner")
+	}
+	if won == 0 {
+		won = 1
+		if wins < 0 {
+			won = 2
+		}
+	}
+	if won == 2 {
+		first, second = second, first
+	}
+	for _, s := range sets {
+		if wins < 0 { // the score was given from the loser's side
+			s[0], s[1] = s[1], s[0]
+		}
+		said.Sets = append(said.Sets, fmt.Sprintf("%d-%d", s[0], s[1]))
+	}
+	for _, handles := range [][]string{first, second} {
+		var at []string
+		for _, h := range handles {
+			at = append(at, "@"+h)
+		}
+		said.Sides = append(said.Sides, at)
+	}
+	return said, nil
+}
+
+// handleList is a side for a message, e.g. "@a and @b" or "no one".
+func handleList(handles []string) string {
+	if len(handles) == 0 {
+		return "no one"
+	}
+	var at []string
+	for _, h := range handles {
+		at = append(at, "@"+h)
+	}
+	return strings.Join(at, " and ")
+}
+
+func dateList(dates []time.Time) []string {
+	var out []string
+	for _, d := range dates {
+		out = append(out, d.Format("2006-01-02"))
+	}
+	return out
+}
+
+func init() {
+	sayCmd.Flags().String("player", "", "Who \"I\", \"me\" and \"we\" are (defaults to the token's user)")
+	sayCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the issue that would be created without creating it")
+	sayCmd.Flags().BoolVar(&noValidate, "no-validate", false, "Skip checking that player handles exist on GitHub")
+	sayCmd.Flags().BoolVar(&qrTerminal, "qr", false, "Print a QR code of the issue link, for the opponent to scan and approve")
+	sayCmd.Flags().StringVar(&qrOut, "qr-out", "", "Write a QR code of the issue link to this PNG file")
+
+	rootCmd.AddCommand(sayCmd)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var seasonCmd = &cobra.Command{
+	Use:   "season",
+	Short: "Manage league seasons",
+}
+
+var yearRegex = regexp.MustCompile(`^\d{4}$`)
+
+var seasonArchiveCmd = &cobra.Command{
+	Use:   "archive <year>",
+	Short: "Export a season into a frozen bundle",
+	Long: `Export a season's matches, standings and final rankings into
+<out>/<year>/:
+
+  singles-matches/, doubles-matches/   the season's match files as recorded
+  standings-singles.csv                per-player record for the season
+  standings-doubles.csv
+  rankings.json                        ratings as they stood on 31 December
+  manifest.json                        season, source repo and commit
+  README.md                            summary with the final leaderboard
+
+With --release the bundle is zipped and attached to a GitHub Release tagged
+season-<year>.
+
+Examples:
+  tennis season archive 2024
+  tennis season archive 2024 --out archive/ --release`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		year := args[0]
+		out, _ := cmd.Flags().GetString("out")
+		release, _ := cmd.Flags().GetBool("release")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if !yearRegex.MatchString(year) {
+			return invalidf("invalid season '%s'. Use a year like 2024", year)
+		}
+		if y, _ := strconv.Atoi(year); y >= time.Now().Year() {
+			fmt.Fprintf(os.Stderr, "⚠️  The %s season isn't over yet; the archive will only include matches so far\n", year)
+		}
+
+		dir := filepath.Join(out, year)
+		if _, err := os.Stat(dir); err == nil {
+			if !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", dir)
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				return err
+			}
+		}
+
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		manifest, err := writeSeasonArchive(year, dir, singles, doubles)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Archived %d singles and %d doubles matches to %s\n", manifest.SinglesMatches, manifest.DoublesMatches, dir)
+
+		if !release {
+			return nil
+		}
+		zipPath := filepath.Join(out, "season-"+year+".zip")
+		if err := zipDir(dir, zipPath); err != nil {
+			return fmt.Errorf("failed to zip archive: %w", err)
+		}
+		return publishSeasonRelease(cmd.Context(), year, dir, zipPath)
+	},
+}
+
+// publishSeasonRelease creates the season-<year> release and attaches the
+// zipped bundle.
+func publishSeasonRelease(ctx context.Context, year, dir, zipPath string) error {
+	client := getGitHubClient()
+
+	tag := "season-" + year
+	if _, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repo, tag); err == nil {
+		return fmt.Errorf("release %s already exists", tag)
+	}
+
+	notes, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		return err
+	}
+	rel, _, err := client.Repositories.CreateRelease(ctx, owner, repo, &github.RepositoryRelease{
+		TagName: github.String(tag),
+		Name:    github.String(year + " season"),
+		Body:    github.String(string(notes)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create release %s: %w", tag, err)
+	}
+
+	f, err := os.Open(zipPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, _, err := client.Repositories.UploadReleaseAsset(ctx, owner, repo, rel.GetID(),
+		&github.UploadOptions{Name: filepath.Base(zipPath)}, f); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", zipPath, err)
+	}
+	fmt.Printf("✅ Published release %s: %s\n", tag, rel.GetHTMLURL())
+	return nil
+}
+
+func init() {
+	seasonArchiveCmd.Flags().String("out", "archive", "Directory to write the archive into")
+	seasonArchiveCmd.Flags().Bool("release", false, "Attach the zipped archive to a GitHub Release tagged season-<year>")
+	seasonArchiveCmd.Flags().Bool("force", false, "Overwrite an existing archive for the season")
+
+	seasonCmd.AddCommand(seasonArchiveCmd)
+	rootCmd.AddCommand(seasonCmd)
+}
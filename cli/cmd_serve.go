@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the league's scheduled jobs in one long-lived process",
+	Long: `Run the jobs under schedule in .tennis.yml on their cron schedules until
+interrupted, for clubs that would rather keep one process running on a
+server than schedule many Actions workflows. A job is any tennis command:
+syncing the league repos ("repos pull"), reminders ("bot nudge"), expiry
+("bot expire"), a weekly digest ("rankings changelog") or publishing
+snapshots ("rankings publish", "rankings snapshot save --release").
+
+schedule:
+  - name: expire
+    cron: "0 3 * * *"
+    run: bot expire
+  - name: digest
+    cron: "0 18 * * 0"
+    run: rankings changelog --since {today-6} --out weekly-digest.md
+
+cron is minute, hour, day of month, month and day of week (0 is Sunday),
+in local time. In run, {today} stands for today's date and {today-N} for
+the date N days ago. Each job runs as its own tennis process with this
+command's --dir, --owner, --repo, --trace and token, and with --yes,
+since nobody is there to confirm. Jobs run one at a time; a run that
+comes due while another job is still going is skipped, and logged.
+
+--list shows the jobs and when each runs next; --run runs one job now.
+
+Examples:
+  tennis serve
+  tennis serve --list
+  tennis serve --run digest`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list, _ := cmd.Flags().GetBool("list")
+		runNow, _ := cmd.Flags().GetString("run")
+
+		if len(leagueSchedule) == 0 {
+			return invalidf("no jobs are scheduled; add them under schedule in %s", configPath())
+		}
+		for _, j := range leagueSchedule {
+			args := j.args(time.Now())
+			if c, _, err := cmd.Root().Find(args); err != nil || c == cmd.Root() || c == cmd {
+				return invalidf("invalid .tennis.yml: schedule: job %q runs %q, which isn't a tennis command", j.Name, j.Run)
+			}
+		}
+
+		now := time.Now()
+		if list {
+			fmt.Printf("%-12s %-16s %-17s %s\n", "Job", "Cron", "Next run", "Command")
+			for _, j := range leagueSchedule {
+				next := "never"
+				if t := j.schedule.next(now); !t.IsZero() {
+					next = t.Format("2006-01-02 15:04")
+				}
+				fmt.Printf("%-12s %-16s %-17s %s\n", j.Name, j.Cron, next, j.Run)
+			}
+			return nil
+		}
+
+		ctx := cmd.Context()
+		if runNow != "" {
+			for _, j := range leagueSchedule {
+				if j.Name == runNow {
+					return runScheduledJob(ctx, j, now)
+				}
+			}
+			return invalidf("no scheduled job named '%s'", runNow)
+		}
+
+		log.Printf("serving %d scheduled jobs for %s/%s from %s", len(leagueSchedule), owner, repo, leagueDir())
+		for {
+			var due []scheduledJob
+			var at time.Time
+			for _, j := range leagueSchedule {
+				t := j.schedule.next(now)
+				switch {
+				case t.IsZero():
+				case at.IsZero() || t.Before(at):
+					due, at = []scheduledJob{j}, t
+				case t.Equal(at):
+					due = append(due, j)
+				}
+			}
+			if at.IsZero() {
+				return fmt.Errorf("none of the scheduled jobs will ever run again")
+			}
+			select {
+			case <-ctx.Done():
+				log.Printf("stopped serving")
+				return nil
+			case <-time.After(time.Until(at)):
+			}
+			for _, j := range due {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if err := runScheduledJob(ctx, j, at); err != nil {
+					log.Printf("⚠️  %v", err)
+				}
+			}
+			// Runs that came due while the jobs ran are skipped, not queued.
+			now = time.Now()
+			for _, j := range leagueSchedule {
+				if t := j.schedule.next(at); !t.IsZero() && t.Before(now.Truncate(time.Minute)) {
+					log.Printf("⚠️  skipped job %s due at %s: another job was still running", j.Name, t.Format("15:04"))
+				}
+			}
+		}
+	},
+}
+
+// runScheduledJob runs one job as a tennis subprocess, passing on the
+// global flags and token this process was started with.
+func runScheduledJob(ctx context.Context, j scheduledJob, at time.Time) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("job %s: %w", j.Name, err)
+	}
+	args := append(j.args(at), "--dir", leagueDir(), "--yes")
+	if owner != "" && repo != "" {
+		args = append(args, "--owner", owner, "--repo", repo)
+	}
+	if noCache {
+		args = append(args, "--no-cache")
+	}
+	if traceTarget != "" {
+		args = append(args, "--trace="+traceTarget)
+	}
+	// A job still running when serve is stopped gets the chance to stop
+	// cleanly too, and is killed if it hasn't within 10 seconds.
+	c := exec.CommandContext(ctx, self, args...)
+	c.Cancel = func() error { return c.Process.Signal(os.Interrupt) }
+	c.WaitDelay = 10 * time.Second
+	c.Stdout, c.Stderr = os.Stdout, os.Stderr
+	c.Env = os.Environ()
+	if token != "" {
+		c.Env = append(c.Env, "GITHUB_TOKEN="+token)
+	}
+
+	log.Printf("▶ %s: tennis %s", j.Name, strings.Join(j.args(at), " "))
+	started := time.Now()
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("job %s failed after %s: %w", j.Name, time.Since(started).Round(time.Second), err)
+	}
+	log.Printf("✅ %s finished in %s", j.Name, time.Since(started).Round(time.Second))
+	return nil
+}
+
+func init() {
+	serveCmd.Flags().Bool("list", false, "List the scheduled jobs and when each runs next, then exit")
+	serveCmd.Flags().String("run", "", "Run the named job once now, then exit")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate how the league could finish",
+}
+
+var simulateSeasonCmd = &cobra.Command{
+	Use:   "season",
+	Short: "Estimate each player's chances in the divisions by simulation",
+	Long: `Play out the rest of the division period many times and show each
+player's chances of winning their division, finishing in its top 3, and
+being promoted or relegated.
+
+Every pair of players in a division who haven't played each other yet in
+the period (from the day after the last rotation, or --from) has a fixture
+left. Each run plays those fixtures as best-of-three matches on the
+players' current singles ratings, each set won with the Elo expected score,
+and ranks the divisions as "divisions standings" does. Ratings aren't
+updated between simulated matches. Promotion and relegation follow
+"divisions rotate --moves".
+
+Examples:
+  tennis simulate season
+  tennis simulate season --runs 50000 --moves 2 --seed 7`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runs, _ := cmd.Flags().GetInt("runs")
+		moves, _ := cmd.Flags().GetInt("moves")
+		seed, _ := cmd.Flags().GetInt64("seed")
+		if runs < 1 {
+			return invalidf("--runs must be at least 1")
+		}
+		if moves < 1 {
+			return invalidf("--moves must be at least 1")
+		}
+		from, to, err := divisionPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		divisions, members := divisionMembers(roster)
+		if len(divisions) == 0 {
+			return fmt.Errorf("no players are in a division (use `tennis divisions assign`)")
+		}
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		var period []Match
+		for _, m := range matches {
+			if m.Date >= from && m.Date <= to {
+				period = append(period, m)
+			}
+		}
+		today := time.Now().Format("2006-01-02")
+		ratings, _ := decayRatings(computeSinglesRatings(matches), matches, today)
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		odds := simulateDivisions(rand.New(rand.NewSource(seed)), divisions, members, period, ratings, moves, runs)
+
+		fmt.Printf("Season simulation, %s, %d runs\n", periodLabel(from, to), runs)
+		division := 0
+		for _, o := range odds {
+			if o.Division != division {
+				division = o.Division
+				fmt.Printf("\nDivision %d\n", division)
+				fmt.Printf("  %-20s %7s %4s %6s %6s %8s %9s\n", "Player", "Rating", "Left", "Win", "Top 3", "Promoted", "Relegated")
+			}
+			promoted, relegated := percent(o.Promoted), percent(o.Relegated)
+			if division == divisions[0] {
+				promoted = "–"
+			}
+			if division == divisions[len(divisions)-1] {
+				relegated = "–"
+			}
+			fmt.Printf("  %-20s %7.1f %4d %6s %6s %8s %9s\n", "@"+o.Player, o.Rating, o.Remaining,
+				percent(o.Win), percent(o.Top3), promoted, relegated)
+		}
+		return nil
+	},
+}
+
+// percent formats a fraction as a whole percentage, keeping near-certain
+// and near-impossible outcomes apart from certain and impossible ones.
+func percent(f float64) string {
+	switch {
+	case f == 0:
+		return "0%"
+	case f < 0.005:
+		return "<1%"
+	case f == 1:
+		return "100%"
+	case f > 0.995:
+		return ">99%"
+	}
+	return fmt.Sprintf("%.0f%%", f*100)
+}
+
+func init() {
+	simulateSeasonCmd.Flags().Int("runs", 10000, "Number of simulated seasons")
+	simulateSeasonCmd.Flags().Int("moves", 1, "Players promoted and relegated between each pair of divisions")
+	simulateSeasonCmd.Flags().String("from", "", "First day of the period (defaults to the day after the last rotation)")
+	simulateSeasonCmd.Flags().String("to", "", "Last day of the period counted so far (defaults to today)")
+	simulateSeasonCmd.Flags().Int64("seed", 0, "Random seed, for repeatable results (defaults to a random one)")
+
+	simulateCmd.AddCommand(simulateSeasonCmd)
+	rootCmd.AddCommand(simulateCmd)
+}
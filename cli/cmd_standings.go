@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var standingsCmd = &cobra.Command{
+	Use:   "standings",
+	Short: "Publish the league standings outside GitHub",
+}
+
+var standingsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the current leaderboard for an external website",
+	Long: `Export the current singles or doubles leaderboard.
+
+Formats:
+  html  self-contained, styled page (no scripts or external files)
+  csv   rank,player,rating,tier,sets,games
+  json  leaderboard rows as in rankings.json
+
+With --out, an HTML export is followed by an iframe snippet for embedding
+the page in another site. The snippet points at --url, which defaults to
+the file's address on the league's GitHub Pages site.
+
+Players with a pseudonym in .tennis.yml appear under it.
+
+With --category, only the players in that category are ranked, on the
+matches they played against each other.
+
+Examples:
+  tennis standings export --format html --out standings.html
+  tennis standings export --kind doubles --title "Club Doubles" --out doubles.html --url https://club.example/doubles.html
+  tennis standings export --format csv > standings.csv
+  tennis standings export --category women --out women.html`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, _ := cmd.Flags().GetString("kind")
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		title, _ := cmd.Flags().GetString("title")
+		url, _ := cmd.Flags().GetString("url")
+		category, _ := cmd.Flags().GetString("category")
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		load, leaderboard := loadSinglesMatches, singlesLeaderboard
+		switch kind {
+		case "singles":
+		case "doubles":
+			load, leaderboard = loadDoublesMatches, doublesLeaderboard
+		default:
+			return invalidf("unknown kind '%s' (use singles or doubles)", kind)
+		}
+		matches, err := load(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		category, roster, err = forCategory(category, roster, &matches)
+		if err != nil {
+			return err
+		}
+		board := leaderboard(matches, roster, time.Now().Format("2006-01-02"))
+		if title == "" {
+			title = map[string]string{"singles": "Singles Standings", "doubles": "Doubles Standings"}[kind]
+			if category != "" {
+				title = strings.ToUpper(category[:1]) + category[1:] + " " + title
+			}
+		}
+
+		w := io.Writer(os.Stdout)
+		if out != "" {
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := writeStandings(w, format, title, time.Now().Format("2 January 2006"), board); err != nil {
+			return err
+		}
+		if out == "" {
+			return nil
+		}
+
+		fmt.Printf("✅ Standings written to %s\n", out)
+		if format == "html" {
+			if url == "" {
+				url = pagesURL(cmd.Context()) + filepath.Base(out)
+			}
+			fmt.Printf("\nEmbed it with:\n\n%s\n", standingsEmbed(url, title, len(board)))
+		}
+		return nil
+	},
+}
+
+// writeStandings renders a leaderboard in the requested format.
+func writeStandings(w io.Writer, format, title, generated string, board []LeaderboardRow) error {
+	switch format {
+	case "html":
+		return writeStandingsHTML(w, title, generated, board)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"rank", "player", "rating", "tier", "sets", "games"})
+		for _, r := range board {
+			rank := strconv.Itoa(r.Rank)
+			if r.Unranked {
+				rank = ""
+			}
+			cw.Write([]string{rank, publicHandle(r.Player), strconv.FormatFloat(r.Rating, 'f', 1, 64), r.Tier,
+				fmt.Sprintf("%d-%d", r.SetWins, r.SetLosses), fmt.Sprintf("%d-%d", r.GameWins, r.GameLosses)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		rows := make([]LeaderboardRow, len(board))
+		for i, r := range board {
+			r.Player = publicHandle(r.Player)
+			rows[i] = r
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	return invalidf("unknown format '%s' (use html, csv or json)", format)
+}
+
+func init() {
+	standingsExportCmd.Flags().String("kind", "singles", "Leaderboard to export: singles or doubles")
+	standingsExportCmd.Flags().StringP("format", "f", "html", "Export format: html, csv or json")
+	standingsExportCmd.Flags().StringP("out", "o", "", "Write to a file instead of stdout")
+	standingsExportCmd.Flags().String("title", "", "Page title (defaults to \"Singles Standings\" or \"Doubles Standings\")")
+	standingsExportCmd.Flags().String("category", "", "Rank only the players in this category")
+	standingsExportCmd.Flags().String("url", "", "Where the exported page will be hosted, for the iframe snippet (defaults to the Pages site)")
+
+	standingsCmd.AddCommand(standingsExportCmd)
+	rootCmd.AddCommand(standingsCmd)
+}
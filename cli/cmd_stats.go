@@ -0,0 +1,374 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show player statistics in the terminal",
+}
+
+var statsPlayerCmd = &cobra.Command{
+	Use:   "player <@handle>",
+	Short: "Show a player's record, form and rating trend",
+	Long: `Show a player's profile (see "tennis profile"), their singles and doubles
+record, their recent results and a sparkline of their rating over their last
+--last matches.
+
+Wins above expectation compare the matches a player won with the wins
+their rating going into each match predicted (the sum of their expected
+scores; a draw counts half). A positive number means they beat the odds.
+
+Reliability is the share of their matches a player turned up for: every
+forfeit they're named in (see "tennis match forfeit") is a no-show.
+
+Examples:
+  tennis stats player @player_one
+  tennis stats player @player_one --last 50`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		last, _ := cmd.Flags().GetInt("last")
+		if last < 2 {
+			return invalidf("--last must be at least 2")
+		}
+		player := normalizePlayer(args[0])
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		player = resolveAlias(aliases, player)
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		today := time.Now().Format("2006-01-02")
+		fmt.Printf("@%s\n", player)
+		if p := roster.Find(player); p != nil && p.Profile != nil {
+			fmt.Println(p.Profile)
+		}
+		played := false
+		for _, kind := range []struct {
+			title   string
+			matches []Match
+			board   []LeaderboardRow
+			replay  func(map[string]float64, []Match)
+			seeds   map[string]float64
+		}{
+			{"Singles", singles, singlesLeaderboard(singles, roster, today), replaySingles, importedSingles},
+			{"Doubles", doubles, doublesLeaderboard(doubles, roster, today), replayDoubles, importedDoubles},
+		} {
+			form := formGuide(kind.matches)[player]
+			baseline := ""
+			if seed, ok := kind.seeds[player]; ok {
+				p := roster.Find(player)
+				baseline = fmt.Sprintf("%.1f, imported from %s on %s", seed, p.Imported.From, p.Imported.Date)
+			}
+			if form == "" {
+				if baseline == "" {
+					fmt.Printf("\n%s: no matches\n", kind.title)
+					continue
+				}
+				played = true
+				fmt.Printf("\n%s: no matches yet, provisional rating %s\n", kind.title, baseline)
+				continue
+			}
+			played = true
+			standing := fmt.Sprintf("rating %.1f", rating(computeRatingsWith(kind.matches, kind.replay), player))
+			ranked := 0
+			for _, r := range kind.board {
+				if !r.Unranked {
+					ranked++
+				}
+			}
+			for _, r := range kind.board {
+				if r.Player != player {
+					continue
+				}
+				standing = fmt.Sprintf("rating %.1f", r.Rating)
+				if r.Unranked {
+					standing += ", unranked"
+				} else {
+					standing += fmt.Sprintf(", #%d of %d", r.Rank, ranked)
+				}
+			}
+
+			wins, losses := 0, 0
+			for _, c := range form {
+				switch c {
+				case 'W':
+					wins++
+				case 'L':
+					losses++
+				}
+			}
+			history := lastN(ratingHistory(kind.matches, kind.replay)[player], last+1)
+
+			fmt.Printf("\n%s: %s\n", kind.title, standing)
+			fmt.Printf("  Record  %d played, %d won, %d lost\n", len(form), wins, losses)
+			fmt.Printf("  Form    %s (last %d, latest on the right)\n", lastResults(form, 10), min(len(form), 10))
+			fmt.Printf("  Rating  %s  %.1f → %.1f over %d matches\n", sparkline(history), history[0], history[len(history)-1], len(history)-1)
+			if r, ok := expectationOf(winsAboveExpectation(kind.matches, "", ""), player); ok {
+				fmt.Printf("  Expect  %.1f wins expected from ratings, %.1f won (%+.1f above expectation)\n", r.Expected, r.Wins, r.Above())
+			}
+			if due, noShows := reliability(kind.matches, player); noShows > 0 {
+				fmt.Printf("  Shows   %.0f%% reliable, turned up for %d of %d matches\n", 100*float64(due-noShows)/float64(due), due-noShows, due)
+			} else {
+				fmt.Printf("  Shows   100%% reliable, turned up for all %d matches\n", due)
+			}
+			if baseline != "" {
+				fmt.Printf("  Start   %s\n", baseline)
+			}
+			if len(leagueRepos) > 0 {
+				var mine []Match
+				for _, m := range kind.matches {
+					if containsString(matchPlayers(m), player) {
+						mine = append(mine, m)
+					}
+				}
+				fmt.Printf("  From    %s\n", strings.Join(matchSources(mine), ", "))
+			}
+		}
+		if !played {
+			return fmt.Errorf("@%s has no recorded matches", player)
+		}
+		return nil
+	},
+}
+
+var statsPartnersCmd = &cobra.Command{
+	Use:   "partners <@handle>",
+	Short: "Show how a player does with each doubles partner",
+	Long: `Show a player's doubles record with each partner they've played with,
+their best and worst pairings, and a pairing matrix of their record with
+each partner (rows) against each opponent (columns).
+
+Best and worst pairings only count partnerships of at least --min decided
+matches, so one lucky win doesn't top the list.
+
+Examples:
+  tennis stats partners @player_one
+  tennis stats partners @player_one --min 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		minMatches, _ := cmd.Flags().GetInt("min")
+		if minMatches < 1 {
+			return invalidf("--min must be at least 1")
+		}
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		player := resolveAlias(aliases, normalizePlayer(args[0]))
+		ctx := cmd.Context()
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		records := partnerRecords(player, doubles)
+		if len(records) == 0 {
+			return fmt.Errorf("@%s has no recorded doubles matches", player)
+		}
+
+		played := 0
+		for _, r := range records {
+			played += r.Played()
+		}
+		fmt.Printf("@%s in doubles: %d matches with %d partners\n\n", player, played, len(records))
+		fmt.Printf("  %-20s %6s %3s %3s %5s %6s\n", "Partner", "Played", "W", "L", "Win", "Sets")
+		for _, r := range records {
+			fmt.Printf("  %-20s %6d %3d %3d %5s %6s\n", "@"+r.Partner, r.Played(), r.Won, r.Lost,
+				percent(r.WinRate()), fmt.Sprintf("%d-%d", r.SetsWon, r.SetsLost))
+		}
+
+		fmt.Println()
+		best, worst := bestAndWorstPartners(records, minMatches)
+		if best == nil {
+			fmt.Printf("No partnership has %d decided matches yet to pick a best or worst pairing\n", minMatches)
+		} else {
+			fmt.Printf("Best pairing:  @%s, %d-%d (%s)\n", best.Partner, best.Won, best.Lost, percent(best.WinRate()))
+			if worst != best {
+				fmt.Printf("Worst pairing: @%s, %d-%d (%s)\n", worst.Partner, worst.Won, worst.Lost, percent(worst.WinRate()))
+			}
+		}
+
+		var opponents []string
+		for _, r := range records {
+			for o := range r.Opponents {
+				if !containsString(opponents, o) {
+					opponents = append(opponents, o)
+				}
+			}
+		}
+		sort.Strings(opponents)
+		fmt.Println("\nPairing matrix (won-lost with each partner against each opponent)")
+		fmt.Printf("  %-20s", "")
+		for _, o := range opponents {
+			fmt.Printf(" %*s", max(len(o)+1, 5), "@"+o)
+		}
+		fmt.Println()
+		for _, r := range records {
+			fmt.Printf("  %-20s", "@"+r.Partner)
+			for _, o := range opponents {
+				cell := "-"
+				if wl, ok := r.Opponents[o]; ok {
+					cell = fmt.Sprintf("%d-%d", wl[0], wl[1])
+				}
+				fmt.Printf(" %*s", max(len(o)+1, 5), cell)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var statsUpsetsCmd = &cobra.Command{
+	Use:   "upsets",
+	Short: "Rank a season's matches by the rating gap the winners overcame",
+	Long: `List the season's upsets, singles and doubles together: matches won by
+the lower-rated side (the lower team average in doubles), biggest rating
+gap first, with the winners' chance going in. Ratings are as they stood
+before each match, replayed from the whole match history.
+
+With --label, every upset of at least --threshold points is tagged with
+the upset label (label_names.upset in .tennis.yml) on its match issue.
+Matches recorded in other league repositories aren't tagged.
+
+Examples:
+  tennis stats upsets
+  tennis stats upsets --season 2025 --top 20
+  tennis stats upsets --label --threshold 150`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		season, _ := cmd.Flags().GetString("season")
+		top, _ := cmd.Flags().GetInt("top")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		label, _ := cmd.Flags().GetBool("label")
+		if season == "" {
+			season = time.Now().Format("2006")
+		}
+		if !yearRegex.MatchString(season) {
+			return invalidf("invalid season '%s'. Use a year like 2024", season)
+		}
+		if top < 1 {
+			return invalidf("--top must be at least 1")
+		}
+		if threshold <= 0 {
+			return invalidf("--threshold must be positive")
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		var upsets []upset
+		for _, u := range append(findUpsets("singles", singles, importedSingles), findUpsets("doubles", doubles, importedDoubles)...) {
+			if strings.HasPrefix(u.Match.Date, season+"-") {
+				upsets = append(upsets, u)
+			}
+		}
+		sortUpsets(upsets)
+		if len(upsets) == 0 {
+			fmt.Printf("No upsets in %s\n", season)
+			return nil
+		}
+
+		names := func(side []string) string {
+			return "@" + strings.Join(side, " & @")
+		}
+		fmt.Printf("Biggest upsets of %s (%d in all, ★ marks gaps of %g or more)\n\n", season, len(upsets), threshold)
+		fmt.Printf("  %3s  %-10s  %-7s  %-28s  %-28s %6s %6s  %s\n", "#", "Date", "Type", "Winner", "Loser", "Gap", "Chance", "Issue")
+		for i, u := range upsets[:min(top, len(upsets))] {
+			star := ""
+			if u.Gap() >= threshold {
+				star = " ★"
+			}
+			issue := ""
+			if u.Match.SourceIssue > 0 {
+				issue = fmt.Sprintf("#%d", u.Match.SourceIssue)
+				if u.Match.Repo != "" {
+					issue = u.Match.Repo + issue
+				}
+			}
+			fmt.Printf("  %3d  %-10s  %-7s  %-28s  %-28s %6.1f %6s  %s%s\n", i+1, u.Match.Date, u.Type,
+				fmt.Sprintf("%s (%.0f)", names(u.Winners), u.WinnerRating),
+				fmt.Sprintf("%s (%.0f)", names(u.Losers), u.LoserRating),
+				u.Gap(), percent(u.Chance()), issue, star)
+		}
+		if !label {
+			return nil
+		}
+
+		var tag []int
+		for _, u := range upsets {
+			if u.Gap() >= threshold && u.Match.SourceIssue > 0 && u.Match.Repo == "" {
+				tag = append(tag, u.Match.SourceIssue)
+			}
+		}
+		fmt.Println()
+		if len(tag) == 0 {
+			fmt.Printf("No upsets of %g points or more to label\n", threshold)
+			return nil
+		}
+		if dryRun {
+			fmt.Printf("[dry-run] would label %d match issues %s\n", len(tag), labelNames.Upset)
+			return nil
+		}
+		if err := confirm("Label %d match issues %s?", len(tag), labelNames.Upset); err != nil {
+			return err
+		}
+		client := getGitHubClient()
+		for _, n := range tag {
+			if _, _, err := client.Issues.AddLabelsToIssue(cmd.Context(), owner, repo, n, []string{labelNames.Upset}); err != nil {
+				return fmt.Errorf("failed to label #%d: %w", n, err)
+			}
+		}
+		fmt.Printf("✅ Labelled %d match issues %s\n", len(tag), labelNames.Upset)
+		return nil
+	},
+}
+
+// computeRatingsWith replays matches into a fresh set of ratings.
+func computeRatingsWith(matches []Match, replay func(map[string]float64, []Match)) map[string]float64 {
+	ratings := make(map[string]float64)
+	replay(ratings, matches)
+	return ratings
+}
+
+func init() {
+	statsPlayerCmd.Flags().Int("last", 20, "Matches to chart in the rating sparkline")
+
+	statsPartnersCmd.Flags().Int("min", 3, "Decided matches a partnership needs to count as a best or worst pairing")
+
+	statsCmd.AddCommand(statsPlayerCmd)
+	statsUpsetsCmd.Flags().String("season", "", "Season (year) to list, defaults to this year")
+	statsUpsetsCmd.Flags().Int("top", 10, "Number of upsets to list")
+	statsUpsetsCmd.Flags().Float64("threshold", 100, "Rating gap that makes a match an upset worth labelling")
+	statsUpsetsCmd.Flags().Bool("label", false, "Tag upsets of at least --threshold points with the upset label")
+	statsUpsetsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be labelled without labelling")
+
+	statsCmd.AddCommand(statsPartnersCmd)
+	statsCmd.AddCommand(statsUpsetsCmd)
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var swissCmd = &cobra.Command{
+	Use:   "swiss",
+	Short: "Run Swiss-system events",
+	Long: `Run a Swiss-system event: a fixed number of rounds in which everyone
+plays, each round pairing players on the same score without repeating an
+opponent. Suited to large one-day events where a knockout would leave most
+players idle.
+
+The event is stored in tournaments/swiss/<name>.yml. Each round's matches
+get fixture issues labelled "tournament" and "tournament:<name>"; the
+first player listed serves first. Results are recorded as normal singles
+matches, and "next" pairs the following round once a round is complete.`,
+}
+
+var swissCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a Swiss event and pair the first round",
+	Long: `Create a Swiss event and open the first round's fixtures.
+
+Players are listed in seed order, or reseeded by current singles rating
+with --seed-by rating. --rounds defaults to the number of rounds a
+knockout of the same size would take.
+
+Examples:
+  tennis tournament swiss create club-day --players "@alice,@bob,@carol,@dave,@erin" --rounds 5
+  tennis tournament swiss create club-day -p "@alice,@bob,@carol,@dave" --seed-by rating --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		players, _ := cmd.Flags().GetString("players")
+		seedBy, _ := cmd.Flags().GetString("seed-by")
+		rounds, _ := cmd.Flags().GetInt("rounds")
+
+		if !tournamentNameRegex.MatchString(name) {
+			return invalidf("invalid tournament name '%s'. Use lowercase letters, digits and dashes", name)
+		}
+		for _, path := range []string{swissPath(name), tournamentPath(name)} {
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("tournament '%s' already exists", name)
+			}
+		}
+		if players == "" {
+			return fmt.Errorf("players are required (use --players)")
+		}
+
+		var seeds []string
+		seen := make(map[string]bool)
+		for _, p := range strings.Split(players, ",") {
+			p = normalizePlayer(p)
+			if p == "" {
+				continue
+			}
+			if seen[p] {
+				return fmt.Errorf("player '@%s' is listed more than once", p)
+			}
+			seen[p] = true
+			seeds = append(seeds, p)
+		}
+		if len(seeds) < 2 {
+			return fmt.Errorf("at least 2 players required for a tournament")
+		}
+		if rounds == 0 {
+			for n := len(seeds); n > 1; n = (n + 1) / 2 {
+				rounds++
+			}
+		}
+		if rounds < 1 || rounds >= len(seeds)+len(seeds)%2 {
+			return invalidf("--rounds must be between 1 and %d for %d players, so nobody has to meet twice", len(seeds)+len(seeds)%2-1, len(seeds))
+		}
+		seeds, err := seedPlayers(cmd.Context(), seeds, seedBy)
+		if err != nil {
+			return err
+		}
+
+		e := &SwissEvent{
+			Name:      name,
+			Created:   time.Now().Format("2006-01-02"),
+			Status:    tournamentInProgress,
+			NumRounds: rounds,
+			Players:   seeds,
+		}
+		if err := pairNextSwissRound(cmd.Context(), e); err != nil {
+			return err
+		}
+		if dryRun {
+			return nil
+		}
+		if err := saveSwissEvent(e); err != nil {
+			return fmt.Errorf("failed to save Swiss event: %w", err)
+		}
+
+		fmt.Printf("✅ Swiss event '%s' created: %d players, %d rounds\n", name, len(seeds), rounds)
+		fmt.Printf("Pairings saved to %s — commit it to share them\n", swissPath(name))
+		return nil
+	},
+}
+
+var swissNextCmd = &cobra.Command{
+	Use:   "next [name]",
+	Short: "Record results and pair the next round",
+	Long: `Look up recorded results for the current round. A board is decided by
+the first singles match between its two players recorded on or after the
+event's creation date. Decided fixture issues are closed with a comment.
+
+Once every board of the round is decided, the next round is paired and
+its fixtures created; after the last round the event is finished and the
+winner announced.
+
+Use --all to update every in-progress Swiss event (as the tournament
+workflow does after match data is merged).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+
+		var names []string
+		switch {
+		case all:
+			files, _ := filepath.Glob(filepath.Join(leagueDir(), "tournaments", "swiss", "*.yml"))
+			for _, f := range files {
+				names = append(names, strings.TrimSuffix(filepath.Base(f), ".yml"))
+			}
+		case len(args) == 1:
+			names = args
+		default:
+			return fmt.Errorf("event name required (or use --all)")
+		}
+
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		for _, name := range names {
+			e, err := loadSwissEvent(name)
+			if err != nil {
+				return err
+			}
+			if e.Status == tournamentFinished {
+				if !all {
+					return fmt.Errorf("Swiss event '%s' is already finished", name)
+				}
+				continue
+			}
+			if err := advanceSwiss(cmd.Context(), e, matches); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var swissStandingsCmd = &cobra.Command{
+	Use:   "standings <name>",
+	Short: "Show the standings and pairings so far",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		e, err := loadSwissEvent(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s (%s, created %s, round %d of %d)\n\n", e.Name, e.Status, e.Created, len(e.Rounds), e.NumRounds)
+		fmt.Printf("  %-4s %-20s %6s %8s %6s\n", "Rank", "Player", "Points", "Buchholz", "Played")
+		for i, s := range swissStandings(e) {
+			fmt.Printf("  %-4d %-20s %6d %8d %6d\n", i+1, "@"+s.Player, s.Score, s.Buchholz, s.Played)
+		}
+		for r, round := range e.Rounds {
+			fmt.Printf("\nRound %d\n", r+1)
+			for _, p := range round {
+				if p.IsBye() {
+					fmt.Printf("  @%s — bye\n", p.Players[0])
+					continue
+				}
+				line := fmt.Sprintf("  @%s vs @%s", p.Players[0], p.Players[1])
+				if p.Issue != 0 {
+					line += fmt.Sprintf(" (#%d)", p.Issue)
+				}
+				if p.Winner != "" {
+					line += fmt.Sprintf(" → @%s", p.Winner)
+				}
+				fmt.Println(line)
+			}
+		}
+		if e.Winner != "" {
+			fmt.Printf("\n🏆 Winner: @%s\n", e.Winner)
+		}
+		return nil
+	},
+}
+
+// advanceSwiss records results for the current round and, if the round is
+// complete, pairs the next one or finishes the event.
+func advanceSwiss(ctx context.Context, e *SwissEvent, matches []Match) error {
+	r := len(e.Rounds) - 1
+	round := e.Rounds[r]
+
+	used := make(map[int]bool)
+	for _, rnd := range e.Rounds {
+		for _, p := range rnd {
+			if p.Result != 0 {
+				used[p.Result] = true
+			}
+		}
+	}
+
+	decided := 0
+	complete := true
+	for i := range round {
+		p := &round[i]
+		if p.Winner != "" {
+			continue
+		}
+		result, ok := findResult(p.Players, e.Created, matches, used)
+		if !ok {
+			complete = false
+			continue
+		}
+		used[result.SourceIssue] = true
+		p.Result = result.SourceIssue
+		p.Winner = result.Players[matchWinner(result)-1]
+		decided++
+		fmt.Printf("Round %d: @%s beats @%s (match #%d)\n", r+1, p.Winner, opponentIn(p.Players, p.Winner), p.Result)
+		if p.Issue != 0 {
+			if err := closeIssue(ctx, p.Issue, fmt.Sprintf("@%s wins — result recorded in #%d.", p.Winner, p.Result)); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch {
+	case !complete:
+		fmt.Printf("%s: round %d still in progress\n", e.Name, r+1)
+	case len(e.Rounds) == e.NumRounds:
+		e.Status = tournamentFinished
+		e.Winner = swissStandings(e)[0].Player
+		fmt.Printf("🏆 @%s wins %s!\n", e.Winner, e.Name)
+	default:
+		if err := pairNextSwissRound(ctx, e); err != nil {
+			return err
+		}
+	}
+
+	if dryRun || (decided == 0 && !complete) {
+		return nil
+	}
+	return saveSwissEvent(e)
+}
+
+// pairNextSwissRound pairs the next round and creates its fixture issues.
+func pairNextSwissRound(ctx context.Context, e *SwissEvent) error {
+	round, ok := pairSwissRound(e)
+	if !ok {
+		return fmt.Errorf("%s: no pairing for round %d avoids a rematch", e.Name, len(e.Rounds)+1)
+	}
+	e.Rounds = append(e.Rounds, round)
+	r := len(e.Rounds)
+
+	fmt.Printf("%s: round %d pairings\n", e.Name, r)
+	for i, p := range round {
+		if p.IsBye() {
+			fmt.Printf("  @%s — bye\n", p.Players[0])
+			continue
+		}
+		fmt.Printf("  @%s vs @%s\n", p.Players[0], p.Players[1])
+
+		p1, p2 := "@"+p.Players[0], "@"+p.Players[1]
+		title := fmt.Sprintf("%s Round %d: %s vs %s", e.Name, r, p1, p2)
+		body := fmt.Sprintf(`### Tournament
+%s — Round %d of %d
+
+### Players
+%s, %s
+
+%s serves first. Play your match, then record the result with `+"`tennis match singles`"+` or the singles match issue form. The next round is paired once every result of this round is merged.`,
+			e.Name, r, e.NumRounds, p1, p2, p1)
+
+		number, err := openIssue(ctx, title, body, []string{labelNames.Tournament, e.Label()})
+		if err != nil {
+			return err
+		}
+		e.Rounds[r-1][i].Issue = number
+	}
+	return nil
+}
+
+// opponentIn returns the other player of a pairing.
+func opponentIn(players [2]string, p string) string {
+	if players[0] == p {
+		return players[1]
+	}
+	return players[0]
+}
+
+func init() {
+	swissCreateCmd.Flags().StringP("players", "p", "", "Players in seed order, comma-separated: @top_seed,@second_seed,...")
+	swissCreateCmd.Flags().String("seed-by", "list", "Seeding: list (as given) or rating (current singles rating)")
+	swissCreateCmd.Flags().Int("rounds", 0, "Number of rounds (defaults to the rounds a knockout would need)")
+	swissNextCmd.Flags().Bool("all", false, "Update every in-progress Swiss event")
+
+	swissCmd.AddCommand(swissCreateCmd)
+	swissCmd.AddCommand(swissNextCmd)
+	swissCmd.AddCommand(swissStandingsCmd)
+	tournamentCmd.AddCommand(swissCmd)
+}
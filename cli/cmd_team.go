@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var teamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Run an inter-club team league",
+	Long: `Run an inter-club team league: clubs meet in ties of several rubbers
+(individual singles or doubles matches), and the club that wins more
+rubbers wins the tie.
+
+Each league is stored in teams/<name>.yml in the league checkout. Rubbers
+are recorded as normal match issues; there is nothing to enter per tie.`,
+}
+
+var teamCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a team league",
+	Long: `Create a team league from two or more clubs, each given with --club as
+the club's name and its players. Every pair of clubs meets once.
+
+A tie is made up of the first --rubbers matches, dated from --start on,
+between one club's players and the other's: a singles match, or a doubles
+match with both partners from the same club. A player plays for one club.
+
+Examples:
+  tennis team create county-2025 --club "Riverside=@alice,@bob,@carol" --club "Hilltop=@dave,@erin,@frank"
+  tennis team create county-2025 --rubbers 4 --start 2025-04-01 --end 2025-09-30 --club ... --club ...`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		clubs, _ := cmd.Flags().GetStringArray("club")
+		rubbers, _ := cmd.Flags().GetInt("rubbers")
+		start, _ := cmd.Flags().GetString("start")
+		end, _ := cmd.Flags().GetString("end")
+
+		if !tournamentNameRegex.MatchString(name) {
+			return invalidf("invalid team league name '%s'. Use lowercase letters, digits and dashes", name)
+		}
+		if _, err := os.Stat(teamLeaguePath(name)); err == nil {
+			return fmt.Errorf("team league '%s' already exists", name)
+		}
+		if rubbers < 1 {
+			return invalidf("--rubbers must be at least 1")
+		}
+		if start == "" {
+			start = time.Now().Format("2006-01-02")
+		}
+		if !isValidDate(start) || end != "" && !isValidDate(end) {
+			return invalidf("invalid date. Use YYYY-MM-DD")
+		}
+		if end != "" && end < start {
+			return invalidf("--end %s is before --start %s", end, start)
+		}
+		if len(clubs) < 2 {
+			return invalidf("at least 2 clubs required (use --club \"Name=@player,@player\")")
+		}
+
+		l := &TeamLeague{Name: name, Start: start, End: end, Rubbers: rubbers}
+		clubOf := make(map[string]string)
+		for _, c := range clubs {
+			clubName, players, ok := strings.Cut(c, "=")
+			clubName = strings.TrimSpace(clubName)
+			if !ok || clubName == "" {
+				return invalidf("invalid club '%s'. Use \"Name=@player,@player\"", c)
+			}
+			club := Club{Name: clubName}
+			for _, p := range strings.Split(players, ",") {
+				p = normalizePlayer(p)
+				if p == "" {
+					continue
+				}
+				if other, taken := clubOf[p]; taken {
+					return fmt.Errorf("@%s is listed for both %s and %s", p, other, clubName)
+				}
+				clubOf[p] = clubName
+				club.Players = append(club.Players, p)
+			}
+			for _, have := range l.Clubs {
+				if strings.EqualFold(have.Name, clubName) {
+					return fmt.Errorf("club '%s' is listed more than once", clubName)
+				}
+			}
+			if len(club.Players) == 0 {
+				return invalidf("club '%s' has no players", clubName)
+			}
+			l.Clubs = append(l.Clubs, club)
+		}
+
+		if dryRun {
+			fmt.Printf("[dry-run] would save %d clubs and %d ties to %s\n", len(l.Clubs), len(l.Clubs)*(len(l.Clubs)-1)/2, teamLeaguePath(name))
+			return nil
+		}
+		if err := saveTeamLeague(l); err != nil {
+			return fmt.Errorf("failed to save team league: %w", err)
+		}
+		fmt.Printf("✅ Team league %s created: %d clubs, %d ties of %d rubbers\n", name, len(l.Clubs), len(l.Clubs)*(len(l.Clubs)-1)/2, rubbers)
+		fmt.Printf("Saved to %s — commit it to share the league\n", teamLeaguePath(name))
+		return nil
+	},
+}
+
+var teamStandingsCmd = &cobra.Command{
+	Use:   "standings <name>",
+	Short: "Show the club table and every tie's rubbers",
+	Long: `Show the team league's club table and the rubbers of every tie so far.
+
+Clubs score 2 points for a tie won and 1 for a tie drawn, and are ranked
+by points, then rubber difference, then rubbers won. A tie counts once all
+its rubbers are played, or once one club has won more than half of them.
+
+Examples:
+  tennis team standings county-2025`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l, err := loadTeamLeague(args[0])
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		ties := teamTies(l, append(singles, doubles...))
+
+		period := "from " + l.Start
+		if l.End != "" {
+			period = l.Start + " to " + l.End
+		}
+		fmt.Printf("%s (%s, ties of %d rubbers)\n\n", l.Name, period, l.Rubbers)
+		fmt.Printf("  %-4s %-20s %3s %3s %3s %3s %8s %6s\n", "Rank", "Club", "P", "W", "D", "L", "Rubbers", "Points")
+		for i, r := range teamStandings(l, ties) {
+			fmt.Printf("  %-4d %-20s %3d %3d %3d %3d %8s %6d\n", i+1, r.Club, r.Played, r.Won, r.Drawn, r.Lost,
+				fmt.Sprintf("%d-%d", r.RubbersWon, r.RubbersLost), r.Points)
+		}
+
+		for _, t := range ties {
+			status := fmt.Sprintf("%d of %d rubbers played", len(t.Rubbers), l.Rubbers)
+			switch {
+			case len(t.Rubbers) == 0:
+				status = "not started"
+			case t.Decided(l.Rubbers) && t.Winner() == -1:
+				status = "drawn"
+			case t.Decided(l.Rubbers):
+				status = t.Clubs[t.Winner()] + " won"
+			}
+			fmt.Printf("\n%s %d-%d %s (%s)\n", t.Clubs[0], t.Won[0], t.Won[1], t.Clubs[1], status)
+			for _, m := range t.Rubbers {
+				line := fmt.Sprintf("  %s  %s", m.Date, matchHeadline(m))
+				if m.SourceIssue != 0 {
+					line += fmt.Sprintf(" (#%d)", m.SourceIssue)
+				}
+				fmt.Println(line)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	teamCreateCmd.Flags().StringArray("club", nil, "Club and its players, as \"Name=@player,@player\" (repeatable)")
+	teamCreateCmd.Flags().Int("rubbers", 6, "Rubbers in each tie")
+	teamCreateCmd.Flags().String("start", "", "First day rubbers count (YYYY-MM-DD), defaults to today")
+	teamCreateCmd.Flags().String("end", "", "Last day rubbers count (YYYY-MM-DD), defaults to open-ended")
+	teamCreateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Check the clubs without saving the league")
+
+	teamCmd.AddCommand(teamCreateCmd)
+	teamCmd.AddCommand(teamStandingsCmd)
+	rootCmd.AddCommand(teamCmd)
+}
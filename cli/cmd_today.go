@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Show a morning briefing: today's matches, approvals and ranking changes",
+	Long: `Show a one-shot briefing for a player: their fixtures scheduled today,
+match results waiting on their approval, and how the leaderboards have
+moved since yesterday.
+
+The player is the owner of the GitHub token unless --player is given.
+
+Examples:
+  tennis today
+  tennis today --player @player_one
+  tennis today --date 2025-06-12`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		player, _ := cmd.Flags().GetString("player")
+		date, _ := cmd.Flags().GetString("date")
+
+		day := time.Now()
+		if date != "" {
+			var err error
+			if day, err = time.Parse("2006-01-02", date); err != nil {
+				return invalidf("invalid date '%s'. Use YYYY-MM-DD", date)
+			}
+		}
+		today := day.Format("2006-01-02")
+		yesterday := day.AddDate(0, 0, -1).Format("2006-01-02")
+
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		if player == "" {
+			user, _, err := client.Users.Get(ctx, "")
+			if err != nil {
+				return fmt.Errorf("failed to look up the token's user (use --player): %w", err)
+			}
+			player = user.GetLogin()
+		}
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		player = resolveAlias(aliases, normalizePlayer(player))
+
+		fmt.Printf("Briefing for @%s, %s\n", player, day.Format("Monday 2 January 2006"))
+
+		fmt.Println("\nToday's matches")
+		fixtures, err := fixturesOn(ctx, client, today, player)
+		if err != nil {
+			return err
+		}
+		if len(fixtures) == 0 {
+			fmt.Println("  Nothing scheduled")
+		}
+		for _, f := range fixtures {
+			fmt.Printf("  %s\n", f)
+		}
+
+		fmt.Println("\nWaiting on your approval")
+		waiting, err := approvalsPendingFor(ctx, client, player)
+		if err != nil {
+			return err
+		}
+		if len(waiting) == 0 {
+			fmt.Println("  Nothing to approve")
+		}
+		for _, w := range waiting {
+			fmt.Printf("  #%-5d %s (reported by @%s)\n", w.Issue.GetNumber(), w.Issue.GetTitle(), w.Reporter)
+		}
+
+		fmt.Println("\nRankings since yesterday")
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		for _, kind := range []struct {
+			title  string
+			before []LeaderboardRow
+			after  []LeaderboardRow
+		}{
+			{"Singles", singlesLeaderboard(playedBy(singles, yesterday), roster, yesterday), singlesLeaderboard(playedBy(singles, today), roster, today)},
+			{"Doubles", doublesLeaderboard(playedBy(doubles, yesterday), roster, yesterday), doublesLeaderboard(playedBy(doubles, today), roster, today)},
+		} {
+			fmt.Printf("  %s: %s\n", kind.title, rankingChangeSummary(kind.before, kind.after, player))
+		}
+		return nil
+	},
+}
+
+// fixturesOn lists the open scheduling issues for date that involve player,
+// earliest first.
+func fixturesOn(ctx context.Context, client *github.Client, date, player string) ([]string, error) {
+	issues, err := listLabelledIssues(ctx, client, labelNames.Challenge, issueFilter{State: "open"})
+	if err != nil {
+		return nil, err
+	}
+	type fixture struct {
+		time, line string
+	}
+	var found []fixture
+	for _, issue := range issues {
+		if issue.IsPullRequest() {
+			continue
+		}
+		sections := issueSections(issue.GetBody())
+		if fields := strings.Fields(section(sections, "match date")); len(fields) == 0 || fields[0] != date {
+			continue
+		}
+		sides, err := parseSides(strings.SplitN(section(sections, "players"), "\n", 2)[0])
+		if err != nil {
+			continue
+		}
+		f := standingFixture{Sides: sides}
+		if !containsString(append(append([]string{}, sides[0]...), sides[1]...), "@"+player) {
+			continue
+		}
+		at := strings.TrimSpace(section(sections, "match time"))
+		if at == "" {
+			at = "--:--"
+		}
+		found = append(found, fixture{at, fmt.Sprintf("%s  %s (%s)  #%d", at, f.versus(), f.kind(), issue.GetNumber())})
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].time < found[j].time })
+	var lines []string
+	for _, f := range found {
+		lines = append(lines, f.line)
+	}
+	return lines, nil
+}
+
+// pendingApproval is an open match issue waiting on a player's approval.
+type pendingApproval struct {
+	Issue    *github.Issue
+	Reporter string
+}
+
+// approvalsPendingFor lists the open match issues still waiting on
+// player's approval.
+func approvalsPendingFor(ctx context.Context, client *github.Client, player string) ([]pendingApproval, error) {
+	issues, err := listMatchIssues(ctx, client, "open")
+	if err != nil {
+		return nil, err
+	}
+	var pending []pendingApproval
+	for _, issue := range issues {
+		kind, _, _ := matchIssueKind(issue)
+		if kind == "conflict" || !containsString(parseMatchIssue(kind, issue.GetBody()).Players, player) {
+			continue
+		}
+		a, err := loadMatchApprovals(ctx, client, issue.GetNumber())
+		if err != nil {
+			return nil, err
+		}
+		if containsString(a.Pending(), "@"+player) {
+			pending = append(pending, pendingApproval{issue, a.Reporter})
+		}
+	}
+	return pending, nil
+}
+
+// rankingChangeSummary describes player's place on the after board and how
+// far they and others moved since the before board.
+func rankingChangeSummary(before, after []LeaderboardRow, player string) string {
+	moves := rankMovement(before, after)
+	prev := make(map[string]float64)
+	for _, r := range before {
+		prev[r.Player] = r.Rating
+	}
+
+	mine := "not on the leaderboard"
+	var others []string
+	for _, r := range after {
+		move, moved := moves[r.Player]
+		if r.Player == player {
+			_, known := prev[r.Player]
+			switch {
+			case r.Unranked:
+				mine = fmt.Sprintf("unranked, rating %.1f", r.Rating)
+			case !known:
+				mine = fmt.Sprintf("#%d (new), rating %.1f", r.Rank, r.Rating)
+			case moved && move != 0:
+				mine = fmt.Sprintf("#%d (%s), rating %.1f (%+.1f)", r.Rank, movementArrow(move), r.Rating, r.Rating-prev[r.Player])
+			default:
+				mine = fmt.Sprintf("#%d, rating %.1f (%+.1f)", r.Rank, r.Rating, r.Rating-prev[r.Player])
+			}
+			continue
+		}
+		if moved && move != 0 {
+			others = append(others, fmt.Sprintf("@%s %s", r.Player, movementArrow(move)))
+		}
+	}
+	if len(others) == 0 {
+		return mine + "; no one else moved"
+	}
+	return mine + "; also moved: " + strings.Join(others, ", ")
+}
+
+func init() {
+	todayCmd.Flags().String("player", "", "Player to brief, defaults to the GitHub token's user")
+	todayCmd.Flags().String("date", "", "Day to brief for (YYYY-MM-DD), defaults to today")
+	rootCmd.AddCommand(todayCmd)
+}
@@ -0,0 +1,706 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+var tournamentCmd = &cobra.Command{
+	Use:   "tournament",
+	Short: "Manage single-elimination tournaments",
+	Long: `Manage multi-round knockout events end-to-end.
+
+The draw is stored in tournaments/<name>.yml in the league checkout. Each
+playable match gets a fixture issue labelled "tournament" and
+"tournament:<name>". Once a match between the two players is recorded and
+merged into singles-matches/, "advance" moves the winner through.`,
+}
+
+var tournamentCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a tournament draw and its first-round fixtures",
+	Long: `Create a single-elimination draw from a seeded list of players.
+
+Players are listed in seed order (top seed first), or reseeded by current
+singles rating with --seed-by rating. The draw is padded to a power of two
+with byes, which go to the top seeds.
+
+Examples:
+  tennis tournament create spring-open --players "@alice,@bob,@carol,@dave"
+  tennis tournament create spring-open --players "@alice,@bob,@carol" --seed-by rating`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		players, _ := cmd.Flags().GetString("players")
+		seedBy, _ := cmd.Flags().GetString("seed-by")
+
+		if !tournamentNameRegex.MatchString(name) {
+			return invalidf("invalid tournament name '%s'. Use lowercase letters, digits and dashes", name)
+		}
+		for _, path := range []string{tournamentPath(name), swissPath(name)} {
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("tournament '%s' already exists", name)
+			}
+		}
+		if players == "" {
+			return fmt.Errorf("players are required (use --players)")
+		}
+
+		var seeds []string
+		seen := make(map[string]bool)
+		for _, p := range strings.Split(players, ",") {
+			p = normalizePlayer(p)
+			if p == "" {
+				continue
+			}
+			if seen[p] {
+				return fmt.Errorf("player '@%s' is listed more than once", p)
+			}
+			seen[p] = true
+			seeds = append(seeds, p)
+		}
+		if len(seeds) < 2 {
+			return fmt.Errorf("at least 2 players required for a tournament")
+		}
+		seeds, err := seedPlayers(cmd.Context(), seeds, seedBy)
+		if err != nil {
+			return err
+		}
+
+		t := &Tournament{
+			Name:    name,
+			Created: time.Now().Format("2006-01-02"),
+			Status:  tournamentInProgress,
+			Players: seeds,
+			Rounds:  []TournamentRound{newDraw(seeds)},
+		}
+		for i, m := range t.Rounds[0].Matches {
+			if m.IsBye() {
+				t.Rounds[0].Matches[i].Winner = m.ByeWinner()
+			}
+		}
+
+		// The draw is saved before any fixture is opened, and again after
+		// each one, so a failure part way through leaves no issue unrecorded.
+		if !dryRun {
+			if err := saveTournament(t); err != nil {
+				return fmt.Errorf("failed to save tournament: %w", err)
+			}
+		}
+		if err := openRoundFixtures(cmd.Context(), t, 0); err != nil {
+			return fmt.Errorf("%w (the draw is saved; run `tennis tournament advance %s` to open the remaining fixtures)", err, name)
+		}
+		if dryRun {
+			return nil
+		}
+
+		fmt.Printf("✅ Tournament '%s' created with %d players\n", name, len(seeds))
+		fmt.Printf("Draw saved to %s — commit it to share the draw\n", tournamentPath(name))
+		return nil
+	},
+}
+
+var tournamentSeedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Print a seeded player list",
+	Long: `Print players in seed order so the same seeding can be used for
+"tournament create" or imported into external tournament software.
+
+Without --players, the league's active players are seeded.
+
+Examples:
+  tennis tournament seed --by rating
+  tennis tournament seed --by rating --players "@alice,@bob,@carol" --format csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by, _ := cmd.Flags().GetString("by")
+		players, _ := cmd.Flags().GetString("players")
+		format, _ := cmd.Flags().GetString("format")
+
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		var list []string
+		if players != "" {
+			for _, p := range strings.Split(players, ",") {
+				if p = normalizePlayer(p); p != "" {
+					list = append(list, p)
+				}
+			}
+		} else if list, err = activePlayers(matches, time.Now()); err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("no players to seed (use --players)")
+		}
+
+		seeded, err := seedPlayers(ctx, list, by)
+		if err != nil {
+			return err
+		}
+
+		ratings := computeSinglesRatings(matches)
+		switch format {
+		case "text":
+			for i, p := range seeded {
+				fmt.Printf("%2d. @%s (%.0f)\n", i+1, p, rating(ratings, p))
+			}
+		case "csv":
+			return writeDraw(os.Stdout, &Tournament{Players: seeded}, "csv")
+		case "list":
+			handles := make([]string, len(seeded))
+			for i, p := range seeded {
+				handles[i] = "@" + p
+			}
+			fmt.Println(strings.Join(handles, ","))
+		default:
+			return invalidf("unknown format '%s' (use text, csv or list)", format)
+		}
+		return nil
+	},
+}
+
+var tournamentExportCmd = &cobra.Command{
+	Use:   "export <name>",
+	Short: "Export the draw as JSON, CSV, markdown or PDF",
+	Long: `Export a tournament draw.
+
+Formats:
+  json      full draw with seeds, rounds and results
+  csv       seed list (seed,name) for bracket tools' bulk import
+  markdown  printable draw sheet
+  pdf       printable draw sheet as PDF (requires --out)
+
+Players with a pseudonym in .tennis.yml appear under it in these formats.
+
+With --challonge <slug>, the draw is created on Challonge instead, as a
+single-elimination tournament at challonge.com/<slug> with the players in
+seed order (needs CHALLONGE_API_KEY). The draw is linked to it, so
+results entered on Challonge can be pulled back with
+"tennis tournament import --challonge <slug> --name <name>".
+
+Examples:
+  tennis tournament export spring-open --format json > draw.json
+  tennis tournament export spring-open --format pdf --out draw.pdf
+  tennis tournament export spring-open --challonge spring_open_2025`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		slug, _ := cmd.Flags().GetString("challonge")
+
+		t, err := loadTournament(args[0])
+		if err != nil {
+			return err
+		}
+
+		if slug != "" {
+			if !challongeSlugRegex.MatchString(slug) {
+				return invalidf("invalid Challonge URL '%s'. Use letters, digits and underscores", slug)
+			}
+			if t.Challonge != "" {
+				return fmt.Errorf("tournament '%s' is already linked to Challonge tournament '%s'", t.Name, t.Challonge)
+			}
+			if dryRun {
+				fmt.Printf("[dry-run] would create Challonge tournament '%s' with %d players:\n", slug, len(t.Players))
+				for i, p := range t.Players {
+					fmt.Printf("  %d. @%s\n", i+1, p)
+				}
+				return nil
+			}
+			page, err := pushToChallonge(cmd.Context(), t, slug)
+			if err != nil {
+				return err
+			}
+			t.Challonge = slug
+			if err := saveTournament(t); err != nil {
+				return fmt.Errorf("failed to save tournament: %w", err)
+			}
+			fmt.Printf("✅ Draw exported to Challonge: %s\n", page)
+			return nil
+		}
+
+		if out == "" {
+			if format == "pdf" {
+				return invalidf("--out is required for pdf output")
+			}
+			return writeDraw(os.Stdout, t, format)
+		}
+
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := writeDraw(f, t, format); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Draw written to %s\n", out)
+		return nil
+	},
+}
+
+var tournamentImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a Challonge tournament and its results",
+	Long: `Import a single-elimination tournament run on Challonge, so hybrid
+events still flow into the rankings.
+
+Challonge participants must be named by GitHub handle. The draw is saved
+in tournaments/<name>.yml with Challonge's seeding, and each completed
+Challonge match is opened as a singles match issue, which the usual
+workflow records. Once merged, "tournament advance" moves the winners
+through as for any other draw. Matches may also be recorded here: the
+first result between the two players counts, wherever it was entered.
+
+Run the import again to pick up new Challonge results; matches already
+imported are skipped, and a result already opened as an issue (e.g. by
+an import that was interrupted) isn't opened again. No fixture issues are opened for a Challonge draw.
+
+Results are checked (scores, league rules and players' GitHub accounts)
+--concurrency at a time, then their issues are opened one by one in the
+order the matches finished. An issue that fails to open is tried again up
+to --retries times; if it still fails, the import stops there and saves
+the results opened so far, so a rerun carries on in order.
+
+Issues are opened at most --writes-per-second (default 1), more slowly
+if GitHub answers with a secondary rate limit.
+
+Requires CHALLONGE_API_KEY.
+
+Examples:
+  tennis tournament import --challonge spring_open_2025 --dry-run
+  tennis tournament import --challonge 1234567 --name spring-open`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, _ := cmd.Flags().GetString("challonge")
+		name, _ := cmd.Flags().GetString("name")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		retries, _ := cmd.Flags().GetInt("retries")
+		if id == "" {
+			return fmt.Errorf("a Challonge tournament id or URL is required (use --challonge)")
+		}
+		if concurrency < 1 {
+			return invalidf("--concurrency must be at least 1")
+		}
+		if retries < 0 {
+			return invalidf("--retries can't be negative")
+		}
+		ctx := cmd.Context()
+
+		c, err := fetchChallonge(ctx, id)
+		if err != nil {
+			return err
+		}
+		if c.TournamentType != "single elimination" {
+			return fmt.Errorf("Challonge tournament '%s' is %s; only single elimination can be imported", id, c.TournamentType)
+		}
+		if name == "" {
+			name = strings.ToLower(strings.ReplaceAll(c.URL, "_", "-"))
+		}
+		if !tournamentNameRegex.MatchString(name) {
+			return invalidf("invalid tournament name '%s'. Use lowercase letters, digits and dashes (set one with --name)", name)
+		}
+
+		var t *Tournament
+		if _, err := os.Stat(tournamentPath(name)); err == nil {
+			if t, err = loadTournament(name); err != nil {
+				return err
+			}
+			if t.Challonge != id && t.Challonge != c.URL {
+				return fmt.Errorf("tournament '%s' already exists and is not linked to Challonge tournament '%s'", name, id)
+			}
+		} else {
+			seeds := c.seeds()
+			if len(seeds) < 2 {
+				return fmt.Errorf("Challonge tournament '%s' has fewer than 2 participants", id)
+			}
+			seen := make(map[string]bool)
+			handles := make([]string, 0, len(seeds))
+			for _, p := range seeds {
+				if p == "" || seen[p] {
+					return fmt.Errorf("Challonge participants must have distinct GitHub handles as names (found '%s' twice or empty)", p)
+				}
+				seen[p] = true
+				handles = append(handles, "@"+p)
+			}
+			err := githubapi.ForEach(ctx, concurrency, handles, func(ctx context.Context, h string) error {
+				return validateHandles(ctx, []string{h})
+			})
+			if err != nil {
+				return err
+			}
+
+			created := c.StartedAt
+			if created == "" {
+				created = c.CreatedAt
+			}
+			t = &Tournament{
+				Name:      name,
+				Created:   challongeDate(created),
+				Status:    tournamentInProgress,
+				Players:   seeds,
+				Rounds:    []TournamentRound{newDraw(seeds)},
+				Challonge: id,
+			}
+			for i, m := range t.Rounds[0].Matches {
+				if m.IsBye() {
+					t.Rounds[0].Matches[i].Winner = m.ByeWinner()
+				}
+			}
+			fmt.Printf("Imported draw '%s' with %d players from Challonge\n", name, len(seeds))
+		}
+
+		imported := make(map[int]bool)
+		for _, m := range t.ChallongeMatches {
+			imported[m] = true
+		}
+		// The draw's players were checked when it was imported; only
+		// participants added on Challonge since need checking.
+		validated := make(map[string]bool)
+		for _, p := range t.Players {
+			validated[p] = true
+		}
+		var pending []challongeMatch
+		for _, m := range c.completed() {
+			if !imported[m.ID] {
+				pending = append(pending, m)
+			}
+		}
+		if err := loadRepoRules(ctx); err != nil {
+			return err
+		}
+		imports, err := prepareChallongeImports(ctx, pending, c.handles(), validated, concurrency)
+		if err != nil {
+			return err
+		}
+
+		added := 0
+		var failed error
+		p := newProgress("Importing Challonge results", len(imports))
+		for _, im := range imports {
+			if im.err != nil {
+				p.Printf("⚠️  Skipping %v\n", im.err)
+				p.Add(1)
+				continue
+			}
+			p.Clear()
+			if failed = openChallongeImport(ctx, im, retries); failed != nil {
+				break
+			}
+			t.ChallongeMatches = append(t.ChallongeMatches, im.match.ID)
+			added++
+			p.Add(1)
+		}
+		p.Finish()
+
+		if dryRun {
+			return failed
+		}
+		if err := saveTournament(t); err != nil {
+			return fmt.Errorf("failed to save tournament: %w", err)
+		}
+		if failed != nil {
+			return fmt.Errorf("%d new Challonge results opened as match issues, then the import stopped (saved to %s; run it again to carry on): %w", added, tournamentPath(name), failed)
+		}
+		fmt.Printf("✅ %d new Challonge results opened as match issues\n", added)
+		fmt.Printf("Draw saved to %s — commit it to share the draw\n", tournamentPath(name))
+		return nil
+	},
+}
+
+var tournamentStatusCmd = &cobra.Command{
+	Use:   "status <name>",
+	Short: "Show the draw and results so far",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := loadTournament(args[0])
+		if err != nil {
+			return err
+		}
+		printTournament(t)
+		return nil
+	},
+}
+
+var tournamentAdvanceCmd = &cobra.Command{
+	Use:   "advance [name]",
+	Short: "Advance winners of recorded matches",
+	Long: `Look up recorded results for the current round and advance winners.
+
+A tournament match is decided by the first singles match between its two
+players recorded on or after the tournament's creation date. Decided
+fixture issues are closed with a comment, and when a round completes the
+next round's fixtures are created.
+
+Use --all to advance every in-progress tournament (as the tournament
+workflow does after match data is merged).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+
+		var names []string
+		switch {
+		case all:
+			files, _ := filepath.Glob(filepath.Join(leagueDir(), "tournaments", "*.yml"))
+			for _, f := range files {
+				names = append(names, strings.TrimSuffix(filepath.Base(f), ".yml"))
+			}
+		case len(args) == 1:
+			names = args
+		default:
+			return fmt.Errorf("tournament name required (or use --all)")
+		}
+
+		ctx := cmd.Context()
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		for _, name := range names {
+			t, err := loadTournament(name)
+			if err != nil {
+				return err
+			}
+			if t.Status == tournamentFinished {
+				if !all {
+					return fmt.Errorf("tournament '%s' is already finished", name)
+				}
+				continue
+			}
+			if err := advanceTournament(cmd.Context(), t, matches); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var tournamentFinishCmd = &cobra.Command{
+	Use:   "finish <name>",
+	Short: "Close out a tournament once the final is decided",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := loadTournament(args[0])
+		if err != nil {
+			return err
+		}
+		if t.Status == tournamentFinished {
+			return fmt.Errorf("tournament '%s' is already finished", t.Name)
+		}
+
+		last := t.Rounds[t.CurrentRound()]
+		if len(last.Matches) != 1 || last.Matches[0].Winner == "" {
+			return fmt.Errorf("tournament '%s' is not finished yet: the final hasn't been decided (run `tennis tournament advance %s`)", t.Name, t.Name)
+		}
+
+		t.Status = tournamentFinished
+		t.Champion = last.Matches[0].Winner
+
+		if dryRun {
+			fmt.Printf("[dry-run] would mark '%s' finished with champion @%s\n", t.Name, t.Champion)
+			return nil
+		}
+		if err := saveTournament(t); err != nil {
+			return fmt.Errorf("failed to save tournament: %w", err)
+		}
+
+		fmt.Printf("🏆 @%s wins %s!\n", t.Champion, t.Name)
+		return nil
+	},
+}
+
+// advanceTournament records results for the current round and, if the
+// round is complete, opens the next one.
+func advanceTournament(ctx context.Context, t *Tournament, matches []Match) error {
+	r := t.CurrentRound()
+	round := &t.Rounds[r]
+
+	used := make(map[int]bool)
+	for _, rnd := range t.Rounds {
+		for _, m := range rnd.Matches {
+			if m.Result != 0 {
+				used[m.Result] = true
+			}
+		}
+	}
+
+	var decided []int
+	for i := range round.Matches {
+		tm := &round.Matches[i]
+		if tm.Winner != "" {
+			continue
+		}
+		result, ok := findResult(tm.Players, t.Created, matches, used)
+		if !ok {
+			continue
+		}
+		used[result.SourceIssue] = true
+		tm.Result = result.SourceIssue
+		tm.Winner = result.Players[matchWinner(result)-1]
+		decided = append(decided, i)
+	}
+
+	for _, i := range decided {
+		tm := round.Matches[i]
+		fmt.Printf("%s: @%s advances (match #%d)\n", t.RoundName(r), tm.Winner, tm.Result)
+		if tm.Issue == 0 {
+			continue
+		}
+		if err := closeIssue(ctx, tm.Issue, fmt.Sprintf("@%s advances — result recorded in #%d.", tm.Winner, tm.Result)); err != nil {
+			return err
+		}
+	}
+
+	complete := true
+	for _, tm := range round.Matches {
+		if tm.Winner == "" {
+			complete = false
+		}
+	}
+
+	switch {
+	case !complete:
+		fmt.Printf("%s: %s still in progress\n", t.Name, t.RoundName(r))
+		// Opens any fixture an earlier run failed to.
+		if err := openRoundFixtures(ctx, t, r); err != nil {
+			return err
+		}
+	case len(round.Matches) == 1:
+		fmt.Printf("%s: final decided — run `tennis tournament finish %s`\n", t.Name, t.Name)
+	default:
+		t.Rounds = append(t.Rounds, nextRound(*round))
+		if err := openRoundFixtures(ctx, t, r+1); err != nil {
+			return err
+		}
+	}
+
+	if dryRun || (len(decided) == 0 && !complete) {
+		return nil
+	}
+	return saveTournament(t)
+}
+
+// findResult returns the earliest singles match recorded in this
+// repository between the two players dated on or after since, with a
+// decisive winner and not already credited to another tournament match.
+func findResult(players [2]string, since string, matches []Match, used map[int]bool) (Match, bool) {
+	candidates := make([]Match, 0)
+	for _, m := range matches {
+		if len(m.Players) != 2 || m.Repo != "" || m.Date < since || used[m.SourceIssue] || matchWinner(m) == 0 {
+			continue
+		}
+		if pairKey(m.Players[0], m.Players[1]) == pairKey(players[0], players[1]) {
+			candidates = append(candidates, m)
+		}
+	}
+	if len(candidates) == 0 {
+		return Match{}, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Date < candidates[j].Date })
+	return candidates[0], true
+}
+
+// openRoundFixtures creates fixture issues for every playable match in the
+// given round that doesn't have one yet, and records their numbers in the
+// draw, saving it after each so no issue is ever opened twice. Draws linked
+// to Challonge get none.
+func openRoundFixtures(ctx context.Context, t *Tournament, r int) error {
+	if t.Challonge != "" {
+		// Challonge schedules the matches of a linked draw.
+		return nil
+	}
+	for i, tm := range t.Rounds[r].Matches {
+		if tm.IsBye() || tm.Winner != "" || tm.Issue != 0 {
+			continue
+		}
+		p1, p2 := "@"+tm.Players[0], "@"+tm.Players[1]
+		title := fmt.Sprintf("%s %s: %s vs %s", t.Name, t.RoundName(r), p1, p2)
+		body := fmt.Sprintf(`### Tournament
+%s — %s
+
+### Players
+%s, %s
+
+Play your match, then record the result with `+"`tennis match singles`"+` or the singles match issue form. The winner advances automatically once the match data is merged.`,
+			t.Name, t.RoundName(r), p1, p2)
+
+		number, err := openIssue(ctx, title, body, []string{labelNames.Tournament, t.Label()})
+		if err != nil {
+			return err
+		}
+		t.Rounds[r].Matches[i].Issue = number
+		if dryRun {
+			continue
+		}
+		if err := saveTournament(t); err != nil {
+			return fmt.Errorf("failed to save tournament after opening #%d: %w", number, err)
+		}
+	}
+	return nil
+}
+
+func printTournament(t *Tournament) {
+	fmt.Printf("%s (%s, created %s)\n", t.Name, t.Status, t.Created)
+	for r, round := range t.Rounds {
+		fmt.Printf("\n%s\n", t.RoundName(r))
+		for _, tm := range round.Matches {
+			if tm.IsBye() {
+				fmt.Printf("  @%s — bye\n", tm.ByeWinner())
+				continue
+			}
+			line := fmt.Sprintf("  @%s vs @%s", tm.Players[0], tm.Players[1])
+			if tm.Issue != 0 {
+				line += fmt.Sprintf(" (#%d)", tm.Issue)
+			}
+			if tm.Winner != "" {
+				line += fmt.Sprintf(" → @%s", tm.Winner)
+			}
+			fmt.Println(line)
+		}
+	}
+	if t.Champion != "" {
+		fmt.Printf("\n🏆 Champion: @%s\n", t.Champion)
+	}
+}
+
+func init() {
+	tournamentCreateCmd.Flags().StringP("players", "p", "", "Players in seed order, comma-separated: @top_seed,@second_seed,...")
+	tournamentCreateCmd.Flags().String("seed-by", "list", "Seeding: list (as given) or rating (current singles rating)")
+	tournamentSeedCmd.Flags().String("by", "rating", "Seeding: rating or list")
+	tournamentSeedCmd.Flags().StringP("players", "p", "", "Players to seed, comma-separated (defaults to active players)")
+	tournamentSeedCmd.Flags().String("format", "text", "Output format: text, csv or list")
+	tournamentExportCmd.Flags().StringP("format", "f", "json", "Export format: json, csv, markdown or pdf")
+	tournamentExportCmd.Flags().StringP("out", "o", "", "Write to a file instead of stdout")
+	tournamentExportCmd.Flags().String("challonge", "", "Create the draw on Challonge at this URL slug instead of writing it out")
+	tournamentImportCmd.Flags().String("challonge", "", "Challonge tournament id or URL slug to import")
+	tournamentImportCmd.Flags().String("name", "", "Tournament name (defaults to the Challonge URL slug)")
+	tournamentImportCmd.Flags().BoolVar(&noValidate, "no-validate", false, "Skip checking that participant handles exist on GitHub")
+	tournamentImportCmd.Flags().Int("concurrency", githubapi.Workers, "How many results to check at once before their issues are opened in order")
+	tournamentImportCmd.Flags().Int("retries", 2, "How many more times to try opening an issue that fails")
+	addThrottleFlag(tournamentImportCmd)
+	tournamentAdvanceCmd.Flags().Bool("all", false, "Advance every in-progress tournament")
+
+	tournamentCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the issues that would be created without creating them or saving the draw")
+
+	tournamentCmd.AddCommand(tournamentCreateCmd)
+	tournamentCmd.AddCommand(tournamentSeedCmd)
+	tournamentCmd.AddCommand(tournamentStatusCmd)
+	tournamentCmd.AddCommand(tournamentExportCmd)
+	tournamentCmd.AddCommand(tournamentImportCmd)
+	tournamentCmd.AddCommand(tournamentAdvanceCmd)
+	tournamentCmd.AddCommand(tournamentFinishCmd)
+	rootCmd.AddCommand(tournamentCmd)
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check league data for integrity",
+}
+
+// publishedRankings is the Pages site's rankings.json.
+type publishedRankings struct {
+	Generated         string           `json:"generated"`
+	Singles           []LeaderboardRow `json:"singles"`
+	DoublesIndividual []LeaderboardRow `json:"doubles_individual"`
+	Category          string           `json:"category,omitempty"`
+	Elo               *eloParams       `json:"elo,omitempty"`
+
+	fromHTML bool // scraped from index.html: ratings are whole numbers, no doubles
+}
+
+var verifyRankingsCmd = &cobra.Command{
+	Use:   "rankings",
+	Short: "Compare the published leaderboard with the match data",
+	Long: `Recompute the singles and doubles leaderboards from the match files in
+the local checkout and compare them with what the GitHub Pages site
+publishes. The command fails with a diff if the published data has drifted
+from the source matches (for example a failed deploy, or a match file edited
+without a rebuild).
+
+The site's rankings.json is used when available; older sites without it are
+checked against the singles table in index.html.
+
+Examples:
+  tennis verify rankings
+  tennis verify rankings --url https://my-club.github.io/tennis/`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url, _ := cmd.Flags().GetString("url")
+
+		ctx := cmd.Context()
+		if url == "" {
+			url = pagesURL(ctx)
+		}
+		url = strings.TrimSuffix(url, "/") + "/"
+
+		published, err := fetchPublishedRankings(ctx, url)
+		if err != nil {
+			return err
+		}
+
+		roster, err := loadRoster()
+		if err != nil {
+			return err
+		}
+		singles, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+		doubles, err := loadDoublesMatches(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load matches: %w", err)
+		}
+
+		fmt.Printf("Comparing %s", url)
+		if published.Generated != "" {
+			fmt.Printf(" (built %s)", published.Generated)
+		}
+		fmt.Printf(" with %s\n\n", leagueDir())
+
+		today := time.Now().Format("2006-01-02")
+		var diffs []string
+		if published.Elo != nil && *published.Elo != elo {
+			diffs = append(diffs, fmt.Sprintf("elo: published with %s, .tennis.yml has %s", *published.Elo, elo))
+		}
+		diffs = append(diffs, diffLeaderboards("singles", singlesLeaderboard(singles, roster, today), published.Singles, published.fromHTML)...)
+		if !published.fromHTML {
+			diffs = append(diffs, diffLeaderboards("doubles", doublesLeaderboard(doubles, roster, today), published.DoublesIndividual, false)...)
+		}
+		if len(diffs) == 0 {
+			fmt.Println("✅ Published rankings match the match data")
+			return nil
+		}
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+		return fmt.Errorf("published rankings have drifted from the match data (%d differences)", len(diffs))
+	},
+}
+
+// pagesURL returns the repository's GitHub Pages URL, asking the API and
+// falling back to the default <owner>.github.io/<repo> address.
+func pagesURL(ctx context.Context) string {
+	return repoPagesURL(ctx, owner, repo)
+}
+
+// repoPagesURL returns the GitHub Pages URL of any repository, like pagesURL.
+func repoPagesURL(ctx context.Context, o, name string) string {
+	if token != "" {
+		if pages, _, err := getGitHubClient().Repositories.GetPagesInfo(ctx, o, name); err == nil && pages.GetHTMLURL() != "" {
+			return pages.GetHTMLURL()
+		}
+	}
+	return fmt.Sprintf("https://%s.github.io/%s/", strings.ToLower(o), name)
+}
+
+func fetchPublishedRankings(ctx context.Context, base string) (*publishedRankings, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	body, status, err := httpGet(ctx, client, base+"rankings.json")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusOK {
+		var p publishedRankings
+		if err := json.Unmarshal(body, &p); err != nil {
+			return nil, fmt.Errorf("invalid rankings.json at %s: %w", base, err)
+		}
+		return &p, nil
+	}
+
+	body, status, err = httpGet(ctx, client, base)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch the published leaderboard from %s (HTTP %d)", base, status)
+	}
+	return &publishedRankings{Singles: parseLeaderboardHTML(string(body)), fromHTML: true}, nil
+}
+
+func httpGet(ctx context.Context, client *http.Client, url string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+var leaderboardRowRegex = regexp.MustCompile(`(?s)<td>(\d+)</td>\s*<td><a href="player_profile_([^"]+)\.html">.*?</a></td>\s*<td>(\d+)</td>\s*<td>(\d+)-(\d+)</td>\s*<td>(\d+)-(\d+)</td>`)
+
+// parseLeaderboardHTML reads the singles table from the site's index.html.
+func parseLeaderboardHTML(html string) []LeaderboardRow {
+	if i := strings.Index(html, "Doubles Leaderboard"); i != -1 {
+		html = html[:i]
+	}
+	var rows []LeaderboardRow
+	for _, g := range leaderboardRowRegex.FindAllStringSubmatch(html, -1) {
+		n := make([]int, 8)
+		for i := range g {
+			if i == 0 || i == 2 {
+				continue
+			}
+			n[i], _ = strconv.Atoi(g[i])
+		}
+		rows = append(rows, LeaderboardRow{
+			Rank: n[1], Player: g[2], Rating: float64(n[3]),
+			SetWins: n[4], SetLosses: n[5], GameWins: n[6], GameLosses: n[7],
+		})
+	}
+	return rows
+}
+
+// diffLeaderboards lists every difference between the local and published
+// boards. wholeRatings compares ratings as the HTML table shows them
+// (truncated to whole numbers).
+func rankedLabel(r LeaderboardRow) string {
+	if r.Unranked {
+		return "unranked"
+	}
+	return "ranked"
+}
+
+func diffLeaderboards(name string, local, published []LeaderboardRow, wholeRatings bool) []string {
+	pub := make(map[string]LeaderboardRow)
+	for _, r := range published {
+		pub[r.Player] = r
+	}
+	seen := make(map[string]bool)
+
+	var diffs []string
+	for _, l := range local {
+		// Players with a pseudonym are published under it.
+		seen[publicHandle(l.Player)] = true
+		p, ok := pub[publicHandle(l.Player)]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: + @%s (rating %.1f) is missing from the published leaderboard", name, l.Player, l.Rating))
+			continue
+		}
+		want := l.Rating
+		if wholeRatings {
+			want = math.Trunc(want)
+		}
+		if math.Abs(want-p.Rating) > 0.05 {
+			diffs = append(diffs, fmt.Sprintf("%s: ~ @%s rating published %.1f, match data gives %.1f", name, l.Player, p.Rating, want))
+		}
+		if p.Unranked != l.Unranked && !wholeRatings {
+			diffs = append(diffs, fmt.Sprintf("%s: ~ @%s published as %s, match data gives %s", name, l.Player, rankedLabel(p), rankedLabel(l)))
+		}
+		if p.Tier != l.Tier && !wholeRatings {
+			diffs = append(diffs, fmt.Sprintf("%s: ~ @%s tier published %q, match data gives %q", name, l.Player, p.Tier, l.Tier))
+		}
+		if math.Abs(p.Points-l.Points) > 0.05 && !wholeRatings {
+			diffs = append(diffs, fmt.Sprintf("%s: ~ @%s points published %.1f, match data gives %.1f", name, l.Player, p.Points, l.Points))
+		}
+		if p.SetWins != l.SetWins || p.SetLosses != l.SetLosses {
+			diffs = append(diffs, fmt.Sprintf("%s: ~ @%s sets published %d-%d, match data gives %d-%d", name, l.Player, p.SetWins, p.SetLosses, l.SetWins, l.SetLosses))
+		}
+		if p.GameWins != l.GameWins || p.GameLosses != l.GameLosses {
+			diffs = append(diffs, fmt.Sprintf("%s: ~ @%s games published %d-%d, match data gives %d-%d", name, l.Player, p.GameWins, p.GameLosses, l.GameWins, l.GameLosses))
+		}
+	}
+	for _, p := range published {
+		if !seen[p.Player] {
+			diffs = append(diffs, fmt.Sprintf("%s: - @%s is published but not in the match data's leaderboard", name, p.Player))
+		}
+	}
+	return diffs
+}
+
+func init() {
+	verifyRankingsCmd.Flags().String("url", "", "Base URL of the published site (defaults to the repository's GitHub Pages URL)")
+
+	verifyCmd.AddCommand(verifyRankingsCmd)
+	rootCmd.AddCommand(verifyCmd)
+}
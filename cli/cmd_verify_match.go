@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+// verifyCheck is one check made by "verify match".
+type verifyCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// matchVerification is the result of "verify match", also its JSON output.
+type matchVerification struct {
+	Issue       int           `json:"issue"`
+	Kind        string        `json:"kind"`
+	Players     []string      `json:"players"`
+	PullRequest int           `json:"pull_request,omitempty"`
+	Verified    bool          `json:"verified"`
+	Checks      []verifyCheck `json:"checks"`
+}
+
+var verifyMatchCmd = &cobra.Command{
+	Use:   "match <issue>",
+	Short: "Check a match issue is ready to be recorded",
+	Long: `Verify a match issue before its result is merged:
+
+  format         the issue parses: date, players and set scores are valid,
+                 and the match keeps to the league's rules (.tennis.yml)
+  participants   no player is listed twice
+  collaborators  every player is a repository collaborator, so their
+                 approving review counts
+  approvals      every player other than the reporter has approved the
+                 match pull request (branch match/issue-<n>), by review
+                 or by commenting /approve (or as rules.approvals says)
+
+Exits 0 when every check passes and 1 otherwise, so it can run as a
+required status check. --format json prints a machine-readable result.
+
+Examples:
+  tennis verify match 42
+  tennis verify match 42 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return invalidf("unknown format '%s'. Use text or json", format)
+		}
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			return invalidf("invalid issue number '%s'", args[0])
+		}
+
+		v, err := verifyMatch(cmd.Context(), getGitHubClient(), number)
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+		} else {
+			printMatchVerification(v)
+		}
+		if !v.Verified {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("match #%d failed verification", number)
+		}
+		return nil
+	},
+}
+
+func verifyMatch(ctx context.Context, client *github.Client, number int) (*matchVerification, error) {
+	if err := loadRepoRules(ctx); err != nil {
+		return nil, err
+	}
+	issue, _, err := client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch #%d: %w", number, err)
+	}
+	kind, _, ok := matchIssueKind(issue)
+	if !ok || kind == "conflict" {
+		return nil, fmt.Errorf("#%d is not a singles or doubles match issue", number)
+	}
+
+	parsed := parseMatchIssue(kind, issue.GetBody())
+	v := &matchVerification{Issue: number, Kind: kind, Players: parsed.Players}
+	add := func(name string, ok bool, detail string) {
+		v.Checks = append(v.Checks, verifyCheck{Name: name, OK: ok, Detail: detail})
+	}
+
+	// Format and participants come from the same validation audit uses.
+	var formatProblems, participantProblems []string
+	for _, p := range parsed.validate() {
+		if p.Code == "self_match" {
+			participantProblems = append(participantProblems, p.Message)
+		} else {
+			formatProblems = append(formatProblems, p.Message)
+		}
+	}
+	add("format", len(formatProblems) == 0, strings.Join(formatProblems, "; "))
+	add("participants", len(participantProblems) == 0, strings.Join(participantProblems, "; "))
+
+	var outsiders []string
+	for _, p := range parsed.Players {
+		if p == "" {
+			continue
+		}
+		isCollab, _, err := client.Repositories.IsCollaborator(ctx, owner, repo, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check whether @%s is a collaborator: %w", p, err)
+		}
+		if !isCollab {
+			outsiders = append(outsiders, "@"+p)
+		}
+	}
+	detail := ""
+	if len(outsiders) > 0 {
+		detail = "not collaborators: " + strings.Join(outsiders, ", ")
+	}
+	add("collaborators", len(outsiders) == 0, detail)
+
+	pr, err := matchPullRequest(ctx, client, number)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		add("approvals", false, fmt.Sprintf("no pull request from branch match/issue-%d", number))
+	} else {
+		v.PullRequest = pr.GetNumber()
+		pending, err := pendingApprovals(ctx, client, number, pr.GetNumber(), parsed.Players, normalizePlayer(issue.GetUser().GetLogin()))
+		if err != nil {
+			return nil, err
+		}
+		detail := ""
+		if len(pending) > 0 {
+			detail = "waiting on " + strings.Join(pending, ", ")
+		}
+		add("approvals", len(pending) == 0, detail)
+	}
+
+	v.Verified = true
+	for _, c := range v.Checks {
+		v.Verified = v.Verified && c.OK
+	}
+	return v, nil
+}
+
+// matchPullRequest returns the PR the issue-to-PR workflow opened for an
+// issue, preferring an open or merged one, or nil.
+func matchPullRequest(ctx context.Context, client *github.Client, number int) (*github.PullRequest, error) {
+	prs, _, err := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+		State: "all",
+		Head:  fmt.Sprintf("%s:match/issue-%d", owner, number),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the pull request for #%d: %w", number, err)
+	}
+	var best *github.PullRequest
+	for _, pr := range prs {
+		if best == nil || pr.GetState() == "open" || pr.MergedAt != nil {
+			best = pr
+		}
+	}
+	return best, nil
+}
+
+// pendingApprovals returns the players, other than the reporter, who
+// haven't approved the match (see playerApprovals).
+func pendingApprovals(ctx context.Context, client *github.Client, issue, pr int, players []string, reporter string) ([]string, error) {
+	approved, err := playerApprovals(ctx, client, issue, pr)
+	if err != nil {
+		return nil, err
+	}
+	a := &matchApprovals{Issue: issue, Reporter: reporter, Players: players, Approved: approved}
+	return a.Pending(), nil
+}
+
+func printMatchVerification(v *matchVerification) {
+	fmt.Printf("Match #%d (%s)", v.Issue, v.Kind)
+	if v.PullRequest != 0 {
+		fmt.Printf(", PR #%d", v.PullRequest)
+	}
+	fmt.Println()
+	for _, c := range v.Checks {
+		icon := "✅"
+		if !c.OK {
+			icon = "❌"
+		}
+		if c.Detail != "" {
+			fmt.Printf("%s %-14s %s\n", icon, c.Name, c.Detail)
+		} else {
+			fmt.Printf("%s %s\n", icon, c.Name)
+		}
+	}
+}
+
+func init() {
+	verifyMatchCmd.Flags().String("format", "text", "Output format: text or json")
+
+	verifyCmd.AddCommand(verifyMatchCmd)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Send desktop notifications about your matches and rank",
+	Long: `Poll the league and send a desktop notification when a new match is
+recorded with you in it, a match result is waiting on your approval, or
+your rank on the published leaderboard changes. Runs until interrupted,
+or for --timeout, then prints how many notifications it sent.
+
+The player is the owner of the GitHub token unless --player is given.
+Notifications use notify-send on Linux, osascript on macOS and a toast on
+Windows; each one is also printed, so --no-desktop works anywhere.
+
+Examples:
+  tennis watch
+  tennis watch --interval 2m
+  tennis watch --player @player_one --no-desktop`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		player, _ := cmd.Flags().GetString("player")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		url, _ := cmd.Flags().GetString("url")
+		noDesktop, _ := cmd.Flags().GetBool("no-desktop")
+
+		if interval < time.Minute {
+			return invalidf("--interval must be at least 1m")
+		}
+		ctx := cmd.Context()
+		client := getGitHubClient()
+		if player == "" {
+			user, _, err := client.Users.Get(ctx, "")
+			if err != nil {
+				return fmt.Errorf("failed to look up the token's user (use --player): %w", err)
+			}
+			player = user.GetLogin()
+		}
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		player = resolveAlias(aliases, normalizePlayer(player))
+		if url == "" {
+			url = pagesURL(ctx)
+		}
+
+		w := &matchWatcher{
+			client:    client,
+			player:    player,
+			url:       url,
+			since:     time.Now(),
+			approvals: make(map[int]bool),
+			ranks:     make(map[string]int),
+		}
+		w.notify = func(title, message string) {
+			w.sent++
+			log.Printf("%s: %s", title, message)
+			if noDesktop {
+				return
+			}
+			if err := desktopNotify(title, message); err != nil {
+				log.Printf("⚠️  desktop notification failed: %v", err)
+			}
+		}
+		log.Printf("watching %s/%s for @%s every %s", owner, repo, player, interval)
+		// The first poll only records the current state, so starting the
+		// watch doesn't replay old news.
+		w.poll(ctx, false)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("stopped watching after %d polls; sent %d notifications", w.polls, w.sent)
+				return nil
+			case <-ticker.C:
+				w.poll(ctx, true)
+			}
+		}
+	},
+}
+
+// matchWatcher remembers what a player has already been told about between
+// polls.
+type matchWatcher struct {
+	client *github.Client
+	player string
+	url    string
+	notify func(title, message string)
+
+	since     time.Time    // when the last successful poll started
+	approvals map[int]bool // issues already waiting on the player
+	ranks     map[string]int
+
+	polls, sent int // for the summary when the watch stops
+}
+
+// poll checks for news since the last poll, sending notifications when
+// announce is set. Errors are logged so the watch keeps going, except
+// those from the watch being stopped part-way through.
+func (w *matchWatcher) poll(ctx context.Context, announce bool) {
+	started := time.Now()
+	w.polls++
+	warn := func(err error) {
+		if ctx.Err() == nil {
+			log.Printf("⚠️  %v", err)
+		}
+	}
+
+	waiting, err := approvalsPendingFor(ctx, w.client, w.player)
+	if err != nil {
+		warn(err)
+		return
+	}
+	pending := make(map[int]bool)
+	for _, p := range waiting {
+		n := p.Issue.GetNumber()
+		pending[n] = true
+		if announce && !w.approvals[n] {
+			w.notify("Approval needed", fmt.Sprintf("@%s recorded #%d: %s", p.Reporter, n, p.Issue.GetTitle()))
+		}
+	}
+
+	if announce {
+		for _, label := range []string{labelNames.Singles, labelNames.Doubles} {
+			issues, err := listLabelledIssues(ctx, w.client, label, issueFilter{State: "all", Since: w.since})
+			if err != nil {
+				warn(err)
+				return
+			}
+			for _, issue := range issues {
+				n := issue.GetNumber()
+				kind, _, _ := matchIssueKind(issue)
+				reporter := normalizePlayer(issue.GetUser().GetLogin())
+				if issue.IsPullRequest() || pending[n] || reporter == w.player ||
+					!containsString(parseMatchIssue(kind, issue.GetBody()).Players, w.player) {
+					continue
+				}
+				w.notify("New match", fmt.Sprintf("@%s recorded #%d: %s", reporter, n, issue.GetTitle()))
+			}
+		}
+	}
+	w.approvals = pending
+
+	if published, err := fetchPublishedRankings(ctx, w.url); err != nil {
+		warn(err)
+	} else {
+		for _, kind := range []struct {
+			title string
+			board []LeaderboardRow
+		}{{"Singles", published.Singles}, {"Doubles", published.DoublesIndividual}} {
+			rank := 0
+			for _, r := range kind.board {
+				if r.Player == w.player && !r.Unranked {
+					rank = r.Rank
+				}
+			}
+			if prev, ok := w.ranks[kind.title]; ok && announce && prev != rank {
+				title, message := kind.title+" rank "+movementArrow(prev-rank), fmt.Sprintf("Now #%d (was #%d)", rank, prev)
+				switch {
+				case rank == 0:
+					title, message = kind.title+" rank", fmt.Sprintf("No longer ranked (was #%d)", prev)
+				case prev == 0:
+					title, message = kind.title+" rank", fmt.Sprintf("Now ranked #%d", rank)
+				}
+				w.notify(title, message)
+			}
+			w.ranks[kind.title] = rank
+		}
+	}
+	w.since = started
+}
+
+func init() {
+	watchCmd.Flags().String("player", "", "Player to watch for, defaults to the GitHub token's user")
+	watchCmd.Flags().Duration("interval", 5*time.Minute, "How often to poll")
+	watchCmd.Flags().String("url", "", "Published site to read ranks from (defaults to the repository's GitHub Pages URL)")
+	watchCmd.Flags().Bool("no-desktop", false, "Print notifications without showing them on the desktop")
+	rootCmd.AddCommand(watchCmd)
+}
@@ -1,12 +1,11 @@
 package main
 
 import (
-	"context"
 	"fmt"
-	"strings"
 
-	"github.com/google/go-github/v67/github"
 	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/league"
 )
 
 var workflowCmd = &cobra.Command{
@@ -29,60 +28,28 @@ Examples:
 		workflowName := args[0]
 		environment, _ := cmd.Flags().GetString("environment")
 
-		ctx := context.Background()
+		ctx := cmd.Context()
 		client := getGitHubClient()
+		trigger := league.NewWorkflowTrigger(client.Actions, client.Repositories, owner, repo)
 
-		// First, list workflows to find the right one
-		workflows, _, err := client.Actions.ListWorkflows(ctx, owner, repo, nil)
+		foundWorkflow, workflows, err := trigger.Find(ctx, workflowName)
 		if err != nil {
-			return fmt.Errorf("failed to list workflows: %v", err)
-		}
-
-		var workflowID int64
-		var foundWorkflow *github.Workflow
-
-		// Try to match by name, filename, or ID
-		for _, workflow := range workflows.Workflows {
-			if workflow.Name != nil && strings.EqualFold(*workflow.Name, workflowName) {
-				workflowID = *workflow.ID
-				foundWorkflow = workflow
-				break
-			}
-			if workflow.Path != nil && strings.HasSuffix(*workflow.Path, workflowName) {
-				workflowID = *workflow.ID
-				foundWorkflow = workflow
-				break
-			}
-			// Also try matching the filename without extension
-			if workflow.Path != nil {
-				parts := strings.Split(*workflow.Path, "/")
-				filename := parts[len(parts)-1]
-				nameWithoutExt := strings.TrimSuffix(filename, ".yml")
-				nameWithoutExt = strings.TrimSuffix(nameWithoutExt, ".yaml")
-				if strings.EqualFold(nameWithoutExt, workflowName) {
-					workflowID = *workflow.ID
-					foundWorkflow = workflow
-					break
-				}
-			}
+			return err
 		}
-
 		if foundWorkflow == nil {
 			fmt.Printf("Available workflows:\n")
-			for _, workflow := range workflows.Workflows {
-				fmt.Printf("  - %s (%s)\n", *workflow.Name, *workflow.Path)
+			for _, workflow := range workflows {
+				fmt.Printf("  - %s (%s)\n", workflow.GetName(), workflow.GetPath())
 			}
 			return fmt.Errorf("workflow '%s' not found", workflowName)
 		}
 
 		// Get the default branch for the ref
-		repoInfo, _, err := client.Repositories.Get(ctx, owner, repo)
+		ref, err := trigger.DefaultBranch(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to get repository info: %v", err)
+			return err
 		}
 
-		ref := *repoInfo.DefaultBranch
-
 		// Prepare workflow inputs
 		inputs := make(map[string]interface{})
 		if environment != "" {
@@ -92,22 +59,15 @@ Examples:
 			inputs["environment"] = "github-pages"
 		}
 
-		// Trigger the workflow
-		dispatchOptions := &github.CreateWorkflowDispatchEventRequest{
-			Ref:    ref,
-			Inputs: inputs,
-		}
-
-		fmt.Printf("Triggering workflow: %s\n", *foundWorkflow.Name)
-		fmt.Printf("Path: %s\n", *foundWorkflow.Path)
+		fmt.Printf("Triggering workflow: %s\n", foundWorkflow.GetName())
+		fmt.Printf("Path: %s\n", foundWorkflow.GetPath())
 		fmt.Printf("Ref: %s\n", ref)
 		if len(inputs) > 0 {
 			fmt.Printf("Inputs: %+v\n", inputs)
 		}
 
-		_, err = client.Actions.CreateWorkflowDispatchEventByID(ctx, owner, repo, workflowID, *dispatchOptions)
-		if err != nil {
-			return fmt.Errorf("failed to trigger workflow: %v", err)
+		if err := trigger.Dispatch(ctx, foundWorkflow, ref, inputs); err != nil {
+			return err
 		}
 
 		fmt.Printf("✅ Workflow triggered successfully!\n")
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the league configuration in .tennis.yml at the root of the
+// league checkout. Every setting is optional.
+type Config struct {
+	Name   string        `yaml:"name,omitempty"`
+	Labels []leagueLabel `yaml:"labels,omitempty"`
+	Elo    eloParams     `yaml:"elo,omitempty"`
+
+	Leaderboard qualificationRules `yaml:"leaderboard,omitempty"`
+	Decay       decayRules         `yaml:"decay,omitempty"`
+	Rules       leagueRules        `yaml:"rules,omitempty"`
+	LabelNames  labelScheme        `yaml:"label_names,omitempty"`
+	Tiers       []ratingTier       `yaml:"tiers,omitempty"`
+	Repos       []leagueRepo       `yaml:"repos,omitempty"`
+	Admins      []string           `yaml:"admins,omitempty"`
+	Pseudonyms  map[string]string  `yaml:"pseudonyms,omitempty"`
+	Handicap    handicapRules      `yaml:"handicap,omitempty"`
+	Points      pointsRules        `yaml:"points,omitempty"`
+	Schedule    []scheduledJob     `yaml:"schedule,omitempty"`
+}
+
+// LabelSet returns the declared repository labels, defaulting to the
+// labels the CLI and workflows use. Colours are normalized to lower-case
+// hex without a leading '#'.
+func (c *Config) LabelSet() []leagueLabel {
+	if len(c.Labels) == 0 {
+		return defaultLabels()
+	}
+	labels := make([]leagueLabel, len(c.Labels))
+	for i, l := range c.Labels {
+		l.Color = strings.ToLower(strings.TrimPrefix(l.Color, "#"))
+		labels[i] = l
+	}
+	return labels
+}
+
+func configPath() string {
+	return filepath.Join(leagueDir(), ".tennis.yml")
+}
+
+// loadConfig reads .tennis.yml. A missing file yields the defaults.
+func loadConfig() (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(configPath())
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid .tennis.yml: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadLeagueSettings loads the .tennis.yml settings every command shares:
+// the Elo parameters, the leaderboard qualification rules and tiers, the
+// inactivity decay, the match rules and the label names, along with the
+// rankings freeze.
+func loadLeagueSettings() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	params, err := cfg.Elo.withDefaults()
+	if err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	if cfg.Leaderboard.MinMatches < 0 || cfg.Leaderboard.WindowDays < 0 {
+		return invalidf("invalid .tennis.yml: leaderboard.min_matches and leaderboard.window_days can't be negative")
+	}
+	if cfg.Decay.AfterDays < 0 || cfg.Decay.PointsPerWeek < 0 {
+		return invalidf("invalid .tennis.yml: decay.after_days and decay.points_per_week can't be negative")
+	}
+	if err := cfg.Handicap.check(); err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	if err := cfg.Points.check(); err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	if err := cfg.Rules.check(); err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	names, err := cfg.LabelNames.withDefaults()
+	if err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	bands, err := sortTiers(cfg.Tiers)
+	if err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	var formula *ratingFormula
+	if params.Formula != "" {
+		if formula, err = loadRatingFormula(params.Formula); err != nil {
+			return invalidf("invalid .tennis.yml: elo.formula: %v", err)
+		}
+	}
+	if err := checkLeagueRepos(cfg.Repos); err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	aliases, err := checkPseudonyms(cfg.Pseudonyms)
+	if err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	jobs, err := checkSchedule(cfg.Schedule)
+	if err != nil {
+		return invalidf("invalid .tennis.yml: %v", err)
+	}
+	roster, err := loadRoster()
+	if err != nil {
+		return err
+	}
+	held, err := loadFreeze()
+	if err != nil {
+		return invalidf("%v", err)
+	}
+	elo, customFormula, qualification, decay, rules, labelNames, tiers = params, formula, cfg.Leaderboard, cfg.Decay, cfg.Rules, names, bands
+	leagueRepos, pseudonyms, handicap, rankingPoints = cfg.Repos, aliases, cfg.Handicap, cfg.Points
+	leagueAdmins = nil
+	for _, a := range cfg.Admins {
+		if h := normalizePlayer(a); h != "" {
+			leagueAdmins = append(leagueAdmins, h)
+		}
+	}
+	importedSingles, importedDoubles = roster.ImportedRatings()
+	awayPeriods = roster.AwayPeriods()
+	freeze = held
+	leagueSchedule = jobs
+	return nil
+}
+
+// defaultConfig is the .tennis.yml written by "tennis init".
+func defaultConfig(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `# Tennis league configuration, read by the tennis CLI.
+name: %q
+
+# Names of the labels the CLI applies and looks for. Rename a label here,
+# then run "tennis admin templates sync" and "tennis admin labels sync".
+# label_names:
+#   singles: %s
+#   doubles: %s
+#   approved: %s
+#   needs_correction: %s
+#   challenge: %s
+#   matchmaking: %s
+#   tournament: %s
+#   box_league: %s
+#   standing_fixture: %s
+#   expired: %s
+#   needs_admin: %s
+#   upset: %s
+
+# Repository labels, reconciled by "tennis admin labels sync".
+labels:
+`, name, defaultLabelNames.Singles, defaultLabelNames.Doubles, defaultLabelNames.Approved,
+		defaultLabelNames.NeedsCorrection, defaultLabelNames.Challenge, defaultLabelNames.Matchmaking,
+		defaultLabelNames.Tournament, defaultLabelNames.BoxLeague, defaultLabelNames.StandingFixture,
+		defaultLabelNames.Expired, defaultLabelNames.NeedsAdmin, defaultLabelNames.Upset)
+	for _, l := range defaultLabels() {
+		fmt.Fprintf(&b, "  - name: %s\n    color: %q\n    description: %q\n", l.Name, l.Color, l.Description)
+	}
+	fmt.Fprintf(&b, `
+# Elo rating parameters, used by the CLI and the Pages build.
+#   k               K-factor: how far one set (or game) moves a rating
+#   initial_rating  rating of a player with no matches
+#   margin          weight each set by margin of victory: none, sets
+#                   (the match's set margin) or games (the set's game margin)
+#   formula         file with a custom rating formula (see the CLI README),
+#                   e.g. formula: rating-formula.expr
+#   mode            sets (the default), or games to rate every game, for
+#                   short formats; use a smaller k, and no margin or formula
+elo:
+  k: %g
+  initial_rating: %g
+  margin: %s
+
+# Leaderboard qualification: players with fewer than min_matches matches
+# in the last window_days days (0 = all time) are listed as unranked.
+# leaderboard:
+#   min_matches: 5
+#   window_days: 90
+
+# Leaderboard tiers: a player belongs to the highest tier whose min_rating
+# their rating reaches. Players below every tier have none.
+# tiers:
+#   - name: Gold
+#     min_rating: 1400
+#   - name: Silver
+#     min_rating: 1250
+#   - name: Bronze
+#     min_rating: 0
+
+# Inactivity decay: players who haven't played for after_days days lose
+# points_per_week rating points a week, down to the initial rating.
+# decay:
+#   after_days: 60
+#   points_per_week: 5
+
+# Match rules, enforced by "tennis match", "tennis verify match" and the
+# issue-to-PR workflow. Clients read them from the repository's copy.
+#   formats      match formats played: singles, doubles
+#   best_of      sets in a match: 1, 3 or 5
+#   scoring      allowed set scores: standard, short, match-tiebreak
+#   approvals    who must approve a result: all, any or none of the
+#                players other than the reporter
+#   season       first and last day matches may be played
+#   undo_window  how long a reporter can void a match with "tennis match
+#                undo" (default 15m)
+#   forfeit      how the rankings treat a no-show recorded with "tennis
+#                match forfeit": result default rates the default win as
+#                usual; result penalty rates nothing and takes penalty
+#                rating points off the absent side
+# rules:
+#   formats: [singles, doubles]
+#   best_of: 3
+#   scoring: [standard, match-tiebreak]
+#   approvals: all
+#   season:
+#     start: 2025-04-01
+#     end: 2025-10-31
+#   undo_window: 15m
+#   forfeit:
+#     result: penalty
+#     penalty: 25
+
+# Other repositories in the league, e.g. one per club: their matches count
+# towards rankings, stats and the Pages site alongside this repository's.
+# path reads a local checkout instead of downloading the repository.
+# repos:
+#   - repo: my-club/tennis-north
+#   - repo: my-club/tennis-south
+#     path: ../tennis-south
+
+# League admins: "tennis bot escalate" assigns them matches left disputed
+# or unapproved, and "tennis inbox --admin" lists those matches.
+# admins: [organiser_one, organiser_two]
+
+# Handicaps: the lower-rated side of a match starts every set with one
+# game for every games_per rating points between the sides, up to
+# max_games. Match files keep the result as played (sets) and with the
+# head start (handicap_sets); score picks which the rankings use: raw (the
+# default) or handicap. "tennis handicap" shows a pairing's head start.
+# handicap:
+#   games_per: 100
+#   max_games: 3
+#   score: handicap
+
+# Ranking points, shown beside ratings on the leaderboards: win points for
+# a league match, or rounds points for a knockout tournament match (final,
+# semifinal, quarterfinal, or round for earlier ones), times the multiplier
+# of the highest opponents band the beaten side's rating reached. A win's
+# points drop off window_days days later (default 364, 52 weeks); "tennis
+# rankings upcoming-drops" lists the points about to go. rank_by: points
+# ranks the leaderboards on points instead of ratings.
+# points:
+#   rank_by: points
+#   win: 10
+#   rounds:
+#     final: 100
+#     semifinal: 60
+#     quarterfinal: 35
+#     round: 20
+#   opponents:
+#     - min_rating: 1400
+#       multiplier: 2
+#     - min_rating: 1250
+#       multiplier: 1.5
+#   window_days: 365
+
+# Pseudonyms shown instead of handles on the Pages site and in exports
+# ("tennis standings export", "tennis tournament export"). Issues and match
+# files keep the real handles.
+# pseudonyms:
+#   player_one: Baseline Basher
+#   player_two: Drop Shot
+
+# Recurring jobs for "tennis serve", for clubs that run one long-lived
+# process instead of scheduled Actions. cron is minute, hour, day of month,
+# month and day of week (0 is Sunday); run is a tennis command, where
+# {today} and {today-7} stand for today's date and the date a week ago.
+# schedule:
+#   - name: sync
+#     cron: "*/30 * * * *"
+#     run: repos pull
+#   - name: expire
+#     cron: "0 3 * * *"
+#     run: bot expire
+#   - name: reminders
+#     cron: "0 9 * * 1"
+#     run: bot nudge
+#   - name: digest
+#     cron: "0 18 * * 0"
+#     run: rankings changelog --since {today-6} --out weekly-digest.md
+#   - name: publish
+#     cron: "30 18 * * 0"
+#     run: rankings publish
+`, defaultElo.K, defaultElo.InitialRating, defaultElo.Margin)
+	return b.String()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// assumeYes is --yes: go ahead with destructive and bulk changes without
+// asking.
+var assumeYes bool
+
+// errAborted is returned when the user declines a confirmation prompt.
+var errAborted = errors.New("aborted")
+
+// nonInteractive reports whether $NONINTERACTIVE asks for no prompts, as
+// in automation. Any value other than empty, 0 or false counts.
+func nonInteractive() bool {
+	switch strings.ToLower(os.Getenv("NONINTERACTIVE")) {
+	case "", "0", "false":
+		return false
+	}
+	return true
+}
+
+// confirm asks the user to confirm a destructive or bulk change, described
+// by the question. It doesn't ask on a dry run, under --yes or when
+// $NONINTERACTIVE is set. When stdin isn't a terminal there's no one to
+// ask, so it refuses rather than guess.
+func confirm(format string, args ...any) error {
+	if dryRun || assumeYes || nonInteractive() {
+		return nil
+	}
+	question := fmt.Sprintf(format, args...)
+	if fi, err := os.Stdin.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return invalidf("confirmation needed (%s) but stdin isn't a terminal; pass --yes or set NONINTERACTIVE=1", question)
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return nil
+	}
+	return errAborted
+}
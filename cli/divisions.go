@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DivisionRotation is one entry in divisions.yml, the record of every
+// "tennis divisions rotate": the period whose results decided it and the
+// players it moved.
+type DivisionRotation struct {
+	Date  string         `yaml:"date"`
+	From  string         `yaml:"from,omitempty"` // first day counted; empty for all matches before To
+	To    string         `yaml:"to"`             // last day counted
+	Moves []DivisionMove `yaml:"moves,omitempty"`
+}
+
+// DivisionMove is a player promoted or relegated by a rotation.
+type DivisionMove struct {
+	Player string `yaml:"player"`
+	From   int    `yaml:"from"`
+	To     int    `yaml:"to"`
+}
+
+func divisionsPath() string {
+	return filepath.Join(leagueDir(), "divisions.yml")
+}
+
+// loadDivisionRotations reads divisions.yml. A missing file means no
+// rotations yet.
+func loadDivisionRotations() ([]DivisionRotation, error) {
+	data, err := os.ReadFile(divisionsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rotations []DivisionRotation
+	if err := yaml.Unmarshal(data, &rotations); err != nil {
+		return nil, fmt.Errorf("invalid divisions.yml: %w", err)
+	}
+	return rotations, nil
+}
+
+// divisionMembers groups the roster's active players by division, in
+// division order (1 first), each sorted by handle. Players outside a
+// division are left out.
+func divisionMembers(r *Roster) ([]int, map[int][]string) {
+	members := make(map[int][]string)
+	for _, p := range r.Players {
+		if p.IsActive() && p.Division > 0 {
+			members[p.Division] = append(members[p.Division], p.Handle)
+		}
+	}
+	var divisions []int
+	for d, ps := range members {
+		sort.Strings(ps)
+		divisions = append(divisions, d)
+	}
+	sort.Ints(divisions)
+	return divisions, members
+}
+
+// divisionStandings ranks each division on the singles matches its
+// members played against each other between from and to (inclusive; an
+// empty from counts every match up to to), as box tables are ranked.
+func divisionStandings(divisions []int, members map[int][]string, matches []Match, from, to string) [][]BoxStanding {
+	var period []Match
+	for _, m := range matches {
+		if m.Date >= from && m.Date <= to {
+			period = append(period, m)
+		}
+	}
+	tables := make([][]BoxStanding, len(divisions))
+	for i, d := range divisions {
+		tables[i] = standingsTable(members[d], period)
+	}
+	return tables
+}
+
+// rotateDivisions promotes the top `moves` players of each division and
+// relegates the bottom `moves` to the division below, updating the roster
+// in place, and returns the moves made.
+func rotateDivisions(r *Roster, divisions []int, tables [][]BoxStanding, moves int) []DivisionMove {
+	var made []DivisionMove
+	for i, players := range swapAdjacent(tables, moves) {
+		for _, handle := range players {
+			p := r.Find(handle)
+			if p.Division != divisions[i] {
+				made = append(made, DivisionMove{Player: handle, From: p.Division, To: divisions[i]})
+				p.Division = divisions[i]
+			}
+		}
+	}
+	return made
+}
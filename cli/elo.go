@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Margin-of-victory weightings for eloParams.Margin.
+const (
+	marginNone  = "none"
+	marginSets  = "sets"
+	marginGames = "games"
+)
+
+// Rating modes for eloParams.Mode.
+const (
+	modeSets  = "sets"  // each set is one Elo event, won or lost
+	modeGames = "games" // each set is rated by the games each side won
+)
+
+// eloParams are the rating engine's parameters, set in the elo section of
+// .tennis.yml. The defaults match scripts/elo_utils.py.
+type eloParams struct {
+	K             float64 `yaml:"k,omitempty" json:"k"`
+	InitialRating float64 `yaml:"initial_rating,omitempty" json:"initial_rating"`
+	Margin        string  `yaml:"margin,omitempty" json:"margin"`
+	Formula       string  `yaml:"formula,omitempty" json:"formula,omitempty"` // custom rating formula file (see ratingFormula)
+	Mode          string  `yaml:"mode,omitempty" json:"mode,omitempty"`       // modeGames, or empty for modeSets
+}
+
+var defaultElo = eloParams{K: 32, InitialRating: 1200, Margin: marginNone}
+
+// elo is the league's effective Elo parameters, loaded from .tennis.yml
+// before each command runs (see loadLeagueSettings).
+var elo = defaultElo
+
+// importedSingles and importedDoubles are the provisional starting ratings
+// of players who joined with a rating from another league, loaded from
+// players.yml with the settings.
+var importedSingles, importedDoubles map[string]float64
+
+// withDefaults fills in unset parameters and checks the result.
+func (e eloParams) withDefaults() (eloParams, error) {
+	if e.K == 0 {
+		e.K = defaultElo.K
+	}
+	if e.InitialRating == 0 {
+		e.InitialRating = defaultElo.InitialRating
+	}
+	if e.Margin == "" {
+		e.Margin = defaultElo.Margin
+	}
+	if e.Mode == modeSets {
+		e.Mode = ""
+	}
+	switch {
+	case e.K < 0 || e.K > 100:
+		return e, fmt.Errorf("elo.k must be between 0 and 100, got %g", e.K)
+	case e.InitialRating < 0:
+		return e, fmt.Errorf("elo.initial_rating must be positive, got %g", e.InitialRating)
+	case e.Margin != marginNone && e.Margin != marginSets && e.Margin != marginGames:
+		return e, fmt.Errorf("elo.margin must be %s, %s or %s, got %q", marginNone, marginSets, marginGames, e.Margin)
+	case e.Mode != "" && e.Mode != modeGames:
+		return e, fmt.Errorf("elo.mode must be %s or %s, got %q", modeSets, modeGames, e.Mode)
+	case e.Mode == modeGames && e.Margin != marginNone:
+		return e, fmt.Errorf("elo.margin can't be used with elo.mode: %s, which already counts every game", modeGames)
+	case e.Mode == modeGames && e.Formula != "":
+		return e, fmt.Errorf("elo.formula can't be used with elo.mode: %s", modeGames)
+	}
+	return e, nil
+}
+
+func (e eloParams) String() string {
+	s := fmt.Sprintf("K=%g, initial rating %g, margin weighting %s", e.K, e.InitialRating, e.Margin)
+	if e.Formula != "" {
+		s += ", formula " + e.Formula
+	}
+	if e.Mode == modeGames {
+		s += ", rated per game"
+	}
+	return s
+}
+
+// setK returns the K-factor for one set of a match. With margin weighting
+// it is scaled by 1 + ln(margin): the games margin of the set, or the sets
+// margin of the match, so a 6-0 set (or a 2-0 match) moves ratings more
+// than a 7-5 set (or a 2-1 match). A margin of one leaves K unchanged.
+func (e eloParams) setK(m Match, set []int) float64 {
+	var margin int
+	switch e.Margin {
+	case marginGames:
+		margin = set[0] - set[1]
+	case marginSets:
+		var w1, w2 int
+		for _, s := range m.scoredSets() {
+			if len(s) == 2 && s[0] > s[1] {
+				w1++
+			} else if len(s) == 2 && s[1] > s[0] {
+				w2++
+			}
+		}
+		margin = w1 - w2
+	default:
+		return e.K
+	}
+	if margin < 0 {
+		margin = -margin
+	}
+	if margin <= 1 {
+		return e.K
+	}
+	return e.K * (1 + math.Log(float64(margin)))
+}
+
+// setChange returns the points the winner of a set gains and the loser
+// loses, given their ratings: K × (1 − expected score), or the league's
+// custom formula if it has one. A formula that fails to evaluate for a set
+// leaves the ratings unchanged.
+//
+// Rated per game, every game is an Elo event: the change is K × (games won
+// − expected score × games played), so a favourite who wins a set by less
+// than expected loses points.
+func setChange(m Match, set []int, rW, rL float64, doubles bool) float64 {
+	k := elo.setK(m, set)
+	won, lost := set[0], set[1]
+	if lost > won {
+		won, lost = lost, won
+	}
+	if elo.Mode == modeGames {
+		return k * (float64(won) - expectedScore(rW, rL)*float64(won+lost))
+	}
+	if customFormula == nil {
+		return k * (1 - expectedScore(rW, rL))
+	}
+	vars := map[string]float64{
+		"k": k, "expected": expectedScore(rW, rL), "winner_rating": rW, "loser_rating": rL,
+		"games_won": float64(won), "games_lost": float64(lost), "initial_rating": elo.InitialRating,
+	}
+	if doubles {
+		vars["doubles"] = 1
+	}
+	change, err := customFormula.eval(vars)
+	if err != nil {
+		return 0
+	}
+	return change
+}
+
+// expectedScore is the expected score of a player rated rA against rB.
+func expectedScore(rA, rB float64) float64 {
+	return 1 / (1 + math.Pow(10, (rB-rA)/400))
+}
+
+// rating returns a player's current rating, defaulting to the initial rating.
+func rating(ratings map[string]float64, player string) float64 {
+	if r, ok := ratings[player]; ok {
+		return r
+	}
+	return elo.InitialRating
+}
+
+// computeSinglesRatings replays singles matches in order and returns each
+// player's rating. Like the Python engine, every set is an independent Elo
+// event; tied or malformed sets are ignored.
+func computeSinglesRatings(matches []Match) map[string]float64 {
+	ratings := make(map[string]float64)
+	replaySingles(ratings, matches)
+	return ratings
+}
+
+// seedRatings starts players with an imported rating from it, unless
+// they are rated already.
+func seedRatings(ratings, seeds map[string]float64) {
+	for p, r := range seeds {
+		if _, ok := ratings[p]; !ok {
+			ratings[p] = r
+		}
+	}
+}
+
+// replaySingles applies singles matches to ratings in place. Players with
+// an imported rating start from it.
+func replaySingles(ratings map[string]float64, matches []Match) {
+	seedRatings(ratings, importedSingles)
+	for _, m := range matches {
+		if len(m.Players) != 2 || frozen(m) {
+			continue
+		}
+		p1, p2 := m.Players[0], m.Players[1]
+		for _, s := range m.scoredSets() {
+			if len(s) != 2 || s[0] == s[1] {
+				continue
+			}
+			winner, loser := p1, p2
+			if s[1] > s[0] {
+				winner, loser = p2, p1
+			}
+			rW, rL := rating(ratings, winner), rating(ratings, loser)
+			change := setChange(m, s, rW, rL, false)
+			ratings[winner] = rW + change
+			ratings[loser] = rL - change
+		}
+		chargeForfeit(m, ratings)
+	}
+}
+
+// computeDoublesRatings replays doubles matches and returns each player's
+// individual doubles rating. As in update_doubles_elo_ratings, both partners
+// move by the same amount, based on the teams' average ratings.
+func computeDoublesRatings(matches []Match) map[string]float64 {
+	ratings := make(map[string]float64)
+	replayDoubles(ratings, matches)
+	return ratings
+}
+
+// replayDoubles applies doubles matches to ratings in place. Players with
+// an imported rating start from it.
+func replayDoubles(ratings map[string]float64, matches []Match) {
+	seedRatings(ratings, importedDoubles)
+	for _, m := range matches {
+		if len(m.Team1) != 2 || len(m.Team2) != 2 || frozen(m) {
+			continue
+		}
+		for _, s := range m.scoredSets() {
+			if len(s) != 2 || s[0] == s[1] {
+				continue
+			}
+			winners, losers := m.Team1, m.Team2
+			if s[1] > s[0] {
+				winners, losers = m.Team2, m.Team1
+			}
+			rW := (rating(ratings, winners[0]) + rating(ratings, winners[1])) / 2
+			rL := (rating(ratings, losers[0]) + rating(ratings, losers[1])) / 2
+			change := setChange(m, s, rW, rL, true)
+			for _, p := range winners {
+				ratings[p] = rating(ratings, p) + change
+			}
+			for _, p := range losers {
+				ratings[p] = rating(ratings, p) - change
+			}
+		}
+		chargeForfeit(m, ratings)
+	}
+}
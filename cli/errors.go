@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// Exit codes. They are part of the CLI's interface (see "Exit Codes" in
+// the README), so scripts and workflows can branch on the kind of failure.
+const (
+	exitOK         = 0
+	exitFailure    = 1 // anything not covered below
+	exitValidation = 2 // bad flags, arguments or configuration
+	exitAuth       = 3 // no token, or the token was rejected or lacks access
+	exitNotFound   = 4 // the repository, issue, user or file doesn't exist
+	exitAPI        = 5 // any other GitHub API failure, including rate limits
+	exitTimeout    = 6 // --timeout ran out, or a GitHub API request timed out
+
+	exitInterrupted = 130 // stopped by Ctrl-C or SIGTERM, as shells report it
+)
+
+// errorFormat is --error-format: text, or json for one error object on
+// stderr.
+var errorFormat string
+
+// commandStarted is set once a command's flags and arguments have been
+// accepted, so earlier errors are known to be usage errors.
+var commandStarted bool
+
+// errNoToken is returned when a command needs a token and none was found.
+var errNoToken = errors.New("GitHub token required. Run `tennis auth login`, set GITHUB_TOKEN, run `gh auth login`, or use --token flag")
+
+// errTimedOut is the cause of a command's context ending at --timeout;
+// errInterrupted wraps the error of a command stopped by a signal.
+var (
+	errTimedOut    = errors.New("timed out")
+	errInterrupted = errors.New("interrupted")
+)
+
+// validationError is input the command can't use: a bad flag value,
+// argument or configuration file.
+type validationError struct{ err error }
+
+func (e *validationError) Error() string { return e.err.Error() }
+func (e *validationError) Unwrap() error { return e.err }
+
+// invalidf returns a validation error, which exits with exitValidation.
+func invalidf(format string, args ...any) error {
+	return &validationError{fmt.Errorf(format, args...)}
+}
+
+// cliError is the --error-format json form of an error.
+type cliError struct {
+	Type     string `json:"type"`
+	ExitCode int    `json:"exit_code"`
+	Message  string `json:"message"`
+	Status   int    `json:"status,omitempty"` // the GitHub API's HTTP status
+}
+
+// classifyError maps an error to its exit code and type.
+func classifyError(err error) (int, string) {
+	var invalid *validationError
+	switch {
+	case errors.As(err, &invalid) || !commandStarted:
+		return exitValidation, "validation"
+	case errors.Is(err, errInterrupted):
+		return exitInterrupted, "interrupted"
+	case errors.Is(err, errTimedOut) || errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout, "timeout"
+	case errors.Is(err, errNoToken) || githubapi.IsUnauthorized(err) || githubapi.IsForbidden(err):
+		return exitAuth, "auth"
+	case githubapi.IsNotFound(err):
+		return exitNotFound, "not_found"
+	}
+	var apiErr *githubapi.Error
+	if githubapi.StatusCode(err) != 0 || githubapi.IsRateLimited(err) || errors.As(err, &apiErr) {
+		return exitAPI, "api"
+	}
+	return exitFailure, "error"
+}
+
+// reportError prints err from cmd in the --error-format and returns the
+// exit code. Usage errors also print the command's usage as text.
+func reportError(cmd *cobra.Command, err error) int {
+	code, kind := classifyError(err)
+	if errorFormat == "json" {
+		json.NewEncoder(os.Stderr).Encode(map[string]cliError{"error": {
+			Type:     kind,
+			ExitCode: code,
+			Message:  err.Error(),
+			Status:   githubapi.StatusCode(err),
+		}})
+		return code
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if !commandStarted && cmd != nil {
+		fmt.Fprint(os.Stderr, cmd.UsageString())
+	}
+	return code
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// leagueAdmins are the league admins' handles, from .tennis.yml.
+var leagueAdmins []string
+
+// escalateMatch hands a match issue that has stalled to the league admins:
+// it's labelled needs-admin, assigned to them, and a comment says why. It
+// reports whether the issue was escalated; approved, recorded and already
+// escalated issues are left alone.
+func escalateMatch(ctx context.Context, client *github.Client, issue *github.Issue, days int) (bool, error) {
+	number := issue.GetNumber()
+	if hasLabel(issue, labelNames.NeedsAdmin) {
+		return false, nil
+	}
+	a, err := loadMatchApprovals(ctx, client, number)
+	if err != nil {
+		return false, err
+	}
+	if a.Complete() || (a.PullRequest != nil && a.PullRequest.MergedAt != nil) {
+		return false, nil
+	}
+	if len(a.Disputed()) == 0 && waitingDays(issue, a.Pending()) < days {
+		return false, nil // paused while a player was away
+	}
+
+	reason := fmt.Sprintf("still waiting on %s after %d days", strings.Join(a.Pending(), ", "), days)
+	if disputed := a.Disputed(); len(disputed) > 0 {
+		reason = fmt.Sprintf("disputed by %s for more than %d days", strings.Join(disputed, ", "), days)
+	}
+	fmt.Printf("🧑‍⚖️ #%d: %s\n", number, reason)
+	if dryRun {
+		return true, nil
+	}
+
+	if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, number, []string{labelNames.NeedsAdmin}); err != nil {
+		return false, fmt.Errorf("failed to label #%d: %w", number, err)
+	}
+	var mentions []string
+	for _, admin := range leagueAdmins {
+		mentions = append(mentions, "@"+admin)
+	}
+	if len(leagueAdmins) > 0 {
+		if _, _, err := client.Issues.AddAssignees(ctx, owner, repo, number, leagueAdmins); err != nil {
+			return false, fmt.Errorf("failed to assign #%d to the admins: %w", number, err)
+		}
+	}
+	comment := fmt.Sprintf("🧑‍⚖️ This match has been %s, so it has been escalated to the league admins.", reason)
+	if len(mentions) > 0 {
+		comment += fmt.Sprintf(" %s, please help the players settle the result.", strings.Join(mentions, " "))
+	}
+	if _, err := commentOnce(ctx, client, number, comment, "escalated"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sort"
+)
+
+// expectationRecord compares a player's wins with the wins their rating
+// going into each match predicted: the sum of their expected scores.
+// Drawn matches count as half a win.
+type expectationRecord struct {
+	Player   string
+	Played   int
+	Wins     float64
+	Expected float64
+}
+
+// Above is the player's wins above expectation, negative if they won
+// fewer matches than their ratings predicted.
+func (r expectationRecord) Above() float64 {
+	return r.Wins - r.Expected
+}
+
+// winsAboveExpectation replays matches from the imported ratings and
+// tallies every player's wins and expected wins in the matches dated from
+// from to to (either may be "" for no limit), most above expectation
+// first. Earlier matches still count towards the ratings going in; a
+// penalized forfeit isn't a result to expect.
+func winsAboveExpectation(matches []Match, from, to string) []expectationRecord {
+	singles, doubles := make(map[string]float64), make(map[string]float64)
+	seedRatings(singles, importedSingles)
+	seedRatings(doubles, importedDoubles)
+
+	byPlayer := make(map[string]*expectationRecord)
+	for _, m := range matches {
+		ratings, side1, side2 := singles, m.Team1, m.Team2
+		if m.IsDoubles() {
+			ratings = doubles
+		} else if len(m.Players) == 2 {
+			side1, side2 = m.Players[:1], m.Players[1:]
+		}
+		if len(side1) > 0 && len(side2) > 0 && len(m.scoredSets()) > 0 && m.Date >= from && (to == "" || m.Date <= to) {
+			r1, r2 := sideRating(ratings, side1), sideRating(ratings, side2)
+			won := map[int]float64{0: 0.5, 1: 1, 2: 0}[matchWinner(m)]
+			for i, side := range [][]string{side1, side2} {
+				expected, actual := expectedScore(r1, r2), won
+				if i == 1 {
+					expected, actual = 1-expected, 1-won
+				}
+				for _, p := range side {
+					r := byPlayer[p]
+					if r == nil {
+						r = &expectationRecord{Player: p}
+						byPlayer[p] = r
+					}
+					r.Played++
+					r.Wins += actual
+					r.Expected += expected
+				}
+			}
+		}
+		rateMatch(m, ratings)
+	}
+
+	var out []expectationRecord
+	for _, r := range byPlayer {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Above() != out[j].Above() {
+			return out[i].Above() > out[j].Above()
+		}
+		return out[i].Player < out[j].Player
+	})
+	return out
+}
+
+// expectationOf returns player's record from records, if they have one.
+func expectationOf(records []expectationRecord, player string) (expectationRecord, bool) {
+	for _, r := range records {
+		if r.Player == player {
+			return r, true
+		}
+	}
+	return expectationRecord{}, false
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/stonehenge-collective/tennis/league"
+)
+
+// fingerprintRegex finds the fingerprint embedded in a match issue body.
+var fingerprintRegex = regexp.MustCompile(`<!-- tennis:match:([0-9a-f]{16}) -->`)
+
+// matchFingerprint identifies a match result by its players, date and
+// sets, so the same result is only ever opened as one issue. Doubles
+// partners are sorted; the sides stay in order, winner first.
+func matchFingerprint(m Match) string {
+	side := func(players []string) string {
+		var handles []string
+		for _, p := range players {
+			handles = append(handles, normalizePlayer(p))
+		}
+		if m.IsDoubles() {
+			sort.Strings(handles)
+		}
+		return strings.Join(handles, "&")
+	}
+	var sets []string
+	for _, s := range m.Sets {
+		games := make([]string, len(s))
+		for i, g := range s {
+			games[i] = fmt.Sprint(g)
+		}
+		sets = append(sets, strings.Join(games, "-"))
+	}
+	key := "singles|" + side(m.Players)
+	if m.IsDoubles() {
+		key = "doubles|" + side(m.Team1) + "|" + side(m.Team2)
+	}
+	sum := sha256.Sum256([]byte(key + "|" + m.Date + "|" + strings.Join(sets, ",")))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// issueFingerprint returns the fingerprint embedded in a match issue, or
+// for an issue without one (opened from the issue form, or rewritten by
+// "tennis repair") the fingerprint of the result it reports.
+func issueFingerprint(issue *github.Issue) string {
+	if g := fingerprintRegex.FindStringSubmatch(issue.GetBody()); g != nil {
+		return g[1]
+	}
+	kind, _, ok := matchIssueKind(issue)
+	if !ok || kind == "conflict" {
+		return ""
+	}
+	return matchFingerprint(parseMatchIssue(kind, issue.GetBody()).Match())
+}
+
+// matchID is the short form of a result's fingerprint printed as the
+// match's ID, e.g. 3f9a0c12. Unlike its issue number it stays the same
+// when issues are moved to another repository, and "tennis permalink"
+// takes it (or any longer prefix of the fingerprint) to find the issue.
+func matchID(fingerprint string) string {
+	return fingerprint[:min(len(fingerprint), 8)]
+}
+
+// printMatchID prints a match issue's ID.
+func printMatchID(issue *github.Issue) {
+	if fp := issueFingerprint(issue); fp != "" {
+		fmt.Printf("Match ID: %s\n", matchID(fp))
+	}
+}
+
+// openMatchIssueOnce opens a match issue with the result's fingerprint
+// embedded at the top of body, unless an issue for the same result already
+// exists, in which case that issue is returned with created false. Issues
+// closed as not planned (expired or rejected) don't count, so a result can
+// be reported again after one. Checking first makes every creation path
+// (the match commands, imports, reruns after a crash or a failed request)
+// create each match at most once. Under --dry-run the issue is printed
+// and nil is returned.
+func openMatchIssueOnce(ctx context.Context, kind, title, body, label string) (issue *github.Issue, created bool, err error) {
+	fp := matchFingerprint(parseMatchIssue(kind, body).Match())
+	body = commentMarker("match:"+fp) + "\n\n" + body
+	if dryRun {
+		printDryRun(title, body, []string{label})
+		return nil, false, nil
+	}
+
+	client := getGitHubClient()
+	return league.NewMatchIssues(client.Issues, owner, repo).OpenOnce(ctx, &github.IssueRequest{
+		Title:  &title,
+		Body:   &body,
+		Labels: &[]string{label},
+	}, fp, issueFingerprint)
+}
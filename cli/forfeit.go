@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Results for forfeitRules.Result.
+const (
+	forfeitDefault = "default" // the side that turned up wins by the default score, rated as usual
+	forfeitPenalty = "penalty" // the result isn't rated; the absent side loses Penalty rating points
+)
+
+// forfeitRules say how the rankings treat a forfeit, set in the
+// rules.forfeit section of .tennis.yml. A forfeit is a no-show: unlike a
+// walkover, where a player withdraws in advance, the absent side is
+// charged with it and it counts against their reliability.
+type forfeitRules struct {
+	Result  string  `yaml:"result,omitempty"`  // forfeitDefault (the default) or forfeitPenalty
+	Penalty float64 `yaml:"penalty,omitempty"` // rating points each absent player loses under forfeitPenalty
+}
+
+// check reports the first invalid forfeit rule.
+func (f forfeitRules) check() error {
+	switch f.Result {
+	case "", forfeitDefault:
+		if f.Penalty != 0 {
+			return fmt.Errorf("rules.forfeit.penalty needs rules.forfeit.result: %s", forfeitPenalty)
+		}
+	case forfeitPenalty:
+		if f.Penalty < 0 {
+			return fmt.Errorf("rules.forfeit.penalty can't be negative")
+		}
+	default:
+		return fmt.Errorf("rules.forfeit.result must be %s or %s, got %q", forfeitDefault, forfeitPenalty, f.Result)
+	}
+	return nil
+}
+
+// penalized reports whether forfeits cost the absent side a penalty in
+// place of a rated result.
+func (f forfeitRules) penalized() bool {
+	return f.Result == forfeitPenalty
+}
+
+// forfeited reports whether one side of the match didn't turn up.
+func (m Match) forfeited() bool {
+	return len(m.Forfeit) > 0
+}
+
+// chargeForfeit takes the league's penalty off the ratings of the players
+// who forfeited m, if forfeits are penalized.
+func chargeForfeit(m Match, ratings map[string]float64) {
+	if !m.forfeited() || !rules.Forfeit.penalized() {
+		return
+	}
+	for _, p := range m.Forfeit {
+		ratings[p] = rating(ratings, p) - rules.Forfeit.Penalty
+	}
+}
+
+// forfeitSets returns the default score of a forfeit under the league's
+// rules: the sets needed to win, each 6-0, or 4-0 if the league plays
+// short sets only.
+func forfeitSets(r leagueRules) [][]int {
+	games := 6
+	if len(r.Scoring) > 0 && !containsString(r.Scoring, scoringStandard) && containsString(r.Scoring, scoringShort) {
+		games = 4
+	}
+	bestOf := r.BestOf
+	if bestOf == 0 {
+		bestOf = 3
+	}
+	var sets [][]int
+	for i := 0; i < bestOf/2+1; i++ {
+		sets = append(sets, []int{games, 0})
+	}
+	return sets
+}
+
+// withForfeit appends the forfeit section naming the players who didn't
+// turn up, if any. The issue forms don't ask for it; "tennis match
+// forfeit" adds it.
+func withForfeit(body string, absent []string) string {
+	if len(absent) == 0 {
+		return body
+	}
+	return body + "\n\n### Forfeit (no-show)\n\n" + strings.Join(absent, ", ")
+}
+
+// reliability counts the matches player was due to play and how many of
+// them they forfeited.
+func reliability(matches []Match, player string) (due, noShows int) {
+	for _, m := range matches {
+		if !containsString(matchPlayers(m), player) {
+			continue
+		}
+		due++
+		if containsString(m.Forfeit, player) {
+			noShows++
+		}
+	}
+	return due, noShows
+}
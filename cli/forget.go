@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// forgetPatterns are the league's data files, relative to the checkout,
+// that "tennis player forget" scrubs a player's handle from. players.yml
+// and aliases.yml are edited separately, and the Pages site is rebuilt
+// from these files.
+var forgetPatterns = []string{
+	".tennis.yml",
+	"merges.yml",
+	"divisions.yml",
+	"singles-matches/*.yml",
+	"doubles-matches/*.yml",
+	"boxes/*.yml",
+	"tournaments/*.yml",
+	"tournaments/swiss/*.yml",
+	"teams/*.yml",
+	"rankings-snapshots/*.json",
+	"rankings-snapshots/*.calc.jsonl",
+	".tennis/rankings-snapshot.json",
+}
+
+// forgetPlaceholder returns the anonymous handle a forgotten player's
+// matches are recorded under: the first former-player-N that isn't taken.
+func forgetPlaceholder(taken func(handle string) bool) string {
+	for n := 1; ; n++ {
+		if h := fmt.Sprintf("former-player-%d", n); !taken(h) {
+			return h
+		}
+	}
+}
+
+// scrubber replaces a player's handles in data files with a placeholder.
+// A value that is one of the handles is replaced outright; @mentions of
+// them in longer text, such as a merge reason, are replaced within it.
+type scrubber struct {
+	handles     map[string]bool
+	placeholder string
+	mention     *regexp.Regexp
+}
+
+func newScrubber(handles []string, placeholder string) *scrubber {
+	s := &scrubber{handles: make(map[string]bool), placeholder: placeholder}
+	var quoted []string
+	for _, h := range handles {
+		s.handles[h] = true
+		quoted = append(quoted, regexp.QuoteMeta(h))
+	}
+	s.mention = regexp.MustCompile(`(?i)@(` + strings.Join(quoted, "|") + `)([^A-Za-z0-9-]|$)`)
+	return s
+}
+
+// String returns v with the handles replaced, and whether it changed.
+func (s *scrubber) String(v string) (string, bool) {
+	if s.handles[normalizePlayer(v)] {
+		return s.placeholder, true
+	}
+	out := s.mention.ReplaceAllString(v, s.placeholder+"${2}")
+	return out, out != v
+}
+
+func (s *scrubber) yamlNode(n *yaml.Node) bool {
+	changed := false
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!str" {
+		n.Value, changed = s.String(n.Value)
+	}
+	for _, c := range n.Content {
+		if s.yamlNode(c) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (s *scrubber) jsonValue(v any) (any, bool) {
+	switch v := v.(type) {
+	case string:
+		return s.String(v)
+	case []any:
+		changed := false
+		for i := range v {
+			var c bool
+			if v[i], c = s.jsonValue(v[i]); c {
+				changed = true
+			}
+		}
+		return v, changed
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		changed := false
+		for k, val := range v {
+			k2, c1 := s.String(k)
+			val2, c2 := s.jsonValue(val)
+			out[k2] = val2
+			changed = changed || c1 || c2
+		}
+		return out, changed
+	}
+	return v, false
+}
+
+// File scrubs the YAML, JSON or JSON Lines file at path, reporting whether
+// it has anything to scrub. The file is only rewritten if it has and write
+// is set.
+func (s *scrubber) File(path string, write bool) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var out []byte
+	switch filepath.Ext(path) {
+	case ".jsonl":
+		changed := false
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			// A calculation log for a day before any match is empty.
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			dec := json.NewDecoder(bytes.NewReader(line))
+			dec.UseNumber()
+			var v any
+			if err := dec.Decode(&v); err != nil {
+				return false, fmt.Errorf("invalid %s: %w", path, err)
+			}
+			v, c := s.jsonValue(v)
+			changed = changed || c
+			line, err := json.Marshal(v)
+			if err != nil {
+				return false, err
+			}
+			out = append(append(out, line...), '\n')
+		}
+		if !changed {
+			return false, nil
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return false, fmt.Errorf("invalid %s: %w", path, err)
+		}
+		v, changed := s.jsonValue(v)
+		if !changed {
+			return false, nil
+		}
+		if out, err = json.MarshalIndent(v, "", "  "); err != nil {
+			return false, err
+		}
+		out = append(out, '\n')
+	default:
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return false, fmt.Errorf("invalid %s: %w", path, err)
+		}
+		if !s.yamlNode(&doc) {
+			return false, nil
+		}
+		if out, err = marshalYAML(&doc); err != nil {
+			return false, err
+		}
+	}
+	if !write {
+		return true, nil
+	}
+	return true, os.WriteFile(path, out, 0o644)
+}
+
+// forgetFiles returns the data files of the league checkout to scrub,
+// sorted.
+func forgetFiles() ([]string, error) {
+	var files []string
+	for _, pattern := range forgetPatterns {
+		matches, err := filepath.Glob(filepath.Join(leagueDir(), pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rankingsFreeze holds ratings still, e.g. during playoffs: matches dated
+// from From on are recorded and count in players' records, but don't move
+// ratings or earn ranking points until the freeze lifts after Until, when
+// they're replayed in order. It's stored in rankings-freeze.yml in the
+// league checkout, where the Pages site's scripts read it too.
+type rankingsFreeze struct {
+	From   string `yaml:"from"`
+	Until  string `yaml:"until"`
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// freeze is the league's rankings freeze, loaded with elo. The zero value
+// is no freeze.
+var freeze rankingsFreeze
+
+func freezePath() string {
+	return filepath.Join(leagueDir(), "rankings-freeze.yml")
+}
+
+// loadFreeze reads rankings-freeze.yml, if there is one.
+func loadFreeze() (rankingsFreeze, error) {
+	var f rankingsFreeze
+	data, err := os.ReadFile(freezePath())
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("invalid rankings-freeze.yml: %w", err)
+	}
+	if !isValidDate(f.From) || !isValidDate(f.Until) {
+		return f, fmt.Errorf("invalid rankings-freeze.yml: from and until must be YYYY-MM-DD")
+	}
+	if f.Until < f.From {
+		return f, fmt.Errorf("invalid rankings-freeze.yml: until %s is before from %s", f.Until, f.From)
+	}
+	return f, nil
+}
+
+// Active reports whether the freeze is holding ratings on day.
+func (f rankingsFreeze) Active(day string) bool {
+	return f.From != "" && f.From <= day && day <= f.Until
+}
+
+// frozen reports whether m is held back from the ratings today.
+func frozen(m Match) bool {
+	return freeze.Active(time.Now().Format("2006-01-02")) && m.Date >= freeze.From
+}
+
+// heldBack counts the matches the freeze is holding back.
+func heldBack(matches []Match) int {
+	n := 0
+	for _, m := range matches {
+		if frozen(m) {
+			n++
+		}
+	}
+	return n
+}
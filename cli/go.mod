@@ -6,6 +6,7 @@ require (
 	github.com/google/go-github/v67 v67.0.0
 	github.com/spf13/cobra v1.10.1
 	golang.org/x/oauth2 v0.31.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
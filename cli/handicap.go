@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	scoreRaw      = "raw"      // rank on the games played
+	scoreHandicap = "handicap" // rank on the games played plus head starts
+)
+
+// handicapRules give the lower-rated side of a match a head start of
+// games in every set, set in the handicap section of .tennis.yml: one game
+// for every GamesPer rating points between the sides, up to MaxGames. The
+// zero value disables handicaps.
+type handicapRules struct {
+	GamesPer float64 `yaml:"games_per,omitempty" json:"games_per"`
+	MaxGames int     `yaml:"max_games,omitempty" json:"max_games"`
+	Score    string  `yaml:"score,omitempty" json:"score,omitempty"` // scoreRaw (the default) or scoreHandicap
+}
+
+// handicap is the league's handicap rules, loaded with elo.
+var handicap handicapRules
+
+func (h handicapRules) check() error {
+	switch {
+	case h.GamesPer < 0:
+		return fmt.Errorf("handicap.games_per can't be negative")
+	case h.MaxGames < 0:
+		return fmt.Errorf("handicap.max_games can't be negative")
+	case h.Score != "" && h.Score != scoreRaw && h.Score != scoreHandicap:
+		return fmt.Errorf("handicap.score must be %s or %s, got %q", scoreRaw, scoreHandicap, h.Score)
+	case h.Score == scoreHandicap && h.GamesPer == 0:
+		return fmt.Errorf("handicap.score: %s needs handicap.games_per", scoreHandicap)
+	}
+	return nil
+}
+
+// Enabled reports whether matches are played with head starts.
+func (h handicapRules) Enabled() bool {
+	return h.GamesPer > 0
+}
+
+func (h handicapRules) String() string {
+	s := fmt.Sprintf("1 game per %g rating points", h.GamesPer)
+	if h.MaxGames > 0 {
+		s += fmt.Sprintf(", at most %d", h.MaxGames)
+	}
+	if h.Score == scoreHandicap {
+		return s + ", ranked on handicap results"
+	}
+	return s + ", ranked on raw results"
+}
+
+// matchHandicap is the head start a match was played with, recorded in its
+// match file: the side that received it (1 or 2, as in the sets) and the
+// games it started every set with.
+type matchHandicap struct {
+	Side      int     `yaml:"side" json:"side"`
+	Games     int     `yaml:"games" json:"games"`
+	RatingGap float64 `yaml:"rating_gap" json:"rating_gap"`
+}
+
+// headStart returns the side whose rating is lower and the games it starts
+// each set with, or 0 games if the sides are too close or handicaps are
+// off.
+func (h handicapRules) headStart(r1, r2 float64) (side, games int) {
+	if !h.Enabled() {
+		return 0, 0
+	}
+	side = 1
+	if r2 < r1 {
+		side = 2
+	}
+	games = int(math.Abs(r1-r2) / h.GamesPer)
+	if h.MaxGames > 0 && games > h.MaxGames {
+		games = h.MaxGames
+	}
+	return side, games
+}
+
+// applyHandicap records the head start the lower-rated side of m gets on
+// ratings, and the sets with it added: m.Sets stays the raw result, as
+// played, and m.HandicapSets is the handicap result.
+func applyHandicap(m *Match, ratings map[string]float64) {
+	side1, side2 := m.Team1, m.Team2
+	if !m.IsDoubles() {
+		if len(m.Players) != 2 {
+			return
+		}
+		side1, side2 = m.Players[:1], m.Players[1:]
+	}
+	r1, r2 := sideRating(ratings, side1), sideRating(ratings, side2)
+	side, games := handicap.headStart(r1, r2)
+	if games == 0 {
+		return
+	}
+	m.Handicap = &matchHandicap{Side: side, Games: games, RatingGap: math.Round(math.Abs(r1-r2)*10) / 10}
+	m.HandicapSets = nil
+	for _, s := range m.Sets {
+		adjusted := append([]int{}, s...)
+		if len(s) == 2 {
+			adjusted[side-1] += games
+		}
+		m.HandicapSets = append(m.HandicapSets, adjusted)
+	}
+}
+
+// scoredSets returns the sets the rankings engine scores: none for a
+// forfeit if the league penalizes forfeits, the handicap result if the
+// league ranks on handicap results and the match has one, else the raw
+// result.
+func (m Match) scoredSets() [][]int {
+	if m.forfeited() && rules.Forfeit.penalized() {
+		return nil
+	}
+	if handicap.Score == scoreHandicap && m.HandicapSets != nil {
+		return m.HandicapSets
+	}
+	return m.Sets
+}
@@ -0,0 +1,147 @@
+package githubapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cache keeps the responses to GET requests in memory and in dir. Within
+// ttl a response is reused without asking GitHub; after that it is
+// revalidated with If-None-Match. GitHub doesn't count a 304 Not Modified
+// against the rate limit, so rereading unchanged data is free either way.
+type cache struct {
+	base http.RoundTripper
+	dir  string
+	ttl  time.Duration
+}
+
+type cachedResponse struct {
+	ETag   string      `json:"etag"`
+	Stored time.Time   `json:"stored"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// memory holds the responses read or stored in this process, by cache file
+// path, so clients made by separate New calls share them.
+var memory sync.Map
+
+func (c *cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return c.base.RoundTrip(req)
+	}
+	path := filepath.Join(c.dir, cacheKey(req))
+	cached := loadCached(path)
+	if cached != nil && c.ttl > 0 && time.Since(cached.Stored) < c.ttl {
+		return cached.response(req, nil), nil
+	}
+	if cached != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	resp, err := c.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		fresh := *cached
+		fresh.Stored = time.Now()
+		storeCached(path, &fresh)
+		return fresh.response(req, resp.Header), nil
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	storeCached(path, &cachedResponse{ETag: resp.Header.Get("ETag"), Stored: time.Now(), Header: resp.Header, Body: body})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// response rebuilds the cached response. Its rate-limit headers are out of
+// date, so they're replaced with those of the revalidation (live), or
+// dropped when nothing was sent.
+func (c *cachedResponse) response(req *http.Request, live http.Header) *http.Response {
+	header := make(http.Header)
+	for k, v := range c.Header {
+		if !strings.HasPrefix(k, "X-Ratelimit-") {
+			header[k] = v
+		}
+	}
+	for k, v := range live {
+		if strings.HasPrefix(k, "X-Ratelimit-") {
+			header[k] = v
+		}
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey names a request's cache file after its URL and the media type
+// it asks for, which changes the response's shape.
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + "\n" + req.Header.Get("Accept")))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// loadCached returns the cached response at path, from memory or disk, or
+// nil. A cache file that can't be read is treated as missing.
+func loadCached(path string) *cachedResponse {
+	if c, ok := memory.Load(path); ok {
+		return c.(*cachedResponse)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var c cachedResponse
+	if json.Unmarshal(data, &c) != nil || c.ETag == "" {
+		return nil
+	}
+	memory.Store(path, &c)
+	return &c
+}
+
+// storeCached saves a response in memory and, best effort, on disk: a
+// failed write only costs a fresh request next time.
+func storeCached(path string, c *cachedResponse) {
+	memory.Store(path, c)
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0o700) != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if os.WriteFile(tmp, data, 0o600) != nil {
+		return
+	}
+	os.Rename(tmp, path)
+}
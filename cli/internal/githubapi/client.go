@@ -0,0 +1,101 @@
+// Package githubapi is the CLI's layer over go-github: one place that
+// decides how the tennis CLI talks to the GitHub API.
+//
+// Every client made by New retries transient failures, waits out rate
+// limits and bounds each request with a timeout, all in its transport, so
+// commands get the same behavior from any go-github call; with a CacheDir
+// it also caches GET responses, with a Throttle it paces bulk writes, and
+// with a Trace it logs each request. Record and Replay save and serve
+// fixtures for offline development. ListAll walks
+// paginated endpoints, and Wrap and the Is* predicates classify errors.
+package githubapi
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"golang.org/x/oauth2"
+)
+
+// Options tunes a client. Zero values use the defaults.
+type Options struct {
+	// Timeout bounds a single request attempt, including reading the
+	// response body. Waiting out a rate limit doesn't count against it.
+	Timeout time.Duration
+
+	// Retries is how many times a request is retried after a transient
+	// failure (a 5xx on an idempotent request, a network error or a rate
+	// limit).
+	Retries int
+
+	// Reserve is how many core API requests are left in reserve: once the
+	// remaining quota drops below it, requests wait for the window to reset
+	// rather than running out part-way through a bulk change. A negative
+	// Reserve keeps none.
+	Reserve int
+
+	// CacheDir, if set, keeps the responses to GET requests there and
+	// revalidates them with their ETag, so repeated reads of unchanged data
+	// don't use up the rate limit. Only share it between clients that can
+	// see the same data.
+	CacheDir string
+
+	// CacheTTL is how long a cached response is reused without asking
+	// GitHub at all. Zero always revalidates, which suits commands that
+	// read back what they have just changed.
+	CacheTTL time.Duration
+
+	// Throttle, if set, paces requests to stay under GitHub's secondary
+	// rate limits. Bulk commands share one between their clients.
+	Throttle *Throttle
+
+	// Trace, if set, gets a line for every request sent over the network
+	// (responses served from the cache without asking GitHub don't go
+	// out), with secrets redacted.
+	Trace io.Writer
+
+	// Record, if set, is a directory every exchange with GitHub is saved
+	// to as a JSON fixture; Replay, if set, is one whose fixtures answer
+	// requests instead of GitHub, so commands run without a token or
+	// network access. Responses aren't cached while recording or
+	// replaying, so the fixtures cover every request.
+	Record string
+	Replay string
+}
+
+// Defaults used by New for unset Options.
+const (
+	DefaultTimeout = 30 * time.Second
+	DefaultRetries = 3
+	DefaultReserve = 50
+)
+
+// New returns a go-github client authenticated with token, or making
+// unauthenticated requests if it is empty, whose requests go through the
+// retrying, rate-limit aware transport.
+func New(token string, opts Options) *github.Client {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Retries == 0 {
+		opts.Retries = DefaultRetries
+	}
+	if opts.Reserve == 0 {
+		opts.Reserve = DefaultReserve
+	}
+
+	network := Network(opts)
+	base := network
+	if token != "" {
+		base = &oauth2.Transport{
+			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}),
+			Base:   network,
+		}
+	}
+	if opts.CacheDir != "" && opts.Record == "" && opts.Replay == "" {
+		base = &cache{base: base, dir: opts.CacheDir, ttl: opts.CacheTTL}
+	}
+	return github.NewClient(&http.Client{Transport: &transport{base: base, opts: opts}})
+}
@@ -0,0 +1,77 @@
+package githubapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// Error is a failed GitHub API operation, with the HTTP status when the
+// API answered.
+type Error struct {
+	Op         string // what was being done, e.g. "list comments on #12"
+	StatusCode int    // 0 if no response was received
+	Err        error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed to %s: %v", e.Op, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Wrap records op against a go-github error. It returns nil for a nil err
+// and leaves an existing *Error alone.
+func Wrap(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return &Error{Op: op, StatusCode: StatusCode(err), Err: err}
+}
+
+// StatusCode returns the HTTP status behind err, or 0 if there was none.
+func StatusCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode != 0 {
+		return e.StatusCode
+	}
+	var rate *github.RateLimitError
+	if errors.As(err, &rate) && rate.Response != nil {
+		return rate.Response.StatusCode
+	}
+	var abuse *github.AbuseRateLimitError
+	if errors.As(err, &abuse) && abuse.Response != nil {
+		return abuse.Response.StatusCode
+	}
+	var resp *github.ErrorResponse
+	if errors.As(err, &resp) && resp.Response != nil {
+		return resp.Response.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is a 404, which GitHub also returns for
+// things the token isn't allowed to see.
+func IsNotFound(err error) bool { return StatusCode(err) == http.StatusNotFound }
+
+// IsUnauthorized reports whether err means the token was rejected.
+func IsUnauthorized(err error) bool { return StatusCode(err) == http.StatusUnauthorized }
+
+// IsForbidden reports whether err means the token lacks permission.
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden && !IsRateLimited(err)
+}
+
+// IsRateLimited reports whether err is a primary or secondary rate limit
+// that outlasted the transport's retries.
+func IsRateLimited(err error) bool {
+	var rate *github.RateLimitError
+	var abuse *github.AbuseRateLimitError
+	return errors.As(err, &rate) || errors.As(err, &abuse)
+}
@@ -0,0 +1,199 @@
+package githubapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// fixture is one recorded exchange with GitHub, saved as JSON so it can be
+// read and edited by hand.
+type fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        string      `json:"body,omitempty"`
+	BodyBase64  []byte      `json:"body_base64,omitempty"` // a body that isn't UTF-8 text
+}
+
+// NotRecordedError is what a replaying client returns for a request it has
+// no fixture for. It's never retried.
+type NotRecordedError struct {
+	Method, URL, Dir string
+}
+
+func (e *NotRecordedError) Error() string {
+	return fmt.Sprintf("no recorded response for %s %s in %s (record one with --record)", e.Method, e.URL, e.Dir)
+}
+
+// fixtureFiles names the fixtures of a recording: each request is keyed by
+// its method, URL (secrets redacted) and body, and numbered in the order
+// it was made, so a request made twice (e.g. a listing before and after a
+// change) can get different responses.
+type fixtureFiles struct {
+	dir  string
+	mu   sync.Mutex
+	seen map[string]int
+}
+
+// recordings holds the fixtureFiles of each directory, so every client a
+// command makes numbers its exchanges in the same sequence.
+var recordings sync.Map
+
+func fixturesIn(dir string) *fixtureFiles {
+	f, _ := recordings.LoadOrStore(dir, &fixtureFiles{dir: dir})
+	return f.(*fixtureFiles)
+}
+
+// next returns the key of req and the number of this exchange among
+// those with the same key, and req's body, which is read and put back.
+func (f *fixtureFiles) next(req *http.Request) (string, int, []byte, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return "", 0, nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	sum := sha256.Sum256([]byte(req.Method + "\n" + redactURL(req.URL) + "\n" + string(body)))
+	key := strings.ToLower(req.Method) + "-" + hex.EncodeToString(sum[:])[:12]
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.seen == nil {
+		f.seen = make(map[string]int)
+	}
+	f.seen[key]++
+	return key, f.seen[key], body, nil
+}
+
+// path is the file of a key's nth exchange.
+func (f *fixtureFiles) path(key string, n int) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%s.%d.json", key, n))
+}
+
+// recorder passes requests on to base and saves every exchange as a
+// fixture in dir.
+type recorder struct {
+	base  http.RoundTripper
+	files *fixtureFiles
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, n, reqBody, err := r.files.next(req)
+	if err != nil {
+		return nil, err
+	}
+	path := r.files.path(key, n)
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	fx := fixture{Method: req.Method, URL: redactURL(req.URL), RequestBody: string(reqBody), Status: resp.StatusCode, Header: resp.Header.Clone()}
+	// Redirects to downloads carry a short-lived token.
+	if loc, perr := url.Parse(fx.Header.Get("Location")); perr == nil && loc.String() != "" {
+		fx.Header.Set("Location", redactURL(loc))
+	}
+	if utf8.Valid(body) {
+		fx.Body = string(body)
+	} else {
+		fx.BodyBase64 = body
+	}
+	data, err := json.MarshalIndent(fx, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(r.files.dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to record %s %s: %w", req.Method, req.URL, err)
+	}
+	return resp, nil
+}
+
+// replayer answers requests from the fixtures in dir and never touches the
+// network. Once a request's numbered fixtures run out, the last is served
+// again.
+type replayer struct {
+	files *fixtureFiles
+}
+
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, n, _, err := r.files.next(req)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	for ; n >= 1; n-- {
+		if data, err = os.ReadFile(r.files.path(key, n)); !os.IsNotExist(err) {
+			break
+		}
+	}
+	if n < 1 {
+		return nil, &NotRecordedError{Method: req.Method, URL: redactURL(req.URL), Dir: r.files.dir}
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fx fixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", r.files.path(key, n), err)
+	}
+	body := fx.BodyBase64
+	if body == nil {
+		body = []byte(fx.Body)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fx.Status, http.StatusText(fx.Status)),
+		StatusCode:    fx.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fx.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Network returns the transport a client's requests finally go through:
+// the network, or with Options.Replay the fixtures recorded there. With
+// Options.Record every exchange is saved as a fixture, and with
+// Options.Trace every request is logged. New builds on it; use it too for
+// plain HTTP clients following links the API hands out (archives, release
+// assets).
+func Network(opts Options) http.RoundTripper {
+	var network http.RoundTripper = http.DefaultTransport
+	switch {
+	case opts.Replay != "":
+		network = &replayer{files: fixturesIn(opts.Replay)}
+	case opts.Record != "":
+		network = &recorder{base: network, files: fixturesIn(opts.Record)}
+	}
+	if opts.Trace != nil {
+		network = newTracer(network, opts.Trace)
+	}
+	return network
+}
@@ -0,0 +1,95 @@
+package githubapi
+
+import (
+	"context"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// PageSize is the page size ListAll requests, GitHub's maximum.
+const PageSize = 100
+
+// ListAll calls a paginated list endpoint until the last page and returns
+// every item in order. list is passed the page to fetch, e.g.
+//
+//	comments, err := githubapi.ListAll(ctx, "list comments", func(ctx context.Context, page github.ListOptions) ([]*github.IssueComment, *github.Response, error) {
+//		return client.Issues.ListComments(ctx, owner, repo, n, &github.IssueListCommentsOptions{ListOptions: page})
+//	})
+//
+// When the first response says how many pages there are, the rest are
+// fetched concurrently by up to Workers requests at a time; otherwise
+// pages are followed one by one. list must therefore not share mutable
+// options between calls, and should use the ctx it's given, which is
+// cancelled if another page fails. Errors are wrapped with op (see Wrap).
+func ListAll[T any](ctx context.Context, op string, list func(ctx context.Context, page github.ListOptions) ([]T, *github.Response, error)) ([]T, error) {
+	first, resp, err := list(ctx, github.ListOptions{PerPage: PageSize})
+	if err != nil {
+		return nil, Wrap(op, err)
+	}
+	if resp == nil || resp.NextPage == 0 {
+		return first, nil
+	}
+
+	if resp.LastPage > resp.NextPage {
+		pages := make([][]T, resp.LastPage+1)
+		pages[1] = first
+		var numbers []int
+		for n := 2; n <= resp.LastPage; n++ {
+			numbers = append(numbers, n)
+		}
+		err := ForEach(ctx, Workers, numbers, func(ctx context.Context, n int) error {
+			items, _, err := list(ctx, github.ListOptions{PerPage: PageSize, Page: n})
+			pages[n] = items
+			return err
+		})
+		if err != nil {
+			return nil, Wrap(op, err)
+		}
+		var all []T
+		for _, p := range pages {
+			all = append(all, p...)
+		}
+		return all, nil
+	}
+
+	all := first
+	page := github.ListOptions{PerPage: PageSize, Page: resp.NextPage}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, Wrap(op, err)
+		}
+		items, resp, err := list(ctx, page)
+		if err != nil {
+			return nil, Wrap(op, err)
+		}
+		all = append(all, items...)
+		if resp == nil || resp.NextPage == 0 {
+			return all, nil
+		}
+		page.Page = resp.NextPage
+	}
+}
+
+// ListPages walks a paginated list endpoint like ListAll, but one page at
+// a time, passing each to page as soon as it arrives so long listings can
+// be streamed instead of held in memory. It stops at the first error from
+// list or page; errors from list are wrapped with op.
+func ListPages[T any](ctx context.Context, op string, list func(ctx context.Context, page github.ListOptions) ([]T, *github.Response, error), page func([]T) error) error {
+	opts := github.ListOptions{PerPage: PageSize}
+	for {
+		if err := ctx.Err(); err != nil {
+			return Wrap(op, err)
+		}
+		items, resp, err := list(ctx, opts)
+		if err != nil {
+			return Wrap(op, err)
+		}
+		if err := page(items); err != nil {
+			return err
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,68 @@
+package githubapi
+
+import (
+	"context"
+	"sync"
+)
+
+// Workers is how many requests ListAll and callers of ForEach run at once.
+// GitHub discourages heavy concurrency, so it's kept small; the transport
+// still waits out any secondary rate limit this triggers.
+var Workers = 4
+
+// ForEach calls fn for every item with at most workers calls in flight.
+// The first error cancels the context passed to the remaining calls, and
+// is returned once every call has finished.
+func ForEach[T any](ctx context.Context, workers int, items []T, fn func(ctx context.Context, item T) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	jobs := make(chan T)
+	for w := 0; w < workers && w < len(items); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				if err := fn(ctx, item); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, item := range items {
+		select {
+		case jobs <- item:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr == nil {
+		// Only the parent can have cancelled ctx without an error from fn.
+		firstErr = ctx.Err()
+	}
+	return firstErr
+}
+
+// All runs independent fetches concurrently, cancelling the rest if one
+// fails, and returns the first error.
+func All(ctx context.Context, fetches ...func(ctx context.Context) error) error {
+	return ForEach(ctx, len(fetches), fetches, func(ctx context.Context, fetch func(context.Context) error) error {
+		return fetch(ctx)
+	})
+}
@@ -0,0 +1,91 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxThrottleInterval caps how far a Throttle spaces writes out after
+// repeated secondary rate limits.
+const maxThrottleInterval = 30 * time.Second
+
+// Throttle paces a bulk command's requests to stay under GitHub's
+// secondary rate limits, which cap bursts of writes rather than the hourly
+// quota. Writes (anything but GET and HEAD) are spaced to a budget per
+// second, with jitter so parallel runs don't fall into step. When GitHub
+// answers with a secondary limit anyway, every request pauses until it
+// lifts and writes are spaced twice as far apart, easing back towards the
+// budget with each write that succeeds. Share one Throttle between all the
+// clients a command makes.
+type Throttle struct {
+	mu       sync.Mutex
+	base     time.Duration // spacing at the budget
+	interval time.Duration // current spacing
+	next     time.Time     // earliest the next write may go
+	paused   time.Time     // no request goes before this
+}
+
+// NewThrottle returns a Throttle allowing perSecond writes a second.
+func NewThrottle(perSecond float64) *Throttle {
+	base := time.Duration(float64(time.Second) / perSecond)
+	return &Throttle{base: base, interval: base}
+}
+
+// wait blocks until a request with the given method may be sent, or ctx
+// is cancelled.
+func (t *Throttle) wait(ctx context.Context, method string) error {
+	t.mu.Lock()
+	at := time.Now()
+	if t.paused.After(at) {
+		at = t.paused
+	}
+	if write(method) {
+		if t.next.After(at) {
+			at = t.next
+		}
+		jitter := time.Duration(float64(t.interval) * (0.75 + rand.Float64()/2))
+		t.next = at.Add(jitter)
+	}
+	t.mu.Unlock()
+
+	d := time.Until(at)
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limited records a secondary rate limit that lifts after d.
+func (t *Throttle) limited(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until := time.Now().Add(d); until.After(t.paused) {
+		t.paused = until
+	}
+	t.interval = min(2*t.interval, max(maxThrottleInterval, t.base))
+	fmt.Fprintf(os.Stderr, "🐢 GitHub secondary rate limit hit, slowing to one write every %s\n", t.interval.Round(100*time.Millisecond))
+}
+
+// succeeded records a write GitHub accepted.
+func (t *Throttle) succeeded(method string) {
+	if !write(method) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interval = max(t.base, t.interval*9/10)
+}
+
+func write(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead
+}
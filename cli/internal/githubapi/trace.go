@@ -0,0 +1,92 @@
+package githubapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tracer logs every request that goes out over the network, one line
+// each: method, URL, status, rate-limit headers and duration. Tokens never
+// appear: headers other than the rate limits aren't logged, and query
+// parameters that look like secrets (such as the signatures on asset
+// download links) are redacted.
+type tracer struct {
+	base http.RoundTripper
+	out  io.Writer
+	mu   *sync.Mutex
+}
+
+// traceLocks serializes the lines written to each trace writer, so
+// concurrent requests and several clients never interleave them.
+var traceLocks sync.Map
+
+func newTracer(base http.RoundTripper, out io.Writer) *tracer {
+	mu, _ := traceLocks.LoadOrStore(out, &sync.Mutex{})
+	return &tracer{base: base, out: out, mu: mu.(*sync.Mutex)}
+}
+
+func (t *tracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	took := time.Since(start).Round(time.Millisecond)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s", start.UTC().Format("2006-01-02T15:04:05.000Z"), req.Method, redactURL(req.URL))
+	if err != nil {
+		fmt.Fprintf(&b, " → error after %s: %v", took, err)
+	} else {
+		fmt.Fprintf(&b, " → %d in %s", resp.StatusCode, took)
+		if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+			fmt.Fprintf(&b, " (rate limit %s/%s", remaining, resp.Header.Get("X-RateLimit-Limit"))
+			if r := resp.Header.Get("X-RateLimit-Resource"); r != "" {
+				fmt.Fprintf(&b, " %s", r)
+			}
+			if reset := untilReset(resp); reset > 0 && resp.Header.Get("X-RateLimit-Reset") != "" {
+				fmt.Fprintf(&b, ", resets in %s", reset.Round(time.Second))
+			}
+			b.WriteString(")")
+		}
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			fmt.Fprintf(&b, " retry-after=%ss", ra)
+		}
+		if id := resp.Header.Get("X-GitHub-Request-Id"); id != "" {
+			fmt.Fprintf(&b, " id=%s", id)
+		}
+	}
+	b.WriteString("\n")
+
+	t.mu.Lock()
+	io.WriteString(t.out, b.String())
+	t.mu.Unlock()
+	return resp, err
+}
+
+// secretParams are the query parameters redactURL hides: any whose name
+// contains one of these.
+var secretParams = []string{"token", "secret", "signature", "sig", "credential", "key", "password", "code"}
+
+// redactURL returns u as a string with its user info and any query
+// parameters that look like secrets replaced by REDACTED.
+func redactURL(u *url.URL) string {
+	r := *u
+	if r.User != nil {
+		r.User = url.User("REDACTED")
+	}
+	q := r.Query()
+	for name := range q {
+		lower := strings.ToLower(name)
+		for _, s := range secretParams {
+			if strings.Contains(lower, s) {
+				q[name] = []string{"REDACTED"}
+				break
+			}
+		}
+	}
+	r.RawQuery = q.Encode()
+	return r.String()
+}
@@ -0,0 +1,197 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// transport applies Options to every request: a per-attempt timeout,
+// retries with backoff, waits for rate limits and, with a Throttle,
+// pacing.
+type transport struct {
+	base http.RoundTripper
+	opts Options
+}
+
+// RoundTrip leaves req untouched, as http.RoundTripper requires: each
+// attempt sends a clone, retries with a fresh body from req.GetBody.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := req.Body
+	for attempt := 0; ; attempt++ {
+		if t.opts.Throttle != nil {
+			if werr := t.opts.Throttle.wait(req.Context(), req.Method); werr != nil {
+				return nil, werr
+			}
+		}
+		resp, err := t.attempt(req, body)
+		wait, retry := t.retryAfter(req, resp, err, attempt)
+		if t.opts.Throttle != nil && err == nil {
+			if resp.StatusCode < 400 {
+				t.opts.Throttle.succeeded(req.Method)
+			} else if retry && secondary(resp) {
+				t.opts.Throttle.limited(wait)
+			}
+		}
+		if !retry || attempt >= t.opts.Retries {
+			if err == nil {
+				t.keepReserve(req.Context(), resp)
+			}
+			return resp, err
+		}
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			fresh, gerr := req.GetBody()
+			if gerr != nil {
+				return resp, err
+			}
+			body = fresh
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if werr := sleep(req.Context(), wait); werr != nil {
+			return nil, werr
+		}
+	}
+}
+
+// attempt sends a clone of req with body once, bounded by the timeout. The
+// timeout is released when the response body is closed, so it also covers
+// reading the body.
+func (t *transport) attempt(req *http.Request, body io.ReadCloser) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.opts.Timeout)
+	clone := req.Clone(ctx)
+	clone.Body = body
+	resp, err := t.base.RoundTrip(clone)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// retryAfter decides whether a failed attempt should be retried, and after
+// how long. Rate-limited requests were never processed, so they're safe to
+// retry whatever the method; other failures only for idempotent requests.
+// A secondary rate limit without a Retry-After is waited out for a
+// minute, as GitHub asks.
+func (t *transport) retryAfter(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	backoff := time.Duration(1<<attempt) * time.Second
+	if err != nil {
+		var miss *NotRecordedError
+		if req.Context().Err() != nil || errors.As(err, &miss) {
+			return 0, false
+		}
+		return backoff, idempotent(req.Method)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		if s := resp.Header.Get("Retry-After"); s != "" {
+			if secs, perr := strconv.Atoi(s); perr == nil {
+				return time.Duration(secs) * time.Second, true
+			}
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return untilReset(resp), true
+		}
+		if secondary(resp) {
+			return time.Minute, true
+		}
+	case resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		return backoff, idempotent(req.Method)
+	}
+	return 0, false
+}
+
+// keepReserve waits for the rate-limit window to reset once the remaining
+// quota falls below the reserve. go-github refuses to send further
+// requests once the quota is spent, so waiting here keeps them flowing.
+func (t *transport) keepReserve(ctx context.Context, resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining >= t.opts.Reserve {
+		return
+	}
+	if resp.Header.Get("X-RateLimit-Resource") != "" && resp.Header.Get("X-RateLimit-Resource") != "core" {
+		return
+	}
+	sleep(ctx, untilReset(resp))
+}
+
+func untilReset(resp *http.Response) time.Duration {
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Minute
+	}
+	return time.Until(time.Unix(reset, 0)) + time.Second
+}
+
+// secondary reports whether resp is a secondary rate limit: a 403 or 429
+// with a Retry-After, or whose message says so. The body is read to check
+// and replaced, so the response can still be decoded.
+func secondary(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	if resp.Header.Get("Retry-After") != "" {
+		return true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return false
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	msg := strings.ToLower(string(body))
+	return strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse")
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// sleep waits for d unless ctx is cancelled first, in which case it
+// returns the context's error.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	if d >= 5*time.Second {
+		fmt.Fprintf(os.Stderr, "⏳ GitHub rate limit reached, waiting %s\n", d.Round(time.Second))
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// formField is one field of a match issue form. The same definitions
+// generate the GitHub issue forms (.github/ISSUE_TEMPLATE) and the issue
+// bodies "tennis match" writes, so both produce identical structures.
+type formField struct {
+	ID          string
+	Type        string // "input" or "textarea"
+	Label       string
+	Placeholder string
+	Description string
+	Pattern     string
+}
+
+// issueForm is a match issue form.
+type issueForm struct {
+	File        string // under .github/ISSUE_TEMPLATE
+	Name        string
+	Description string
+	Kind        string // "singles" or "doubles"
+	Fields      []formField
+}
+
+// Label is the label the form applies, from the league's label scheme.
+func (f issueForm) Label() string {
+	if f.Kind == "doubles" {
+		return labelNames.Doubles
+	}
+	return labelNames.Singles
+}
+
+var dateField = formField{
+	ID:          "date",
+	Type:        "input",
+	Label:       "Match date (YYYY-MM-DD)",
+	Placeholder: "2025-08-05",
+	Pattern:     "^[0-9]{4}-[0-9]{2}-[0-9]{2}$",
+}
+
+var singlesForm = issueForm{
+	File:        "singles-match.yml",
+	Name:        "🎾  Record a singles match",
+	Description: "Log a finished singles match – winner first",
+	Kind:        "singles",
+	Fields: []formField{
+		dateField,
+		{
+			ID:          "players",
+			Type:        "textarea",
+			Label:       "Players (player1, player2)",
+			Placeholder: "@github_handle1, @github_handle2",
+		},
+		{
+			ID:          "sets",
+			Type:        "textarea",
+			Label:       "Sets (one line per set, player1's games first)",
+			Description: "Enter as `<player1Games>-<player2Games>`, e.g.\n```\n6-3\n4-6\n10-8\n```",
+		},
+	},
+}
+
+var doublesForm = issueForm{
+	File:        "doubles-match.yml",
+	Name:        "🎾  Record a doubles match",
+	Description: "Log a finished doubles match – winner first",
+	Kind:        "doubles",
+	Fields: []formField{
+		dateField,
+		{
+			ID:          "teams",
+			Type:        "textarea",
+			Label:       "Teams (player1, player2 || player3, player4)",
+			Placeholder: "@github_handle1, @github_handle2 || @github_handle3, @github_handle4",
+		},
+		{
+			ID:          "sets",
+			Type:        "textarea",
+			Label:       "Sets (one line per set, team1's games first)",
+			Description: "Enter as `<team1Games>-<team2Games>`, e.g.\n```\n6-3\n4-6\n10-8\n```",
+		},
+	},
+}
+
+// matchForms are the issue forms a league repository uses.
+var matchForms = []issueForm{singlesForm, doublesForm}
+
+// Body renders field values, in field order, the way GitHub renders a
+// submitted form: a "### <label>" heading and the value for each field.
+func (f issueForm) Body(values ...string) string {
+	var sections []string
+	for i, field := range f.Fields {
+		sections = append(sections, fmt.Sprintf("### %s\n\n%s", field.Label, values[i]))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// YAML renders the GitHub issue form definition.
+func (f issueForm) YAML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %q\n", f.Name)
+	fmt.Fprintf(&b, "description: %q\n", f.Description)
+	fmt.Fprintf(&b, "labels: [%q]\n", f.Label())
+	b.WriteString("body:\n")
+	for _, field := range f.Fields {
+		fmt.Fprintf(&b, "  - type: %s\n", field.Type)
+		fmt.Fprintf(&b, "    id: %s\n", field.ID)
+		b.WriteString("    attributes:\n")
+		fmt.Fprintf(&b, "      label: %q\n", field.Label)
+		writeFormText(&b, "placeholder", field.Placeholder, field.Type == "textarea")
+		writeFormText(&b, "description", field.Description, true)
+		b.WriteString("    validations:\n")
+		b.WriteString("      required: true\n")
+		if field.Pattern != "" {
+			fmt.Fprintf(&b, "      pattern: %q\n", field.Pattern)
+		}
+	}
+	return b.String()
+}
+
+// writeFormText writes an attribute, as a literal block when block is set.
+func writeFormText(b *strings.Builder, key, text string, block bool) {
+	if text == "" {
+		return
+	}
+	if !block {
+		fmt.Fprintf(b, "      %s: %q\n", key, text)
+		return
+	}
+	fmt.Fprintf(b, "      %s: |\n", key)
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(b, "        %s\n", line)
+	}
+}
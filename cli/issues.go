@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// openIssue creates an issue and returns its number. Under --dry-run the
+// issue is printed instead and 0 is returned.
+func openIssue(ctx context.Context, title, body string, labels []string) (int, error) {
+	if dryRun {
+		printDryRun(title, body, labels)
+		fmt.Println()
+		return 0, nil
+	}
+
+	client := getGitHubClient()
+
+	issue, _, err := client.Issues.Create(ctx, owner, repo, &github.IssueRequest{
+		Title:  &title,
+		Body:   &body,
+		Labels: &labels,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create issue '%s': %w", title, err)
+	}
+	fmt.Printf("Created #%d: %s\n", issue.GetNumber(), title)
+	return issue.GetNumber(), nil
+}
+
+// closeIssue posts a comment explaining why an issue is done, then closes
+// it. A no-op under --dry-run.
+func closeIssue(ctx context.Context, number int, comment string) error {
+	if dryRun {
+		fmt.Printf("[dry-run] would close #%d: %s\n", number, comment)
+		return nil
+	}
+
+	client := getGitHubClient()
+
+	// Keyed by the reason, so closing an issue again after it was reopened
+	// still explains why.
+	sum := sha256.Sum256([]byte(comment))
+	if _, err := commentOnce(ctx, client, number, comment, "closed:"+hex.EncodeToString(sum[:6])); err != nil {
+		return err
+	}
+	closed := "closed"
+	if _, _, err := client.Issues.Edit(ctx, owner, repo, number, &github.IssueRequest{State: &closed}); err != nil {
+		return fmt.Errorf("failed to close #%d: %w", number, err)
+	}
+	return nil
+}
+
+// commentMarker is the hidden HTML comment that identifies a bot comment
+// posted under key, so later runs can find it.
+func commentMarker(key string) string {
+	return "<!-- tennis:" + key + " -->"
+}
+
+// commentOnce posts a comment on an issue or pull request with a hidden
+// marker for dedupeKey appended, unless a comment carrying that marker
+// already exists, so re-runs of automation never repeat themselves. It
+// reports whether a comment was (or, under --dry-run, would be) posted.
+func commentOnce(ctx context.Context, client *github.Client, number int, body, dedupeKey string) (bool, error) {
+	marker := commentMarker(dedupeKey)
+	comments, err := listComments(ctx, client, number)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range comments {
+		if strings.Contains(c.GetBody(), marker) {
+			return false, nil
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] would comment on #%d: %s\n", number, body)
+		return true, nil
+	}
+	body += "\n\n" + marker
+	if _, _, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: &body}); err != nil {
+		return false, fmt.Errorf("failed to comment on #%d: %w", number, err)
+	}
+	return true, nil
+}
+
+// upsertComment keeps a single comment on an issue or pull request up to
+// date: the comment containing marker is edited to body (which should
+// contain the marker too), or created if there is none yet. Honors
+// --dry-run.
+func upsertComment(ctx context.Context, client *github.Client, number int, marker, body string) error {
+	comments, err := listComments(ctx, client, number)
+	if err != nil {
+		return err
+	}
+	var existing *github.IssueComment
+	for _, c := range comments {
+		if strings.Contains(c.GetBody(), marker) {
+			existing = c
+			break
+		}
+	}
+
+	switch {
+	case existing != nil && existing.GetBody() == body:
+		return nil
+	case dryRun:
+		fmt.Printf("[dry-run] would update the status comment on #%d:\n%s\n", number, body)
+		return nil
+	case existing != nil:
+		if _, _, err := client.Issues.EditComment(ctx, owner, repo, existing.GetID(), &github.IssueComment{Body: &body}); err != nil {
+			return fmt.Errorf("failed to update comment on #%d: %w", number, err)
+		}
+	default:
+		if _, _, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: &body}); err != nil {
+			return fmt.Errorf("failed to comment on #%d: %w", number, err)
+		}
+	}
+	return nil
+}
+
+// listComments returns every comment on an issue or pull request.
+func listComments(ctx context.Context, client *github.Client, number int) ([]*github.IssueComment, error) {
+	return githubapi.ListAll(ctx, fmt.Sprintf("list comments on #%d", number), func(ctx context.Context, page github.ListOptions) ([]*github.IssueComment, *github.Response, error) {
+		return client.Issues.ListComments(ctx, owner, repo, number, &github.IssueListCommentsOptions{ListOptions: page})
+	})
+}
+
+// listReviews returns every review of a pull request.
+func listReviews(ctx context.Context, client *github.Client, pr int) ([]*github.PullRequestReview, error) {
+	return githubapi.ListAll(ctx, fmt.Sprintf("list reviews for PR #%d", pr), func(ctx context.Context, page github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+		return client.PullRequests.ListReviews(ctx, owner, repo, pr, &page)
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import "fmt"
+
+// leagueLabel is an issue label the league's commands and workflows use.
+type leagueLabel struct {
+	Name        string `yaml:"name"`
+	Color       string `yaml:"color"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// labelScheme names the labels the CLI applies and looks for, set in the
+// label_names section of .tennis.yml. Unset names keep the defaults.
+type labelScheme struct {
+	Singles         string `yaml:"singles,omitempty"`          // singles match issues
+	Doubles         string `yaml:"doubles,omitempty"`          // doubles match issues
+	Approved        string `yaml:"approved,omitempty"`         // every player has approved
+	NeedsCorrection string `yaml:"needs_correction,omitempty"` // the reporter must fix the issue
+	Challenge       string `yaml:"challenge,omitempty"`        // matchmaking fixtures
+	Matchmaking     string `yaml:"matchmaking,omitempty"`      // matchmaking summaries
+	Tournament      string `yaml:"tournament,omitempty"`       // tournament fixtures
+	BoxLeague       string `yaml:"box_league,omitempty"`       // box league fixtures
+	StandingFixture string `yaml:"standing_fixture,omitempty"` // recurring fixture definitions
+	Expired         string `yaml:"expired,omitempty"`          // closed unapproved by "bot expire"
+	NeedsAdmin      string `yaml:"needs_admin,omitempty"`      // escalated to the admins by "bot escalate"
+	Upset           string `yaml:"upset,omitempty"`            // upsets tagged by "stats upsets --label"
+}
+
+var defaultLabelNames = labelScheme{
+	Singles:         "new-singles-match",
+	Doubles:         "new-doubles-match",
+	Approved:        "players-approved",
+	NeedsCorrection: "needs-correction",
+	Challenge:       "challenge",
+	Matchmaking:     "matchmaking",
+	Tournament:      "tournament",
+	BoxLeague:       "box-league",
+	StandingFixture: "standing-fixture",
+	Expired:         "expired",
+	NeedsAdmin:      "needs-admin",
+	Upset:           "upset",
+}
+
+// labelNames is the league's label scheme, loaded with elo.
+var labelNames = defaultLabelNames
+
+// withDefaults fills in unset names. Singles and doubles issues must be
+// labelled differently, since the label tells them apart.
+func (s labelScheme) withDefaults() (labelScheme, error) {
+	for _, f := range []struct {
+		name *string
+		def  string
+	}{
+		{&s.Singles, defaultLabelNames.Singles},
+		{&s.Doubles, defaultLabelNames.Doubles},
+		{&s.Approved, defaultLabelNames.Approved},
+		{&s.NeedsCorrection, defaultLabelNames.NeedsCorrection},
+		{&s.Challenge, defaultLabelNames.Challenge},
+		{&s.Matchmaking, defaultLabelNames.Matchmaking},
+		{&s.Tournament, defaultLabelNames.Tournament},
+		{&s.BoxLeague, defaultLabelNames.BoxLeague},
+		{&s.StandingFixture, defaultLabelNames.StandingFixture},
+		{&s.Expired, defaultLabelNames.Expired},
+		{&s.NeedsAdmin, defaultLabelNames.NeedsAdmin},
+		{&s.Upset, defaultLabelNames.Upset},
+	} {
+		if *f.name == "" {
+			*f.name = f.def
+		}
+	}
+	if s.Singles == s.Doubles {
+		return s, fmt.Errorf("label_names.singles and label_names.doubles must differ, both are %q", s.Singles)
+	}
+	return s, nil
+}
+
+// defaultLabels are the labels "tennis init" creates, and the declared
+// labels when .tennis.yml has no labels list.
+func defaultLabels() []leagueLabel {
+	return []leagueLabel{
+		{labelNames.Singles, "0e8a16", "Singles match result, recorded by the issue-to-PR workflow"},
+		{labelNames.Doubles, "1d76db", "Doubles match result, recorded by the issue-to-PR workflow"},
+		{labelNames.Challenge, "fbca04", "Scheduled fixture from weekly matchmaking"},
+		{labelNames.Matchmaking, "c5def5", "Weekly matchmaking summary"},
+		{labelNames.Tournament, "d93f0b", "Tournament fixture"},
+		{labelNames.BoxLeague, "5319e7", "Monthly box league fixture"},
+		{labelNames.StandingFixture, "bfd4f2", "Recurring fixture, scheduled every week until closed"},
+		{labelNames.NeedsCorrection, "b60205", "Match issue that needs fixing by the reporter"},
+		{labelNames.Approved, "0e8a16", "Every player has approved the match result"},
+		{labelNames.Expired, "cfd3d7", "Match closed unrecorded after waiting too long for approval"},
+		{labelNames.NeedsAdmin, "e99695", "Disputed or stalled match escalated to the league admins"},
+		{labelNames.Upset, "f9d0c4", "Match won by the lower-rated side by a wide margin"},
+	}
+}
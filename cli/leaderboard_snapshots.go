@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// snapshotsReleaseTag is the release whose assets hold leaderboard
+// snapshots saved with --release.
+const snapshotsReleaseTag = "rankings-snapshots"
+
+// leaderboardSnapshot is the leaderboards as they stood on a day, saved
+// by "tennis rankings snapshot save" so past leaderboards and movement can
+// be shown without replaying the match history.
+type leaderboardSnapshot struct {
+	Date              string           `json:"date"`
+	Generated         string           `json:"generated"`
+	Commit            string           `json:"commit,omitempty"`
+	Singles           []LeaderboardRow `json:"singles"`
+	DoublesIndividual []LeaderboardRow `json:"doubles_individual"`
+	Elo               *eloParams       `json:"elo,omitempty"`
+}
+
+func snapshotsDir() string {
+	return filepath.Join(leagueDir(), "rankings-snapshots")
+}
+
+func snapshotAssetName(date string) string {
+	return date + ".json"
+}
+
+// listLeaderboardSnapshots returns the dates with a saved snapshot, oldest
+// first, from the data directory or the snapshots release.
+func listLeaderboardSnapshots(ctx context.Context, release bool) ([]string, error) {
+	var names []string
+	if release {
+		_, assets, err := snapshotsRelease(ctx, getGitHubClient(), false)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range assets {
+			names = append(names, a.GetName())
+		}
+	} else {
+		files, err := filepath.Glob(filepath.Join(snapshotsDir(), "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			names = append(names, filepath.Base(f))
+		}
+	}
+
+	var dates []string
+	for _, n := range names {
+		date := strings.TrimSuffix(n, ".json")
+		if isValidDate(date) {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// loadLeaderboardSnapshot reads the snapshot saved for a date.
+func loadLeaderboardSnapshot(ctx context.Context, date string, release bool) (*leaderboardSnapshot, error) {
+	var data []byte
+	if release {
+		client := getGitHubClient()
+		_, assets, err := snapshotsRelease(ctx, client, false)
+		if err != nil {
+			return nil, err
+		}
+		asset := findAsset(assets, snapshotAssetName(date))
+		if asset == nil {
+			return nil, fmt.Errorf("no snapshot for %s in release %s", date, snapshotsReleaseTag)
+		}
+		rc, _, err := client.Repositories.DownloadReleaseAsset(ctx, owner, repo, asset.GetID(), downloadClient(0))
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %v", asset.GetName(), err)
+		}
+		defer rc.Close()
+		if data, err = io.ReadAll(rc); err != nil {
+			return nil, fmt.Errorf("failed to download %s: %v", asset.GetName(), err)
+		}
+	} else {
+		path := filepath.Join(snapshotsDir(), snapshotAssetName(date))
+		var err error
+		data, err = os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no snapshot for %s (looked in %s)", date, snapshotsDir())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var snap leaderboardSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot for %s: %w", date, err)
+	}
+	return &snap, nil
+}
+
+// saveLeaderboardSnapshot stores a snapshot in the data directory or as an
+// asset of the snapshots release, refusing to replace an existing one
+// unless force is set.
+func saveLeaderboardSnapshot(ctx context.Context, snap *leaderboardSnapshot, release, force bool) (string, error) {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	data = append(data, '\n')
+	return saveSnapshotAsset(ctx, snapshotAssetName(snap.Date), data, release, force)
+}
+
+// saveSnapshotAsset stores a file named name in the data directory's
+// snapshots folder or as an asset of the snapshots release, refusing to
+// replace an existing one unless force is set.
+func saveSnapshotAsset(ctx context.Context, name string, data []byte, release, force bool) (string, error) {
+	if !release {
+		path := filepath.Join(snapshotsDir(), name)
+		if _, err := os.Stat(path); err == nil && !force {
+			return "", fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		}
+		if dryRun {
+			return path, nil
+		}
+		if err := os.MkdirAll(snapshotsDir(), 0o755); err != nil {
+			return "", err
+		}
+		return path, os.WriteFile(path, data, 0o644)
+	}
+
+	client := getGitHubClient()
+	rel, assets, err := snapshotsRelease(ctx, client, !dryRun)
+	if err != nil {
+		return "", err
+	}
+	existing := findAsset(assets, name)
+	if existing != nil && !force {
+		return "", fmt.Errorf("release %s already has %s (use --force to overwrite)", snapshotsReleaseTag, name)
+	}
+	where := fmt.Sprintf("release %s (%s)", snapshotsReleaseTag, name)
+	if dryRun {
+		return where, nil
+	}
+	if existing == nil {
+		_, err := uploadReleaseAsset(ctx, client, rel, name, "application/json", data)
+		return where, err
+	}
+	return where, replaceReleaseAsset(ctx, client, rel, existing, "application/json", data)
+}
+
+// replaceReleaseAsset replaces an asset's contents without ever leaving the
+// release without it: the new file is uploaded under a temporary name, and
+// the old one deleted only once that has succeeded.
+func replaceReleaseAsset(ctx context.Context, client *github.Client, rel *github.RepositoryRelease, existing *github.ReleaseAsset, mediaType string, data []byte) error {
+	name := existing.GetName()
+	uploaded, err := uploadReleaseAsset(ctx, client, rel, "replacing-"+name, mediaType, data)
+	if err != nil {
+		return fmt.Errorf("failed to replace %s, which is unchanged: %w", name, err)
+	}
+	if _, err := client.Repositories.DeleteReleaseAsset(ctx, owner, repo, existing.GetID()); err != nil {
+		return fmt.Errorf("failed to replace %s (the new one is uploaded as %s): %w", name, uploaded.GetName(), err)
+	}
+	if _, _, err := client.Repositories.EditReleaseAsset(ctx, owner, repo, uploaded.GetID(), &github.ReleaseAsset{Name: &name}); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", uploaded.GetName(), name, err)
+	}
+	return nil
+}
+
+// uploadReleaseAsset attaches data to a release as an asset named name.
+func uploadReleaseAsset(ctx context.Context, client *github.Client, rel *github.RepositoryRelease, name, mediaType string, data []byte) (*github.ReleaseAsset, error) {
+	// UploadReleaseAsset needs a file to size the upload.
+	tmp, err := os.CreateTemp("", "asset-*"+filepath.Ext(name))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if _, err := tmp.Write(data); err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	asset, _, err := client.Repositories.UploadReleaseAsset(ctx, owner, repo, rel.GetID(),
+		&github.UploadOptions{Name: name, MediaType: mediaType}, tmp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	return asset, nil
+}
+
+// snapshotsRelease returns the snapshots release and its assets, creating
+// the release if create is set and it doesn't exist yet.
+func snapshotsRelease(ctx context.Context, client *github.Client, create bool) (*github.RepositoryRelease, []*github.ReleaseAsset, error) {
+	rel, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repo, snapshotsReleaseTag)
+	if githubapi.IsNotFound(err) {
+		if !create {
+			return nil, nil, nil
+		}
+		rel, _, err = client.Repositories.CreateRelease(ctx, owner, repo, &github.RepositoryRelease{
+			TagName: github.String(snapshotsReleaseTag),
+			Name:    github.String("Rankings snapshots"),
+			Body:    github.String("Leaderboard snapshots saved by `tennis rankings snapshot save --release`, one JSON file per day."),
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create release %s: %w", snapshotsReleaseTag, err)
+		}
+		return rel, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch release %s: %w", snapshotsReleaseTag, err)
+	}
+	assets, err := githubapi.ListAll(ctx, "list release assets", func(ctx context.Context, page github.ListOptions) ([]*github.ReleaseAsset, *github.Response, error) {
+		return client.Repositories.ListReleaseAssets(ctx, owner, repo, rel.GetID(), &page)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rel, assets, nil
+}
+
+func findAsset(assets []*github.ReleaseAsset, name string) *github.ReleaseAsset {
+	for _, a := range assets {
+		if a.GetName() == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// rankMovement returns how far each ranked player has moved between two
+// boards: positive is up. Players who weren't ranked on the earlier board
+// are left out.
+func rankMovement(before, after []LeaderboardRow) map[string]int {
+	prev := make(map[string]int)
+	for _, r := range before {
+		if !r.Unranked {
+			prev[r.Player] = r.Rank
+		}
+	}
+	moves := make(map[string]int)
+	for _, r := range after {
+		if rank, ok := prev[r.Player]; ok && !r.Unranked {
+			moves[r.Player] = rank - r.Rank
+		}
+	}
+	return moves
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Match is a recorded match as stored by the issue-to-PR workflow under
+// singles-matches/ or doubles-matches/. Singles matches use Players;
+// doubles matches use Team1/Team2. Sets are [side1Games, side2Games].
+type Match struct {
+	Date         string         `yaml:"date"`
+	Time         string         `yaml:"time,omitempty"`    // HH:MM, when known
+	Surface      string         `yaml:"surface,omitempty"` // one of surfaces, when known
+	Players      []string       `yaml:"players,omitempty"`
+	Team1        []string       `yaml:"team1,omitempty"`
+	Team2        []string       `yaml:"team2,omitempty"`
+	Sets         [][]int        `yaml:"sets"`
+	Handicap     *matchHandicap `yaml:"handicap,omitempty"`      // head start, if played with one
+	HandicapSets [][]int        `yaml:"handicap_sets,omitempty"` // sets with the head start added
+	Forfeit      []string       `yaml:"forfeit,omitempty"`       // the side that didn't turn up, if it was a forfeit
+	SourceIssue  int            `yaml:"source_issue"`
+	Repo         string         `yaml:"repo,omitempty"` // owner/name of the league repo it was recorded in, if not this one
+
+	File string `yaml:"-"` // path of the match file it was loaded from
+}
+
+// IsDoubles reports whether the match was a doubles match.
+func (m Match) IsDoubles() bool {
+	return len(m.Team1) > 0 || len(m.Team2) > 0
+}
+
+// matchWinner returns 1 or 2 for the side that won more sets, or 0 for a
+// tie or a match with no decided sets.
+func matchWinner(m Match) int {
+	var w1, w2 int
+	for _, s := range m.Sets {
+		if len(s) != 2 {
+			continue
+		}
+		switch {
+		case s[0] > s[1]:
+			w1++
+		case s[1] > s[0]:
+			w2++
+		}
+	}
+	switch {
+	case w1 > w2:
+		return 1
+	case w2 > w1:
+		return 2
+	}
+	return 0
+}
+
+// normalizePlayer canonicalizes a handle for case-insensitive identity,
+// mirroring normalize_player in scripts/elo_utils.py.
+func normalizePlayer(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+}
+
+// leagueDir returns the root of the league checkout whose data files the
+// CLI reads: --dir if given, else the enclosing git checkout, else the
+// current directory.
+func leagueDir() string {
+	if dataDir != "" {
+		return dataDir
+	}
+	if path, err := exec.LookPath("git"); err == nil {
+		if out, err := exec.Command(path, "rev-parse", "--show-toplevel").Output(); err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	}
+	return "."
+}
+
+// loadMatches reads every match file in the given data directory (e.g.
+// "singles-matches"), sorted by filename so replay order matches the
+// Python ranking scripts. Handles are normalized and renamed handles are
+// resolved through aliases.yml. Unreadable files are reported and skipped.
+// Matches from the league's other repositories (see leagueRepos) are
+// merged in, ordered by the names "tennis repos pull" gives their files.
+func loadMatches(ctx context.Context, dir string) ([]Match, error) {
+	files, err := filepath.Glob(filepath.Join(leagueDir(), dir, "*.yml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, fn := range files {
+		data, err := os.ReadFile(fn)
+		if err != nil {
+			return nil, err
+		}
+		m, err := parseMatchFile(fn, data, aliases)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", fn, err)
+			continue
+		}
+		matches = append(matches, m)
+	}
+	if len(leagueRepos) == 0 {
+		return matches, nil
+	}
+
+	remote, err := loadRepoMatches(ctx, dir, aliases, matches)
+	if err != nil {
+		return nil, err
+	}
+	matches = append(matches, remote...)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return filepath.Base(matches[i].File) < filepath.Base(matches[j].File)
+	})
+	return matches, nil
+}
+
+// parseMatchFile reads one match file, normalizing its handles.
+func parseMatchFile(name string, data []byte, aliases map[string]string) (Match, error) {
+	var m Match
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return Match{}, err
+	}
+	m.File = name
+	for _, side := range [][]string{m.Players, m.Team1, m.Team2} {
+		for i, p := range side {
+			side[i] = resolveAlias(aliases, normalizePlayer(p))
+		}
+	}
+	return m, nil
+}
+
+// loadSinglesMatches returns all recorded singles matches.
+func loadSinglesMatches(ctx context.Context) ([]Match, error) {
+	return loadMatches(ctx, "singles-matches")
+}
+
+// loadDoublesMatches returns all recorded doubles matches.
+func loadDoublesMatches(ctx context.Context) ([]Match, error) {
+	return loadMatches(ctx, "doubles-matches")
+}
+
+// writeYAMLFile writes v to path as YAML with two-space indentation (the
+// style of the workflow-generated data files), creating parent directories.
+func writeYAMLFile(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := marshalYAML(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// marshalYAML encodes v as YAML with two-space indentation.
+func marshalYAML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,54 @@
+package league
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// MatchIssues opens match issues at most once each, recognizing a result
+// already reported by its fingerprint.
+type MatchIssues struct {
+	issues      IssueService
+	owner, repo string
+}
+
+// NewMatchIssues returns a MatchIssues for owner/repo.
+func NewMatchIssues(issues IssueService, owner, repo string) *MatchIssues {
+	return &MatchIssues{issues: issues, owner: owner, repo: repo}
+}
+
+// OpenOnce opens the issue req describes unless an existing issue, open
+// or closed, has the same fingerprint, in which case that issue is
+// returned with created false. fingerprintOf gives an existing issue's
+// fingerprint, or "" for issues that aren't match issues. Issues closed as
+// not planned (expired or rejected) don't count, so a result can be
+// reported again after one.
+func (m *MatchIssues) OpenOnce(ctx context.Context, req *github.IssueRequest, fingerprint string, fingerprintOf func(*github.Issue) string) (issue *github.Issue, created bool, err error) {
+	opts := &github.IssueListByRepoOptions{State: "all", Sort: "created", Direction: "asc"}
+	existing, err := githubapi.ListAll(ctx, "list issues", func(ctx context.Context, page github.ListOptions) ([]*github.Issue, *github.Response, error) {
+		o := *opts
+		o.ListOptions = page
+		return m.issues.ListByRepo(ctx, m.owner, m.repo, &o)
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check for an existing issue: %w", err)
+	}
+	for _, e := range existing {
+		if e.IsPullRequest() || e.GetStateReason() == "not_planned" {
+			continue
+		}
+		if fingerprintOf(e) == fingerprint {
+			return e, false, nil
+		}
+	}
+
+	issue, _, err = m.issues.Create(ctx, m.owner, m.repo, req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return issue, true, nil
+}
@@ -0,0 +1,52 @@
+// Package league holds GitHub operations of the tennis CLI that other Go
+// programs can embed. Each depends on small interfaces rather than a
+// *github.Client, and its constructor takes any implementation: pass the
+// services of a go-github client (client.Issues, client.Actions,
+// client.Repositories), or fakes in tests.
+//
+// The CLI runs these operations through the same constructors. Its other
+// commands still live in package main.
+package league
+
+import (
+	"context"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// IssueCreator opens issues. *github.IssuesService implements it.
+type IssueCreator interface {
+	Create(ctx context.Context, owner, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+}
+
+// IssueLister lists a repository's issues a page at a time.
+// *github.IssuesService implements it.
+type IssueLister interface {
+	ListByRepo(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+}
+
+// IssueService is an IssueCreator and IssueLister in one.
+type IssueService interface {
+	IssueCreator
+	IssueLister
+}
+
+// WorkflowDispatcher lists a repository's Actions workflows and starts
+// them. *github.ActionsService implements it.
+type WorkflowDispatcher interface {
+	ListWorkflows(ctx context.Context, owner, repo string, opts *github.ListOptions) (*github.Workflows, *github.Response, error)
+	CreateWorkflowDispatchEventByID(ctx context.Context, owner, repo string, workflowID int64, event github.CreateWorkflowDispatchEventRequest) (*github.Response, error)
+}
+
+// RepositoryGetter looks up a repository. *github.RepositoriesService
+// implements it.
+type RepositoryGetter interface {
+	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+}
+
+// The go-github services implement the interfaces.
+var (
+	_ IssueService       = (*github.IssuesService)(nil)
+	_ WorkflowDispatcher = (*github.ActionsService)(nil)
+	_ RepositoryGetter   = (*github.RepositoriesService)(nil)
+)
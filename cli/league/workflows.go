@@ -0,0 +1,62 @@
+package league
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// WorkflowTrigger starts a repository's Actions workflows by name.
+type WorkflowTrigger struct {
+	workflows   WorkflowDispatcher
+	repos       RepositoryGetter
+	owner, repo string
+}
+
+// NewWorkflowTrigger returns a WorkflowTrigger for owner/repo.
+func NewWorkflowTrigger(workflows WorkflowDispatcher, repos RepositoryGetter, owner, repo string) *WorkflowTrigger {
+	return &WorkflowTrigger{workflows: workflows, repos: repos, owner: owner, repo: repo}
+}
+
+// Find returns the workflow called name, matched case-insensitively
+// against its display name or its file name with or without the
+// extension, or nil if none matches. It also returns every workflow, to
+// list when none matched.
+func (t *WorkflowTrigger) Find(ctx context.Context, name string) (*github.Workflow, []*github.Workflow, error) {
+	workflows, _, err := t.workflows.ListWorkflows(ctx, t.owner, t.repo, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list workflows: %w", err)
+	}
+	for _, workflow := range workflows.Workflows {
+		if strings.EqualFold(workflow.GetName(), name) || strings.HasSuffix(workflow.GetPath(), name) {
+			return workflow, workflows.Workflows, nil
+		}
+		parts := strings.Split(workflow.GetPath(), "/")
+		filename := parts[len(parts)-1]
+		if strings.EqualFold(strings.TrimSuffix(strings.TrimSuffix(filename, ".yml"), ".yaml"), name) {
+			return workflow, workflows.Workflows, nil
+		}
+	}
+	return nil, workflows.Workflows, nil
+}
+
+// DefaultBranch returns the repository's default branch, the ref
+// workflows are run on.
+func (t *WorkflowTrigger) DefaultBranch(ctx context.Context) (string, error) {
+	info, _, err := t.repos.Get(ctx, t.owner, t.repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository info: %w", err)
+	}
+	return info.GetDefaultBranch(), nil
+}
+
+// Dispatch runs workflow on ref with inputs.
+func (t *WorkflowTrigger) Dispatch(ctx context.Context, workflow *github.Workflow, ref string, inputs map[string]interface{}) error {
+	event := github.CreateWorkflowDispatchEventRequest{Ref: ref, Inputs: inputs}
+	if _, err := t.workflows.CreateWorkflowDispatchEventByID(ctx, t.owner, t.repo, workflow.GetID(), event); err != nil {
+		return fmt.Errorf("failed to trigger workflow: %w", err)
+	}
+	return nil
+}
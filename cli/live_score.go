@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// liveFormat is how a match scored live is played.
+type liveFormat struct {
+	BestOf        int  // sets in the match: 1, 3 or 5
+	Games         int  // games to win a set: 6, or 4 for short sets
+	MatchTiebreak bool // a deciding set is played as a first-to-10 tiebreak
+}
+
+// liveFormatFor is the format the league's rules describe: best of 3 unless
+// rules.best_of says otherwise, short sets if they're the only scoring
+// allowed, and a match tiebreak if one is allowed.
+func liveFormatFor(r leagueRules) liveFormat {
+	f := liveFormat{BestOf: r.BestOf, Games: 6, MatchTiebreak: containsString(r.Scoring, scoringMatchTiebreak)}
+	if f.BestOf == 0 {
+		f.BestOf = 3
+	}
+	if containsString(r.Scoring, scoringShort) && !containsString(r.Scoring, scoringStandard) {
+		f.Games = 4
+	}
+	return f
+}
+
+func (f liveFormat) String() string {
+	s := fmt.Sprintf("best of %d sets, first to %d games", f.BestOf, f.Games)
+	if f.MatchTiebreak && f.BestOf > 1 {
+		s += ", match tiebreak for the deciding set"
+	}
+	return s
+}
+
+// liveScore is the score of a match in play. It's rebuilt from the log of
+// everything the scorer entered, so any entry can be undone.
+type liveScore struct {
+	Format liveFormat
+	Log    []string // entries, as liveScore.apply takes them
+	Sets   [][]int  // finished sets, side 1's games first
+	Games  [2]int   // games in the set in play
+	Points [2]int   // points in the game or tiebreak in play
+	Winner int      // 1 or 2 once the match is won, else 0
+}
+
+// replayLive rebuilds a score from its log.
+func replayLive(format liveFormat, log []string) (*liveScore, error) {
+	s := &liveScore{Format: format}
+	for _, entry := range log {
+		if err := s.apply(entry); err != nil {
+			return nil, fmt.Errorf("live score log entry %q: %w", entry, err)
+		}
+	}
+	return s, nil
+}
+
+// apply enters one thing that happened: "1" or "2" for a point won by
+// that side, "g1" or "g2" for a whole game, or a finished set's score such
+// as "6-3" (side 1's games first) between sets.
+func (s *liveScore) apply(entry string) error {
+	if s.Winner != 0 {
+		return fmt.Errorf("the match is over; undo the last entry to change it")
+	}
+	switch entry {
+	case "1", "2":
+		s.point(int(entry[0] - '1'))
+	case "g1", "g2":
+		if s.inMatchTiebreak() {
+			return fmt.Errorf("the match tiebreak is scored in points, not games")
+		}
+		s.game(int(entry[1] - '1'))
+	default:
+		score, ok := parseSetScore(entry)
+		if !ok {
+			return fmt.Errorf("not a point (1 or 2), game (g1 or g2) or set score (e.g. 6-3)")
+		}
+		if s.Games != [2]int{} || s.Points != [2]int{} {
+			return fmt.Errorf("a set score can only be entered between sets")
+		}
+		if !s.setOver(score[0], score[1]) {
+			return fmt.Errorf("%s isn't a finished set, %s", entry, s.Format)
+		}
+		s.finishSet(score)
+	}
+	s.Log = append(s.Log, entry)
+	return nil
+}
+
+// inMatchTiebreak reports whether the set in play is a match tiebreak.
+func (s *liveScore) inMatchTiebreak() bool {
+	won := s.setsWon()
+	return s.Format.MatchTiebreak && s.Format.BestOf > 1 && len(s.Sets) == s.Format.BestOf-1 && won[0] == won[1]
+}
+
+// inTiebreak reports whether the game in play is a set's tiebreak.
+func (s *liveScore) inTiebreak() bool {
+	return !s.inMatchTiebreak() && s.Games[0] == s.Format.Games && s.Games[1] == s.Format.Games
+}
+
+func (s *liveScore) point(side int) {
+	s.Points[side]++
+	p, o := s.Points[side], s.Points[1-side]
+	switch {
+	case s.inMatchTiebreak():
+		if p >= 10 && p-o >= 2 {
+			s.finishSet(s.Points[:])
+		}
+	case s.inTiebreak():
+		if p >= 7 && p-o >= 2 {
+			s.game(side)
+		}
+	case p >= 4 && p-o >= 2:
+		s.game(side)
+	}
+}
+
+func (s *liveScore) game(side int) {
+	s.Points = [2]int{}
+	s.Games[side]++
+	if s.setOver(s.Games[0], s.Games[1]) {
+		s.finishSet(s.Games[:])
+	}
+}
+
+// setOver reports whether a set with these games is finished: first to
+// the format's games by two, or one more after a tiebreak or a 5-5 (7-5,
+// 7-6). A match tiebreak is over at 10 points by two.
+func (s *liveScore) setOver(g1, g2 int) bool {
+	won, lost := max(g1, g2), min(g1, g2)
+	if s.inMatchTiebreak() {
+		return won >= 10 && won-lost >= 2
+	}
+	return (won >= s.Format.Games && won-lost >= 2 && won <= s.Format.Games+1) ||
+		(won == s.Format.Games+1 && lost == s.Format.Games)
+}
+
+func (s *liveScore) finishSet(score []int) {
+	s.Sets = append(s.Sets, []int{score[0], score[1]})
+	s.Games, s.Points = [2]int{}, [2]int{}
+	won := s.setsWon()
+	need := s.Format.BestOf/2 + 1
+	switch {
+	case won[0] == need:
+		s.Winner = 1
+	case won[1] == need:
+		s.Winner = 2
+	}
+}
+
+func (s *liveScore) setsWon() [2]int {
+	var won [2]int
+	for _, set := range s.Sets {
+		if set[0] > set[1] {
+			won[0]++
+		} else if set[1] > set[0] {
+			won[1]++
+		}
+	}
+	return won
+}
+
+// FinalSets is the sets to record: the finished ones, and the set in play
+// if any games of it were played, for a match ended early.
+func (s *liveScore) FinalSets() []string {
+	var sets []string
+	for _, set := range s.Sets {
+		sets = append(sets, fmt.Sprintf("%d-%d", set[0], set[1]))
+	}
+	if s.Winner == 0 && s.Games != [2]int{} {
+		sets = append(sets, fmt.Sprintf("%d-%d", s.Games[0], s.Games[1]))
+	}
+	return sets
+}
+
+// pointsShown is the score of the game in play for each side: 0, 15, 30,
+// 40 and Ad in a game, or the points in a tiebreak. It's empty between
+// points of a game not yet started.
+func (s *liveScore) pointsShown() [2]string {
+	p := s.Points
+	if s.inTiebreak() || s.inMatchTiebreak() {
+		return [2]string{strconv.Itoa(p[0]), strconv.Itoa(p[1])}
+	}
+	if p[0] >= 3 && p[1] >= 3 {
+		switch {
+		case p[0] > p[1]:
+			return [2]string{"Ad", ""}
+		case p[1] > p[0]:
+			return [2]string{"", "Ad"}
+		}
+		return [2]string{"40", "40"}
+	}
+	calls := []string{"0", "15", "30", "40"}
+	return [2]string{calls[p[0]], calls[p[1]]}
+}
+
+// scoreboard lays the score out as rows, one per side: the side's name,
+// each set's games and, while the match is in play, the set and game in
+// play. header names the columns.
+func (s *liveScore) scoreboard(names [2]string) (header []string, rows [2][]string) {
+	header = []string{""}
+	rows = [2][]string{{names[0]}, {names[1]}}
+	for i, set := range s.Sets {
+		header = append(header, fmt.Sprintf("Set %d", i+1))
+		rows[0] = append(rows[0], strconv.Itoa(set[0]))
+		rows[1] = append(rows[1], strconv.Itoa(set[1]))
+	}
+	if s.Winner != 0 {
+		return header, rows
+	}
+	if s.inMatchTiebreak() {
+		header = append(header, "Match tiebreak")
+		rows[0] = append(rows[0], strconv.Itoa(s.Points[0]))
+		rows[1] = append(rows[1], strconv.Itoa(s.Points[1]))
+		return header, rows
+	}
+	game := "Game"
+	if s.inTiebreak() {
+		game = "Tiebreak"
+	}
+	points := s.pointsShown()
+	header = append(header, fmt.Sprintf("Set %d", len(s.Sets)+1), game)
+	rows[0] = append(rows[0], strconv.Itoa(s.Games[0]), points[0])
+	rows[1] = append(rows[1], strconv.Itoa(s.Games[1]), points[1])
+	return header, rows
+}
+
+// Text is the scoreboard for the terminal.
+func (s *liveScore) Text(names [2]string) string {
+	header, rows := s.scoreboard(names)
+	width := len(header[0])
+	for _, r := range rows {
+		width = max(width, len(r[0]))
+	}
+	var b strings.Builder
+	for _, row := range append([][]string{header}, rows[0], rows[1]) {
+		fmt.Fprintf(&b, "  %-*s", width, row[0])
+		for i, cell := range row[1:] {
+			fmt.Fprintf(&b, "  %*s", max(len(header[i+1]), 2), cell)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Markdown is the scoreboard as a markdown table, for the live comment.
+func (s *liveScore) Markdown(names [2]string) string {
+	header, rows := s.scoreboard(names)
+	align := []string{"---"}
+	for range header[1:] {
+		align = append(align, "---:")
+	}
+	var b strings.Builder
+	for _, row := range [][]string{header, align, rows[0], rows[1]} {
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(row, " | "))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -2,22 +2,51 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/google/go-github/v67/github"
 	"github.com/spf13/cobra"
-	"golang.org/x/oauth2"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
 )
 
 const version = "1.0.0"
 
 var (
-	token string
-	owner string
-	repo  string
+	token       string
+	tokenSource string // where token came from, for `tennis auth status`
+	owner       string
+	repo        string
+	dataDir     string
+
+	noCache     bool
+	cacheTTL    time.Duration
+	readOnlyRun bool // the running command is readOnly, so cached reads may be reused
+
+	bulkThrottle *githubapi.Throttle // paces a bulk command's requests; nil for the rest
+
+	traceTarget string    // --trace: "-" for stderr, else a file
+	traceOut    io.Writer // where API requests are logged; nil unless --trace
+	traceFile   *os.File  // the --trace=FILE file, closed as the command exits
+
+	recordDir string // --record: save every GitHub API exchange here as fixtures
+	replayDir string // --replay: answer GitHub API requests from the fixtures here
+
+	timeout       time.Duration      // --timeout: how long the whole command may run; 0 for no limit
+	cancelTimeout context.CancelFunc // releases the --timeout deadline; nil without one
 )
 
 var rootCmd = &cobra.Command{
@@ -26,24 +55,77 @@ var rootCmd = &cobra.Command{
 	Short:   "Tennis repository CLI tool",
 	Long:    "A CLI tool to interact with the tennis repository - trigger workflows and create match issues",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// Skip token validation for version and help commands
-		if cmd.Name() == "version" || cmd.Name() == "help" {
+		commandStarted = true
+		if errorFormat != "text" && errorFormat != "json" {
+			return invalidf("unknown --error-format '%s'. Use text or json", errorFormat)
+		}
+		if traceTarget == "-" {
+			traceOut = os.Stderr
+		} else if traceTarget != "" {
+			f, err := os.OpenFile(traceTarget, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+			if err != nil {
+				return fmt.Errorf("failed to open the trace file: %w", err)
+			}
+			traceOut, traceFile = f, f
+		}
+		if timeout < 0 {
+			return invalidf("--timeout can't be negative")
+		}
+		if timeout > 0 {
+			cause := fmt.Errorf("%w after %s (--timeout)", errTimedOut, timeout)
+			ctx, cancel := context.WithTimeoutCause(cmd.Context(), timeout, cause)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
+		if recordDir != "" && replayDir != "" {
+			return invalidf("--record and --replay can't be used together")
+		}
+		if replayDir != "" {
+			if info, err := os.Stat(replayDir); err != nil || !info.IsDir() {
+				return invalidf("--replay %s is not a directory of recorded fixtures", replayDir)
+			}
+		}
+		readOnlyRun = readOnly(cmd)
+		if cmd.Flags().Lookup("writes-per-second") != nil && replayDir == "" {
+			rate, _ := cmd.Flags().GetFloat64("writes-per-second")
+			if rate <= 0 {
+				return invalidf("--writes-per-second must be more than 0")
+			}
+			bulkThrottle = githubapi.NewThrottle(rate)
+		}
+
+		// Skip token validation for commands that never contact GitHub,
+		// and for auth login and logout, which manage the token themselves
+		if cmd.Name() == "version" || cmd.Name() == "help" || cmd.Name() == "bench" ||
+			(cmd.Parent() == authCmd && cmd != authStatusCmd) {
 			return nil
 		}
 
 		// Get token from environment if not provided. A dry run never
-		// contacts GitHub, so a token isn't required for it.
+		// contacts GitHub, so a token isn't required for it, and read-only
+		// commands fall back to unauthenticated requests. Nor does a
+		// replay, which answers from recorded fixtures.
+		tokenSource = "--token"
 		if token == "" {
-			token = os.Getenv("GITHUB_TOKEN")
+			token, tokenSource = os.Getenv("GITHUB_TOKEN"), "GITHUB_TOKEN"
 			if token == "" {
-				token = os.Getenv("GH_TOKEN")
+				token, tokenSource = os.Getenv("GH_TOKEN"), "GH_TOKEN"
+			}
+			// Then the token saved by `tennis auth login`
+			if token == "" {
+				var store string
+				token, store = storedToken()
+				tokenSource = "the " + store
 			}
 			// Fall back to the gh CLI's stored token if available
 			if token == "" {
-				token = ghAuthToken()
+				token, tokenSource = ghAuthToken(), "the gh CLI"
 			}
-			if token == "" && !dryRun {
-				return fmt.Errorf("GitHub token required. Set GITHUB_TOKEN, run `gh auth login`, or use --token flag")
+			if token == "" {
+				tokenSource = ""
+				if !dryRun && replayDir == "" && cmd != authStatusCmd && !readOnly(cmd) {
+					return errNoToken
+				}
 			}
 		}
 
@@ -80,7 +162,7 @@ var rootCmd = &cobra.Command{
 			repo = "tennis"
 		}
 
-		return nil
+		return loadLeagueSettings()
 	},
 }
 
@@ -138,13 +220,78 @@ func splitRepoString(repoString string) []string {
 	return []string{repoString}
 }
 
+// getGitHubClient returns a client for the GitHub API. Requests retry
+// transient failures, wait out rate limits and time out consistently
+// across commands (see internal/githubapi).
 func getGitHubClient() *github.Client {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc)
+	var opts githubapi.Options
+	if !noCache {
+		opts.CacheDir = githubCacheDir()
+		// Only commands that just read can use a response without checking
+		// it's still current.
+		if readOnlyRun {
+			opts.CacheTTL = cacheTTL
+		}
+	}
+	if token == "" && replayDir == "" {
+		anonymousNotice.Do(func() {
+			fmt.Fprintln(os.Stderr, "ℹ️  No GitHub token: reading public data unauthenticated (60 requests an hour)")
+		})
+		// The hourly quota is too small to hold any in reserve.
+		opts.Reserve = -1
+	}
+	opts.Throttle = bulkThrottle
+	opts.Trace, opts.Record, opts.Replay = traceOut, recordDir, replayDir
+	return githubapi.New(token, opts)
+}
+
+// downloadClient is an http.Client for the links the API hands out, such
+// as archives and release assets, traced, recorded and replayed like API
+// requests.
+func downloadClient(timeout time.Duration) *http.Client {
+	opts := githubapi.Options{Trace: traceOut, Record: recordDir, Replay: replayDir}
+	return &http.Client{Timeout: timeout, Transport: githubapi.Network(opts)}
+}
+
+var anonymousNotice sync.Once
+
+// githubCacheDir is where API responses are cached, one directory per
+// token so no token reads what another was allowed to see, or "" if the
+// user has no cache directory.
+func githubCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	id := "public"
+	if token != "" {
+		sum := sha256.Sum256([]byte(token))
+		id = hex.EncodeToString(sum[:8])
+	}
+	return filepath.Join(dir, "tennis", "github", id)
+}
+
+// readOnly reports whether cmd only reads league data. Read-only commands
+// also run without a token, against public repositories, and reuse cached
+// API responses for up to --cache-ttl.
+func readOnly(cmd *cobra.Command) bool {
+	switch cmd {
+	case rankingsComputeCmd, rankingsSnapshotListCmd, rankingsSnapshotShowCmd,
+		statsPlayerCmd, statsPartnersCmd, recentCmd, activityCmd, todayCmd, reportSeasonCmd,
+		standingsExportCmd, playerListCmd, fixtureListCmd, auditCmd,
+		verifyRankingsCmd, verifyMatchCmd, matchCardCmd, tournamentStatusCmd,
+		boxesStandingsCmd, divisionsStandingsCmd, swissStandingsCmd, reposListCmd, inboxCmd,
+		profileShowCmd, exportMyDataCmd, simulateSeasonCmd, rankingsExplainCmd, rankingsUpcomingDropsCmd, rankingsChangelogCmd, handicapCmd, teamStandingsCmd, permalinkCmd, leaderboardCmd:
+		return true
+	case statsUpsetsCmd:
+		label, _ := cmd.Flags().GetBool("label")
+		return !label
+	case rankingsSnapshotSaveCmd:
+		// Saving to the league checkout writes only a local file.
+		release, _ := cmd.Flags().GetBool("release")
+		return !release
+	}
+	return false
 }
 
 var versionCmd = &cobra.Command{
@@ -160,13 +307,53 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&token, "token", "", "GitHub token")
 	rootCmd.PersistentFlags().StringVar(&owner, "owner", "", "Repository owner")
 	rootCmd.PersistentFlags().StringVar(&repo, "repo", "", "Repository name")
+	rootCmd.PersistentFlags().IntVar(&githubapi.Workers, "workers", githubapi.Workers, "Maximum concurrent GitHub API requests when fetching many pages")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Don't ask before destructive or bulk changes (also set by NONINTERACTIVE=1)")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "text", "How to print errors: text, or json for a machine-readable object on stderr")
+	// main reports errors itself, with their exit codes.
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Always fetch from the GitHub API instead of reusing cached responses")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", 5*time.Minute, "How long read-only commands reuse a cached API response without revalidating it")
+	rootCmd.PersistentFlags().StringVar(&traceTarget, "trace", "", "Log every GitHub API request (method, URL, status, rate limit, duration) to stderr, or to a file with --trace=FILE")
+	rootCmd.PersistentFlags().Lookup("trace").NoOptDefVal = "-"
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Save every GitHub API request and response to this directory as JSON fixtures")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Answer GitHub API requests from the fixtures in this directory instead of GitHub (no token or network needed)")
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Don't report the progress of long-running operations on stderr")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Give up on the command after this long, e.g. 30s or 10m (watch and serve stop cleanly); 0 for no limit")
+	rootCmd.PersistentFlags().StringVar(&dataDir, "dir", "", "Path to the league checkout holding match data (defaults to the current git checkout)")
 
 	rootCmd.AddCommand(versionCmd)
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	// Ctrl-C (or SIGTERM) cancels in-flight requests and rate-limit waits,
+	// so commands stop cleanly; a second one kills the process as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	interrupted := ctx.Err() != nil
+	stop()
+	if cancelTimeout != nil {
+		// Errors from the deadline itself (rather than a request's) say why.
+		if cause := context.Cause(cmd.Context()); err != nil && errors.Is(cause, errTimedOut) && !errors.Is(err, errTimedOut) {
+			err = fmt.Errorf("%w: %w", cause, err)
+		}
+		cancelTimeout()
+	}
+	if traceFile != nil {
+		if cerr := traceFile.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to write the trace file: %w", cerr)
+		}
+	}
+	if err != nil {
+		if interrupted {
+			err = fmt.Errorf("%w: %w", errInterrupted, err)
+		}
+		os.Exit(reportError(cmd, err))
 	}
 }
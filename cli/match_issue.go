@@ -0,0 +1,357 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MatchIssue is a match issue body parsed the same way the issue-to-PR
+// workflow's scripts/parse_*_issue.py parse it.
+type MatchIssue struct {
+	Kind    string // "singles" or "doubles"
+	Date    string
+	Time    string // HH:MM, from the optional match time section
+	Surface string // from the optional surface section
+	Players []string
+	Team1   []string
+	Team2   []string
+	Sets    [][]int
+	BadSets []string // set lines that aren't <games>-<games>
+	Forfeit []string // the side that didn't turn up, from the optional forfeit section
+
+	hasTeams bool // doubles: a Teams line was present
+}
+
+var (
+	issueDateRegex    = regexp.MustCompile(`### Match date \(YYYY-MM-DD\)\s*\n\s*([0-9]{4}-[0-9]{2}-[0-9]{2})`)
+	issueTimeRegex    = regexp.MustCompile(`### Match time \(HH:MM\)\s*\n\s*([^\n]+)`)
+	issuePlayersRegex = regexp.MustCompile(`### Players.*?\n\s*([^\n]+)`)
+	issueTeamsRegex   = regexp.MustCompile(`### Teams.*?\n\s*([^\n]+)`)
+	issueSetsRegex    = regexp.MustCompile(`(?s)### Sets.*?\n(.*?)(?:\n###|\z)`)
+	issueForfeitRegex = regexp.MustCompile(`### Forfeit \(no-show\)\s*\n\s*([^\n]+)`)
+	issueSurfaceRegex = regexp.MustCompile(`### Surface\s*\n\s*([^\n]+)`)
+)
+
+// parseMatchIssue parses a singles or doubles match issue body. Fields that
+// can't be found are left empty; validate reports what's wrong.
+func parseMatchIssue(kind, body string) MatchIssue {
+	m := MatchIssue{Kind: kind}
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+
+	if g := issueDateRegex.FindStringSubmatch(body); g != nil {
+		m.Date = strings.TrimSpace(g[1])
+	}
+	if g := issueTimeRegex.FindStringSubmatch(body); g != nil {
+		m.Time = strings.TrimSpace(g[1])
+	}
+	if g := issueForfeitRegex.FindStringSubmatch(body); g != nil {
+		m.Forfeit = splitHandles(g[1])
+	}
+	if g := issueSurfaceRegex.FindStringSubmatch(body); g != nil {
+		m.Surface = strings.ToLower(strings.TrimSpace(g[1]))
+	}
+
+	if kind == "doubles" {
+		if g := issueTeamsRegex.FindStringSubmatch(body); g != nil {
+			if parts := strings.Split(strings.TrimSpace(g[1]), "||"); len(parts) == 2 {
+				m.hasTeams = true
+				m.Team1 = splitHandles(parts[0])
+				m.Team2 = splitHandles(parts[1])
+				m.Players = append(append([]string{}, m.Team1...), m.Team2...)
+			}
+		}
+	} else if g := issuePlayersRegex.FindStringSubmatch(body); g != nil {
+		m.Players = splitHandles(g[1])
+	}
+
+	if g := issueSetsRegex.FindStringSubmatch(body); g != nil {
+		for _, line := range strings.Split(strings.TrimSpace(g[1]), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if s, ok := parseSetScore(line); ok {
+				m.Sets = append(m.Sets, s)
+			} else {
+				m.BadSets = append(m.BadSets, line)
+			}
+		}
+	}
+	return m
+}
+
+func splitHandles(s string) []string {
+	var handles []string
+	for _, p := range strings.Split(s, ",") {
+		handles = append(handles, normalizePlayer(p))
+	}
+	return handles
+}
+
+// parseSetScore parses "6-3" into [6 3].
+func parseSetScore(s string) ([]int, bool) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return nil, false
+	}
+	g1, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	g2, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || g1 < 0 || g2 < 0 {
+		return nil, false
+	}
+	return []int{g1, g2}, true
+}
+
+// Match converts the parsed issue into the match file representation.
+func (m MatchIssue) Match() Match {
+	match := Match{Date: m.Date, Time: m.Time, Surface: m.Surface, Sets: m.Sets, Forfeit: m.Forfeit}
+	if m.Kind == "doubles" {
+		match.Team1, match.Team2 = m.Team1, m.Team2
+	} else {
+		match.Players = m.Players
+	}
+	return match
+}
+
+// issueProblem is one thing wrong with a match issue.
+type issueProblem struct {
+	Code    string
+	Message string
+}
+
+// validate reports everything that would stop the issue being recorded, or
+// would record it wrongly, including breaches of the league's rules.
+func (m MatchIssue) validate() []issueProblem {
+	var problems []issueProblem
+	add := func(code, format string, args ...interface{}) {
+		problems = append(problems, issueProblem{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if m.Date == "" {
+		add("missing_date", "match date is missing or not YYYY-MM-DD")
+	} else if !isValidDate(m.Date) {
+		add("invalid_date", "match date %s is not a real date", m.Date)
+	}
+	if m.Time != "" {
+		if _, err := parseTimeOfDay(m.Time); err != nil {
+			add("invalid_time", "match time %q is not HH:MM", m.Time)
+		}
+	}
+	if m.Surface != "" && !containsString(surfaces, m.Surface) {
+		add("invalid_surface", "surface %q is not one of %s", m.Surface, strings.Join(surfaces, ", "))
+	}
+
+	if m.Kind == "doubles" {
+		if !m.hasTeams {
+			add("missing_players", "teams line is missing or not in '@a, @b || @c, @d' form")
+		} else if len(m.Team1) != 2 || len(m.Team2) != 2 {
+			add("wrong_player_count", "each team must have exactly 2 players")
+		}
+	} else if len(m.Players) != 2 {
+		add("wrong_player_count", "exactly 2 players must be listed, found %d", len(m.Players))
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range m.Players {
+		if p == "" {
+			add("missing_players", "a player handle is empty")
+			continue
+		}
+		if seen[p] {
+			add("self_match", "@%s is listed more than once", p)
+		}
+		seen[p] = true
+	}
+
+	for _, s := range m.BadSets {
+		add("invalid_set", "set %q is not in <games>-<games> form", s)
+	}
+	if len(m.Sets) == 0 && len(m.BadSets) == 0 {
+		add("missing_sets", "no sets recorded")
+	}
+	for _, s := range m.Sets {
+		if s[0] == s[1] {
+			add("tied_set", "set %d-%d has no winner", s[0], s[1])
+		}
+	}
+
+	if len(m.Sets) > 0 && len(m.BadSets) == 0 {
+		if matchWinner(m.Match()) == 0 {
+			add("missing_winner", "sets are split evenly, so the match has no winner")
+		}
+	}
+
+	if len(m.Forfeit) > 0 {
+		absent := m.Players[len(m.Players)/2:]
+		if m.Kind == "doubles" {
+			absent = m.Team2
+		}
+		named := len(m.Forfeit) == len(absent)
+		for _, p := range m.Forfeit {
+			named = named && containsString(absent, p)
+		}
+		if !named {
+			add("invalid_forfeit", "the forfeit section must name the side listed second, which didn't turn up")
+		} else if len(m.Sets) > 0 && len(m.BadSets) == 0 && matchWinner(m.Match()) != 1 {
+			add("invalid_forfeit", "a forfeit is a win for the side listed first, which turned up")
+		}
+	}
+
+	// The league's rules only apply to an otherwise valid match.
+	if len(problems) == 0 {
+		problems = rules.problems(m.Kind, m.Match())
+	}
+	return problems
+}
+
+// Body formats the match as a canonical issue body, the layout
+// "tennis match" creates.
+func (m MatchIssue) Body() string {
+	at := func(handles []string) []string {
+		var out []string
+		for _, h := range handles {
+			out = append(out, "@"+h)
+		}
+		return out
+	}
+	var sets []string
+	for _, s := range m.Sets {
+		sets = append(sets, fmt.Sprintf("%d-%d", s[0], s[1]))
+	}
+	if m.Kind == "doubles" {
+		return withForfeit(withSurface(doublesIssueBody(m.Date, m.Time, [][]string{at(m.Team1), at(m.Team2)}, sets), m.Surface), at(m.Forfeit))
+	}
+	return withForfeit(withSurface(singlesIssueBody(m.Date, m.Time, at(m.Players), sets), m.Surface), at(m.Forfeit))
+}
+
+var (
+	looseDateRegex   = regexp.MustCompile(`(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})`)
+	looseHandleRegex = regexp.MustCompile(`@([A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)`)
+	bareHandleRegex  = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?$`)
+	handleSepRegex   = regexp.MustCompile(`(?i)\|\||[|,;&\n]|\s+vs\.?\s+|\s+and\s+`)
+	looseSetRegex    = regexp.MustCompile(`(\d{1,2})\s*[-–—:/]\s*(\d{1,2})`)
+	parenRegex       = regexp.MustCompile(`\([^)]*\)`)
+)
+
+// issueSection is one "### Heading" section of an issue body, its heading
+// lower-cased.
+type issueSection struct {
+	heading, content string
+}
+
+// issueSections splits an issue body into its "### Heading" sections, in
+// the order they appear.
+func issueSections(body string) []issueSection {
+	var sections []issueSection
+	var heading string
+	var content []string
+	flush := func() {
+		if heading != "" {
+			sections = append(sections, issueSection{heading, strings.TrimSpace(strings.Join(content, "\n"))})
+		}
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "###") {
+			flush()
+			heading = strings.ToLower(strings.TrimSpace(strings.TrimLeft(line, "#")))
+			content = nil
+			continue
+		}
+		content = append(content, line)
+	}
+	flush()
+	return sections
+}
+
+// section returns the content of the first section whose heading starts
+// with one of the prefixes. Headings are matched by prefix, not anywhere,
+// because a heading's explanation can name other sections: "Sets (one line
+// per set, player1's games first)" isn't the players section.
+func section(sections []issueSection, prefixes ...string) string {
+	for _, s := range sections {
+		for _, p := range prefixes {
+			if strings.HasPrefix(s.heading, p) {
+				return s.content
+			}
+		}
+	}
+	return ""
+}
+
+// repairMatchIssue re-reads a malformed match issue leniently: dates like
+// 2025/8/5, handles separated by "vs", "and" or new lines, and scores such
+// as "6–3", "6:3" or "6-3, 6-4" on one line. ok is false unless the result
+// is a complete, valid match that agrees with everything the strict parser
+// could already read, so only unambiguous fixes are made.
+func repairMatchIssue(kind, body string) (m MatchIssue, ok bool) {
+	strict := parseMatchIssue(kind, body)
+	sections := issueSections(body)
+	m = MatchIssue{Kind: kind, Time: strict.Time, Surface: strict.Surface, Forfeit: strict.Forfeit}
+
+	if g := looseDateRegex.FindStringSubmatch(section(sections, "match date", "date")); g != nil {
+		y, _ := strconv.Atoi(g[1])
+		mo, _ := strconv.Atoi(g[2])
+		d, _ := strconv.Atoi(g[3])
+		m.Date = fmt.Sprintf("%04d-%02d-%02d", y, mo, d)
+	}
+
+	handles := looseHandles(section(sections, "players", "teams"))
+	if kind == "doubles" {
+		if len(handles) == 4 {
+			m.hasTeams = true
+			m.Team1, m.Team2 = handles[:2], handles[2:]
+			m.Players = handles
+		}
+	} else {
+		m.Players = handles
+	}
+
+	sets := parenRegex.ReplaceAllString(section(sections, "sets", "score"), "")
+	for _, g := range looseSetRegex.FindAllStringSubmatch(sets, -1) {
+		g1, _ := strconv.Atoi(g[1])
+		g2, _ := strconv.Atoi(g[2])
+		m.Sets = append(m.Sets, []int{g1, g2})
+	}
+
+	if len(m.validate()) > 0 {
+		return m, false
+	}
+	if strict.Date != "" && strict.Date != m.Date {
+		return m, false
+	}
+	if len(strict.Sets) > 0 && len(strict.BadSets) == 0 && fmt.Sprint(strict.Sets) != fmt.Sprint(m.Sets) {
+		return m, false
+	}
+	for _, p := range strict.Players {
+		if bareHandleRegex.MatchString(p) && !containsString(m.Players, p) {
+			return m, false
+		}
+	}
+	return m, true
+}
+
+// looseHandles extracts player handles from a players or teams section.
+// When any handle is @-prefixed only @handles are taken, so words such as
+// "vs" are never mistaken for players.
+func looseHandles(s string) []string {
+	var handles []string
+	if strings.Contains(s, "@") {
+		for _, g := range looseHandleRegex.FindAllStringSubmatch(s, -1) {
+			handles = append(handles, normalizePlayer(g[1]))
+		}
+		return handles
+	}
+	for _, part := range handleSepRegex.Split(s, -1) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !bareHandleRegex.MatchString(part) {
+			return nil
+		}
+		handles = append(handles, normalizePlayer(part))
+	}
+	return handles
+}
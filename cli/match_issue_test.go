@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// The sets heading of the issue forms mentions "player" and "team", so a
+// repair must find the players by their own heading, every time.
+func TestRepairMatchIssueFormHeadings(t *testing.T) {
+	tests := []struct {
+		form    issueForm
+		players string
+		want    string
+	}{
+		{singlesForm, "@alice vs @bob", "[alice bob]"},
+		{doublesForm, "@alice and @carol vs @bob and @dave", "[alice carol bob dave]"},
+	}
+	for _, tt := range tests {
+		body := tt.form.Body("2025/8/5", tt.players, "6-3\n6-4")
+		for i := 0; i < 100; i++ {
+			m, ok := repairMatchIssue(tt.form.Kind, body)
+			if !ok {
+				t.Fatalf("%s: repair refused %q", tt.form.Kind, body)
+			}
+			if got := fmt.Sprint(m.Players); got != tt.want {
+				t.Fatalf("%s: players = %s, want %s", tt.form.Kind, got, tt.want)
+			}
+		}
+	}
+}
+
+// A repair never replaces players the strict parse could already read.
+func TestRepairMatchIssueKeepsStrictPlayers(t *testing.T) {
+	body := singlesForm.Body("2025-08-05", "alice\n@carol vs @bob", "6-3\n6-4")
+	if m, ok := repairMatchIssue("singles", body); ok {
+		t.Fatalf("repair accepted players %v, want it refused", m.Players)
+	}
+}
+
+// A repair keeps the parts of the issue it has nothing to fix.
+func TestRepairMatchIssueKeepsTime(t *testing.T) {
+	body := withMatchTime(singlesForm.Body("2025/8/5", "@alice vs @bob", "6-3\n6-4"), "18:30")
+	m, ok := repairMatchIssue("singles", body)
+	if !ok {
+		t.Fatalf("repair refused %q", body)
+	}
+	if got := parseMatchIssue("singles", m.Body()).Time; got != "18:30" {
+		t.Fatalf("time after repair = %q, want 18:30", got)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"sort"
+)
+
+// partnerRecord is how a player has done in doubles with one partner.
+type partnerRecord struct {
+	Partner   string
+	Won       int
+	Lost      int
+	Drawn     int
+	SetsWon   int
+	SetsLost  int
+	Opponents map[string][2]int // won and lost against each opponent
+}
+
+func (r partnerRecord) Played() int {
+	return r.Won + r.Lost + r.Drawn
+}
+
+// WinRate is the share of decided matches won, or 0 if none were decided.
+func (r partnerRecord) WinRate() float64 {
+	if r.Won+r.Lost == 0 {
+		return 0
+	}
+	return float64(r.Won) / float64(r.Won+r.Lost)
+}
+
+// partnerRecords tallies player's doubles matches by partner, most played
+// first, then by win rate and handle.
+func partnerRecords(player string, doubles []Match) []partnerRecord {
+	byPartner := make(map[string]*partnerRecord)
+	for _, m := range doubles {
+		if len(m.Team1) != 2 || len(m.Team2) != 2 {
+			continue
+		}
+		mine, theirs, side := m.Team1, m.Team2, 1
+		if !containsString(mine, player) {
+			mine, theirs, side = m.Team2, m.Team1, 2
+		}
+		if !containsString(mine, player) {
+			continue
+		}
+		partner := mine[0]
+		if partner == player {
+			partner = mine[1]
+		}
+		r := byPartner[partner]
+		if r == nil {
+			r = &partnerRecord{Partner: partner, Opponents: make(map[string][2]int)}
+			byPartner[partner] = r
+		}
+		for _, s := range m.Sets {
+			if len(s) != 2 || s[0] == s[1] {
+				continue
+			}
+			if (s[0] > s[1]) == (side == 1) {
+				r.SetsWon++
+			} else {
+				r.SetsLost++
+			}
+		}
+		switch w := matchWinner(m); {
+		case w == 0:
+			r.Drawn++
+			continue
+		case w == side:
+			r.Won++
+		default:
+			r.Lost++
+		}
+		for _, o := range theirs {
+			wl := r.Opponents[o]
+			if matchWinner(m) == side {
+				wl[0]++
+			} else {
+				wl[1]++
+			}
+			r.Opponents[o] = wl
+		}
+	}
+
+	var out []partnerRecord
+	for _, r := range byPartner {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Played() != b.Played() {
+			return a.Played() > b.Played()
+		}
+		if a.WinRate() != b.WinRate() {
+			return a.WinRate() > b.WinRate()
+		}
+		return a.Partner < b.Partner
+	})
+	return out
+}
+
+// bestAndWorstPartners returns the partnerships of at least min decided
+// matches with the highest and lowest win rates, or nil if there are none.
+// More matches break ties, so a long record beats a short one.
+func bestAndWorstPartners(records []partnerRecord, min int) (best, worst *partnerRecord) {
+	for i := range records {
+		r := &records[i]
+		if r.Won+r.Lost < min {
+			continue
+		}
+		if best == nil || r.WinRate() > best.WinRate() ||
+			r.WinRate() == best.WinRate() && r.Played() > best.Played() {
+			best = r
+		}
+		if worst == nil || r.WinRate() < worst.WinRate() ||
+			r.WinRate() == worst.WinRate() && r.Played() > worst.Played() {
+			worst = r
+		}
+	}
+	return best, worst
+}
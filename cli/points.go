@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	rankByRating = "rating" // rank the leaderboards on Elo ratings
+	rankByPoints = "points" // rank the leaderboards on ranking points
+)
+
+// pointsRounds are the keys of pointsRules.Rounds: a knockout tournament's
+// last three rounds, and "round" for any earlier one.
+var pointsRounds = []string{"final", "semifinal", "quarterfinal", "round"}
+
+// pointsRules award ranking points for wins, set in the points section of
+// .tennis.yml, as an alternative to Elo: Win points for a league match, or
+// Rounds points for a knockout tournament match by its round, times the
+// multiplier of the highest Opponents band the beaten side's rating reached
+// going in. Points drop off WindowDays days after the win, 52 weeks unless
+// set. The zero value disables points.
+type pointsRules struct {
+	RankBy     string             `yaml:"rank_by,omitempty" json:"rank_by,omitempty"` // rankByRating (the default) or rankByPoints
+	Win        float64            `yaml:"win,omitempty" json:"win"`
+	Rounds     map[string]float64 `yaml:"rounds,omitempty" json:"rounds,omitempty"`
+	Opponents  []opponentBand     `yaml:"opponents,omitempty" json:"opponents,omitempty"`
+	WindowDays int                `yaml:"window_days,omitempty" json:"window_days"`
+}
+
+// opponentBand multiplies the points for beating a side rated at least
+// MinRating.
+type opponentBand struct {
+	MinRating  float64 `yaml:"min_rating" json:"min_rating"`
+	Multiplier float64 `yaml:"multiplier" json:"multiplier"`
+}
+
+// rankingPoints is the league's points rules, loaded with elo.
+var rankingPoints pointsRules
+
+// check validates the rules and orders the opponent bands highest first.
+func (p *pointsRules) check() error {
+	switch {
+	case p.RankBy != "" && p.RankBy != rankByRating && p.RankBy != rankByPoints:
+		return fmt.Errorf("points.rank_by must be %s or %s, got %q", rankByRating, rankByPoints, p.RankBy)
+	case p.Win < 0:
+		return fmt.Errorf("points.win can't be negative")
+	case p.WindowDays < 0:
+		return fmt.Errorf("points.window_days can't be negative")
+	}
+	for round, n := range p.Rounds {
+		if !containsString(pointsRounds, round) {
+			return fmt.Errorf("points.rounds: unknown round %q (use %s)", round, strings.Join(pointsRounds, ", "))
+		}
+		if n < 0 {
+			return fmt.Errorf("points.rounds.%s can't be negative", round)
+		}
+	}
+	sort.SliceStable(p.Opponents, func(i, j int) bool { return p.Opponents[i].MinRating > p.Opponents[j].MinRating })
+	for i, b := range p.Opponents {
+		if b.Multiplier <= 0 {
+			return fmt.Errorf("points.opponents: the multiplier from %g must be positive", b.MinRating)
+		}
+		if i > 0 && b.MinRating == p.Opponents[i-1].MinRating {
+			return fmt.Errorf("points.opponents: two bands start at %g", b.MinRating)
+		}
+	}
+	if p.RankBy == rankByPoints && !p.Enabled() {
+		return fmt.Errorf("points.rank_by: %s needs points.win or points.rounds", rankByPoints)
+	}
+	return nil
+}
+
+// defaultPointsWindow is how long points count unless points.window_days
+// says otherwise: 52 weeks, as in the ATP rankings.
+const defaultPointsWindow = 52 * 7
+
+// Window returns the days a win's points count for.
+func (p pointsRules) Window() int {
+	if p.WindowDays == 0 {
+		return defaultPointsWindow
+	}
+	return p.WindowDays
+}
+
+// Enabled reports whether wins earn ranking points.
+func (p pointsRules) Enabled() bool {
+	if p.Win > 0 {
+		return true
+	}
+	for _, n := range p.Rounds {
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// forWin returns the points for beating a side rated opponentRating in a
+// match of the given round ("" for a league match).
+func (p pointsRules) forWin(round string, opponentRating float64) float64 {
+	base := p.Win
+	if n, ok := p.Rounds[round]; ok {
+		base = n
+	}
+	for _, b := range p.Opponents {
+		if opponentRating >= b.MinRating {
+			return base * b.Multiplier
+		}
+	}
+	return base
+}
+
+// tournamentRounds maps the source_issue of every match that decided a
+// knockout tournament slot to its pointsRounds key.
+func tournamentRounds() map[int]string {
+	rounds := make(map[int]string)
+	files, _ := filepath.Glob(filepath.Join(leagueDir(), "tournaments", "*.yml"))
+	for _, f := range files {
+		t, err := loadTournament(strings.TrimSuffix(filepath.Base(f), ".yml"))
+		if err != nil {
+			continue
+		}
+		for i, r := range t.Rounds {
+			round := strings.ToLower(t.RoundName(i))
+			if !containsString(pointsRounds, round) {
+				round = "round"
+			}
+			for _, m := range r.Matches {
+				if m.Result != 0 {
+					rounds[m.Result] = round
+				}
+			}
+		}
+	}
+	return rounds
+}
+
+// pointsAward is the points one player earned for a win, and the day they
+// drop off: WindowDays after the match.
+type pointsAward struct {
+	Player  string
+	Match   Match
+	Round   string // the pointsRounds key, or "" for a league match
+	Beaten  []string
+	Points  float64
+	Expires string
+}
+
+// pointAwards replays matches from the imported ratings and returns every
+// point awarded for a win, in match order. Doubles winners each earn the
+// points for beating the losing team's average rating. A penalized
+// forfeit earns nothing, nor does a match the rankings freeze holds back.
+func pointAwards(matches []Match) []pointsAward {
+	rounds := tournamentRounds()
+	singles, doubles := make(map[string]float64), make(map[string]float64)
+	seedRatings(singles, importedSingles)
+	seedRatings(doubles, importedDoubles)
+
+	var awards []pointsAward
+	for _, m := range matches {
+		ratings, side1, side2 := singles, m.Team1, m.Team2
+		if m.IsDoubles() {
+			ratings = doubles
+		} else if len(m.Players) == 2 {
+			side1, side2 = m.Players[:1], m.Players[1:]
+		}
+		if w := matchWinner(m); w != 0 && len(side1) > 0 && len(side2) > 0 && len(m.scoredSets()) > 0 && !frozen(m) {
+			winners, losers := side1, side2
+			if w == 2 {
+				winners, losers = side2, side1
+			}
+			round := ""
+			if m.Repo == "" && !m.IsDoubles() {
+				round = rounds[m.SourceIssue]
+			}
+			won := rankingPoints.forWin(round, sideRating(ratings, losers))
+			expires := ""
+			if t, err := time.Parse("2006-01-02", m.Date); err == nil {
+				expires = t.AddDate(0, 0, rankingPoints.Window()).Format("2006-01-02")
+			}
+			for _, p := range winners {
+				awards = append(awards, pointsAward{Player: p, Match: m, Round: round, Beaten: losers, Points: won, Expires: expires})
+			}
+		}
+		rateMatch(m, ratings)
+	}
+	return awards
+}
+
+// live reports whether the award counts on asOf: the match was played by
+// then and its points haven't dropped off yet.
+func (a pointsAward) live(asOf string) bool {
+	return a.Match.Date <= asOf && asOf < a.Expires
+}
+
+// matchPoints totals each player's ranking points on asOf.
+func matchPoints(matches []Match, asOf string) map[string]float64 {
+	total := make(map[string]float64)
+	for _, a := range pointAwards(matches) {
+		if a.live(asOf) {
+			total[a.Player] += a.Points
+		}
+	}
+	for p, n := range total {
+		total[p] = math.Round(n*10) / 10
+	}
+	return total
+}
+
+// rankOnPoints reorders a leaderboard by points, then rating, and numbers
+// it again.
+func rankOnPoints(board []LeaderboardRow) {
+	sort.SliceStable(board, func(i, j int) bool { return board[i].Points > board[j].Points })
+	for i := range board {
+		board[i].Rank = i + 1
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// noProgress is --no-progress.
+var noProgress bool
+
+const (
+	progressDelay    = 500 * time.Millisecond // quicker operations show nothing
+	progressRedraw   = 100 * time.Millisecond
+	progressLogEvery = 10 * time.Second
+	progressWidth    = 30
+)
+
+// progress reports how far a long-running operation has got on stderr: a
+// bar redrawn in place when stderr is a terminal, or a line every 10
+// seconds when it's a log or a pipe. Operations over in under half a
+// second show nothing. A nil *progress (from --no-progress) reports
+// nothing, so callers needn't check.
+type progress struct {
+	label   string
+	total   int // 0 if unknown, for a spinner with a count instead of a bar
+	done    int
+	tty     bool
+	started time.Time
+	shown   time.Time // when progress was last drawn or logged
+	drawn   bool      // the bar is on screen
+	logged  bool
+}
+
+// newProgress starts reporting progress through total steps, or an
+// unknown number if total is 0.
+func newProgress(label string, total int) *progress {
+	if noProgress {
+		return nil
+	}
+	fi, err := os.Stderr.Stat()
+	return &progress{
+		label:   label,
+		total:   total,
+		tty:     err == nil && fi.Mode()&os.ModeCharDevice != 0,
+		started: time.Now(),
+	}
+}
+
+// SetTotal sets the number of steps, once it's known.
+func (p *progress) SetTotal(total int) {
+	if p == nil {
+		return
+	}
+	p.total = total
+}
+
+// Add records n more steps done.
+func (p *progress) Add(n int) {
+	if p == nil {
+		return
+	}
+	p.done += n
+	now := time.Now()
+	switch {
+	case now.Sub(p.started) < progressDelay:
+	case p.tty && (!p.drawn || now.Sub(p.shown) >= progressRedraw):
+		p.draw(now)
+	case !p.tty && now.Sub(p.shown) >= progressLogEvery && now.Sub(p.started) >= progressLogEvery:
+		fmt.Fprintf(os.Stderr, "%s: %s\n", p.label, p.count())
+		p.shown, p.logged = now, true
+	}
+}
+
+// Printf prints a line on stdout, above the bar when one is drawn.
+func (p *progress) Printf(format string, args ...any) {
+	if p == nil || !p.drawn {
+		fmt.Printf(format, args...)
+		return
+	}
+	p.Clear()
+	fmt.Printf(format, args...)
+	p.draw(time.Now())
+}
+
+// Clear takes the bar off the screen, so code that prints for itself can
+// run mid-operation. The next Add draws it again.
+func (p *progress) Clear() {
+	if p == nil || !p.drawn {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\r\033[K")
+	p.drawn = false
+}
+
+// Finish ends the report: the bar is left showing the final count, and a
+// logged operation gets a last line saying it's done.
+func (p *progress) Finish() {
+	if p == nil {
+		return
+	}
+	switch {
+	case p.tty && !p.shown.IsZero():
+		p.draw(time.Now())
+		fmt.Fprintln(os.Stderr)
+	case p.logged:
+		fmt.Fprintf(os.Stderr, "%s: %s, done in %s\n", p.label, p.count(), time.Since(p.started).Round(time.Second))
+	}
+	p.shown, p.drawn, p.logged = time.Time{}, false, false
+}
+
+func (p *progress) draw(now time.Time) {
+	bar := string(`|/-\`[int(now.Sub(p.started)/progressRedraw)%4])
+	if p.total > 0 {
+		filled := min(p.done, p.total) * progressWidth / p.total
+		bar = "[" + strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled) + "]"
+	}
+	fmt.Fprintf(os.Stderr, "\r\033[K%s %s %s", p.label, bar, p.count())
+	p.shown, p.drawn = now, true
+}
+
+// count is e.g. "12/40 (30%)", or "12" when the total isn't known.
+func (p *progress) count() string {
+	if p.total == 0 {
+		return fmt.Sprint(p.done)
+	}
+	return fmt.Sprintf("%d/%d (%d%%)", p.done, p.total, min(p.done, p.total)*100/p.total)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pseudonyms maps handles to the names public output shows instead, from
+// .tennis.yml, for leagues whose players would rather not be identified
+// outside the repository. Issues and match files keep the real handles.
+var pseudonyms map[string]string
+
+// checkPseudonyms normalizes the handles of .tennis.yml's pseudonyms and
+// rejects blank or shared pseudonyms.
+func checkPseudonyms(raw map[string]string) (map[string]string, error) {
+	mapped := make(map[string]string, len(raw))
+	seen := make(map[string]string, len(raw))
+	for handle, name := range raw {
+		handle, name = normalizePlayer(handle), strings.TrimSpace(name)
+		if handle == "" || name == "" {
+			return nil, fmt.Errorf("pseudonyms: every entry needs a handle and a pseudonym")
+		}
+		if other, ok := seen[strings.ToLower(name)]; ok && other != handle {
+			return nil, fmt.Errorf("pseudonyms: @%s and @%s can't share the pseudonym %q", other, handle, name)
+		}
+		seen[strings.ToLower(name)] = handle
+		mapped[handle] = name
+	}
+	return mapped, nil
+}
+
+// publicHandle returns the name a player appears under in public output:
+// their pseudonym, or their handle if they have none.
+func publicHandle(handle string) string {
+	if name, ok := pseudonyms[handle]; ok {
+		return name
+	}
+	return handle
+}
+
+// publicName is publicHandle for display, with an @ before real handles.
+func publicName(handle string) string {
+	if name, ok := pseudonyms[handle]; ok {
+		return name
+	}
+	return "@" + handle
+}
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// qrCode is a QR code as a grid of modules, true for dark. It is a
+// minimal, dependency-free encoder for links: byte mode, error correction
+// level M (a smudged or half-lit code still scans) and versions 1 to 10,
+// enough for 213 bytes.
+type qrCode [][]bool
+
+// qrVersions holds, per version from 1, the total codewords and, at level
+// M, the error correction blocks and codewords per block.
+var qrVersions = []struct{ total, blocks, ecc int }{
+	{26, 1, 10}, {44, 1, 16}, {70, 1, 26}, {100, 2, 18}, {134, 2, 24},
+	{172, 4, 16}, {196, 4, 18}, {242, 4, 22}, {292, 5, 22}, {346, 5, 26},
+}
+
+// qrAlignment lists the alignment pattern centres per version from 2.
+var qrAlignment = [][]int{
+	{6, 18}, {6, 22}, {6, 26}, {6, 30}, {6, 34},
+	{6, 22, 38}, {6, 24, 42}, {6, 26, 46}, {6, 28, 50},
+}
+
+// newQRCode encodes text in the smallest version that holds it.
+func newQRCode(text string) (qrCode, error) {
+	version := 0
+	for v, info := range qrVersions {
+		countBits := 8
+		if v+1 >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(text) <= 8*(info.total-info.blocks*info.ecc) {
+			version = v + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%d bytes is too long for a QR code here (at most 213)", len(text))
+	}
+	q := &qrBuilder{version: version, size: 17 + 4*version}
+	q.modules = make([][]bool, q.size)
+	q.function = make([][]bool, q.size)
+	for i := range q.modules {
+		q.modules[i] = make([]bool, q.size)
+		q.function[i] = make([]bool, q.size)
+	}
+	q.drawPatterns()
+	q.drawData(q.codewords(text))
+
+	// Keep the mask that leaves the fewest patterns confusing a scanner.
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormat(mask)
+		if p := q.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		q.applyMask(mask) // masking twice undoes it
+	}
+	q.applyMask(best)
+	q.drawFormat(best)
+	return q.modules, nil
+}
+
+type qrBuilder struct {
+	version, size int
+	modules       [][]bool // [y][x]
+	function      [][]bool // finder, timing, alignment, format and version modules
+}
+
+func (q *qrBuilder) set(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+// drawPatterns draws the modules every code of this version has.
+func (q *qrBuilder) drawPatterns() {
+	for i := 0; i < q.size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+	for _, c := range [][2]int{{3, 3}, {q.size - 4, 3}, {3, q.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x >= 0 && x < q.size && y >= 0 && y < q.size {
+					d := max(abs(dx), abs(dy))
+					q.set(x, y, d != 2 && d != 4)
+				}
+			}
+		}
+	}
+	if q.version > 1 {
+		centres := qrAlignment[q.version-2]
+		last := len(centres) - 1
+		for i, cy := range centres {
+			for j, cx := range centres {
+				if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+					continue // under a finder pattern
+				}
+				for dy := -2; dy <= 2; dy++ {
+					for dx := -2; dx <= 2; dx++ {
+						q.set(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+					}
+				}
+			}
+		}
+	}
+	q.drawFormat(0) // reserves the format modules; redrawn once masked
+	if q.version >= 7 {
+		rem := q.version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := q.version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>i&1 == 1
+			a, b := q.size-11+i%3, i/3
+			q.set(a, b, dark)
+			q.set(b, a, dark)
+		}
+	}
+}
+
+// drawFormat draws both copies of the level and mask.
+func (q *qrBuilder) drawFormat(mask int) {
+	data := mask // level M's bits are 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		q.set(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, q.size-15+i, bit(i))
+	}
+	q.set(8, q.size-8, true)
+}
+
+// codewords encodes text in byte mode, pads it to the version's capacity
+// and interleaves its blocks with their error correction.
+func (q *qrBuilder) codewords(text string) []byte {
+	info := qrVersions[q.version-1]
+	capacity := info.total - info.blocks*info.ecc
+
+	var bits []bool
+	put := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, v>>i&1 == 1)
+		}
+	}
+	put(0b0100, 4)
+	if q.version >= 10 {
+		put(len(text), 16)
+	} else {
+		put(len(text), 8)
+	}
+	for i := 0; i < len(text); i++ {
+		put(int(text[i]), 8)
+	}
+	put(0, min(4, 8*capacity-len(bits)))
+	put(0, (8-len(bits)%8)%8)
+	data := make([]byte, 0, capacity)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for _, dark := range bits[i : i+8] {
+			b <<= 1
+			if dark {
+				b |= 1
+			}
+		}
+		data = append(data, b)
+	}
+	for pad := byte(0xEC); len(data) < capacity; pad ^= 0xEC ^ 0x11 {
+		data = append(data, pad)
+	}
+
+	// Blocks differ in length by at most one data codeword, the short
+	// ones first.
+	short := info.blocks - info.total%info.blocks
+	shortLen := info.total/info.blocks - info.ecc
+	var blocks [][]byte
+	for i, k := 0, 0; i < info.blocks; i++ {
+		n := shortLen
+		if i >= short {
+			n++
+		}
+		blocks = append(blocks, append(append([]byte{}, data[k:k+n]...), reedSolomon(data[k:k+n], info.ecc)...))
+		k += n
+	}
+	var out []byte
+	for i := 0; i < shortLen+1; i++ {
+		for j, b := range blocks {
+			if i < shortLen || j >= short {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecc; i++ {
+		for j, b := range blocks {
+			n := shortLen
+			if j >= short {
+				n++
+			}
+			out = append(out, b[n+i])
+		}
+	}
+	return out
+}
+
+// drawData lays the codewords out in the zigzag of two-module columns
+// from the bottom right, skipping the function modules.
+func (q *qrBuilder) drawData(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // the vertical timing pattern
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] && i < len(data)*8 {
+					q.modules[y][x] = data[i>>3]>>(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (q *qrBuilder) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip && !q.function[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the code by the spec's four rules: long runs, 2x2
+// blocks, finder-like patterns and an unbalanced dark share.
+func (q *qrBuilder) penalty() int {
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return q.modules[x][y]
+		}
+		return q.modules[y][x]
+	}
+	finder := []bool{true, false, true, true, true, false, true}
+	score, dark := 0, 0
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < q.size; y++ {
+			run := 0
+			for x := 0; x < q.size; x++ {
+				if x > 0 && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+				} else {
+					run = 1
+				}
+				if run == 5 {
+					score += 3
+				} else if run > 5 {
+					score++
+				}
+				if x+7 > q.size {
+					continue
+				}
+				match := true
+				for k, d := range finder {
+					if at(x+k, y, transpose) != d {
+						match = false
+						break
+					}
+				}
+				if match && (q.light(x-4, x, y, transpose) || q.light(x+7, x+11, y, transpose)) {
+					score += 40
+				}
+			}
+		}
+	}
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			c := q.modules[y][x]
+			if c {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size && c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+				score += 3
+			}
+		}
+	}
+	total := q.size * q.size
+	return score + abs(dark*20-total*10)/total*10
+}
+
+// light reports whether modules from to to (exclusive) of a row, or a
+// column if transposed, are light; those outside the code are.
+func (q *qrBuilder) light(from, to, y int, transpose bool) bool {
+	for x := from; x < to; x++ {
+		if x < 0 || x >= q.size {
+			continue
+		}
+		if (transpose && q.modules[x][y]) || (!transpose && q.modules[y][x]) {
+			return false
+		}
+	}
+	return true
+}
+
+// reedSolomon returns the n error correction codewords for data, over
+// GF(256) with the polynomial QR codes use.
+func reedSolomon(data []byte, n int) []byte {
+	mul := func(a, b byte) byte {
+		var p byte
+		for ; b > 0; b >>= 1 {
+			if b&1 == 1 {
+				p ^= a
+			}
+			hi := a & 0x80
+			a <<= 1
+			if hi != 0 {
+				a ^= 0x1D
+			}
+		}
+		return p
+	}
+	// The generator is the product of (x - 2^i) for i below n.
+	gen := make([]byte, n)
+	gen[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			gen[j] = mul(gen[j], root)
+			if j+1 < n {
+				gen[j] ^= gen[j+1]
+			}
+		}
+		root = mul(root, 2)
+	}
+	rem := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for j := range rem {
+			rem[j] ^= mul(gen[j], factor)
+		}
+	}
+	return rem
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// qrQuiet is the light border a scanner needs around a code, in modules.
+const qrQuiet = 4
+
+// writeTerminal draws the code with half-block characters, two rows of
+// modules per line, in black on white whatever the terminal's colours.
+func (c qrCode) writeTerminal(w io.Writer) error {
+	size := len(c)
+	dark := func(x, y int) bool {
+		x, y = x-qrQuiet, y-qrQuiet
+		return x >= 0 && y >= 0 && x < size && y < size && c[y][x]
+	}
+	var b strings.Builder
+	for y := 0; y < size+2*qrQuiet; y += 2 {
+		b.WriteString("\033[30;107m")
+		for x := 0; x < size+2*qrQuiet; x++ {
+			switch top, bottom := dark(x, y), dark(x, y+1); {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\033[0m\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writePNG draws the code as a PNG, scale pixels to a module.
+func (c qrCode) writePNG(w io.Writer, scale int) error {
+	size := (len(c) + 2*qrQuiet) * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			mx, my := x/scale-qrQuiet, y/scale-qrQuiet
+			if mx >= 0 && my >= 0 && mx < len(c) && my < len(c) && c[my][mx] {
+				img.SetGray(x, y, color.Gray{0})
+			} else {
+				img.SetGray(x, y, color.Gray{255})
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// --qr and --qr-out, for commands that open an issue someone nearby may
+// want to open on their phone.
+var (
+	qrTerminal bool
+	qrOut      string
+)
+
+// showQR prints a QR code of link under --qr and writes it as a PNG to
+// --qr-out.
+func showQR(link string) error {
+	if !qrTerminal && qrOut == "" {
+		return nil
+	}
+	code, err := newQRCode(link)
+	if err != nil {
+		return err
+	}
+	if qrTerminal {
+		if err := code.writeTerminal(os.Stdout); err != nil {
+			return err
+		}
+	}
+	if qrOut != "" {
+		f, err := os.Create(qrOut)
+		if err != nil {
+			return err
+		}
+		if err := code.writePNG(f, 8); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("QR code written to %s\n", qrOut)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// LeaderboardRow is one player's leaderboard entry, as published in the
+// Pages site's rankings.json by scripts/build_pages.py.
+type LeaderboardRow struct {
+	Rank       int     `json:"rank"`
+	Player     string  `json:"player"`
+	Rating     float64 `json:"rating"`
+	SetWins    int     `json:"set_wins"`
+	SetLosses  int     `json:"set_losses"`
+	GameWins   int     `json:"game_wins"`
+	GameLosses int     `json:"game_losses"`
+	Unranked   bool    `json:"unranked,omitempty"` // listed after the ranked players, with rank 0
+	Decay      float64 `json:"decay,omitempty"`    // rating points lost to inactivity
+	Tier       string  `json:"tier,omitempty"`     // the rating's tier, if the league has tiers
+	Points     float64 `json:"points,omitempty"`   // ranking points, if the league awards them
+}
+
+// qualificationRules decide who is ranked on a leaderboard, set in the
+// leaderboard section of .tennis.yml. The zero value ranks everyone.
+type qualificationRules struct {
+	MinMatches int `yaml:"min_matches,omitempty" json:"min_matches"`
+	WindowDays int `yaml:"window_days,omitempty" json:"window_days"` // 0 counts every match
+}
+
+// qualification is the league's qualification rules, loaded with elo.
+var qualification qualificationRules
+
+func (q qualificationRules) String() string {
+	s := fmt.Sprintf("at least %d matches", q.MinMatches)
+	if q.WindowDays > 0 {
+		s += fmt.Sprintf(" in the last %d days", q.WindowDays)
+	}
+	return s
+}
+
+// qualify moves players who don't meet the qualification rules on asOf
+// (YYYY-MM-DD) below the ranked players, as unranked, and renumbers the
+// ranks. Matches are counted per player, not per set.
+func qualify(board []LeaderboardRow, matches []Match, asOf string) []LeaderboardRow {
+	if qualification.MinMatches == 0 {
+		return board
+	}
+	since := ""
+	if qualification.WindowDays > 0 {
+		if t, err := time.Parse("2006-01-02", asOf); err == nil {
+			since = t.AddDate(0, 0, -qualification.WindowDays).Format("2006-01-02")
+		}
+	}
+	played := make(map[string]int)
+	for _, m := range matches {
+		if m.Date > asOf || m.Date <= since {
+			continue
+		}
+		for _, side := range [][]string{m.Players, m.Team1, m.Team2} {
+			for _, p := range side {
+				played[p]++
+			}
+		}
+	}
+
+	var ranked, unranked []LeaderboardRow
+	for _, r := range board {
+		if played[r.Player] >= qualification.MinMatches {
+			r.Rank, r.Unranked = len(ranked)+1, false
+			ranked = append(ranked, r)
+		} else {
+			r.Rank, r.Unranked = 0, true
+			unranked = append(unranked, r)
+		}
+	}
+	return append(ranked, unranked...)
+}
+
+// decayRules take rating points off players who stop playing, set in the
+// decay section of .tennis.yml. The zero value disables decay.
+type decayRules struct {
+	AfterDays     int     `yaml:"after_days,omitempty" json:"after_days"`
+	PointsPerWeek float64 `yaml:"points_per_week,omitempty" json:"points_per_week"`
+}
+
+// decay is the league's inactivity decay, loaded with elo.
+var decay decayRules
+
+// awayPeriods are the players' away periods from the roster, loaded with
+// elo. Days away don't count towards decay or approval timers.
+var awayPeriods map[string]awayPeriod
+
+// decayRatings returns ratings as they stand on asOf once inactivity decay
+// is applied, and the points each player lost. A player whose last match
+// was more than AfterDays days before asOf loses PointsPerWeek for every
+// full week beyond that, but never drops below the initial rating. Days
+// the player was away (see awayPeriods) don't count. The
+// replayed ratings are left untouched, so a returning player's matches
+// are rated from their earned rating.
+func decayRatings(ratings map[string]float64, matches []Match, asOf string) (map[string]float64, map[string]float64) {
+	if decay.PointsPerWeek == 0 {
+		return ratings, nil
+	}
+	day, err := time.Parse("2006-01-02", asOf)
+	if err != nil {
+		return ratings, nil
+	}
+	last := make(map[string]string)
+	for _, m := range matches {
+		if m.Date > asOf {
+			continue
+		}
+		for _, side := range [][]string{m.Players, m.Team1, m.Team2} {
+			for _, p := range side {
+				if m.Date > last[p] {
+					last[p] = m.Date
+				}
+			}
+		}
+	}
+
+	decayed := make(map[string]float64, len(ratings))
+	lost := make(map[string]float64)
+	for p, r := range ratings {
+		decayed[p] = r
+		played, err := time.Parse("2006-01-02", last[p])
+		if err != nil || r <= elo.InitialRating {
+			continue
+		}
+		idle := int(day.Sub(played).Hours()/24) - awayPeriods[p].Days(played, day) - decay.AfterDays
+		if idle < 7 {
+			continue
+		}
+		decayed[p] = math.Max(elo.InitialRating, r-decay.PointsPerWeek*float64(idle/7))
+		lost[p] = r - decayed[p]
+	}
+	return decayed, lost
+}
+
+// leaderboardOn ranks players by their ratings on asOf, after inactivity
+// decay, places them in tiers and applies the qualification rules. If the
+// league awards ranking points, they are added too, and rank the players
+// instead if points.rank_by asks for it.
+func leaderboardOn(records map[string]*LeaderboardRow, ratings map[string]float64, matches []Match, roster *Roster, asOf string) []LeaderboardRow {
+	decayed, lost := decayRatings(ratings, matches, asOf)
+	board := rankLeaderboard(records, decayed, roster)
+	var points map[string]float64
+	if rankingPoints.Enabled() {
+		points = matchPoints(matches, asOf)
+	}
+	for i := range board {
+		board[i].Decay = math.Round(lost[board[i].Player]*10) / 10
+		board[i].Tier = tierOf(board[i].Rating)
+		board[i].Points = points[board[i].Player]
+	}
+	if rankingPoints.RankBy == rankByPoints {
+		rankOnPoints(board)
+	}
+	return qualify(board, matches, asOf)
+}
+
+// singlesLeaderboard computes the singles leaderboard on asOf exactly as
+// generate_singles_ranking.py does.
+func singlesLeaderboard(matches []Match, roster *Roster, asOf string) []LeaderboardRow {
+	records := make(map[string]*LeaderboardRow)
+	tallySingles(records, matches)
+	return leaderboardOn(records, computeSinglesRatings(matches), matches, roster, asOf)
+}
+
+// doublesLeaderboard computes the doubles individual leaderboard on asOf
+// exactly as generate_doubles_ranking.py does.
+func doublesLeaderboard(matches []Match, roster *Roster, asOf string) []LeaderboardRow {
+	records := make(map[string]*LeaderboardRow)
+	tallyDoubles(records, matches)
+	return leaderboardOn(records, computeDoublesRatings(matches), matches, roster, asOf)
+}
+
+// tallySingles adds singles matches' set and game counts to records.
+func tallySingles(records map[string]*LeaderboardRow, matches []Match) {
+	for _, m := range matches {
+		if len(m.Players) == 2 {
+			// Singles counts games from tied sets; doubles does not.
+			tallySets(records, m.scoredSets(), m.Players[:1], m.Players[1:], true)
+		}
+	}
+}
+
+// tallyDoubles adds doubles matches' set and game counts to records.
+func tallyDoubles(records map[string]*LeaderboardRow, matches []Match) {
+	for _, m := range matches {
+		if len(m.Team1) == 2 && len(m.Team2) == 2 {
+			tallySets(records, m.scoredSets(), m.Team1, m.Team2, false)
+		}
+	}
+}
+
+func tallySets(records map[string]*LeaderboardRow, sets [][]int, side1, side2 []string, tiedSetGames bool) {
+	row := func(p string) *LeaderboardRow {
+		if records[p] == nil {
+			records[p] = &LeaderboardRow{Player: p}
+		}
+		return records[p]
+	}
+	for _, s := range sets {
+		if len(s) != 2 || (s[0] == s[1] && !tiedSetGames) {
+			continue
+		}
+		for _, p := range side1 {
+			row(p).GameWins += s[0]
+			row(p).GameLosses += s[1]
+		}
+		for _, p := range side2 {
+			row(p).GameWins += s[1]
+			row(p).GameLosses += s[0]
+		}
+		if s[0] == s[1] {
+			continue
+		}
+		winners, losers := side1, side2
+		if s[1] > s[0] {
+			winners, losers = side2, side1
+		}
+		for _, p := range winners {
+			row(p).SetWins++
+		}
+		for _, p := range losers {
+			row(p).SetLosses++
+		}
+	}
+}
+
+// rankLeaderboard orders the players by rating.
+func rankLeaderboard(records map[string]*LeaderboardRow, ratings map[string]float64, roster *Roster) []LeaderboardRow {
+	// The roster, when present, decides who is listed (see leaderboard_players).
+	var players []string
+	if roster != nil && len(roster.Players) > 0 {
+		players = roster.ActiveHandles()
+	} else {
+		for p := range ratings {
+			players = append(players, p)
+		}
+		sort.Strings(players)
+	}
+
+	board := make([]LeaderboardRow, 0, len(players))
+	for _, p := range players {
+		r := LeaderboardRow{Player: p}
+		if records[p] != nil {
+			r = *records[p]
+		}
+		r.Rating = math.Round(rating(ratings, p)*10) / 10
+		board = append(board, r)
+	}
+	sort.SliceStable(board, func(i, j int) bool { return board[i].Rating > board[j].Rating })
+	for i := range board {
+		board[i].Rank = i + 1
+	}
+	return board
+}
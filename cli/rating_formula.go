@@ -0,0 +1,295 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	gotoken "go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxFormulaSize caps a rating formula file. Formulas have no loops or
+// side effects, so a bounded source bounds the work of evaluating one.
+const maxFormulaSize = 4096
+
+// formulaVars are the values a rating formula is fed for each set.
+var formulaVars = []string{
+	"k",              // K-factor for the set, after margin weighting
+	"expected",       // the winner's expected score
+	"winner_rating",  // the winner's rating (team average in doubles)
+	"loser_rating",   // the loser's rating (team average in doubles)
+	"games_won",      // the winner's games in the set
+	"games_lost",     // the loser's games in the set
+	"initial_rating", // rating of a player with no matches
+	"doubles",        // 1 for a doubles set, 0 for singles
+}
+
+// formulaFuncs are the functions a rating formula may call, by arity
+// (-1 for two or more arguments).
+var formulaFuncs = map[string]int{
+	"abs": 1, "sqrt": 1, "ln": 1, "log10": 1, "exp": 1,
+	"pow": 2, "min": -1, "max": -1, "when": 3, "clamp": 3,
+}
+
+var errNonFinite = errors.New("result is not a finite number")
+
+// formulaNumber is the numbers a formula may contain: plain decimals, as
+// in scripts/rating_formula.py (no hex or underscores).
+var formulaNumber = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// ratingFormula is a custom rating formula: an arithmetic expression giving
+// the points the winner of a set gains and the loser loses, in place of
+// K × (1 − expected). It can only read formulaVars and call formulaFuncs,
+// so it can't reach the file system, network or engine state.
+type ratingFormula struct {
+	Source string // the expression, without comments
+	expr   ast.Expr
+}
+
+// customFormula is the league's rating formula, or nil for standard Elo.
+// It is loaded with elo from the file elo.formula names.
+var customFormula *ratingFormula
+
+// loadRatingFormula reads and checks the formula file at path, relative to
+// the league checkout. The file holds one expression, which may span
+// lines; '#' starts a comment.
+func loadRatingFormula(path string) (*ratingFormula, error) {
+	full := path
+	if !filepath.IsAbs(full) {
+		full = filepath.Join(leagueDir(), path)
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFormulaSize {
+		return nil, fmt.Errorf("%s is over %d bytes", path, maxFormulaSize)
+	}
+	f, err := parseRatingFormula(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// parseRatingFormula compiles a formula and checks it against a sample
+// set, so mistakes surface when the config is loaded, not mid-replay.
+func parseRatingFormula(src string) (*ratingFormula, error) {
+	var lines []string
+	for _, line := range strings.Split(src, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	text := strings.TrimSpace(strings.Join(lines, " "))
+	if text == "" {
+		return nil, errors.New("the formula is empty")
+	}
+	expr, err := parser.ParseExpr(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFormula(expr); err != nil {
+		return nil, err
+	}
+
+	f := &ratingFormula{Source: text, expr: expr}
+	sample := map[string]float64{
+		"k": defaultElo.K, "expected": 0.5, "winner_rating": 1200, "loser_rating": 1200,
+		"games_won": 6, "games_lost": 4, "initial_rating": 1200, "doubles": 0,
+	}
+	if _, err := f.eval(sample); err != nil {
+		return nil, fmt.Errorf("evaluating it for an even 6-4 set: %w", err)
+	}
+	return f, nil
+}
+
+// checkFormula rejects anything but numbers, the formula variables,
+// arithmetic, comparisons and calls to the formula functions.
+func checkFormula(e ast.Expr) error {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != gotoken.INT && e.Kind != gotoken.FLOAT {
+			return fmt.Errorf("unsupported literal %s", e.Value)
+		}
+		if _, err := strconv.ParseFloat(e.Value, 64); err != nil || !formulaNumber.MatchString(e.Value) {
+			return fmt.Errorf("invalid number %s", e.Value)
+		}
+	case *ast.Ident:
+		for _, v := range formulaVars {
+			if e.Name == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown variable %q (available: %s)", e.Name, strings.Join(formulaVars, ", "))
+	case *ast.ParenExpr:
+		return checkFormula(e.X)
+	case *ast.UnaryExpr:
+		if e.Op != gotoken.ADD && e.Op != gotoken.SUB {
+			return fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		return checkFormula(e.X)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case gotoken.ADD, gotoken.SUB, gotoken.MUL, gotoken.QUO:
+		case gotoken.LSS, gotoken.LEQ, gotoken.GTR, gotoken.GEQ, gotoken.EQL, gotoken.NEQ:
+			// Python reads a < b < c as a chain, so require parentheses.
+			if isComparison(e.X) || isComparison(e.Y) {
+				return errors.New("unsupported comparison: parenthesize chained comparisons")
+			}
+		default:
+			return fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		if err := checkFormula(e.X); err != nil {
+			return err
+		}
+		return checkFormula(e.Y)
+	case *ast.CallExpr:
+		name, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return errors.New("only the built-in functions can be called")
+		}
+		arity, ok := formulaFuncs[name.Name]
+		if !ok {
+			return fmt.Errorf("unknown function %q", name.Name)
+		}
+		if (arity < 0 && len(e.Args) < 2) || (arity >= 0 && len(e.Args) != arity) || e.Ellipsis.IsValid() {
+			return fmt.Errorf("wrong number of arguments to %s", name.Name)
+		}
+		for _, a := range e.Args {
+			if err := checkFormula(a); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported expression %T", e)
+	}
+	return nil
+}
+
+func isComparison(e ast.Expr) bool {
+	b, ok := e.(*ast.BinaryExpr)
+	return ok && b.Op.Precedence() == gotoken.EQL.Precedence()
+}
+
+// eval evaluates the formula. Comparisons yield 1 or 0, and when(c, a, b)
+// is a if c is non-zero, else b. Any non-finite intermediate value, such
+// as a division by zero, is an error.
+func (f *ratingFormula) eval(vars map[string]float64) (float64, error) {
+	return evalFormula(f.expr, vars)
+}
+
+func evalFormula(e ast.Expr, vars map[string]float64) (float64, error) {
+	var v float64
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		v, _ = strconv.ParseFloat(e.Value, 64)
+	case *ast.Ident:
+		v = vars[e.Name]
+	case *ast.ParenExpr:
+		return evalFormula(e.X, vars)
+	case *ast.UnaryExpr:
+		x, err := evalFormula(e.X, vars)
+		if err != nil {
+			return 0, err
+		}
+		v = x
+		if e.Op == gotoken.SUB {
+			v = -x
+		}
+	case *ast.BinaryExpr:
+		x, err := evalFormula(e.X, vars)
+		if err != nil {
+			return 0, err
+		}
+		y, err := evalFormula(e.Y, vars)
+		if err != nil {
+			return 0, err
+		}
+		v = binaryOp(e.Op, x, y)
+	case *ast.CallExpr:
+		args := make([]float64, len(e.Args))
+		for i, a := range e.Args {
+			x, err := evalFormula(a, vars)
+			if err != nil {
+				return 0, err
+			}
+			args[i] = x
+		}
+		v = callFunc(e.Fun.(*ast.Ident).Name, args)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errNonFinite
+	}
+	return v, nil
+}
+
+func binaryOp(op gotoken.Token, x, y float64) float64 {
+	truth := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	switch op {
+	case gotoken.ADD:
+		return x + y
+	case gotoken.SUB:
+		return x - y
+	case gotoken.MUL:
+		return x * y
+	case gotoken.QUO:
+		return x / y
+	case gotoken.LSS:
+		return truth(x < y)
+	case gotoken.LEQ:
+		return truth(x <= y)
+	case gotoken.GTR:
+		return truth(x > y)
+	case gotoken.GEQ:
+		return truth(x >= y)
+	case gotoken.EQL:
+		return truth(x == y)
+	default:
+		return truth(x != y)
+	}
+}
+
+func callFunc(name string, args []float64) float64 {
+	switch name {
+	case "abs":
+		return math.Abs(args[0])
+	case "sqrt":
+		return math.Sqrt(args[0])
+	case "ln":
+		return math.Log(args[0])
+	case "log10":
+		return math.Log10(args[0])
+	case "exp":
+		return math.Exp(args[0])
+	case "pow":
+		return math.Pow(args[0], args[1])
+	case "min", "max":
+		v := args[0]
+		for _, a := range args[1:] {
+			if (name == "min" && a < v) || (name == "max" && a > v) {
+				v = a
+			}
+		}
+		return v
+	case "when":
+		if args[0] != 0 {
+			return args[1]
+		}
+		return args[2]
+	default: // clamp
+		return math.Min(math.Max(args[0], args[1]), args[2])
+	}
+}
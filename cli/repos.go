@@ -0,0 +1,215 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// leagueRepo is another repository in the league, e.g. another club's.
+type leagueRepo struct {
+	Repo string `yaml:"repo"`           // owner/name
+	Path string `yaml:"path,omitempty"` // local checkout to read instead of downloading
+}
+
+// leagueRepos are the league's other repositories, from .tennis.yml.
+var leagueRepos []leagueRepo
+
+// repoArchives caches the match files downloaded from each repository,
+// keyed by their path in it, for the life of the command.
+var repoArchives = make(map[string]map[string][]byte)
+
+// checkLeagueRepos validates the repos list of .tennis.yml.
+func checkLeagueRepos(repos []leagueRepo) error {
+	seen := make(map[string]bool)
+	for _, r := range repos {
+		parts := strings.Split(r.Repo, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("repos: %q is not owner/name", r.Repo)
+		}
+		key := strings.ToLower(r.Repo)
+		if strings.EqualFold(r.Repo, owner+"/"+repo) {
+			return fmt.Errorf("repos: %s is this repository", r.Repo)
+		}
+		if seen[key] {
+			return fmt.Errorf("repos: %s is listed twice", r.Repo)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// pulledMatchName is the file name "tennis repos pull" gives a match file
+// from another repository, e.g. 2024-01-01--owner--repo-123.yml, so files
+// from different repos can't collide, still sort by date and still end in
+// the issue number the Pages scripts look for.
+func pulledMatchName(fullName, name string) string {
+	source := "--" + strings.ReplaceAll(strings.ToLower(fullName), "/", "--")
+	base := strings.TrimSuffix(name, ".yml")
+	if i := strings.LastIndex(base, "-"); i >= 0 {
+		if _, err := strconv.Atoi(base[i+1:]); err == nil {
+			return base[:i] + source + base[i:] + ".yml"
+		}
+	}
+	return base + source + ".yml"
+}
+
+// loadRepoMatches loads the matches in dir of every other league
+// repository, tagged with the repository they came from. Matches already
+// pulled into the local checkout (have) are left out.
+func loadRepoMatches(ctx context.Context, dir string, aliases map[string]string, have []Match) ([]Match, error) {
+	pulled := make(map[string]bool)
+	for _, m := range have {
+		if m.Repo != "" {
+			pulled[fmt.Sprintf("%s#%d", strings.ToLower(m.Repo), m.SourceIssue)] = true
+		}
+	}
+
+	var matches []Match
+	p := newProgress("Reading league repositories", len(leagueRepos))
+	defer p.Finish()
+	for _, r := range leagueRepos {
+		files, err := repoMatchFiles(ctx, r, dir)
+		p.Add(1)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			m, err := parseMatchFile(path.Join(r.Repo, dir, pulledMatchName(r.Repo, name)), files[name], aliases)
+			if err != nil {
+				p.Clear()
+				fmt.Fprintf(os.Stderr, "Error reading %s/%s/%s: %v\n", r.Repo, dir, name, err)
+				continue
+			}
+			if m.Repo == "" {
+				m.Repo = r.Repo
+			}
+			// Skip matches already pulled here, and this repository's own
+			// matches pulled into the other one.
+			if pulled[fmt.Sprintf("%s#%d", strings.ToLower(m.Repo), m.SourceIssue)] || strings.EqualFold(m.Repo, owner+"/"+repo) {
+				continue
+			}
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+// repoMatchFiles returns the .yml files in dir of a league repository, by
+// name: from its local checkout if it has a path, else from a download of
+// its default branch.
+func repoMatchFiles(ctx context.Context, r leagueRepo, dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	if r.Path != "" {
+		root := r.Path
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(leagueDir(), root)
+		}
+		paths, err := filepath.Glob(filepath.Join(root, dir, "*.yml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			files[filepath.Base(p)] = data
+		}
+		return files, nil
+	}
+
+	archive, ok := repoArchives[r.Repo]
+	if !ok {
+		var err error
+		if archive, err = downloadMatchFiles(ctx, r.Repo); err != nil {
+			return nil, err
+		}
+		repoArchives[r.Repo] = archive
+	}
+	prefix := dir + "/"
+	for name, data := range archive {
+		if strings.HasPrefix(name, prefix) {
+			files[strings.TrimPrefix(name, prefix)] = data
+		}
+	}
+	return files, nil
+}
+
+// downloadMatchFiles downloads a repository's default branch as one
+// tarball and returns its singles and doubles match files, keyed by their
+// path in the repository.
+func downloadMatchFiles(ctx context.Context, fullName string) (map[string][]byte, error) {
+	o, n, _ := strings.Cut(fullName, "/")
+	link, _, err := getGitHubClient().Repositories.GetArchiveLink(ctx, o, n, github.Tarball, nil, 3)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", fullName, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := downloadClient(2 * time.Minute).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", fullName, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", fullName, resp.Status)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s's archive: %w", fullName, err)
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s's archive: %w", fullName, err)
+		}
+		// Entries are under a top-level <owner>-<repo>-<sha>/ directory.
+		_, name, _ := strings.Cut(hdr.Name, "/")
+		dir, file := path.Split(name)
+		if hdr.Typeflag != tar.TypeReg || (dir != "singles-matches/" && dir != "doubles-matches/") || !strings.HasSuffix(file, ".yml") {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s's archive: %w", fullName, err)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// matchSources counts matches by the repository they were recorded in,
+// this repository first.
+func matchSources(matches []Match) []string {
+	sources := []string{fmt.Sprintf("%s/%s (%d)", owner, repo, countFrom(matches, ""))}
+	for _, r := range leagueRepos {
+		sources = append(sources, fmt.Sprintf("%s (%d)", r.Repo, countFrom(matches, r.Repo)))
+	}
+	return sources
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RosterPlayer is one entry in the league roster, players.yml. The roster is
+// the source of truth for who is in the league; the ranking scripts and
+// pages builder read the same file.
+type RosterPlayer struct {
+	Handle string `yaml:"handle"`
+	Name   string `yaml:"name,omitempty"`
+	Joined string `yaml:"joined,omitempty"`
+	Status string `yaml:"status,omitempty"`
+
+	// Division is the player's division, 1 being the top; 0 means the
+	// player isn't in a division.
+	Division int `yaml:"division,omitempty"`
+
+	// Categories are the competitions the player takes part in, such as
+	// open, women or veterans, set by "tennis player category".
+	Categories []string `yaml:"categories,omitempty"`
+
+	// Imported is the provisional rating the player joined with, carried
+	// over from another league by "tennis player import-rating".
+	Imported *importedRating `yaml:"imported,omitempty"`
+
+	// Away is a stretch when the player can't play, set by "tennis
+	// availability away".
+	Away *awayPeriod `yaml:"away,omitempty"`
+
+	// Profile is what the player has shared about their game, set by
+	// "tennis profile set".
+	Profile *playerProfile `yaml:"profile,omitempty"`
+}
+
+// playerProfile is a player's profile. Every field is optional.
+type playerProfile struct {
+	Hand string  `yaml:"hand,omitempty" json:"hand,omitempty"` // left or right
+	NTRP float64 `yaml:"ntrp,omitempty" json:"ntrp,omitempty"` // self-rating, 1.0 to 7.0 in steps of 0.5
+	Club string  `yaml:"club,omitempty" json:"club,omitempty"`
+}
+
+// String summarizes the profile on one line, e.g. "Left-handed · NTRP 4.0
+// · Riverside". It's empty if nothing is set.
+func (p *playerProfile) String() string {
+	if p == nil {
+		return ""
+	}
+	var parts []string
+	if p.Hand != "" {
+		parts = append(parts, strings.ToUpper(p.Hand[:1])+p.Hand[1:]+"-handed")
+	}
+	if p.NTRP > 0 {
+		parts = append(parts, fmt.Sprintf("NTRP %.1f", p.NTRP))
+	}
+	if p.Club != "" {
+		parts = append(parts, p.Club)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// awayPeriod runs from From up to, but not including, Until (YYYY-MM-DD).
+// While a player is away, matchmaking skips them, approval reminders and
+// timers pause, and inactivity decay doesn't count the days.
+type awayPeriod struct {
+	From  string `yaml:"from" json:"from"`
+	Until string `yaml:"until" json:"until"`
+}
+
+// On reports whether date (YYYY-MM-DD) falls in the period.
+func (a awayPeriod) On(date string) bool {
+	return a.From <= date && date < a.Until
+}
+
+// Days returns how many days of the period fall between from and to.
+func (a awayPeriod) Days(from, to time.Time) int {
+	start, err1 := time.Parse("2006-01-02", a.From)
+	end, err2 := time.Parse("2006-01-02", a.Until)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	if from.After(start) {
+		start = from
+	}
+	if to.Before(end) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// importedRating is a newcomer's starting rating taken from their standing
+// in another league. A zero rating means none was imported for that format.
+type importedRating struct {
+	From    string  `yaml:"from" json:"from"` // owner/repo of the other league
+	Date    string  `yaml:"date" json:"date"`
+	Singles float64 `yaml:"singles,omitempty" json:"singles,omitempty"`
+	Doubles float64 `yaml:"doubles,omitempty" json:"doubles,omitempty"`
+}
+
+const (
+	playerActive   = "active"
+	playerInactive = "inactive"
+)
+
+// UnmarshalYAML accepts both the structured form and the original flat list
+// of handles, so existing players.yml files keep working.
+func (p *RosterPlayer) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		p.Handle = node.Value
+		return nil
+	}
+	type plain RosterPlayer
+	return node.Decode((*plain)(p))
+}
+
+// DisplayName returns the player's name, falling back to their handle.
+func (p RosterPlayer) DisplayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Handle
+}
+
+// AwayOn reports whether the player is away on date (YYYY-MM-DD).
+func (p RosterPlayer) AwayOn(date string) bool {
+	return p.Away != nil && p.Away.On(date)
+}
+
+// IsActive reports whether the player currently takes part in the league.
+// An empty status means active.
+func (p RosterPlayer) IsActive() bool {
+	return p.Status == "" || p.Status == playerActive
+}
+
+// Roster is the parsed players.yml.
+type Roster struct {
+	Players []RosterPlayer
+}
+
+func rosterPath() string {
+	return filepath.Join(leagueDir(), "players.yml")
+}
+
+// loadRoster reads players.yml. A missing file yields an empty roster.
+func loadRoster() (*Roster, error) {
+	data, err := os.ReadFile(rosterPath())
+	if os.IsNotExist(err) {
+		return &Roster{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var players []RosterPlayer
+	if err := yaml.Unmarshal(data, &players); err != nil {
+		return nil, fmt.Errorf("invalid players.yml: %w", err)
+	}
+	for i := range players {
+		players[i].Handle = normalizePlayer(players[i].Handle)
+		if players[i].Status == "" {
+			players[i].Status = playerActive
+		}
+	}
+	return &Roster{Players: players}, nil
+}
+
+// saveRoster writes players.yml in the structured form, sorted by handle.
+func saveRoster(r *Roster) error {
+	sort.SliceStable(r.Players, func(i, j int) bool {
+		return r.Players[i].Handle < r.Players[j].Handle
+	})
+	return writeYAMLFile(rosterPath(), r.Players)
+}
+
+// Find returns the roster entry for a handle, or nil.
+func (r *Roster) Find(handle string) *RosterPlayer {
+	handle = normalizePlayer(handle)
+	for i := range r.Players {
+		if r.Players[i].Handle == handle {
+			return &r.Players[i]
+		}
+	}
+	return nil
+}
+
+// Remove deletes a handle from the roster, reporting whether it was present.
+func (r *Roster) Remove(handle string) bool {
+	handle = normalizePlayer(handle)
+	for i := range r.Players {
+		if r.Players[i].Handle == handle {
+			r.Players = append(r.Players[:i], r.Players[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ImportedRatings returns the singles and doubles starting ratings imported
+// from other leagues, by handle.
+func (r *Roster) ImportedRatings() (singles, doubles map[string]float64) {
+	singles, doubles = make(map[string]float64), make(map[string]float64)
+	for _, p := range r.Players {
+		if p.Imported == nil {
+			continue
+		}
+		if p.Imported.Singles > 0 {
+			singles[p.Handle] = p.Imported.Singles
+		}
+		if p.Imported.Doubles > 0 {
+			doubles[p.Handle] = p.Imported.Doubles
+		}
+	}
+	return singles, doubles
+}
+
+// AwayPeriods returns the players' away periods, by handle.
+func (r *Roster) AwayPeriods() map[string]awayPeriod {
+	periods := make(map[string]awayPeriod)
+	for _, p := range r.Players {
+		if p.Away != nil {
+			periods[p.Handle] = *p.Away
+		}
+	}
+	return periods
+}
+
+// AvailableHandles returns the handles of the active players who aren't
+// away on date.
+func (r *Roster) AvailableHandles(date string) []string {
+	var handles []string
+	for _, p := range r.Players {
+		if p.IsActive() && !p.AwayOn(date) {
+			handles = append(handles, p.Handle)
+		}
+	}
+	return handles
+}
+
+// ActiveHandles returns the handles of all active players.
+func (r *Roster) ActiveHandles() []string {
+	var handles []string
+	for _, p := range r.Players {
+		if p.IsActive() {
+			handles = append(handles, p.Handle)
+		}
+	}
+	return handles
+}
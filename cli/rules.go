@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stonehenge-collective/tennis/internal/githubapi"
+)
+
+// Set scoring formats for leagueRules.Scoring.
+const (
+	scoringStandard      = "standard"       // first to 6 games, 7-5, or 7-6 after a tiebreak
+	scoringShort         = "short"          // first to 4 games, 5-3, or 5-4 after a tiebreak
+	scoringMatchTiebreak = "match-tiebreak" // a deciding set played as a first-to-10 tiebreak
+)
+
+// Approval requirements for leagueRules.Approvals.
+const (
+	approvalsAll  = "all"  // every player other than the reporter
+	approvalsAny  = "any"  // at least one player other than the reporter
+	approvalsNone = "none" // results are recorded without approval
+)
+
+// leagueRules are the rules matches are held to, set in the rules section
+// of .tennis.yml. Every rule is optional; the zero value allows any match
+// the issue forms accept and requires every player's approval.
+type leagueRules struct {
+	Formats    []string     `yaml:"formats,omitempty"`   // singles and/or doubles
+	BestOf     int          `yaml:"best_of,omitempty"`   // sets in a match: 1, 3 or 5
+	Scoring    []string     `yaml:"scoring,omitempty"`   // allowed set scoring formats
+	Approvals  string       `yaml:"approvals,omitempty"` // all, any or none
+	Season     seasonDates  `yaml:"season,omitempty"`
+	UndoWindow string       `yaml:"undo_window,omitempty"` // how long "match undo" works, e.g. 30m
+	Forfeit    forfeitRules `yaml:"forfeit,omitempty"`
+}
+
+// defaultUndoWindow is how long after reporting a match "match undo" can
+// void it, unless rules.undo_window says otherwise.
+const defaultUndoWindow = 15 * time.Minute
+
+// seasonDates bound the dates matches may be played on, inclusive.
+type seasonDates struct {
+	Start string `yaml:"start,omitempty"`
+	End   string `yaml:"end,omitempty"`
+}
+
+// rules is the league's match rules, loaded with elo. Commands that create
+// or verify matches replace them with the repository's (see loadRepoRules).
+var rules leagueRules
+
+// check reports the first invalid rule.
+func (r leagueRules) check() error {
+	for _, f := range r.Formats {
+		if f != "singles" && f != "doubles" {
+			return fmt.Errorf("rules.formats may only list singles and doubles, got %q", f)
+		}
+	}
+	if r.BestOf != 0 && r.BestOf != 1 && r.BestOf != 3 && r.BestOf != 5 {
+		return fmt.Errorf("rules.best_of must be 1, 3 or 5, got %d", r.BestOf)
+	}
+	for _, s := range r.Scoring {
+		if s != scoringStandard && s != scoringShort && s != scoringMatchTiebreak {
+			return fmt.Errorf("rules.scoring may only list %s, %s and %s, got %q", scoringStandard, scoringShort, scoringMatchTiebreak, s)
+		}
+	}
+	switch r.Approvals {
+	case "", approvalsAll, approvalsAny, approvalsNone:
+	default:
+		return fmt.Errorf("rules.approvals must be %s, %s or %s, got %q", approvalsAll, approvalsAny, approvalsNone, r.Approvals)
+	}
+	for _, d := range []string{r.Season.Start, r.Season.End} {
+		if d != "" && !isValidDate(d) {
+			return fmt.Errorf("rules.season dates must be YYYY-MM-DD, got %q", d)
+		}
+	}
+	if r.Season.Start != "" && r.Season.End != "" && r.Season.End < r.Season.Start {
+		return fmt.Errorf("rules.season ends (%s) before it starts (%s)", r.Season.End, r.Season.Start)
+	}
+	if r.UndoWindow != "" {
+		if d, err := time.ParseDuration(r.UndoWindow); err != nil || d < 0 {
+			return fmt.Errorf("rules.undo_window must be a duration such as 15m or 1h, got %q", r.UndoWindow)
+		}
+	}
+	return r.Forfeit.check()
+}
+
+// undoWindow returns how long after reporting a match "match undo" can
+// void it. check has already validated rules.undo_window.
+func (r leagueRules) undoWindow() time.Duration {
+	if d, err := time.ParseDuration(r.UndoWindow); err == nil {
+		return d
+	}
+	return defaultUndoWindow
+}
+
+// approvals returns the approval requirement, defaulting to all.
+func (r leagueRules) approvals() string {
+	if r.Approvals == "" {
+		return approvalsAll
+	}
+	return r.Approvals
+}
+
+// problems reports how a match of the given kind breaks the rules. The
+// checks assume the match is otherwise well formed (see validate).
+func (r leagueRules) problems(kind string, m Match) []issueProblem {
+	var problems []issueProblem
+	add := func(code, format string, args ...interface{}) {
+		problems = append(problems, issueProblem{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(r.Formats) > 0 && !containsString(r.Formats, kind) {
+		add("format_not_allowed", "%s matches aren't played in this league (rules.formats: %s)", kind, strings.Join(r.Formats, ", "))
+	}
+
+	if m.Date != "" {
+		switch {
+		case r.Season.Start != "" && m.Date < r.Season.Start:
+			add("out_of_season", "match date %s is before the season starts on %s", m.Date, r.Season.Start)
+		case r.Season.End != "" && m.Date > r.Season.End:
+			add("out_of_season", "match date %s is after the season ended on %s", m.Date, r.Season.End)
+		}
+	}
+
+	if r.BestOf > 0 && len(m.Sets) > 0 {
+		need := r.BestOf/2 + 1
+		var w1, w2 int
+		decidedAt := 0
+		for i, s := range m.Sets {
+			if s[0] > s[1] {
+				w1++
+			} else if s[1] > s[0] {
+				w2++
+			}
+			if decidedAt == 0 && (w1 == need || w2 == need) {
+				decidedAt = i + 1
+			}
+		}
+		switch {
+		case len(m.Sets) > r.BestOf:
+			add("too_many_sets", "%d sets recorded, but matches are best of %d", len(m.Sets), r.BestOf)
+		case decidedAt == 0:
+			add("unfinished_match", "no one won %d sets, as a best-of-%d match needs", need, r.BestOf)
+		case decidedAt < len(m.Sets):
+			add("too_many_sets", "the match was decided after %d sets, but %d are recorded", decidedAt, len(m.Sets))
+		}
+	}
+
+	if len(r.Scoring) > 0 {
+		for i, s := range m.Sets {
+			if !r.validSetScore(s, i == len(m.Sets)-1) {
+				add("invalid_score", "set %d-%d isn't a valid %s set", s[0], s[1], strings.Join(r.Scoring, " or "))
+			}
+		}
+	}
+	return problems
+}
+
+// validSetScore reports whether a set's score is possible under one of the
+// allowed scoring formats. A match tiebreak may only be the last set.
+func (r leagueRules) validSetScore(s []int, last bool) bool {
+	won, lost := s[0], s[1]
+	if lost > won {
+		won, lost = lost, won
+	}
+	for _, format := range r.Scoring {
+		switch format {
+		case scoringStandard:
+			if (won == 6 && lost <= 4) || (won == 7 && (lost == 5 || lost == 6)) {
+				return true
+			}
+		case scoringShort:
+			if (won == 4 && lost <= 2) || (won == 5 && (lost == 3 || lost == 4)) {
+				return true
+			}
+		case scoringMatchTiebreak:
+			if last && won >= 10 && (won == 10 && lost <= 8 || won > 10 && won-lost == 2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// loadRepoRules replaces the local rules with those in the .tennis.yml on
+// the repository's default branch, so every member's client enforces the
+// same rules whatever their checkout holds. A repository without one
+// uses the defaults. A dry run never contacts GitHub, so it keeps the
+// local rules.
+func loadRepoRules(ctx context.Context) error {
+	if dryRun || token == "" {
+		return nil
+	}
+	file, _, _, err := getGitHubClient().Repositories.GetContents(ctx, owner, repo, ".tennis.yml", nil)
+	if githubapi.IsNotFound(err) {
+		rules = leagueRules{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch the league rules (.tennis.yml): %w", err)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return fmt.Errorf("failed to read the league rules (.tennis.yml): %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+		return fmt.Errorf("invalid .tennis.yml in %s/%s: %w", owner, repo, err)
+	}
+	if err := cfg.Rules.check(); err != nil {
+		return fmt.Errorf("invalid .tennis.yml in %s/%s: %w", owner, repo, err)
+	}
+	rules = cfg.Rules
+	return nil
+}
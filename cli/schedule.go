@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduledJob is a tennis command "tennis serve" runs on a cron schedule,
+// declared under schedule in .tennis.yml.
+type scheduledJob struct {
+	Name string `yaml:"name"`
+	Cron string `yaml:"cron"`
+	Run  string `yaml:"run"`
+
+	schedule cronSchedule
+}
+
+// leagueSchedule is the league's scheduled jobs, loaded with elo.
+var leagueSchedule []scheduledJob
+
+// jobDateRegex matches the date placeholders a job's command may use:
+// {today} and {today-N}, N days ago.
+var jobDateRegex = regexp.MustCompile(`\{today(?:-(\d+))?\}`)
+
+// checkSchedule parses each job's cron expression and checks the names
+// are unique.
+func checkSchedule(jobs []scheduledJob) ([]scheduledJob, error) {
+	seen := make(map[string]bool)
+	checked := make([]scheduledJob, len(jobs))
+	for i, j := range jobs {
+		if j.Name == "" {
+			return nil, fmt.Errorf("schedule entry %d needs a name", i+1)
+		}
+		if seen[j.Name] {
+			return nil, fmt.Errorf("schedule: duplicate job %q", j.Name)
+		}
+		seen[j.Name] = true
+		if strings.TrimSpace(j.Run) == "" {
+			return nil, fmt.Errorf("schedule: job %q needs a run command", j.Name)
+		}
+		s, err := parseCron(j.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule: job %q: %v", j.Name, err)
+		}
+		j.schedule = s
+		checked[i] = j
+	}
+	return checked, nil
+}
+
+// args returns the job's command line as run at now, with its date
+// placeholders filled in.
+func (j scheduledJob) args(now time.Time) []string {
+	run := jobDateRegex.ReplaceAllStringFunc(j.Run, func(p string) string {
+		days, _ := strconv.Atoi(jobDateRegex.FindStringSubmatch(p)[1])
+		return now.AddDate(0, 0, -days).Format("2006-01-02")
+	})
+	return strings.Fields(strings.TrimPrefix(strings.TrimSpace(run), "tennis "))
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	scorecardWidth  = 1200
+	scorecardHeight = 630
+)
+
+// scorecardSVG draws a match as a 1200x630 SVG card, the OpenGraph image
+// size, with the players, set scores, date and each player's rating
+// change. It matches the cards scripts/scorecards.py publishes.
+func scorecardSVG(m Match, changes map[string]float64) string {
+	kind := "Singles"
+	sides := [][]string{m.Players[:1], m.Players[1:]}
+	if m.IsDoubles() {
+		kind = "Doubles"
+		sides = [][]string{m.Team1, m.Team2}
+	}
+	winner := matchWinner(m) - 1
+
+	var rows []string
+	for i, side := range sides {
+		y := 250 + i*170
+		weight, fill := "400", "#37474f"
+		if winner == i {
+			weight, fill = "700", "#1b5e20"
+		}
+		for j, p := range side {
+			py := y + j*52 - (len(side)-1)*26
+			row := fmt.Sprintf(`<text x="80" y="%d" font-size="44" font-weight="%s" fill="%s">@%s</text>`, py, weight, fill, html.EscapeString(p))
+			if change, ok := changes[p]; ok {
+				colour := "#2e7d32"
+				if change < 0 {
+					colour = "#c62828"
+				}
+				row += fmt.Sprintf(`<text x="600" y="%d" font-size="30" fill="%s" text-anchor="end">%+.1f</text>`, py, colour, change)
+			}
+			rows = append(rows, row)
+		}
+		for k, s := range m.Sets {
+			if len(s) != 2 {
+				continue
+			}
+			bold := "400"
+			if s[i] > s[1-i] {
+				bold = "700"
+			}
+			rows = append(rows, fmt.Sprintf(`<text x="%d" y="%d" font-size="56" font-weight="%s" fill="#263238" text-anchor="middle">%d</text>`, 700+k*110, y, bold, s[i]))
+		}
+		if winner == i {
+			rows = append(rows, fmt.Sprintf(`<text x="40" y="%d" font-size="36" fill="#f9a825">●</text>`, y))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		scorecardWidth, scorecardHeight, scorecardWidth, scorecardHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#f1f8e9"/>`+"\n", scorecardWidth, scorecardHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="110" fill="#2e7d32"/>`+"\n", scorecardWidth)
+	fmt.Fprintf(&b, `<text x="80" y="72" font-size="40" font-weight="700" fill="#ffffff">%s match</text>`+"\n", kind)
+	fmt.Fprintf(&b, `<text x="%d" y="72" font-size="34" fill="#ffffff" text-anchor="end">%s</text>`+"\n", scorecardWidth-80, html.EscapeString(m.Date))
+	fmt.Fprintf(&b, `<line x1="80" y1="335" x2="%d" y2="335" stroke="#c5e1a5" stroke-width="3"/>`+"\n", scorecardWidth-80)
+	b.WriteString(strings.Join(rows, "\n"))
+	b.WriteString("\n</svg>\n")
+	return b.String()
+}
+
+// ratingChanges replays matches up to the one recorded from issue in this
+// repository and returns how much it moved each player's rating.
+func ratingChanges(matches []Match, issue int, replay func(map[string]float64, []Match)) (Match, map[string]float64, bool) {
+	ratings := make(map[string]float64)
+	for i, m := range matches {
+		if m.SourceIssue != issue || m.Repo != "" {
+			continue
+		}
+		replay(ratings, matches[:i])
+		before := make(map[string]float64)
+		for _, p := range matchPlayers(m) {
+			before[p] = rating(ratings, p)
+		}
+		replay(ratings, matches[i:i+1])
+		changes := make(map[string]float64)
+		for p, r := range before {
+			changes[p] = rating(ratings, p) - r
+		}
+		return m, changes, true
+	}
+	return Match{}, nil, false
+}
+
+// matchRatingChanges replays matches in order and returns how much each
+// one moved its players' ratings, indexed like matches.
+func matchRatingChanges(matches []Match, replay func(map[string]float64, []Match)) []map[string]float64 {
+	ratings := make(map[string]float64)
+	replay(ratings, nil) // seeds imported ratings
+	out := make([]map[string]float64, len(matches))
+	for i, m := range matches {
+		changes := make(map[string]float64)
+		for _, p := range matchPlayers(m) {
+			changes[p] = -rating(ratings, p)
+		}
+		replay(ratings, matches[i:i+1])
+		for p := range changes {
+			changes[p] += rating(ratings, p)
+		}
+		out[i] = changes
+	}
+	return out
+}
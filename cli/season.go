@@ -0,0 +1,324 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SeasonStanding is a player's record over a season.
+type SeasonStanding struct {
+	Player    string `json:"player"`
+	Played    int    `json:"played"`
+	Won       int    `json:"won"`
+	Lost      int    `json:"lost"`
+	SetsWon   int    `json:"sets_won"`
+	SetsLost  int    `json:"sets_lost"`
+	GamesWon  int    `json:"games_won"`
+	GamesLost int    `json:"games_lost"`
+}
+
+// RankingEntry is one row of a final rankings table.
+type RankingEntry struct {
+	Rank   int     `json:"rank"`
+	Player string  `json:"player"`
+	Rating float64 `json:"rating"`
+}
+
+// seasonManifest describes an archived season bundle.
+type seasonManifest struct {
+	Season         string `json:"season"`
+	Generated      string `json:"generated"`
+	Repo           string `json:"repo"`
+	Commit         string `json:"commit,omitempty"`
+	SinglesMatches int    `json:"singles_matches"`
+	DoublesMatches int    `json:"doubles_matches"`
+}
+
+// inSeason returns the matches played in the given year.
+func inSeason(matches []Match, year string) []Match {
+	var out []Match
+	for _, m := range matches {
+		if strings.HasPrefix(m.Date, year+"-") {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// playedBy returns the matches dated on or before the given day, so final
+// rankings can be frozen at the end of a season.
+func playedBy(matches []Match, last string) []Match {
+	var out []Match
+	for _, m := range matches {
+		if m.Date <= last {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// seasonStandings tallies each player's matches, sets and games. Doubles
+// matches are credited to both partners. Rows are ordered by matches won,
+// then set difference, then game difference.
+func seasonStandings(matches []Match) []SeasonStanding {
+	rows := make(map[string]*SeasonStanding)
+	row := func(p string) *SeasonStanding {
+		if rows[p] == nil {
+			rows[p] = &SeasonStanding{Player: p}
+		}
+		return rows[p]
+	}
+
+	for _, m := range matches {
+		side1, side2 := m.Team1, m.Team2
+		if !m.IsDoubles() {
+			if len(m.Players) != 2 {
+				continue
+			}
+			side1, side2 = m.Players[:1], m.Players[1:]
+		}
+		winner := matchWinner(m)
+		for _, s := range m.Sets {
+			if len(s) != 2 {
+				continue
+			}
+			for _, p := range side1 {
+				r := row(p)
+				r.GamesWon += s[0]
+				r.GamesLost += s[1]
+				if s[0] > s[1] {
+					r.SetsWon++
+				} else if s[1] > s[0] {
+					r.SetsLost++
+				}
+			}
+			for _, p := range side2 {
+				r := row(p)
+				r.GamesWon += s[1]
+				r.GamesLost += s[0]
+				if s[1] > s[0] {
+					r.SetsWon++
+				} else if s[0] > s[1] {
+					r.SetsLost++
+				}
+			}
+		}
+		for _, p := range append(append([]string{}, side1...), side2...) {
+			row(p).Played++
+		}
+		for _, p := range side1 {
+			if winner == 1 {
+				row(p).Won++
+			} else if winner == 2 {
+				row(p).Lost++
+			}
+		}
+		for _, p := range side2 {
+			if winner == 2 {
+				row(p).Won++
+			} else if winner == 1 {
+				row(p).Lost++
+			}
+		}
+	}
+
+	out := make([]SeasonStanding, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Won != b.Won {
+			return a.Won > b.Won
+		}
+		if a.SetsWon-a.SetsLost != b.SetsWon-b.SetsLost {
+			return a.SetsWon-a.SetsLost > b.SetsWon-b.SetsLost
+		}
+		if a.GamesWon-a.GamesLost != b.GamesWon-b.GamesLost {
+			return a.GamesWon-a.GamesLost > b.GamesWon-b.GamesLost
+		}
+		return a.Player < b.Player
+	})
+	return out
+}
+
+// rankRatings orders ratings highest first, rounded to one decimal place.
+func rankRatings(ratings map[string]float64) []RankingEntry {
+	entries := make([]RankingEntry, 0, len(ratings))
+	for p, r := range ratings {
+		entries = append(entries, RankingEntry{Player: p, Rating: math.Round(r*10) / 10})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Rating != entries[j].Rating {
+			return entries[i].Rating > entries[j].Rating
+		}
+		return entries[i].Player < entries[j].Player
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+// writeSeasonArchive writes a frozen bundle for the season into dir: the
+// season's match files as recorded, per-player standings, the rankings as
+// they stood at the end of the season, a manifest and a README.
+func writeSeasonArchive(year, dir string, singles, doubles []Match) (*seasonManifest, error) {
+	seasonSingles, seasonDoubles := inSeason(singles, year), inSeason(doubles, year)
+	if len(seasonSingles)+len(seasonDoubles) == 0 {
+		return nil, fmt.Errorf("no matches recorded in %s", year)
+	}
+	last := year + "-12-31"
+
+	for sub, matches := range map[string][]Match{"singles-matches": seasonSingles, "doubles-matches": seasonDoubles} {
+		for _, m := range matches {
+			target := filepath.Join(dir, sub, filepath.Base(m.File))
+			if _, err := os.Stat(m.File); err != nil && m.Repo != "" {
+				// Downloaded from another league repository, not on disk.
+				if err := writeYAMLFile(target, m); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err := copyFile(m.File, target); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := writeStandingsCSV(filepath.Join(dir, "standings-singles.csv"), seasonStandings(seasonSingles)); err != nil {
+		return nil, err
+	}
+	if err := writeStandingsCSV(filepath.Join(dir, "standings-doubles.csv"), seasonStandings(seasonDoubles)); err != nil {
+		return nil, err
+	}
+
+	rankings := map[string][]RankingEntry{
+		"singles": rankRatings(computeSinglesRatings(playedBy(singles, last))),
+		"doubles": rankRatings(computeDoublesRatings(playedBy(doubles, last))),
+	}
+	if err := writeJSONFile(filepath.Join(dir, "rankings.json"), rankings); err != nil {
+		return nil, err
+	}
+
+	manifest := &seasonManifest{
+		Season:         year,
+		Generated:      time.Now().UTC().Format(time.RFC3339),
+		Repo:           owner + "/" + repo,
+		Commit:         gitHead(),
+		SinglesMatches: len(seasonSingles),
+		DoublesMatches: len(seasonDoubles),
+	}
+	if err := writeJSONFile(filepath.Join(dir, "manifest.json"), manifest); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s season archive\n\n", year)
+	fmt.Fprintf(&b, "%d singles and %d doubles matches, archived from %s", manifest.SinglesMatches, manifest.DoublesMatches, manifest.Repo)
+	if manifest.Commit != "" {
+		fmt.Fprintf(&b, " at %s", manifest.Commit[:min(len(manifest.Commit), 12)])
+	}
+	b.WriteString(".\n\n## Final singles rankings\n\n| Rank | Player | Rating |\n| ---: | --- | ---: |\n")
+	for _, e := range rankings["singles"] {
+		fmt.Fprintf(&b, "| %d | @%s | %.1f |\n", e.Rank, e.Player, e.Rating)
+	}
+	b.WriteString("\nStandings are in `standings-*.csv`, final ratings in `rankings.json` and the match files as recorded in `singles-matches/` and `doubles-matches/`.\n")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(b.String()), 0o644); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeStandingsCSV(path string, rows []SeasonStanding) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"player", "played", "won", "lost", "sets_won", "sets_lost", "games_won", "games_lost"})
+	for _, r := range rows {
+		w.Write([]string{r.Player, strconv.Itoa(r.Played), strconv.Itoa(r.Won), strconv.Itoa(r.Lost),
+			strconv.Itoa(r.SetsWon), strconv.Itoa(r.SetsLost), strconv.Itoa(r.GamesWon), strconv.Itoa(r.GamesLost)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}
+
+// gitHead returns the commit the league checkout is at, or "".
+func gitHead() string {
+	out, err := exec.Command("git", "-C", leagueDir(), "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// zipDir writes every file under dir into a zip archive at path, with
+// paths relative to dir's parent so the archive unpacks into one folder.
+func zipDir(dir, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	base := filepath.Dir(dir)
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		w, err := zw.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(w, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
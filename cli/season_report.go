@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// awardMinMatches is how many matches a player needs in a season to be
+// considered for the best-record award.
+const awardMinMatches = 5
+
+// seasonAward is one line of a season report's awards.
+type seasonAward struct {
+	Name   string
+	Player string
+	Detail string
+}
+
+// seasonReportMarkdown renders a season's report: awards, singles and
+// doubles standings, and charts of activity and final ratings.
+func seasonReportMarkdown(year string, singles, doubles []Match) (string, error) {
+	seasonSingles, seasonDoubles := inSeason(singles, year), inSeason(doubles, year)
+	if len(seasonSingles)+len(seasonDoubles) == 0 {
+		return "", fmt.Errorf("no matches recorded in %s", year)
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return "", fmt.Errorf("invalid season '%s'. Use a year like 2024", year)
+	}
+	first, last := year+"-01-01", year+"-12-31"
+	before := computeSinglesRatings(playedBy(singles, fmt.Sprintf("%d-12-31", y-1)))
+	after := computeSinglesRatings(playedBy(singles, last))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s season report\n\n", year)
+	fmt.Fprintf(&b, "%d singles and %d doubles matches from %s to %s. Generated %s.\n",
+		len(seasonSingles), len(seasonDoubles), first, last, time.Now().Format("2 January 2006"))
+
+	b.WriteString("\n## Awards\n\n")
+	awards := seasonAwards(seasonSingles, seasonDoubles, before, after)
+	if len(awards) == 0 {
+		b.WriteString("No awards: not enough matches.\n")
+	}
+	for _, a := range awards {
+		fmt.Fprintf(&b, "- **%s:** @%s (%s)\n", a.Name, a.Player, a.Detail)
+	}
+
+	for _, kind := range []struct {
+		title   string
+		matches []Match
+		all     []Match
+	}{{"Singles", seasonSingles, singles}, {"Doubles", seasonDoubles, doubles}} {
+		if len(kind.matches) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s standings\n\n", kind.title)
+		expectations := winsAboveExpectation(kind.all, first, last)
+		var rows [][]string
+		for i, r := range seasonStandings(kind.matches) {
+			e, _ := expectationOf(expectations, r.Player)
+			rows = append(rows, []string{fmt.Sprint(i + 1), "@" + r.Player, fmt.Sprint(r.Played), fmt.Sprint(r.Won), fmt.Sprint(r.Lost),
+				fmt.Sprintf("%d-%d", r.SetsWon, r.SetsLost), fmt.Sprintf("%d-%d", r.GamesWon, r.GamesLost),
+				fmt.Sprintf("%.1f", e.Expected), fmt.Sprintf("%+.1f", e.Above())})
+		}
+		writeAlignedTable(&b, []string{"#", "Player", "P", "W", "L", "Sets", "Games", "xW", "+/-"}, rows, []bool{true, false, true, true, true, true, true, true, true})
+		if n := len(expectations); n > 1 {
+			over, under := expectations[0], expectations[n-1]
+			fmt.Fprintf(&b, "\nxW is the wins expected from ratings going into each match; +/- is the wins above it. ")
+			fmt.Fprintf(&b, "Most above expectation: @%s (%+.1f). Most below: @%s (%+.1f).\n", over.Player, over.Above(), under.Player, under.Above())
+		}
+	}
+
+	b.WriteString("\n## Matches per month\n\n```\n")
+	perMonth := make([]float64, 12)
+	for _, m := range append(append([]Match{}, seasonSingles...), seasonDoubles...) {
+		if t, err := time.Parse("2006-01-02", m.Date); err == nil {
+			perMonth[t.Month()-1]++
+		}
+	}
+	var months []string
+	for i := range perMonth {
+		months = append(months, time.Month(i + 1).String()[:3])
+	}
+	writeBarChart(&b, months, perMonth, 0, "%.0f")
+	b.WriteString("```\n")
+
+	if board := rankRatings(onlyPlayers(after, seasonStandings(seasonSingles))); len(board) > 0 {
+		b.WriteString("\n## Final singles ratings\n\n```\n")
+		var labels []string
+		var values []float64
+		for _, e := range board[:min(len(board), 10)] {
+			labels = append(labels, "@"+e.Player)
+			values = append(values, e.Rating)
+		}
+		// Bars start a little below the lowest rating so differences show.
+		floor := values[len(values)-1] - 50
+		writeBarChart(&b, labels, values, floor, "%.1f")
+		b.WriteString("```\n")
+	}
+	return b.String(), nil
+}
+
+// seasonAwards picks the season's award winners: the singles champion
+// (highest rating at the end of the season), the most improved singles
+// player, the most active player, the best record and the longest singles
+// winning streak.
+func seasonAwards(singles, doubles []Match, before, after map[string]float64) []seasonAward {
+	var awards []seasonAward
+	standings := seasonStandings(singles)
+
+	if board := rankRatings(onlyPlayers(after, standings)); len(board) > 0 {
+		awards = append(awards, seasonAward{"Singles champion", board[0].Player, fmt.Sprintf("rating %.1f", board[0].Rating)})
+	}
+
+	best, gain := "", 0.0
+	for _, r := range standings {
+		if g := rating(after, r.Player) - rating(before, r.Player); best == "" || g > gain || (g == gain && r.Player < best) {
+			best, gain = r.Player, g
+		}
+	}
+	if best != "" && gain > 0 {
+		awards = append(awards, seasonAward{"Most improved", best, fmt.Sprintf("%+.1f rating", gain)})
+	}
+
+	all := seasonStandings(append(append([]Match{}, singles...), doubles...))
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Played > all[j].Played })
+	if len(all) > 0 {
+		awards = append(awards, seasonAward{"Most active", all[0].Player, fmt.Sprintf("%d matches", all[0].Played)})
+	}
+
+	var record *SeasonStanding
+	for i, r := range all {
+		if r.Played < awardMinMatches {
+			continue
+		}
+		if record == nil || r.Won*record.Played > record.Won*r.Played {
+			record = &all[i]
+		}
+	}
+	if record != nil {
+		awards = append(awards, seasonAward{"Best record", record.Player,
+			fmt.Sprintf("%d-%d, %d%% won", record.Won, record.Lost, 100*record.Won/record.Played)})
+	}
+
+	streaks := make(map[string]int)
+	longest := make(map[string]int)
+	for _, m := range singles {
+		w := matchWinner(m)
+		if len(m.Players) != 2 || w == 0 {
+			continue
+		}
+		winner, loser := m.Players[w-1], m.Players[2-w]
+		streaks[winner]++
+		streaks[loser] = 0
+		longest[winner] = max(longest[winner], streaks[winner])
+	}
+	streaker, streak := "", 0
+	for p, n := range longest {
+		if n > streak || (n == streak && p < streaker) {
+			streaker, streak = p, n
+		}
+	}
+	if streak > 1 {
+		awards = append(awards, seasonAward{"Longest winning streak", streaker, fmt.Sprintf("%d singles matches", streak)})
+	}
+	return awards
+}
+
+// onlyPlayers keeps the ratings of the players in the standings.
+func onlyPlayers(ratings map[string]float64, standings []SeasonStanding) map[string]float64 {
+	out := make(map[string]float64)
+	for _, r := range standings {
+		out[r.Player] = rating(ratings, r.Player)
+	}
+	return out
+}
+
+// writeAlignedTable writes a markdown table padded so it also reads as a
+// plain-text table (as in the PDF). right marks right-aligned columns.
+func writeAlignedTable(b *strings.Builder, header []string, rows [][]string, right []bool) {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, c := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(c), 3)
+		}
+	}
+	line := func(cells []string) {
+		b.WriteString("|")
+		for i, c := range cells {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c))
+			if right[i] {
+				fmt.Fprintf(b, " %s%s |", pad, c)
+			} else {
+				fmt.Fprintf(b, " %s%s |", c, pad)
+			}
+		}
+		b.WriteString("\n")
+	}
+	line(header)
+	b.WriteString("|")
+	for i, w := range widths {
+		if right[i] {
+			fmt.Fprintf(b, " %s: |", strings.Repeat("-", w-1))
+		} else {
+			fmt.Fprintf(b, " %s |", strings.Repeat("-", w))
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range rows {
+		line(row)
+	}
+}
+
+// writeBarChart writes a horizontal bar chart, bars measured from floor.
+func writeBarChart(b *strings.Builder, labels []string, values []float64, floor float64, format string) {
+	const width = 40
+	labelWidth, top := 0, floor
+	for i, l := range labels {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(l))
+		top = max(top, values[i])
+	}
+	for i, l := range labels {
+		n := 0
+		if top > floor {
+			n = int((values[i] - floor) / (top - floor) * width)
+		}
+		fmt.Fprintf(b, "%-*s %s %s\n", labelWidth, l, strings.Repeat("█", max(n, 0)), fmt.Sprintf(format, values[i]))
+	}
+}
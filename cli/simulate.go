@@ -0,0 +1,123 @@
+package main
+
+import (
+	"math/rand"
+)
+
+// seasonOdds is a player's chances over the simulated ends of a division
+// period, as fractions of the runs.
+type seasonOdds struct {
+	Player    string
+	Division  int
+	Rating    float64
+	Remaining int // fixtures left to play
+	Win       float64
+	Top3      float64
+	Promoted  float64
+	Relegated float64
+}
+
+// remainingFixtures returns the pairs of players who haven't yet played a
+// decided singles match against each other among matches: what's left of
+// the round robin.
+func remainingFixtures(players []string, matches []Match) [][2]string {
+	played := make(map[[2]string]bool)
+	for _, m := range matches {
+		if len(m.Players) == 2 && matchWinner(m) != 0 {
+			played[[2]string{m.Players[0], m.Players[1]}] = true
+			played[[2]string{m.Players[1], m.Players[0]}] = true
+		}
+	}
+	var left [][2]string
+	for i, a := range players {
+		for _, b := range players[i+1:] {
+			if !played[[2]string{a, b}] {
+				left = append(left, [2]string{a, b})
+			}
+		}
+	}
+	return left
+}
+
+// simulateMatch plays a best-of-three singles match between a and b on
+// their ratings. As in the rankings every set is an independent Elo event,
+// so a wins each set with their expected score; the loser of a set takes
+// 0 to 4 games.
+func simulateMatch(rng *rand.Rand, a, b string, ratings map[string]float64) Match {
+	p := expectedScore(rating(ratings, a), rating(ratings, b))
+	m := Match{Players: []string{a, b}}
+	won, lost := 0, 0
+	for won < 2 && lost < 2 {
+		games := rng.Intn(5)
+		if rng.Float64() < p {
+			m.Sets = append(m.Sets, []int{6, games})
+			won++
+		} else {
+			m.Sets = append(m.Sets, []int{games, 6})
+			lost++
+		}
+	}
+	return m
+}
+
+// simulateDivisions plays out the remaining fixtures of every division
+// runs times on fixed ratings, ranking each division as its standings are
+// ranked, and returns each player's odds in division order. Like "divisions
+// rotate", the top moves players of a division are promoted and the bottom
+// moves relegated.
+func simulateDivisions(rng *rand.Rand, divisions []int, members map[int][]string, period []Match, ratings map[string]float64, moves, runs int) []seasonOdds {
+	var odds []seasonOdds
+	index := make(map[string]int)
+	fixtures := make([][][2]string, len(divisions))
+	for i, d := range divisions {
+		fixtures[i] = remainingFixtures(members[d], period)
+		for _, p := range members[d] {
+			index[p] = len(odds)
+			odds = append(odds, seasonOdds{Player: p, Division: d, Rating: rating(ratings, p)})
+		}
+		for _, f := range fixtures[i] {
+			odds[index[f[0]]].Remaining++
+			odds[index[f[1]]].Remaining++
+		}
+	}
+
+	for run := 0; run < runs; run++ {
+		tables := make([][]BoxStanding, len(divisions))
+		for i, d := range divisions {
+			matches := append([]Match{}, period...)
+			for _, f := range fixtures[i] {
+				matches = append(matches, simulateMatch(rng, f[0], f[1], ratings))
+			}
+			tables[i] = standingsTable(members[d], matches)
+			for pos, r := range tables[i] {
+				o := &odds[index[r.Player]]
+				if pos == 0 {
+					o.Win++
+				}
+				if pos < 3 {
+					o.Top3++
+				}
+			}
+		}
+		for i, players := range swapAdjacent(tables, moves) {
+			for _, p := range players {
+				o := &odds[index[p]]
+				switch {
+				case divisions[i] < o.Division:
+					o.Promoted++
+				case divisions[i] > o.Division:
+					o.Relegated++
+				}
+			}
+		}
+	}
+
+	for i := range odds {
+		o := &odds[i]
+		o.Win /= float64(runs)
+		o.Top3 /= float64(runs)
+		o.Promoted /= float64(runs)
+		o.Relegated /= float64(runs)
+	}
+	return odds
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// rankingsSnapshotVersion is bumped whenever the rating rules change, so
+// snapshots taken under the old rules are replayed from scratch.
+const rankingsSnapshotVersion = 1
+
+// rankingsSnapshot is the state "tennis rankings compute" saves between
+// runs so it only has to replay matches recorded since the last one.
+type rankingsSnapshot struct {
+	Version int         `json:"version"`
+	Elo     eloParams   `json:"elo"`
+	Formula string      `json:"formula,omitempty"` // the custom formula's source, if any
+	Score   string      `json:"score,omitempty"`   // handicap.score
+	Seeds   string      `json:"seeds,omitempty"`   // seedsDigest of the imported ratings
+	Singles replayState `json:"singles"`
+	Doubles replayState `json:"doubles"`
+}
+
+// replayState is the ratings and records after replaying a prefix of the
+// match files, in the filename order loadMatches returns them.
+type replayState struct {
+	Cursor   string                     `json:"cursor"`   // name of the last match file replayed
+	Replayed int                        `json:"replayed"` // number of match files replayed
+	Digest   string                     `json:"digest"`   // hash chain over the replayed matches
+	Ratings  map[string]float64         `json:"ratings"`
+	Records  map[string]*LeaderboardRow `json:"records"`
+}
+
+func defaultSnapshotPath() string {
+	return filepath.Join(leagueDir(), ".tennis", "rankings-snapshot.json")
+}
+
+// newRankingsSnapshot is an empty snapshot for the league's current rating
+// settings, which a saved snapshot must match to be resumed from.
+func newRankingsSnapshot() *rankingsSnapshot {
+	snap := &rankingsSnapshot{Version: rankingsSnapshotVersion, Elo: elo, Score: handicap.Score, Seeds: seedsDigest()}
+	if customFormula != nil {
+		snap.Formula = customFormula.Source
+	}
+	return snap
+}
+
+// seedsDigest identifies the imported ratings players start from, or is
+// empty if there are none. Imported ratings only seed players not yet
+// rated, so a snapshot taken before an import can't be brought up to date.
+func seedsDigest() string {
+	if len(importedSingles) == 0 && len(importedDoubles) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for _, seeds := range []map[string]float64{importedSingles, importedDoubles} {
+		players := make([]string, 0, len(seeds))
+		for p := range seeds {
+			players = append(players, p)
+		}
+		sort.Strings(players)
+		for _, p := range players {
+			fmt.Fprintf(h, "%s %g\n", p, seeds[p])
+		}
+		fmt.Fprintln(h)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// loadRankingsSnapshot reads a snapshot. A missing, unreadable or outdated
+// snapshot, or one taken with other Elo parameters, another formula,
+// another handicap.score or other imported ratings, yields an empty one,
+// which replays everything.
+func loadRankingsSnapshot(path string) *rankingsSnapshot {
+	snap := newRankingsSnapshot()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snap
+	}
+	var saved rankingsSnapshot
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Version != rankingsSnapshotVersion || saved.Elo != elo || saved.Formula != snap.Formula ||
+		saved.Score != snap.Score || saved.Seeds != snap.Seeds {
+		return snap
+	}
+	return &saved
+}
+
+func saveRankingsSnapshot(path string, snap *rankingsSnapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeJSONFile(path, snap)
+}
+
+// advance brings the state up to date with matches, replaying only those
+// after the cursor. If any match already replayed has since been edited,
+// removed or had one inserted before it, the state is rebuilt from
+// scratch. It returns how many matches were replayed and whether the
+// state was rebuilt. p, if not nil, follows the replay.
+func (st *replayState) advance(matches []Match, replay func(map[string]float64, []Match), tally func(map[string]*LeaderboardRow, []Match), p *progress) (int, bool) {
+	start, digest := 0, ""
+	if st.Replayed > 0 && st.Replayed <= len(matches) && filepath.Base(matches[st.Replayed-1].File) == st.Cursor {
+		for _, m := range matches[:st.Replayed] {
+			digest = chainDigest(digest, m)
+		}
+		if digest == st.Digest {
+			start = st.Replayed
+		}
+	}
+
+	rebuilt := start == 0 && st.Replayed > 0
+	if start == 0 || st.Ratings == nil || st.Records == nil {
+		start, digest = 0, ""
+		st.Ratings = make(map[string]float64)
+		st.Records = make(map[string]*LeaderboardRow)
+	}
+
+	// Replaying in chunks gives the same state as in one go, as resuming
+	// from a snapshot does.
+	fresh := matches[start:]
+	p.SetTotal(len(fresh))
+	for i := 0; i < len(fresh); i += replayChunk {
+		chunk := fresh[i:min(i+replayChunk, len(fresh))]
+		replay(st.Ratings, chunk)
+		tally(st.Records, chunk)
+		p.Add(len(chunk))
+	}
+	p.Finish()
+	for _, m := range fresh {
+		digest = chainDigest(digest, m)
+	}
+	st.Replayed, st.Digest = len(matches), digest
+	if len(matches) > 0 {
+		st.Cursor = filepath.Base(matches[len(matches)-1].File)
+	} else {
+		st.Cursor = ""
+	}
+	return len(fresh), rebuilt
+}
+
+// replayChunk is how many matches advance replays between progress
+// updates.
+const replayChunk = 500
+
+// chainDigest extends a hash chain with a match, as its handles and scored
+// sets were loaded (so renamed handles count as an edit).
+func chainDigest(prev string, m Match) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%v\n%v\n%v\n%v\n", prev, filepath.Base(m.File), m.Date, m.Players, m.Team1, m.Team2, m.scoredSets())
+	if frozen(m) {
+		fmt.Fprintf(h, "frozen until %s\n", freeze.Until)
+	}
+	if m.forfeited() {
+		fmt.Fprintf(h, "forfeit %v %s %g\n", m.Forfeit, rules.Forfeit.Result, rules.Forfeit.Penalty)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
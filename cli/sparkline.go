@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a one-line unicode chart, scaled between their
+// lowest and highest. A flat series is drawn at mid height.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := len(sparkBlocks) / 2
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// ratingHistory replays matches one at a time and returns each player's
+// rating after every match they played, starting from their initial (or
+// imported) rating.
+func ratingHistory(matches []Match, replay func(map[string]float64, []Match)) map[string][]float64 {
+	ratings := make(map[string]float64)
+	replay(ratings, nil) // seeds imported ratings
+	history := make(map[string][]float64)
+	for i, m := range matches {
+		for _, p := range matchPlayers(m) {
+			if len(history[p]) == 0 {
+				history[p] = append(history[p], rating(ratings, p))
+			}
+		}
+		replay(ratings, matches[i:i+1])
+		for _, p := range matchPlayers(m) {
+			history[p] = append(history[p], rating(ratings, p))
+		}
+	}
+	return history
+}
+
+// formGuide returns each player's results, oldest first: W for a win, L
+// for a loss and D for a drawn match.
+func formGuide(matches []Match) map[string]string {
+	form := make(map[string]string)
+	for _, m := range matches {
+		side1, side2 := m.Team1, m.Team2
+		if !m.IsDoubles() {
+			if len(m.Players) != 2 {
+				continue
+			}
+			side1, side2 = m.Players[:1], m.Players[1:]
+		}
+		r1, r2 := "D", "D"
+		switch matchWinner(m) {
+		case 1:
+			r1, r2 = "W", "L"
+		case 2:
+			r1, r2 = "L", "W"
+		}
+		for _, p := range side1 {
+			form[p] += r1
+		}
+		for _, p := range side2 {
+			form[p] += r2
+		}
+	}
+	return form
+}
+
+// matchPlayers lists everyone who played in a match.
+func matchPlayers(m Match) []string {
+	return append(append(append([]string{}, m.Players...), m.Team1...), m.Team2...)
+}
+
+// lastN returns the last n items of s.
+func lastN[T any](s []T, n int) []T {
+	if len(s) > n {
+		return s[len(s)-n:]
+	}
+	return s
+}
+
+// lastResults returns the last n results of a form guide.
+func lastResults(form string, n int) string {
+	if len(form) > n {
+		return form[len(form)-n:]
+	}
+	return form
+}
+
+// leaderboardForm returns each player's form column for the leaderboard:
+// their last 5 results and a sparkline of their last 10 ratings.
+func leaderboardForm(matches []Match, replay func(map[string]float64, []Match)) map[string]string {
+	history := ratingHistory(matches, replay)
+	out := make(map[string]string)
+	for p, f := range formGuide(matches) {
+		out[p] = fmt.Sprintf("%-5s %s", lastResults(f, 5), sparkline(lastN(history[p], 10)))
+	}
+	return out
+}
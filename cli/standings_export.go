@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// standingsPage is a self-contained HTML page holding one leaderboard. It
+// carries its own styles and no scripts, so clubs can host it anywhere
+// and embed it in their website with an iframe.
+var standingsPage = template.Must(template.New("standings").Funcs(template.FuncMap{"name": publicName}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  body { margin: 0; padding: 12px; font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; font-size: 14px; color: #1f2328; background: transparent; }
+  h1 { font-size: 18px; margin: 0 0 8px; }
+  table { width: 100%; border-collapse: collapse; background: #fff; }
+  th, td { padding: 6px 10px; text-align: left; border-bottom: 1px solid #d0d7de; }
+  th { background: #2e7d32; color: #fff; font-weight: 600; }
+  tbody tr:nth-child(even) { background: #f6f8fa; }
+  td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+  tr.unranked td { color: #656d76; font-style: italic; }
+  .tier { display: inline-block; padding: 1px 6px; border-radius: 10px; background: #e8f5e9; color: #2e7d32; font-size: 12px; }
+  p.generated { margin: 8px 0 0; font-size: 12px; color: #656d76; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead>
+<tr><th class="num">#</th><th>Player</th><th class="num">Rating</th>{{if .Tiers}}<th>Tier</th>{{end}}<th class="num">Sets</th><th class="num">Games</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr{{if .Unranked}} class="unranked"{{end}}><td class="num">{{if .Unranked}}–{{else}}{{.Rank}}{{end}}</td><td>{{name .Player}}</td><td class="num">{{printf "%.1f" .Rating}}</td>{{if $.Tiers}}<td>{{if .Tier}}<span class="tier">{{.Tier}}</span>{{end}}</td>{{end}}<td class="num">{{.SetWins}}-{{.SetLosses}}</td><td class="num">{{.GameWins}}-{{.GameLosses}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<p class="generated">Updated {{.Generated}}</p>
+</body>
+</html>
+`))
+
+// writeStandingsHTML renders a leaderboard as a standalone HTML page.
+func writeStandingsHTML(w io.Writer, title, generated string, board []LeaderboardRow) error {
+	tiered := false
+	for _, r := range board {
+		if r.Tier != "" {
+			tiered = true
+		}
+	}
+	return standingsPage.Execute(w, struct {
+		Title     string
+		Generated string
+		Tiers     bool
+		Rows      []LeaderboardRow
+	}{title, generated, tiered, board})
+}
+
+// standingsEmbed returns an iframe snippet showing the page at url, tall
+// enough for rows rows up to a limit.
+func standingsEmbed(url, title string, rows int) string {
+	height := min(110+33*rows, 1200)
+	return fmt.Sprintf(`<iframe src="%s" title="%s" width="100%%" height="%d" style="border: 0;" loading="lazy"></iframe>`,
+		template.HTMLEscapeString(url), template.HTMLEscapeString(title), height)
+}
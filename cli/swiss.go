@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SwissEvent is a Swiss-system tournament stored under
+// tournaments/swiss/<name>.yml. Everyone plays every round against an
+// opponent on the same score where possible; nobody meets twice.
+// Players are listed in seed order.
+type SwissEvent struct {
+	Name      string           `yaml:"name"`
+	Created   string           `yaml:"created"`
+	Status    string           `yaml:"status"`
+	NumRounds int              `yaml:"num_rounds"`
+	Winner    string           `yaml:"winner,omitempty"`
+	Players   []string         `yaml:"players"`
+	Rounds    [][]SwissPairing `yaml:"rounds"`
+}
+
+// SwissPairing is one board of a round. Players[0] serves first; an empty
+// Players[1] is a bye, worth a win. Issue and Result are as for
+// TournamentMatch.
+type SwissPairing struct {
+	Players [2]string `yaml:"players,flow"`
+	Issue   int       `yaml:"issue,omitempty"`
+	Winner  string    `yaml:"winner,omitempty"`
+	Result  int       `yaml:"result,omitempty"`
+}
+
+// SwissStanding is a player's score after the rounds played so far.
+// Buchholz, the sum of their opponents' scores, breaks ties.
+type SwissStanding struct {
+	Player   string
+	Score    int
+	Buchholz int
+	Played   int
+	seed     int
+}
+
+func swissPath(name string) string {
+	return filepath.Join(leagueDir(), "tournaments", "swiss", name+".yml")
+}
+
+func loadSwissEvent(name string) (*SwissEvent, error) {
+	data, err := os.ReadFile(swissPath(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("Swiss event '%s' not found (looked in %s)", name, swissPath(name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e SwissEvent
+	if err := yaml.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("invalid Swiss event file %s: %v", swissPath(name), err)
+	}
+
+	// Follow renames so pairings keep matching recorded results.
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range e.Players {
+		e.Players[i] = resolveAlias(aliases, p)
+	}
+	for r := range e.Rounds {
+		for i := range e.Rounds[r] {
+			p := &e.Rounds[r][i]
+			for j, h := range p.Players {
+				if h != "" {
+					p.Players[j] = resolveAlias(aliases, h)
+				}
+			}
+			if p.Winner != "" {
+				p.Winner = resolveAlias(aliases, p.Winner)
+			}
+		}
+	}
+	if e.Winner != "" {
+		e.Winner = resolveAlias(aliases, e.Winner)
+	}
+	return &e, nil
+}
+
+func saveSwissEvent(e *SwissEvent) error {
+	return writeYAMLFile(swissPath(e.Name), e)
+}
+
+// Label returns the per-event issue label, shared with knockout draws.
+func (e *SwissEvent) Label() string {
+	return "tournament:" + e.Name
+}
+
+// IsBye reports whether the board has only one player.
+func (p SwissPairing) IsBye() bool {
+	return p.Players[1] == ""
+}
+
+// swissStandings ranks players by score, then Buchholz, then seed.
+func swissStandings(e *SwissEvent) []SwissStanding {
+	byPlayer := make(map[string]*SwissStanding)
+	rows := make([]*SwissStanding, len(e.Players))
+	for i, p := range e.Players {
+		rows[i] = &SwissStanding{Player: p, seed: i}
+		byPlayer[p] = rows[i]
+	}
+	opponents := make(map[string][]string)
+	for _, round := range e.Rounds {
+		for _, p := range round {
+			if p.Winner != "" {
+				byPlayer[p.Winner].Score++
+			}
+			if p.IsBye() {
+				continue
+			}
+			if p.Winner != "" {
+				byPlayer[p.Players[0]].Played++
+				byPlayer[p.Players[1]].Played++
+			}
+			opponents[p.Players[0]] = append(opponents[p.Players[0]], p.Players[1])
+			opponents[p.Players[1]] = append(opponents[p.Players[1]], p.Players[0])
+		}
+	}
+	for _, r := range rows {
+		for _, o := range opponents[r.Player] {
+			r.Buchholz += byPlayer[o].Score
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Buchholz != b.Buchholz {
+			return a.Buchholz > b.Buchholz
+		}
+		return a.seed < b.seed
+	})
+	out := make([]SwissStanding, len(rows))
+	for i, r := range rows {
+		out[i] = *r
+	}
+	return out
+}
+
+// pairSwissRound pairs the next round. Players are taken in standings
+// order, and within each score group the top half plays the bottom half
+// (1 v 3 and 2 v 4 in a group of four). Whoever can't be paired in their
+// group without a rematch plays down into the next. With an odd number
+// of players, the lowest-placed player without a bye sits out. It returns
+// false if no pairing avoids a rematch.
+func pairSwissRound(e *SwissEvent) ([]SwissPairing, bool) {
+	met := make(map[string]bool)
+	byes := make(map[string]bool)
+	served := make(map[string]int)
+	servedLast := make(map[string]bool)
+	for r, round := range e.Rounds {
+		for _, p := range round {
+			if p.IsBye() {
+				byes[p.Players[0]] = true
+				continue
+			}
+			met[pairKey(p.Players[0], p.Players[1])] = true
+			served[p.Players[0]]++
+			served[p.Players[1]]--
+			if r == len(e.Rounds)-1 {
+				servedLast[p.Players[0]] = true
+			}
+		}
+	}
+
+	var order []string
+	score := make(map[string]int)
+	for _, s := range swissStandings(e) {
+		order = append(order, s.Player)
+		score[s.Player] = s.Score
+	}
+
+	var pairs [][2]string
+	var pair func(rest []string) bool
+	pair = func(rest []string) bool {
+		if len(rest) == 0 {
+			return true
+		}
+		first := rest[0]
+		group := 1
+		for group < len(rest) && score[rest[group]] == score[first] {
+			group++
+		}
+		for _, i := range swissCandidates(group, len(rest)) {
+			if met[pairKey(first, rest[i])] {
+				continue
+			}
+			remaining := append(append([]string{}, rest[1:i]...), rest[i+1:]...)
+			pairs = append(pairs, [2]string{first, rest[i]})
+			if pair(remaining) {
+				return true
+			}
+			pairs = pairs[:len(pairs)-1]
+		}
+		return false
+	}
+
+	bye := ""
+	if len(order)%2 == 0 {
+		if !pair(order) {
+			return nil, false
+		}
+	} else {
+		for i := len(order) - 1; i >= 0 && bye == ""; i-- {
+			if byes[order[i]] {
+				continue
+			}
+			rest := append(append([]string{}, order[:i]...), order[i+1:]...)
+			if pair(rest) {
+				bye = order[i]
+			}
+		}
+		if bye == "" {
+			return nil, false
+		}
+	}
+
+	var round []SwissPairing
+	for _, p := range pairs {
+		a, b := p[0], p[1]
+		// Whoever has served first less often serves first; then whoever
+		// didn't serve first last round; then the higher-placed player.
+		if served[b] < served[a] || (served[b] == served[a] && servedLast[a] && !servedLast[b]) {
+			a, b = b, a
+		}
+		round = append(round, SwissPairing{Players: [2]string{a, b}})
+	}
+	if bye != "" {
+		round = append(round, SwissPairing{Players: [2]string{bye, ""}, Winner: bye})
+	}
+	return round, true
+}
+
+// swissCandidates orders the opponents tried for the top player of a
+// list of n: first the rest of their score group of size group, from the
+// middle down and then back up from the middle, then everyone below.
+func swissCandidates(group, n int) []int {
+	var order []int
+	for i := group / 2; i < group; i++ {
+		if i > 0 {
+			order = append(order, i)
+		}
+	}
+	for i := group/2 - 1; i > 0; i-- {
+		order = append(order, i)
+	}
+	for i := group; i < n; i++ {
+		order = append(order, i)
+	}
+	return order
+}
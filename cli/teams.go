@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TeamLeague is an inter-club team competition stored under
+// teams/<name>.yml. Every pair of clubs meets once in a tie of Rubbers
+// matches. A rubber is a singles or doubles match, dated from Start to End,
+// with one club's players on one side and the other club's on the other;
+// the first Rubbers such matches between two clubs make up their tie.
+type TeamLeague struct {
+	Name    string `yaml:"name"`
+	Start   string `yaml:"start"`
+	End     string `yaml:"end,omitempty"`
+	Rubbers int    `yaml:"rubbers"`
+	Clubs   []Club `yaml:"clubs"`
+}
+
+// Club is a team in a team league and the players who play for it.
+type Club struct {
+	Name    string   `yaml:"name"`
+	Players []string `yaml:"players,flow"`
+}
+
+// TeamTie is a meeting of two clubs and the rubbers played in it so far.
+// Won counts the rubbers each club won; drawn rubbers count for neither.
+type TeamTie struct {
+	Clubs   [2]string
+	Rubbers []Match
+	Won     [2]int
+}
+
+// ClubStanding is a club's record in the team league table.
+type ClubStanding struct {
+	Club        string
+	Played      int
+	Won         int
+	Drawn       int
+	Lost        int
+	RubbersWon  int
+	RubbersLost int
+	Points      int
+	order       int
+}
+
+// Points for a tie won or drawn; a lost tie scores nothing.
+const (
+	tieWinPoints  = 2
+	tieDrawPoints = 1
+)
+
+func teamLeaguePath(name string) string {
+	return filepath.Join(leagueDir(), "teams", name+".yml")
+}
+
+func loadTeamLeague(name string) (*TeamLeague, error) {
+	data, err := os.ReadFile(teamLeaguePath(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("team league '%s' not found (looked in %s)", name, teamLeaguePath(name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l TeamLeague
+	if err := yaml.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("invalid team league file %s: %v", teamLeaguePath(name), err)
+	}
+	if l.Rubbers < 1 {
+		return nil, fmt.Errorf("invalid team league file %s: rubbers must be at least 1", teamLeaguePath(name))
+	}
+
+	// Follow renames so clubs keep matching recorded results.
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	for i := range l.Clubs {
+		for j, p := range l.Clubs[i].Players {
+			l.Clubs[i].Players[j] = resolveAlias(aliases, p)
+		}
+	}
+	return &l, nil
+}
+
+func saveTeamLeague(l *TeamLeague) error {
+	return writeYAMLFile(teamLeaguePath(l.Name), l)
+}
+
+// Decided reports whether the tie is over: all its rubbers are played, or
+// one club has won more than half of them.
+func (t TeamTie) Decided(rubbers int) bool {
+	return len(t.Rubbers) == rubbers || 2*t.Won[0] > rubbers || 2*t.Won[1] > rubbers
+}
+
+// Winner returns the index of the club leading the tie, or -1 if it is
+// level.
+func (t TeamTie) Winner() int {
+	switch {
+	case t.Won[0] > t.Won[1]:
+		return 0
+	case t.Won[1] > t.Won[0]:
+		return 1
+	}
+	return -1
+}
+
+// clubSide returns the club every player of side belongs to, or -1 if they
+// don't all play for the same club.
+func clubSide(clubOf map[string]int, side []string) int {
+	club := -1
+	for _, p := range side {
+		c, ok := clubOf[p]
+		if !ok || club != -1 && c != club {
+			return -1
+		}
+		club = c
+	}
+	return club
+}
+
+// teamTies rolls matches up into a tie for every pair of clubs, in the
+// order the clubs are listed.
+func teamTies(l *TeamLeague, matches []Match) []TeamTie {
+	clubOf := make(map[string]int)
+	for i, c := range l.Clubs {
+		for _, p := range c.Players {
+			clubOf[p] = i
+		}
+	}
+	index := make(map[[2]int]int)
+	var ties []TeamTie
+	for i := range l.Clubs {
+		for j := i + 1; j < len(l.Clubs); j++ {
+			index[[2]int{i, j}] = len(ties)
+			ties = append(ties, TeamTie{Clubs: [2]string{l.Clubs[i].Name, l.Clubs[j].Name}})
+		}
+	}
+
+	sorted := append([]Match{}, matches...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+	for _, m := range sorted {
+		if m.Date < l.Start || l.End != "" && m.Date > l.End {
+			continue
+		}
+		side1, side2 := m.Team1, m.Team2
+		if !m.IsDoubles() {
+			if len(m.Players) != 2 {
+				continue
+			}
+			side1, side2 = m.Players[:1], m.Players[1:]
+		}
+		c1, c2 := clubSide(clubOf, side1), clubSide(clubOf, side2)
+		if c1 == -1 || c2 == -1 || c1 == c2 {
+			continue
+		}
+		key := [2]int{c1, c2}
+		if c1 > c2 {
+			key = [2]int{c2, c1}
+		}
+		t := &ties[index[key]]
+		if len(t.Rubbers) == l.Rubbers {
+			continue
+		}
+		t.Rubbers = append(t.Rubbers, m)
+		if w := matchWinner(m); w != 0 {
+			club := []int{c1, c2}[w-1]
+			if club == key[0] {
+				t.Won[0]++
+			} else {
+				t.Won[1]++
+			}
+		}
+	}
+	return ties
+}
+
+// teamStandings ranks the clubs on their decided ties: by points, then
+// rubber difference, then rubbers won, then the order they are listed in.
+func teamStandings(l *TeamLeague, ties []TeamTie) []ClubStanding {
+	rows := make([]ClubStanding, len(l.Clubs))
+	byClub := make(map[string]*ClubStanding)
+	for i, c := range l.Clubs {
+		rows[i] = ClubStanding{Club: c.Name, order: i}
+		byClub[c.Name] = &rows[i]
+	}
+	for _, t := range ties {
+		if !t.Decided(l.Rubbers) {
+			continue
+		}
+		w := t.Winner()
+		for side, name := range t.Clubs {
+			r := byClub[name]
+			r.Played++
+			r.RubbersWon += t.Won[side]
+			r.RubbersLost += t.Won[1-side]
+			switch w {
+			case -1:
+				r.Drawn++
+				r.Points += tieDrawPoints
+			case side:
+				r.Won++
+				r.Points += tieWinPoints
+			default:
+				r.Lost++
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(x, y int) bool {
+		rx, ry := rows[x], rows[y]
+		if rx.Points != ry.Points {
+			return rx.Points > ry.Points
+		}
+		if dx, dy := rx.RubbersWon-rx.RubbersLost, ry.RubbersWon-ry.RubbersLost; dx != dy {
+			return dx > dy
+		}
+		if rx.RubbersWon != ry.RubbersWon {
+			return rx.RubbersWon > ry.RubbersWon
+		}
+		return rx.order < ry.order
+	})
+	return rows
+}
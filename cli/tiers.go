@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ratingTier is a named band of the leaderboard, set in the tiers section
+// of .tennis.yml. A player belongs to the highest tier whose min_rating
+// their rating reaches; players below every tier have none.
+type ratingTier struct {
+	Name      string  `yaml:"name"`
+	MinRating float64 `yaml:"min_rating"`
+}
+
+// tiers is the league's tiers, highest first, loaded with elo.
+var tiers []ratingTier
+
+// sortTiers checks the tiers and orders them highest first.
+func sortTiers(ts []ratingTier) ([]ratingTier, error) {
+	sorted := append([]ratingTier(nil), ts...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MinRating > sorted[j].MinRating })
+	names := make(map[string]bool)
+	for i, t := range sorted {
+		if t.Name == "" {
+			return nil, fmt.Errorf("tiers: every tier needs a name")
+		}
+		if names[t.Name] {
+			return nil, fmt.Errorf("tiers: %q is listed twice", t.Name)
+		}
+		names[t.Name] = true
+		if i > 0 && t.MinRating == sorted[i-1].MinRating {
+			return nil, fmt.Errorf("tiers: %q and %q both start at %g", sorted[i-1].Name, t.Name, t.MinRating)
+		}
+	}
+	return sorted, nil
+}
+
+// tierOf returns the name of the tier a rating falls in, or "" if it is
+// below every tier.
+func tierOf(rating float64) string {
+	for _, t := range tiers {
+		if rating >= t.MinRating {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+// tierChanges lists the players whose tier differs between two boards,
+// in the order of the later one. Players only on one board are skipped.
+func tierChanges(prev, cur []LeaderboardRow) []string {
+	before := make(map[string]string)
+	for _, r := range prev {
+		before[r.Player] = r.Tier
+	}
+	label := func(tier string) string {
+		if tier == "" {
+			return "no tier"
+		}
+		return tier
+	}
+	var changes []string
+	for _, r := range cur {
+		was, ok := before[r.Player]
+		if !ok || was == r.Tier {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("@%s: %s → %s", r.Player, label(was), label(r.Tier)))
+	}
+	return changes
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// keychainService and keychainAccount name the token's entry in the
+	// OS keychain.
+	keychainService = "tennis-cli"
+	keychainAccount = "github.com"
+
+	// tokenPassphraseEnv overrides the machine-bound passphrase of the
+	// encrypted token file.
+	tokenPassphraseEnv = "TENNIS_TOKEN_PASSPHRASE"
+
+	tokenKDFIterations = 600000
+)
+
+// errNoStoredToken is returned by a tokenStore holding no token.
+var errNoStoredToken = errors.New("no token stored")
+
+// tokenStore keeps the GitHub token saved by "tennis auth login".
+type tokenStore interface {
+	Name() string
+	Get() (string, error)
+	Set(token string) error
+	Delete() error
+}
+
+// tokenStores lists the stores in the order tokens are looked up: the OS
+// keychain, then the encrypted file, then the plaintext file written with
+// --insecure-store.
+func tokenStores() []tokenStore {
+	dir := tokenStoreDir()
+	return []tokenStore{
+		keychainStore{},
+		encryptedFileStore{filepath.Join(dir, "token.enc")},
+		plainFileStore{filepath.Join(dir, "token")},
+	}
+}
+
+// tokenStoreDir is the per-user directory holding token files.
+func tokenStoreDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "tennis")
+}
+
+// storedToken returns the token saved by "tennis auth login" and the name
+// of the store holding it, or "" if none is saved.
+func storedToken() (string, string) {
+	for _, s := range tokenStores() {
+		if t, err := s.Get(); err == nil && t != "" {
+			return t, s.Name()
+		}
+	}
+	return "", ""
+}
+
+// keychainStore keeps the token in the OS keychain: the login Keychain on
+// macOS, the Secret Service (GNOME Keyring, KWallet) on Linux and the
+// Credential Manager on Windows. It drives the OS's own tools, so nothing
+// is stored if they're missing.
+type keychainStore struct{}
+
+func (keychainStore) Name() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macOS Keychain"
+	case "windows":
+		return "Windows Credential Manager"
+	}
+	return "Secret Service keyring"
+}
+
+func (keychainStore) Get() (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w")
+	case "windows":
+		cmd = winCredCommand("read")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", keychainAccount)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errNoStoredToken
+	}
+	if t := strings.TrimSpace(string(out)); t != "" {
+		return t, nil
+	}
+	return "", errNoStoredToken
+}
+
+func (s keychainStore) Set(token string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// -w on the command line is the only non-interactive way to pass
+		// the password to security(1).
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keychainService, "-a", keychainAccount, "-l", "tennis CLI GitHub token", "-w", token)
+	case "windows":
+		cmd = winCredCommand("write")
+		cmd.Stdin = strings.NewReader(token)
+	default:
+		cmd = exec.Command("secret-tool", "store", "--label=tennis CLI GitHub token", "service", keychainService, "account", keychainAccount)
+		cmd.Stdin = strings.NewReader(token)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s unavailable: %v %s", s.Name(), err, strings.TrimSpace(string(out)))
+	}
+	if t, err := s.Get(); err != nil || t != token {
+		return fmt.Errorf("%s didn't keep the token", s.Name())
+	}
+	return nil
+}
+
+func (keychainStore) Delete() error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", keychainAccount)
+	case "windows":
+		cmd = winCredCommand("delete")
+	default:
+		cmd = exec.Command("secret-tool", "clear", "service", keychainService, "account", keychainAccount)
+	}
+	// Deleting an entry that isn't there fails; either way it's gone.
+	_ = cmd.Run()
+	return nil
+}
+
+// winCredScript reads, writes or deletes the token's generic credential
+// through the Credential Manager API. The token is passed on stdin.
+const winCredScript = `Add-Type -TypeDefinition @"
+using System;
+using System.Runtime.InteropServices;
+public static class TennisCred {
+  [StructLayout(LayoutKind.Sequential, CharSet = CharSet.Unicode)]
+  public struct CREDENTIAL {
+    public int Flags; public int Type; public string TargetName; public string Comment;
+    public long LastWritten; public int CredentialBlobSize; public IntPtr CredentialBlob;
+    public int Persist; public int AttributeCount; public IntPtr Attributes;
+    public string TargetAlias; public string UserName;
+  }
+  [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+  public static extern bool CredRead(string target, int type, int flags, out IntPtr cred);
+  [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+  public static extern bool CredWrite(ref CREDENTIAL cred, int flags);
+  [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+  public static extern bool CredDelete(string target, int type, int flags);
+  [DllImport("advapi32.dll")]
+  public static extern void CredFree(IntPtr cred);
+}
+"@
+$target = '%s'
+switch ('%s') {
+  'read' {
+    $p = [IntPtr]::Zero
+    if (-not [TennisCred]::CredRead($target, 1, 0, [ref]$p)) { exit 1 }
+    $c = [Runtime.InteropServices.Marshal]::PtrToStructure($p, [type][TennisCred+CREDENTIAL])
+    [Runtime.InteropServices.Marshal]::PtrToStringUni($c.CredentialBlob, $c.CredentialBlobSize / 2)
+    [TennisCred]::CredFree($p)
+  }
+  'write' {
+    $token = [Console]::In.ReadToEnd().Trim()
+    $c = New-Object TennisCred+CREDENTIAL
+    $c.Type = 1; $c.Persist = 2; $c.TargetName = $target; $c.UserName = '%s'
+    $c.CredentialBlobSize = $token.Length * 2
+    $c.CredentialBlob = [Runtime.InteropServices.Marshal]::StringToCoTaskMemUni($token)
+    if (-not [TennisCred]::CredWrite([ref]$c, 0)) { exit 1 }
+  }
+  'delete' { [TennisCred]::CredDelete($target, 1, 0) | Out-Null }
+}`
+
+func winCredCommand(action string) *exec.Cmd {
+	script := fmt.Sprintf(winCredScript, keychainService, action, keychainAccount)
+	return exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+}
+
+// encryptedFileStore keeps the token in a file encrypted with AES-GCM. The
+// key is derived from $TENNIS_TOKEN_PASSPHRASE, or else from this machine's
+// ID and the user's home directory, so a copied file is useless elsewhere;
+// it doesn't protect the token from other programs run by the same user.
+type encryptedFileStore struct {
+	path string
+}
+
+// encryptedToken is the encrypted file's contents.
+type encryptedToken struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (encryptedFileStore) Name() string { return "encrypted file" }
+
+func (s encryptedFileStore) Get() (string, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return "", errNoStoredToken
+	}
+	if err != nil {
+		return "", err
+	}
+	var e encryptedToken
+	if err := json.Unmarshal(data, &e); err != nil || e.Version != 1 {
+		return "", fmt.Errorf("unreadable token file %s", s.path)
+	}
+	gcm, err := tokenCipher(e.Salt)
+	if err != nil {
+		return "", err
+	}
+	plain, err := gcm.Open(nil, e.Nonce, e.Ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("can't decrypt %s: wrong %s or another machine's file", s.path, tokenPassphraseEnv)
+	}
+	return string(plain), nil
+}
+
+func (s encryptedFileStore) Set(token string) error {
+	e := encryptedToken{Version: 1, Salt: make([]byte, 16)}
+	if _, err := rand.Read(e.Salt); err != nil {
+		return err
+	}
+	gcm, err := tokenCipher(e.Salt)
+	if err != nil {
+		return err
+	}
+	e.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(e.Nonce); err != nil {
+		return err
+	}
+	e.Ciphertext = gcm.Seal(nil, e.Nonce, []byte(token), nil)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(s.path, data)
+}
+
+func (s encryptedFileStore) Delete() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// tokenCipher derives the encrypted file's AES-256-GCM cipher.
+func tokenCipher(salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, tokenPassphrase(), salt, tokenKDFIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// tokenPassphrase is $TENNIS_TOKEN_PASSPHRASE, or a passphrase bound to
+// this machine and user.
+func tokenPassphrase() string {
+	if p := os.Getenv(tokenPassphraseEnv); p != "" {
+		return p
+	}
+	var id []byte
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if data, err := os.ReadFile(path); err == nil {
+			id = bytes.TrimSpace(data)
+			break
+		}
+	}
+	if len(id) == 0 {
+		host, _ := os.Hostname()
+		id = []byte(host)
+	}
+	home, _ := os.UserHomeDir()
+	return keychainService + "\x00" + string(id) + "\x00" + home
+}
+
+// plainFileStore keeps the token unencrypted, for --insecure-store.
+type plainFileStore struct {
+	path string
+}
+
+func (plainFileStore) Name() string { return "plaintext file" }
+
+func (s plainFileStore) Get() (string, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return "", errNoStoredToken
+	}
+	return strings.TrimSpace(string(data)), err
+}
+
+func (s plainFileStore) Set(token string) error {
+	return writePrivateFile(s.path, []byte(token+"\n"))
+}
+
+func (s plainFileStore) Delete() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// writePrivateFile writes data readable only by the current user.
+func writePrivateFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tournament is a single-elimination event stored under tournaments/ in the
+// league checkout. Players are listed in seed order.
+type Tournament struct {
+	Name     string            `yaml:"name"`
+	Created  string            `yaml:"created"`
+	Status   string            `yaml:"status"`
+	Champion string            `yaml:"champion,omitempty"`
+	Players  []string          `yaml:"players"`
+	Rounds   []TournamentRound `yaml:"rounds"`
+
+	// Challonge links the draw to a Challonge tournament (its id or URL
+	// slug); ChallongeMatches are the Challonge matches already imported
+	// as match issues.
+	Challonge        string `yaml:"challonge,omitempty"`
+	ChallongeMatches []int  `yaml:"challonge_matches,omitempty"`
+}
+
+// TournamentRound is one round of the draw.
+type TournamentRound struct {
+	Matches []TournamentMatch `yaml:"matches"`
+}
+
+// TournamentMatch is one slot in the draw. An empty player is a bye.
+// Issue is the fixture issue; Result is the source_issue of the recorded
+// match that decided it.
+type TournamentMatch struct {
+	Players [2]string `yaml:"players,flow"`
+	Issue   int       `yaml:"issue,omitempty"`
+	Winner  string    `yaml:"winner,omitempty"`
+	Result  int       `yaml:"result,omitempty"`
+}
+
+const (
+	tournamentInProgress = "in-progress"
+	tournamentFinished   = "finished"
+)
+
+var tournamentNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+func tournamentPath(name string) string {
+	return filepath.Join(leagueDir(), "tournaments", name+".yml")
+}
+
+func loadTournament(name string) (*Tournament, error) {
+	data, err := os.ReadFile(tournamentPath(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("tournament '%s' not found (looked in %s)", name, tournamentPath(name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var t Tournament
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid tournament file %s: %v", tournamentPath(name), err)
+	}
+
+	// Follow renames so draws keep matching recorded results.
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range t.Players {
+		t.Players[i] = resolveAlias(aliases, p)
+	}
+	for r := range t.Rounds {
+		for i := range t.Rounds[r].Matches {
+			m := &t.Rounds[r].Matches[i]
+			for j, p := range m.Players {
+				if p != "" {
+					m.Players[j] = resolveAlias(aliases, p)
+				}
+			}
+			if m.Winner != "" {
+				m.Winner = resolveAlias(aliases, m.Winner)
+			}
+		}
+	}
+	if t.Champion != "" {
+		t.Champion = resolveAlias(aliases, t.Champion)
+	}
+	return &t, nil
+}
+
+func saveTournament(t *Tournament) error {
+	return writeYAMLFile(tournamentPath(t.Name), t)
+}
+
+// Label returns the per-tournament issue label.
+func (t *Tournament) Label() string {
+	return "tournament:" + t.Name
+}
+
+// CurrentRound returns the index of the latest round in the draw.
+func (t *Tournament) CurrentRound() int {
+	return len(t.Rounds) - 1
+}
+
+// RoundName names a round by how many rounds remain, e.g. "Final".
+func (t *Tournament) RoundName(i int) string {
+	total := 0
+	for n := len(t.Players); n > 1; n = (n + 1) / 2 {
+		total++
+	}
+	switch total - i {
+	case 1:
+		return "Final"
+	case 2:
+		return "Semifinal"
+	case 3:
+		return "Quarterfinal"
+	}
+	return fmt.Sprintf("Round %d", i+1)
+}
+
+// bracketOrder returns the standard seeding layout for a draw of size n (a
+// power of two): seed positions such that 1 and 2 can only meet in the
+// final, 1-4 only in the semifinals, and so on.
+func bracketOrder(n int) []int {
+	order := []int{1}
+	for size := 2; size <= n; size *= 2 {
+		next := make([]int, 0, size)
+		for _, s := range order {
+			next = append(next, s, size+1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+// newDraw builds the first round for the seeded players, padding to a power
+// of two with byes so the top seeds receive them.
+func newDraw(players []string) TournamentRound {
+	size := 1
+	for size < len(players) {
+		size *= 2
+	}
+	order := bracketOrder(size)
+
+	var round TournamentRound
+	for i := 0; i < size; i += 2 {
+		var m TournamentMatch
+		for j, seed := range order[i : i+2] {
+			if seed <= len(players) {
+				m.Players[j] = players[seed-1]
+			}
+		}
+		round.Matches = append(round.Matches, m)
+	}
+	return round
+}
+
+// IsBye reports whether the match has only one player.
+func (m TournamentMatch) IsBye() bool {
+	return m.Players[0] == "" || m.Players[1] == ""
+}
+
+// ByeWinner returns the player advancing through a bye.
+func (m TournamentMatch) ByeWinner() string {
+	if m.Players[0] != "" {
+		return m.Players[0]
+	}
+	return m.Players[1]
+}
+
+// nextRound pairs the winners of a completed round in draw order.
+func nextRound(prev TournamentRound) TournamentRound {
+	var round TournamentRound
+	for i := 0; i+1 < len(prev.Matches); i += 2 {
+		round.Matches = append(round.Matches, TournamentMatch{
+			Players: [2]string{prev.Matches[i].Winner, prev.Matches[i+1].Winner},
+		})
+	}
+	return round
+}
+
+// seedPlayers orders players for the draw. "rating" sorts by current
+// singles rating, highest first; "list" keeps the given order.
+func seedPlayers(ctx context.Context, players []string, by string) ([]string, error) {
+	switch by {
+	case "", "list":
+		return players, nil
+	case "rating":
+		matches, err := loadSinglesMatches(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load matches: %w", err)
+		}
+		ratings := computeSinglesRatings(matches)
+		seeded := append([]string{}, players...)
+		sort.SliceStable(seeded, func(i, j int) bool {
+			ri, rj := rating(ratings, seeded[i]), rating(ratings, seeded[j])
+			if ri != rj {
+				return ri > rj
+			}
+			return seeded[i] < seeded[j]
+		})
+		return seeded, nil
+	}
+	return nil, fmt.Errorf("unknown seeding '%s' (use rating or list)", by)
+}
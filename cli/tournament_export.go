@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// drawExport is the portable JSON form of a tournament draw. Seeds come
+// first so external tournament software can import them directly.
+type drawExport struct {
+	Name     string            `json:"name"`
+	Created  string            `json:"created"`
+	Status   string            `json:"status"`
+	Champion string            `json:"champion,omitempty"`
+	Seeds    []drawExportSeed  `json:"seeds"`
+	Rounds   []drawExportRound `json:"rounds"`
+}
+
+type drawExportSeed struct {
+	Seed   int    `json:"seed"`
+	Player string `json:"player"`
+}
+
+type drawExportRound struct {
+	Name    string            `json:"name"`
+	Matches []drawExportMatch `json:"matches"`
+}
+
+type drawExportMatch struct {
+	Player1 string `json:"player1,omitempty"`
+	Player2 string `json:"player2,omitempty"`
+	Winner  string `json:"winner,omitempty"`
+	Issue   int    `json:"issue,omitempty"`
+	Result  int    `json:"result,omitempty"`
+}
+
+func newDrawExport(t *Tournament) drawExport {
+	d := drawExport{Name: t.Name, Created: t.Created, Status: t.Status, Champion: publicHandle(t.Champion)}
+	for i, p := range t.Players {
+		d.Seeds = append(d.Seeds, drawExportSeed{Seed: i + 1, Player: publicHandle(p)})
+	}
+	for r, round := range t.Rounds {
+		dr := drawExportRound{Name: t.RoundName(r)}
+		for _, m := range round.Matches {
+			dr.Matches = append(dr.Matches, drawExportMatch{
+				Player1: publicHandle(m.Players[0]),
+				Player2: publicHandle(m.Players[1]),
+				Winner:  publicHandle(m.Winner),
+				Issue:   m.Issue,
+				Result:  m.Result,
+			})
+		}
+		d.Rounds = append(d.Rounds, dr)
+	}
+	return d
+}
+
+// writeDraw renders the tournament in the requested format: json (full
+// draw), csv (seed list, as accepted by bracket tools' bulk import),
+// markdown or pdf (printable draw sheet).
+func writeDraw(w io.Writer, t *Tournament, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(newDrawExport(t))
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"seed", "name"})
+		for i, p := range t.Players {
+			cw.Write([]string{strconv.Itoa(i + 1), publicHandle(p)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "markdown", "md":
+		_, err := io.WriteString(w, drawMarkdown(t))
+		return err
+	case "pdf":
+		var lines []string
+		for _, l := range strings.Split(drawMarkdown(t), "\n") {
+			lines = append(lines, strings.TrimLeft(l, "#"))
+		}
+		return writeTextPDF(w, t.Name, lines)
+	}
+	return invalidf("unknown format '%s' (use json, csv, markdown or pdf)", format)
+}
+
+// drawMarkdown renders a printable draw sheet.
+func drawMarkdown(t *Tournament) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", t.Name)
+	fmt.Fprintf(&b, "Created %s · %s\n\n", t.Created, t.Status)
+
+	b.WriteString("## Seeds\n\n| Seed | Player |\n| ---: | --- |\n")
+	for i, p := range t.Players {
+		fmt.Fprintf(&b, "| %d | %s |\n", i+1, publicName(p))
+	}
+
+	for r, round := range t.Rounds {
+		fmt.Fprintf(&b, "\n## %s\n\n", t.RoundName(r))
+		for _, m := range round.Matches {
+			if m.IsBye() {
+				fmt.Fprintf(&b, "- %s — bye\n", publicName(m.ByeWinner()))
+				continue
+			}
+			winner := "________"
+			if m.Winner != "" {
+				winner = publicName(m.Winner)
+			}
+			fmt.Fprintf(&b, "- %s vs %s → %s\n", publicName(m.Players[0]), publicName(m.Players[1]), winner)
+		}
+	}
+
+	if t.Champion != "" {
+		fmt.Fprintf(&b, "\n**Champion:** %s\n", publicName(t.Champion))
+	}
+	return b.String()
+}
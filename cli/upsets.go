@@ -0,0 +1,65 @@
+package main
+
+import (
+	"sort"
+)
+
+// upset is a match won by the lower-rated side, with both sides' ratings
+// going in (team averages in doubles).
+type upset struct {
+	Match        Match
+	Type         string
+	Winners      []string
+	Losers       []string
+	WinnerRating float64
+	LoserRating  float64
+}
+
+// Gap is the rating difference the winners overcame.
+func (u upset) Gap() float64 {
+	return u.LoserRating - u.WinnerRating
+}
+
+// Chance is the winners' expected score going in.
+func (u upset) Chance() float64 {
+	return expectedScore(u.WinnerRating, u.LoserRating)
+}
+
+// findUpsets replays matches from the imported ratings and returns those
+// the lower-rated side won, biggest gap first.
+func findUpsets(kind string, matches []Match, seed map[string]float64) []upset {
+	ratings := make(map[string]float64)
+	seedRatings(ratings, seed)
+	var out []upset
+	for _, m := range matches {
+		side1, side2 := m.Team1, m.Team2
+		if !m.IsDoubles() && len(m.Players) == 2 {
+			side1, side2 = m.Players[:1], m.Players[1:]
+		}
+		u := upset{Match: m, Type: kind, Winners: side1, Losers: side2}
+		w := matchWinner(m)
+		if w == 2 {
+			u.Winners, u.Losers = side2, side1
+		}
+		if w != 0 && len(side1) > 0 && len(side2) > 0 {
+			u.WinnerRating, u.LoserRating = sideRating(ratings, u.Winners), sideRating(ratings, u.Losers)
+		}
+		rateMatch(m, ratings)
+		if u.Gap() > 0 {
+			out = append(out, u)
+		}
+	}
+	sortUpsets(out)
+	return out
+}
+
+// sortUpsets orders upsets by the gap overcome, biggest first, then by
+// date.
+func sortUpsets(upsets []upset) {
+	sort.SliceStable(upsets, func(i, j int) bool {
+		if upsets[i].Gap() != upsets[j].Gap() {
+			return upsets[i].Gap() > upsets[j].Gap()
+		}
+		return upsets[i].Match.Date < upsets[j].Match.Date
+	})
+}
@@ -5,6 +5,7 @@ import time
 from collections.abc import Iterable
 
 import requests
+import yaml
 
 
 """
@@ -233,3 +234,97 @@ def request_reviewers(
     resp = gh_post(url, {"reviewers": reviewers_list}, token)
     if resp.status_code not in (200, 201):
         print(f"Warn: failed to request reviewers: {resp.status_code} {resp.text}")
+
+
+GITHUB_GRAPHQL: str = f"{GITHUB_API}/graphql"
+
+
+def gh_graphql(query: str, variables: dict | None = None, token: str | None = None) -> dict:
+    """Run a GraphQL query and return its `data`.
+
+    Raises RuntimeError on HTTP or GraphQL errors so callers can fall back.
+    """
+    resp = gh_post(GITHUB_GRAPHQL, {"query": query, "variables": variables or {}}, token)
+    if resp.status_code != 200:
+        raise RuntimeError(f"GraphQL request failed: {resp.status_code} {resp.text}")
+    payload = resp.json()
+    if payload.get("errors"):
+        raise RuntimeError(f"GraphQL errors: {payload['errors']}")
+    return payload["data"]
+
+
+# Default labels of match issues, including "new-match" from before singles
+# and doubles were split. See match_issue_labels.
+MATCH_ISSUE_LABELS: tuple[str, ...] = ("new-singles-match", "new-doubles-match", "new-match")
+
+
+def match_issue_labels(path: str = ".tennis.yml") -> list[str]:
+    """Return the labels of match issues.
+
+    The singles and doubles labels can be renamed in .tennis.yml's
+    label_names section, which the tennis CLI reads too.
+    """
+    singles, doubles, legacy = MATCH_ISSUE_LABELS
+    if os.path.exists(path):
+        with open(path) as f:
+            names = (yaml.safe_load(f) or {}).get("label_names") or {}
+        singles = names.get("singles") or singles
+        doubles = names.get("doubles") or doubles
+    return [singles, doubles, legacy]
+
+# One page of match issues with everything history builds need, so a whole
+# league is fetched in (issues / 100) requests instead of one REST call per
+# issue for its comments.
+MATCH_ISSUES_QUERY: str = """
+query($owner: String!, $repo: String!, $labels: [String!], $after: String) {
+  repository(owner: $owner, name: $repo) {
+    issues(first: 100, after: $after, labels: $labels, orderBy: {field: CREATED_AT, direction: ASC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number
+        title
+        body
+        state
+        author { login }
+        labels(first: 20) { nodes { name } }
+        comments(first: 50) { nodes { author { login } body } }
+      }
+    }
+  }
+}
+"""
+
+
+def fetch_match_issues(owner: str, repo: str, token: str | None = None) -> list[dict]:
+    """Return every match issue with its body, labels and comments.
+
+    Each issue is a dict with `number`, `title`, `body`, `state`, `author`
+    (login), `labels` (names) and `comments` (dicts with `author`, `body`).
+    """
+    issues: list[dict] = []
+    after: str | None = None
+    while True:
+        data = gh_graphql(
+            MATCH_ISSUES_QUERY,
+            {"owner": owner, "repo": repo, "labels": match_issue_labels(), "after": after},
+            token,
+        )
+        page = data["repository"]["issues"]
+        for node in page["nodes"]:
+            issues.append(
+                {
+                    "number": node["number"],
+                    "title": node["title"],
+                    "body": node["body"],
+                    "state": node["state"],
+                    "author": (node.get("author") or {}).get("login"),
+                    "labels": [label["name"] for label in node["labels"]["nodes"]],
+                    "comments": [
+                        {"author": (c.get("author") or {}).get("login"), "body": c["body"]}
+                        for c in node["comments"]["nodes"]
+                    ],
+                }
+            )
+        if not page["pageInfo"]["hasNextPage"]:
+            return issues
+        after = page["pageInfo"]["endCursor"]
@@ -0,0 +1,93 @@
+"""
+Fetches player avatars for the Pages site.
+
+Rostered players get their GitHub avatar; guests (players not on the roster,
+or whose avatar can't be fetched) and players with a pseudonym get a
+generated Gravatar identicon. Avatars are cached in .tennis/avatars and only
+fetched again once they're older than AVATAR_MAX_AGE_DAYS, so a rebuild
+doesn't refetch the whole league. The site embeds copies from its own
+avatars/ directory; if an avatar can't be fetched or found in the cache, the
+player is shown without one.
+"""
+
+import hashlib
+import os
+import shutil
+import sys
+import time
+
+import requests
+
+from scripts.roster import public_handle, public_slug
+
+AVATAR_CACHE_DIR = os.path.join(".tennis", "avatars")
+AVATAR_MAX_AGE_DAYS = 7
+AVATAR_SIZE = 64
+AVATAR_TIMEOUT_SECONDS = 10
+
+
+def avatar_url(handle, guest):
+    """Return the URL to fetch a player's avatar from: their GitHub avatar,
+    or an identicon generated from their handle for a guest."""
+    if guest:
+        digest = hashlib.md5(handle.encode()).hexdigest()
+        return f"https://www.gravatar.com/avatar/{digest}?d=identicon&s={AVATAR_SIZE}"
+    return f"https://github.com/{handle}.png?size={AVATAR_SIZE}"
+
+
+def _fetch_cached(url, path, fetch, now):
+    """Return path once it holds the image at url, fetching it unless the
+    cached copy is fresh. A stale copy is kept if the fetch fails."""
+    if os.path.exists(path) and now - os.path.getmtime(path) < AVATAR_MAX_AGE_DAYS * 86400:
+        return path
+    try:
+        resp = fetch(url, timeout=AVATAR_TIMEOUT_SECONDS)
+        ok = resp.status_code == 200 and resp.headers.get("Content-Type", "").startswith("image/")
+    except requests.RequestException as e:
+        print(f"Warn: failed to fetch {url}: {e}", file=sys.stderr)
+        ok = False
+    if ok:
+        os.makedirs(os.path.dirname(path), exist_ok=True)
+        with open(path, "wb") as f:
+            f.write(resp.content)
+        return path
+    return path if os.path.exists(path) else None
+
+
+def fetch_avatars(players, roster, output_dir, cache_dir=AVATAR_CACHE_DIR, fetch=requests.get, now=None, pseudonyms=None):
+    """Fetch the avatars of `players` into `output_dir`/avatars and return
+    {handle: relative path} for the pages to embed.
+
+    Players on the roster get their GitHub avatar, falling back to an
+    identicon; everyone else is a guest and gets the identicon. Players
+    with a pseudonym get an identicon of it, saved under its page name, so
+    neither gives their handle away. Players whose avatar can't be had at
+    all are left out.
+    """
+    now = now or time.time()
+    pseudonyms = pseudonyms or {}
+    avatars = {}
+    os.makedirs(os.path.join(output_dir, "avatars"), exist_ok=True)
+    for handle in sorted(set(players)):
+        name, slug = public_handle(pseudonyms, handle), public_slug(pseudonyms, handle)
+        path = None
+        if handle in roster and handle not in pseudonyms:
+            path = _fetch_cached(avatar_url(handle, False), os.path.join(cache_dir, f"{handle}.png"), fetch, now)
+        if path is None:
+            path = _fetch_cached(avatar_url(name, True), os.path.join(cache_dir, f"{slug}.identicon.png"), fetch, now)
+        if path is None:
+            continue
+        shutil.copyfile(path, os.path.join(output_dir, "avatars", f"{slug}.png"))
+        avatars[handle] = f"avatars/{slug}.png"
+    return avatars
+
+
+def avatar_img(avatars, handle, size=24):
+    """Return an <img> tag for a player's avatar, or "" if they have none."""
+    src = (avatars or {}).get(handle)
+    if not src:
+        return ""
+    return (
+        f'<img src="{src}" alt="" width="{size}" height="{size}" '
+        f'class="rounded-circle me-1" style="vertical-align: middle;" loading="lazy">'
+    )
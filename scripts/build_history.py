@@ -6,8 +6,10 @@ from datetime import datetime, timezone
 import tempfile
 from typing import Optional
 
-from github_utils import get_repo_owner_and_name_or_default, list_issue_comments
-from scripts.elo_utils import update_elo_ratings, update_doubles_elo_ratings, normalize_team, normalize_player
+from github_utils import fetch_match_issues, get_repo_owner_and_name_or_default
+from scripts.elo_utils import CONFIG_FILE, initial_rating, scored_sets, set_k, update_elo_ratings, update_doubles_elo_ratings, normalize_team, normalize_player
+from scripts.roster import imported_ratings, load_pseudonyms, load_roster, public_handle, public_slug
+from scripts.scorecards import write_scorecards
 
 # Pre-compiled regex for efficiency
 # Matches " #123" in the bot's comment
@@ -16,23 +18,35 @@ PR_NUM_RE = re.compile(r"#(\d+)")
 ISSUE_NUM_RE = re.compile(r"-(\d+)\.yml$")
 
 
-def find_pr_number_from_comments(owner: str, repo: str, issue_number: int) -> int:
+# The Actions bot's login: "github-actions[bot]" over REST, "github-actions"
+# over GraphQL.
+BOT_LOGINS = {"github-actions[bot]", "github-actions"}
+
+
+def pr_number_from_comments(comments: list[dict]) -> int:
     """
-    Find the PR number from the comments of an issue.
+    Find the PR number in an issue's comments.
     The bot posts a comment with a link to the PR.
     """
+    for comment in comments:
+        if comment.get("author") in BOT_LOGINS:
+            match = PR_NUM_RE.search(comment.get("body") or "")
+            if match:
+                return int(match.group(1))
+    return 0
+
+
+def fetch_pr_numbers(owner: str, repo: str) -> dict[int, int]:
+    """
+    Map match issue numbers to the PR that recorded them, fetching every
+    match issue and its comments in bulk over GraphQL.
+    """
     try:
-        comments = list_issue_comments(owner, repo, issue_number)
-        for comment in comments:
-            # The bot's username is "github-actions[bot]"
-            if comment["user"]["login"] == "github-actions[bot]":
-                match = PR_NUM_RE.search(comment["body"])
-                if match:
-                    return int(match.group(1))
+        issues = fetch_match_issues(owner, repo)
     except Exception:
         # Gracefully handle GitHub API errors (missing token, rate limits, etc.)
-        pass
-    return 0
+        return {}
+    return {issue["number"]: pr_number_from_comments(issue["comments"]) for issue in issues}
 
 
 def load_matches_from_directory(directory: str, match_type: str, ratings, team_ratings=None):
@@ -40,6 +54,8 @@ def load_matches_from_directory(directory: str, match_type: str, ratings, team_r
     script_dir = os.path.dirname(os.path.abspath(__file__))
     repo_root = os.path.dirname(script_dir)
     directory = os.path.join(repo_root, directory)
+    # Players with a pseudonym are shown, and linked, by it
+    pseudonyms = load_pseudonyms(os.path.join(repo_root, CONFIG_FILE))
 
     if not os.path.exists(directory):
         return []
@@ -68,11 +84,15 @@ def load_matches_from_directory(directory: str, match_type: str, ratings, team_r
 
         sets_html = ""
         elo_changes_display = []
+        card_sets = []
+        card_changes = {}
 
-        for s in match_data["sets"]:
+        sets = scored_sets(match_data)
+        for s in sets:
             if isinstance(s, list) and len(s) == 2:
                 sets_html += f"<li>{s[0]}-{s[1]}</li>"
                 p1_games, p2_games = s[0], s[1]
+                card_sets.append([p1_games, p2_games])
             else:
                 continue
 
@@ -80,10 +100,10 @@ def load_matches_from_directory(directory: str, match_type: str, ratings, team_r
                 player1, player2 = match_data["players"]
                 winner, loser = (player1, player2) if p1_games > p2_games else (player2, player1)
 
-                old_winner_rating = ratings.get(winner, 1200)
-                old_loser_rating = ratings.get(loser, 1200)
+                old_winner_rating = ratings.get(winner, initial_rating())
+                old_loser_rating = ratings.get(loser, initial_rating())
 
-                new_winner_rating, new_loser_rating = update_elo_ratings(ratings, winner, loser)
+                new_winner_rating, new_loser_rating = update_elo_ratings(ratings, winner, loser, k=set_k(sets, s), games=s)
 
                 ratings[winner] = new_winner_rating
                 ratings[loser] = new_loser_rating
@@ -92,19 +112,21 @@ def load_matches_from_directory(directory: str, match_type: str, ratings, team_r
                 elo_change_loser = new_loser_rating - old_loser_rating
 
                 elo_changes_display.append(
-                    f'{winner}: {elo_change_winner:+.1f}, {loser}: {elo_change_loser:+.1f}'
+                    f'{public_handle(pseudonyms, winner)}: {elo_change_winner:+.1f}, {public_handle(pseudonyms, loser)}: {elo_change_loser:+.1f}'
                 )
+                card_changes[winner] = card_changes.get(winner, 0) + elo_change_winner
+                card_changes[loser] = card_changes.get(loser, 0) + elo_change_loser
 
             else: # doubles
                 team1, team2 = match_data["team1"], match_data["team2"]
                 winner_team, loser_team = (team1, team2) if p1_games > p2_games else (team2, team1)
 
-                new_rW_team, new_rL_team, new_r_w1, new_r_w2, new_r_l1, new_r_l2 = update_doubles_elo_ratings(team_ratings, ratings, winner_team, loser_team)
+                new_rW_team, new_rL_team, new_r_w1, new_r_w2, new_r_l1, new_r_l2 = update_doubles_elo_ratings(team_ratings, ratings, winner_team, loser_team, k=set_k(sets, s), games=s)
 
-                elo_change_w1 = new_r_w1 - ratings.get(winner_team[0], 1200)
-                elo_change_w2 = new_r_w2 - ratings.get(winner_team[1], 1200)
-                elo_change_l1 = new_r_l1 - ratings.get(loser_team[0], 1200)
-                elo_change_l2 = new_r_l2 - ratings.get(loser_team[1], 1200)
+                elo_change_w1 = new_r_w1 - ratings.get(winner_team[0], initial_rating())
+                elo_change_w2 = new_r_w2 - ratings.get(winner_team[1], initial_rating())
+                elo_change_l1 = new_r_l1 - ratings.get(loser_team[0], initial_rating())
+                elo_change_l2 = new_r_l2 - ratings.get(loser_team[1], initial_rating())
 
                 ratings[winner_team[0]] = new_r_w1
                 ratings[winner_team[1]] = new_r_w2
@@ -114,28 +136,42 @@ def load_matches_from_directory(directory: str, match_type: str, ratings, team_r
                 team_ratings[normalize_team(winner_team)] = new_rW_team
                 team_ratings[normalize_team(loser_team)] = new_rL_team
 
-                elo_changes_display.append(
-                    f'{winner_team[0]}: {elo_change_w1:+.1f}, {winner_team[1]}: {elo_change_w2:+.1f}, '
-                    f'{loser_team[0]}: {elo_change_l1:+.1f}, {loser_team[1]}: {elo_change_l2:+.1f}'
-                )
+                for player, change in zip(winner_team + loser_team, (elo_change_w1, elo_change_w2, elo_change_l1, elo_change_l2)):
+                    card_changes[player] = card_changes.get(player, 0) + change
+
+                elo_changes_display.append(", ".join(
+                    f'{public_handle(pseudonyms, p)}: {change:+.1f}'
+                    for p, change in zip(winner_team + loser_team, (elo_change_w1, elo_change_w2, elo_change_l1, elo_change_l2))
+                ))
 
         score_html = f"<ul>{sets_html}</ul>"
 
+        def player_link(p):
+            return f'<a href="player_profile_{public_slug(pseudonyms, p)}.html">{public_handle(pseudonyms, p)}</a>'
+
         if match_type == "singles":
             player1, player2 = match_data["players"]
-            players_display = f'<a href="player_profile_{player1}.html">{player1}</a> vs <a href="player_profile_{player2}.html">{player2}</a>'
+            players_display = f'{player_link(player1)} vs {player_link(player2)}'
         else:
-            team1_links = ", ".join([f'<a href="player_profile_{p}.html">{p}</a>' for p in match_data["team1"]])
-            team2_links = ", ".join([f'<a href="player_profile_{p}.html">{p}</a>' for p in match_data["team2"]])
+            team1_links = ", ".join([player_link(p) for p in match_data["team1"]])
+            team2_links = ", ".join([player_link(p) for p in match_data["team2"]])
             players_display = f"({team1_links}) vs ({team2_links})"
+        sides = [match_data["players"][:1], match_data["players"][1:]] if match_type == "singles" else [match_data["team1"], match_data["team2"]]
 
         matches.append({
             "date": match_data["date"],
             "players_display": players_display,
             "score": score_html,
             "issue_number": issue_number,
+            # Set on matches pulled from the league's other repositories.
+            "repo": match_data.get("repo"),
             "type": match_type.title(),
-            "elo_changes": "<br>".join(elo_changes_display)
+            "elo_changes": "<br>".join(elo_changes_display),
+            "card": {
+                "sides": [[public_handle(pseudonyms, p) for p in side] for side in sides],
+                "sets": card_sets,
+                "changes": {public_handle(pseudonyms, p): change for p, change in card_changes.items()},
+            },
         })
 
     return matches
@@ -150,7 +186,8 @@ def build_history_page(output_dir: Optional[str] = None):
     """
     owner, repo = get_repo_owner_and_name_or_default()
 
-    ratings = {}
+    # Newcomers with a rating imported from another league start from it
+    ratings = imported_ratings(load_roster(), "singles")
     team_ratings = {}
 
     # Load matches from both directories
@@ -161,21 +198,28 @@ def build_history_page(output_dir: Optional[str] = None):
     all_matches = singles_matches + doubles_matches
     all_matches.sort(key=lambda x: x["date"], reverse=True)
 
+    local_matches = [m for m in all_matches if not m["repo"]]
+
+    pr_numbers = fetch_pr_numbers(owner, repo) if os.environ.get("GITHUB_TOKEN") else {}
+
     # Generate the HTML table rows
     table_rows = ""
     for match in all_matches:
-        pr_number = 0
-        if os.environ.get("GITHUB_TOKEN"):
-            pr_number = find_pr_number_from_comments(owner, repo, match["issue_number"])
-        
-        pr_link = f'<a href="https://github.com/{owner}/{repo}/pull/{pr_number}">PR</a>' if pr_number else "N/A"
-        issue_link = f'<a href="https://github.com/{owner}/{repo}/issues/{match["issue_number"]}">Issue</a>'
+        source = match["repo"] or f"{owner}/{repo}"
+        pr_number = 0 if match["repo"] else pr_numbers.get(match["issue_number"], 0)
+
+        pr_link = f'<a href="https://github.com/{source}/pull/{pr_number}">PR</a>' if pr_number else "N/A"
+        issue_label = f'{source}#{match["issue_number"]}' if match["repo"] else "Issue"
+        issue_link = f'<a href="https://github.com/{source}/issues/{match["issue_number"]}">{issue_label}</a>'
+        # Match pages are keyed by issue number, so only this repository's
+        # matches get one.
+        date_cell = match["date"] if match["repo"] else f'<a href="match_{match["issue_number"]}.html">{match["date"]}</a>'
         
         type_badge = f'<span class="badge bg-{"primary" if match["type"] == "Singles" else "success"}">{match["type"]}</span>'
 
         table_rows += f"""
         <tr>
-            <td>{match["date"]}</td>
+            <td>{date_cell}</td>
             <td>{type_badge}</td>
             <td>{match["players_display"]}</td>
             <td>{match["score"]}</td>
@@ -259,6 +303,7 @@ def build_history_page(output_dir: Optional[str] = None):
         output_file = os.path.join(output_dir, "history.html")
         with open(output_file, "w") as f:
             f.write(html_template)
+        write_scorecards(output_dir, local_matches, owner, repo)
         return None, output_file
 
     # Standalone execution for testing or other uses
@@ -266,6 +311,7 @@ def build_history_page(output_dir: Optional[str] = None):
     output_file = os.path.join(temp_dir, "history.html")
     with open(output_file, "w") as f:
         f.write(html_template)
+    write_scorecards(temp_dir, local_matches, owner, repo)
     return temp_dir, output_file
 
 
@@ -1,10 +1,24 @@
 #!/usr/bin/env python3
+import json
 import os
 import tempfile
 import pandas as pd
 from datetime import datetime, timezone
 
 from github_utils import get_repo_owner_and_name_or_default
+from scripts.avatars import avatar_img, fetch_avatars
+from scripts.elo_utils import load_elo_params
+from scripts.ranking_points import load_points, points_enabled
+from scripts.roster import (
+    display_name,
+    load_pseudonyms,
+    load_qualification,
+    load_roster,
+    load_tiers,
+    public_handle,
+    public_slug,
+    tier_for,
+)
 
 
 """
@@ -25,7 +39,91 @@ def load_ranking_data(file_path: str, columns: list[str]):
         if col not in df.columns:
             df[col] = 0
 
-    return df.sort_values(by="rating", ascending=False).reset_index(drop=True)
+    df = df.sort_values(by="rating", ascending=False)
+    if "points" in df.columns and load_points()["rank_by"] == "points":
+        # Ranked on points, then rating, as the CLI does
+        df = df.sort_values(by="points", ascending=False, kind="stable")
+    if "unranked" in df.columns:
+        # Unranked players follow the ranked ones, still by rating
+        df["unranked"] = df["unranked"].astype(bool)
+        df = df.sort_values(by="unranked", kind="stable")
+    return df.reset_index(drop=True)
+
+
+def split_unranked(df: pd.DataFrame):
+    """Split a leaderboard into its ranked and unranked players."""
+    if "unranked" not in df.columns:
+        return df, df.iloc[0:0]
+    unranked = df["unranked"].astype(bool)
+    return df[~unranked].reset_index(drop=True), df[unranked].reset_index(drop=True)
+
+
+def tier_header(tiers):
+    """The Tier column heading, when the league has tiers."""
+    return "<th>Tier</th>" if tiers else ""
+
+
+def points_header():
+    """The Points column heading, when the league awards ranking points."""
+    return "<th>Points</th>" if points_enabled(load_points()) else ""
+
+
+def points_cell(row):
+    """A player's ranking points, when the league awards them."""
+    if not points_enabled(load_points()):
+        return ""
+    return f'<td>{float(row.get("points", 0)):.1f}</td>'
+
+
+def tier_cell(rating, tiers):
+    """A player's tier badge, when the league has tiers. The tier follows
+    the rating as rankings.json rounds it, as in the CLI."""
+    if not tiers:
+        return ""
+    tier = tier_for(round(float(rating), 1), tiers)
+    return f'<td><span class="badge text-bg-secondary">{tier}</span></td>' if tier else "<td></td>"
+
+
+def generate_unranked_table(df: pd.DataFrame, roster=None, avatars=None):
+    """Generate the HTML section listing players who don't qualify to be
+    ranked under .tennis.yml's leaderboard rules."""
+    if df.empty:
+        return ""
+    roster = roster or {}
+    rules = load_qualification()
+    rule = f'at least {rules["min_matches"]} matches'
+    if rules["window_days"]:
+        rule += f' in the last {rules["window_days"]} days'
+    tiers = load_tiers()
+    pseudonyms = load_pseudonyms()
+
+    table_rows = ""
+    for _, row in df.iterrows():
+        player = row["player"]
+        player_link = f'{avatar_img(avatars, player)}<a href="player_profile_{public_slug(pseudonyms, player)}.html">{display_name(roster, player, pseudonyms)}</a>'
+        table_rows += f"""
+        <tr>
+            <td>–</td>
+            <td>{player_link}</td>
+            <td>{int(row["rating"])}</td>
+            {points_cell(row)}
+            <td>{int(row.get("set_wins", 0))}-{int(row.get("set_losses", 0))}</td>
+            <td>{int(row.get("game_wins", 0))}-{int(row.get("game_losses", 0))}</td>
+            {tier_cell(row["rating"], tiers)}
+        </tr>
+        """
+
+    return f"""
+        <h5 class="mt-3">Unranked</h5>
+        <p class="text-muted small">Players need {rule} to be ranked.</p>
+        <div class="table-responsive">
+            <table class="table table-sm table-hover text-muted">
+                <tbody>
+                    {table_rows}
+                </tbody>
+            </table>
+        </div>
+    """
 
 
 def generate_marquee_content():
@@ -38,9 +136,10 @@ def generate_marquee_content():
         if recent_changes.empty:
             return "No recent ELO changes."
 
+        pseudonyms = load_pseudonyms()
         parts = []
         for _, row in recent_changes.iterrows():
-            player = row["player"]
+            player = public_handle(pseudonyms, row["player"])
             change = row["change"]
 
             if change > 0:
@@ -58,15 +157,19 @@ def generate_marquee_content():
         return "🎾 No ball boys were harmed in the making of these statistics • Serving up fresh rankings daily! • Love means nothing in tennis, but these scores mean everything! • Deuce you believe these rankings? • Game, Set, Match... and GitHub Issues! 🎾"
 
 
-def generate_singles_table(df: pd.DataFrame):
+def generate_singles_table(df: pd.DataFrame, roster=None, avatars=None):
     """Generate HTML table for singles leaderboard"""
+    roster = roster or {}
+    df, unranked = split_unranked(df)
     df.index += 1
     df.index.name = "Rank"
+    tiers = load_tiers()
+    pseudonyms = load_pseudonyms()
 
     table_rows = ""
     for rank, row in df.iterrows():
         player = row["player"]
-        player_link = f'<a href="player_profile_{player}.html">{player}</a>'
+        player_link = f'{avatar_img(avatars, player)}<a href="player_profile_{public_slug(pseudonyms, player)}.html">{display_name(roster, player, pseudonyms)}</a>'
         games_record = f'{int(row.get("game_wins", 0))}-{int(row.get("game_losses", 0))}'
         sets_record = f'{int(row.get("set_wins", 0))}-{int(row.get("set_losses", 0))}'
         table_rows += f"""
@@ -74,8 +177,10 @@ def generate_singles_table(df: pd.DataFrame):
             <td>{rank}</td>
             <td>{player_link}</td>
             <td>{int(row["rating"])}</td>
+            {points_cell(row)}
             <td>{sets_record}</td>
             <td>{games_record}</td>
+            {tier_cell(row["rating"], tiers)}
         </tr>
         """
 
@@ -89,8 +194,10 @@ def generate_singles_table(df: pd.DataFrame):
                         <th>Rank</th>
                         <th>Player</th>
                         <th>Rating</th>
+                        {points_header()}
                         <th>Sets W-L</th>
                         <th>Games W-L</th>
+                        {tier_header(tiers)}
                     </tr>
                 </thead>
                 <tbody>
@@ -98,6 +205,7 @@ def generate_singles_table(df: pd.DataFrame):
                 </tbody>
             </table>
         </div>
+        {generate_unranked_table(unranked, roster, avatars)}
     </div>
     """
 
@@ -107,13 +215,16 @@ def generate_doubles_table(df: pd.DataFrame):
     df.index += 1
     df.index.name = "Rank"
 
+    pseudonyms = load_pseudonyms()
     table_rows = ""
     for rank, row in df.iterrows():
         team = row["team"]
         # Split team names for individual GitHub links
         players = team.split(", ")
         if len(players) == 2:
-            team_links = f'<a href="player_profile_{players[0]}.html">{players[0]}</a>, <a href="player_profile_{players[1]}.html">{players[1]}</a>'
+            team_links = ", ".join(
+                f'<a href="player_profile_{public_slug(pseudonyms, p)}.html">{public_handle(pseudonyms, p)}</a>' for p in players
+            )
         else:
             team_links = team
 
@@ -148,15 +259,19 @@ def generate_doubles_table(df: pd.DataFrame):
     </div>
     """
 
-def generate_doubles_individual_table(df: pd.DataFrame):
+def generate_doubles_individual_table(df: pd.DataFrame, roster=None, avatars=None):
     """Generate HTML table for doubles individual leaderboard"""
+    roster = roster or {}
+    df, unranked = split_unranked(df)
     df.index += 1
     df.index.name = "Rank"
+    tiers = load_tiers()
+    pseudonyms = load_pseudonyms()
 
     table_rows = ""
     for rank, row in df.iterrows():
         player = row["player"]
-        player_link = f'<a href="player_profile_{player}.html">{player}</a>'
+        player_link = f'{avatar_img(avatars, player)}<a href="player_profile_{public_slug(pseudonyms, player)}.html">{display_name(roster, player, pseudonyms)}</a>'
         games_record = f'{int(row.get("game_wins", 0))}-{int(row.get("game_losses", 0))}'
         sets_record = f'{int(row.get("set_wins", 0))}-{int(row.get("set_losses", 0))}'
         table_rows += f"""
@@ -164,8 +279,10 @@ def generate_doubles_individual_table(df: pd.DataFrame):
             <td>{rank}</td>
             <td>{player_link}</td>
             <td>{int(row["rating"])}</td>
+            {points_cell(row)}
             <td>{sets_record}</td>
             <td>{games_record}</td>
+            {tier_cell(row["rating"], tiers)}
         </tr>
         """
 
@@ -177,8 +294,10 @@ def generate_doubles_individual_table(df: pd.DataFrame):
                     <th>Rank</th>
                     <th>Player</th>
                     <th>Rating</th>
+                    {points_header()}
                     <th>Sets W-L</th>
                     <th>Games W-L</th>
+                    {tier_header(tiers)}
                 </tr>
             </thead>
             <tbody>
@@ -186,9 +305,54 @@ def generate_doubles_individual_table(df: pd.DataFrame):
             </tbody>
         </table>
     </div>
+    {generate_unranked_table(unranked, roster, avatars)}
     """
 
 
+def leaderboard_records(df: pd.DataFrame):
+    """Return leaderboard rows as plain dicts for rankings.json. Unranked
+    players follow the ranked ones with rank 0, as `tennis rankings
+    compute` lists them."""
+    ranked, unranked = split_unranked(df)
+    records = []
+    for rank, (_, row) in enumerate(ranked.iterrows(), start=1):
+        records.append(_leaderboard_record(row, rank))
+    for _, row in unranked.iterrows():
+        records.append({**_leaderboard_record(row, 0), "unranked": True})
+    return records
+
+
+def _leaderboard_record(row, rank):
+    record = {
+        "rank": rank,
+        "player": public_handle(load_pseudonyms(), row["player"]),
+        "rating": round(float(row["rating"]), 1),
+        "set_wins": int(row.get("set_wins", 0)),
+        "set_losses": int(row.get("set_losses", 0)),
+        "game_wins": int(row.get("game_wins", 0)),
+        "game_losses": int(row.get("game_losses", 0)),
+    }
+    tier = tier_for(record["rating"], load_tiers())
+    if tier:
+        record["tier"] = tier
+    if float(row.get("points", 0) or 0):
+        record["points"] = round(float(row["points"]), 1)
+    return record
+
+
+def write_rankings_json(output_dir: str, singles_df: pd.DataFrame, doubles_individual_df: pd.DataFrame, timestamp: str):
+    """Publish the leaderboards as rankings.json so they can be verified
+    against the match data (`tennis verify rankings`)."""
+    data = {
+        "generated": timestamp,
+        "singles": leaderboard_records(singles_df),
+        "doubles_individual": leaderboard_records(doubles_individual_df),
+        "elo": load_elo_params(),
+    }
+    with open(os.path.join(output_dir, "rankings.json"), "w") as f:
+        json.dump(data, f, indent=2)
+
+
 def build_site():
     from scripts.build_history import build_history_page
     from scripts.build_player_pages import build_player_pages
@@ -199,7 +363,7 @@ def build_site():
     # --- Load ranking data ---
     singles_df = load_ranking_data(
         "temp-rankings/singles-ranking.csv",
-        ["player", "rating", "set_wins", "set_losses", "game_wins", "game_losses"]
+        ["player", "rating", "set_wins", "set_losses", "game_wins", "game_losses", "unranked", "points"]
     )
 
     doubles_df = load_ranking_data(
@@ -209,13 +373,24 @@ def build_site():
 
     doubles_individual_df = load_ranking_data(
         "temp-rankings/doubles-individual-ranking.csv",
-        ["player", "rating", "set_wins", "set_losses", "game_wins", "game_losses"]
+        ["player", "rating", "set_wins", "set_losses", "game_wins", "game_losses", "unranked", "points"]
+    )
+
+    write_rankings_json(
+        temp_dir,
+        singles_df,
+        doubles_individual_df,
+        datetime.now(timezone.utc).strftime("%Y-%m-%d %H:%M:%S UTC"),
     )
 
     # --- Generate leaderboard tables ---
-    singles_table = generate_singles_table(singles_df)
+    roster = load_roster()
+    avatars = fetch_avatars(
+        list(singles_df["player"]) + list(doubles_individual_df["player"]), roster, temp_dir, pseudonyms=load_pseudonyms()
+    )
+    singles_table = generate_singles_table(singles_df, roster, avatars)
     doubles_team_table = generate_doubles_table(doubles_df)
-    doubles_individual_table = generate_doubles_individual_table(doubles_individual_df)
+    doubles_individual_table = generate_doubles_individual_table(doubles_individual_df, roster, avatars)
 
     doubles_tab_content = f"""
     <div class="leaderboard-container">
@@ -331,7 +506,7 @@ def build_site():
     build_history_page(output_dir=temp_dir)
 
     # --- Build Player Pages ---
-    build_player_pages(output_dir=temp_dir)
+    build_player_pages(output_dir=temp_dir, avatars=avatars)
 
     return temp_dir, index_output_file
 
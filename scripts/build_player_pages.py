@@ -1,4 +1,5 @@
 import glob
+import html
 import sys
 import yaml
 import pandas as pd
@@ -10,9 +11,17 @@ import sys
 import os
 sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
 from github_utils import get_repo_owner_and_name_or_default
-from scripts.elo_utils import normalize_player
-
-K = 32
+from scripts.avatars import avatar_img
+from scripts.elo_utils import CONFIG_FILE, initial_rating, normalize_player, scored_sets, set_change, set_k
+from scripts.roster import (
+    ROSTER_FILE,
+    imported_ratings,
+    load_pseudonyms,
+    load_roster,
+    profile_summary,
+    public_handle,
+    public_slug,
+)
 PLAYER_DATA = {} # {player: {singles: {candlestick: [], scatter: []}, doubles: {candlestick: [], scatter: []}}}
 
 def expected(rA, rB):
@@ -29,11 +38,14 @@ def calculate_elo_history():
     """
     Calculates ELO history for all players for both singles and doubles.
     """
-    singles_ratings = {}
-    doubles_ratings = {}
-
     script_dir = os.path.dirname(os.path.abspath(__file__))
     repo_root = os.path.dirname(script_dir)
+
+    # Newcomers with a rating imported from another league start from it
+    roster = load_roster(os.path.join(repo_root, ROSTER_FILE))
+    pseudonyms = load_pseudonyms(os.path.join(repo_root, CONFIG_FILE))
+    singles_ratings = imported_ratings(roster, "singles")
+    doubles_ratings = imported_ratings(roster, "doubles")
     singles_matches_dir = os.path.join(repo_root, 'singles-matches', '*.yml')
     doubles_matches_dir = os.path.join(repo_root, 'doubles-matches', '*.yml')
     match_files = glob.glob(singles_matches_dir) + glob.glob(doubles_matches_dir)
@@ -77,20 +89,24 @@ def calculate_elo_history():
                 ensure_player_data(player1)
                 ensure_player_data(player2)
 
-                for s in match_data.get('sets', []):
+                for s in scored_sets(match_data):
                     p1_games, p2_games = int(s[0]), int(s[1])
                     if p1_games == p2_games: continue
 
                     winner, loser = (player1, player2) if p1_games > p2_games else (player2, player1)
 
-                    rW_before = singles_ratings.get(winner, 1200)
-                    rL_before = singles_ratings.get(loser, 1200)
+                    rW_before = singles_ratings.get(winner, initial_rating())
+                    rL_before = singles_ratings.get(loser, initial_rating())
 
                     eW = expected(rW_before, rL_before)
                     eL = expected(rL_before, rW_before)
 
-                    rW_after = rW_before + K * (1 - eW)
-                    rL_after = rL_before + K * (0 - eL)
+                    k = set_k(scored_sets(match_data), s)
+                    rW_after = rW_before + k * (1 - eW)
+                    rL_after = rL_before + k * (0 - eL)
+                    change = set_change(rW_before, rL_before, k, s)
+                    if change is not None:
+                        rW_after, rL_after = rW_before + change, rL_before - change
 
                     elo_change_winner = rW_after - rW_before
                     elo_change_loser = rL_after - rL_before
@@ -101,8 +117,8 @@ def calculate_elo_history():
                     daily_elo_changes[winner]['singles']['elos'].append(rW_after)
                     daily_elo_changes[loser]['singles']['elos'].append(rL_after)
 
-                    winner_details = {'date': date, 'opponent': loser, 'sets': f"{p1_games}-{p2_games}" if winner==player1 else f"{p2_games}-{p1_games}", 'elo_change': round(elo_change_winner), 'elo': round(rW_after), 'result': 'W', 'issue_number': issue_number}
-                    loser_details = {'date': date, 'opponent': winner, 'sets': f"{p2_games}-{p1_games}" if loser==player1 else f"{p1_games}-{p2_games}", 'elo_change': round(elo_change_loser), 'elo': round(rL_after), 'result': 'L', 'issue_number': issue_number}
+                    winner_details = {'date': date, 'opponent': public_handle(pseudonyms, loser), 'sets': f"{p1_games}-{p2_games}" if winner==player1 else f"{p2_games}-{p1_games}", 'elo_change': round(elo_change_winner), 'elo': round(rW_after), 'result': 'W', 'issue_number': issue_number}
+                    loser_details = {'date': date, 'opponent': public_handle(pseudonyms, winner), 'sets': f"{p2_games}-{p1_games}" if loser==player1 else f"{p1_games}-{p2_games}", 'elo_change': round(elo_change_loser), 'elo': round(rL_after), 'result': 'L', 'issue_number': issue_number}
                     daily_elo_changes[winner]['singles']['details'].append(winner_details)
                     daily_elo_changes[loser]['singles']['details'].append(loser_details)
 
@@ -113,31 +129,36 @@ def calculate_elo_history():
                 for p in all_players:
                     ensure_player_data(p)
 
-                r_team1_avg = sum(doubles_ratings.get(p, 1200) for p in team1) / 2
-                r_team2_avg = sum(doubles_ratings.get(p, 1200) for p in team2) / 2
+                r_team1_avg = sum(doubles_ratings.get(p, initial_rating()) for p in team1) / 2
+                r_team2_avg = sum(doubles_ratings.get(p, initial_rating()) for p in team2) / 2
 
-                for s in match_data.get('sets', []):
+                for s in scored_sets(match_data):
                     t1_games, t2_games = int(s[0]), int(s[1])
                     if t1_games == t2_games: continue
 
                     winning_team, losing_team = (team1, team2) if t1_games > t2_games else (team2, team1)
                     e_win = expected(r_team1_avg, r_team2_avg) if winning_team == team1 else expected(r_team2_avg, r_team1_avg)
-                    elo_change_per_player = K * (1 - e_win) / 2
+                    k = set_k(scored_sets(match_data), s)
+                    elo_change_per_player = k * (1 - e_win) / 2
+                    r_win, r_lose = (r_team1_avg, r_team2_avg) if winning_team == team1 else (r_team2_avg, r_team1_avg)
+                    change = set_change(r_win, r_lose, k, s, doubles=True)
+                    if change is not None:
+                        elo_change_per_player = change / 2
 
                     for p in winning_team:
-                        r_before = doubles_ratings.get(p, 1200)
+                        r_before = doubles_ratings.get(p, initial_rating())
                         r_after = r_before + elo_change_per_player
                         doubles_ratings[p] = r_after
                         daily_elo_changes[p]['doubles']['elos'].append(r_after)
-                        details = {'date': date, 'opponent': ", ".join(losing_team), 'sets': f"{t1_games}-{t2_games}" if winning_team==team1 else f"{t2_games}-{t1_games}", 'elo_change': round(elo_change_per_player), 'elo': round(r_after), 'result': 'W', 'issue_number': issue_number, 'partner': [partner for partner in winning_team if partner != p][0]}
+                        details = {'date': date, 'opponent': ", ".join(public_handle(pseudonyms, t) for t in losing_team), 'sets': f"{t1_games}-{t2_games}" if winning_team==team1 else f"{t2_games}-{t1_games}", 'elo_change': round(elo_change_per_player), 'elo': round(r_after), 'result': 'W', 'issue_number': issue_number, 'partner': public_handle(pseudonyms, [partner for partner in winning_team if partner != p][0])}
                         daily_elo_changes[p]['doubles']['details'].append(details)
 
                     for p in losing_team:
-                        r_before = doubles_ratings.get(p, 1200)
+                        r_before = doubles_ratings.get(p, initial_rating())
                         r_after = r_before - elo_change_per_player
                         doubles_ratings[p] = r_after
                         daily_elo_changes[p]['doubles']['elos'].append(r_after)
-                        details = {'date': date, 'opponent': ", ".join(winning_team), 'sets': f"{t2_games}-{t1_games}" if losing_team==team1 else f"{t1_games}-{t2_games}", 'elo_change': round(-elo_change_per_player), 'elo': round(r_after), 'result': 'L', 'issue_number': issue_number, 'partner': [partner for partner in losing_team if partner != p][0]}
+                        details = {'date': date, 'opponent': ", ".join(public_handle(pseudonyms, t) for t in winning_team), 'sets': f"{t2_games}-{t1_games}" if losing_team==team1 else f"{t1_games}-{t2_games}", 'elo_change': round(-elo_change_per_player), 'elo': round(r_after), 'result': 'L', 'issue_number': issue_number, 'partner': public_handle(pseudonyms, [partner for partner in losing_team if partner != p][0])}
                         daily_elo_changes[p]['doubles']['details'].append(details)
 
         # Process daily aggregations for both singles and doubles
@@ -164,21 +185,30 @@ def calculate_elo_history():
                     PLAYER_DATA[player][match_type]['scatter'].append(scatter_data)
 
 
-def generate_player_pages(output_dir):
+def generate_player_pages(output_dir, avatars=None):
     history_dir = os.path.join(output_dir, 'history')
     os.makedirs(history_dir, exist_ok=True)
 
     owner, repo = get_repo_owner_and_name_or_default()
     repo_url = f"https://github.com/{owner}/{repo}"
+    repo_root = os.path.dirname(os.path.dirname(os.path.abspath(__file__)))
+    roster = load_roster(os.path.join(repo_root, ROSTER_FILE))
+    pseudonyms = load_pseudonyms(os.path.join(repo_root, CONFIG_FILE))
+
+    for handle, data in PLAYER_DATA.items():
+        # Players with a pseudonym are shown, and their files named, by it
+        player, slug = public_handle(pseudonyms, handle), public_slug(pseudonyms, handle)
 
-    for player, data in PLAYER_DATA.items():
         # Generate JSON file for the player
-        json_path = os.path.join(history_dir, f'{player}.json')
+        json_path = os.path.join(history_dir, f'{slug}.json')
         with open(json_path, 'w') as f:
             json.dump(data, f)
 
         # Generate HTML page for the player
-        html_path = os.path.join(output_dir, f'player_profile_{player}.html')
+        html_path = os.path.join(output_dir, f'player_profile_{slug}.html')
+
+        profile = profile_summary(roster, handle)
+        profile_html = f'<p class="lead text-muted">{html.escape(profile)}</p>' if profile else ''
 
         html_content = f"""
 <!DOCTYPE html>
@@ -197,7 +227,8 @@ def generate_player_pages(output_dir):
 </head>
 <body>
     <div class="container">
-        <h1 class="mb-4">{player}'s ELO History</h1>
+        <h1 class="mb-4">{avatar_img(avatars, handle, 48)}{player}'s ELO History</h1>
+        {profile_html}
 
         <div class="mb-3">
             <select id="matchTypeSelector" class="form-select" style="width: auto;">
@@ -385,7 +416,7 @@ def generate_player_pages(output_dir):
                 }});
             }}
 
-            fetch('history/{player}.json')
+            fetch('history/{slug}.json')
                 .then(response => response.json())
                 .then(data => {{
                     playerData = data;
@@ -406,12 +437,13 @@ def generate_player_pages(output_dir):
             f.write(html_content)
 
 
-def build_player_pages(output_dir):
+def build_player_pages(output_dir, avatars=None):
     """
-    Main function to generate all player pages.
+    Main function to generate all player pages. `avatars` maps players to
+    the avatars fetched by scripts.avatars.
     """
     calculate_elo_history()
-    generate_player_pages(output_dir)
+    generate_player_pages(output_dir, avatars)
 
 if __name__ == "__main__":
     output_directory = sys.argv[1] if len(sys.argv) > 1 else "dist"
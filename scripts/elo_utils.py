@@ -2,17 +2,328 @@
 This module provides shared ELO rating calculation functions.
 """
 
+import functools
+import math
+import os
+from datetime import date
+
+import yaml
+
+from scripts.rating_formula import FormulaError, load_formula
+
 K = 32
+INITIAL_RATING = 1200
+
+ALIASES_FILE = "aliases.yml"
+CONFIG_FILE = ".tennis.yml"
+FREEZE_FILE = "rankings-freeze.yml"
+
+MARGIN_WEIGHTINGS = ("none", "sets", "games")
+RATING_MODES = ("sets", "games")
+
+
+@functools.lru_cache(maxsize=None)
+def load_elo_params(path=CONFIG_FILE):
+    """Return the league's Elo parameters as a dict (k, initial_rating, margin,
+    formula when the league has a custom rating formula, and mode when it
+    rates every game).
+
+    They are read from the elo section of .tennis.yml, which the tennis CLI
+    reads too, so the Pages build and the CLI rank players the same way.
+    Unset parameters keep the defaults; invalid ones raise ValueError.
+    """
+    params = {"k": K, "initial_rating": INITIAL_RATING, "margin": "none"}
+    if os.path.exists(path):
+        with open(path) as f:
+            data = yaml.safe_load(f) or {}
+        for key, value in (data.get("elo") or {}).items():
+            if key in params and value not in (None, 0, ""):
+                params[key] = value
+            elif key == "formula" and value:
+                params[key] = str(value)
+            elif key == "mode" and value and value != "sets":
+                params[key] = value
+    if not 0 <= params["k"] <= 100:
+        raise ValueError(f"elo.k must be between 0 and 100, got {params['k']}")
+    if params["initial_rating"] < 0:
+        raise ValueError(f"elo.initial_rating must be positive, got {params['initial_rating']}")
+    if params["margin"] not in MARGIN_WEIGHTINGS:
+        raise ValueError(f"elo.margin must be none, sets or games, got {params['margin']!r}")
+    if params.get("mode", "sets") not in RATING_MODES:
+        raise ValueError(f"elo.mode must be sets or games, got {params['mode']!r}")
+    if params.get("mode") == "games" and params["margin"] != "none":
+        raise ValueError("elo.margin can't be used with elo.mode: games, which already counts every game")
+    if params.get("mode") == "games" and params.get("formula"):
+        raise ValueError("elo.formula can't be used with elo.mode: games")
+    return params
+
+
+@functools.lru_cache(maxsize=None)
+def load_rating_formula(path=CONFIG_FILE):
+    """Return the league's custom rating formula, or None for standard Elo.
+
+    The file named by elo.formula is read relative to the working
+    directory, like .tennis.yml itself. An invalid formula raises
+    ValueError (see scripts/rating_formula.py).
+    """
+    name = load_elo_params(path).get("formula")
+    return load_formula(name) if name else None
+
+
+def set_change(r_winner, r_loser, k, games, doubles=False):
+    """Points the winner of a set gains and the loser loses when the league
+    rates sets other than by plain Elo, or None when it doesn't. Rated per
+    game (elo.mode: games), the change is k * (games won - expected * games
+    played), as in setChange in the CLI; otherwise see formula_change.
+    """
+    if load_elo_params().get("mode") == "games":
+        won, lost = sorted((int(games[0]), int(games[1])), reverse=True)
+        return k * (won - expected(r_winner, r_loser) * (won + lost))
+    return formula_change(r_winner, r_loser, k, games, doubles)
+
+
+def formula_change(r_winner, r_loser, k, games, doubles=False):
+    """Points the winner of a set gains and the loser loses under the
+    league's custom formula, or None without one. `games` is the set's
+    (winner, loser) games. A formula that fails to evaluate for a set
+    leaves the ratings unchanged, as in the CLI.
+    """
+    formula = load_rating_formula()
+    if formula is None:
+        return None
+    won, lost = sorted((int(games[0]), int(games[1])), reverse=True)
+    try:
+        return formula.evaluate({
+            "k": k, "expected": expected(r_winner, r_loser),
+            "winner_rating": r_winner, "loser_rating": r_loser,
+            "games_won": won, "games_lost": lost,
+            "initial_rating": initial_rating(), "doubles": 1 if doubles else 0,
+        })
+    except FormulaError:
+        return 0
+
+
+def initial_rating():
+    """Rating of a player with no matches."""
+    return load_elo_params()["initial_rating"]
+
+
+def _set_games(s):
+    """A set's (side1, side2) games, or None if the entry is malformed."""
+    try:
+        return int(s[0]), int(s[1])
+    except (ValueError, TypeError, IndexError):
+        return None
+
+
+def set_k(sets, s, params=None):
+    """K-factor for set `s` of a match with the given sets.
+
+    With margin weighting, K is scaled by 1 + ln(margin): the games margin
+    of the set ("games") or the sets margin of the match ("sets"). A margin
+    of one leaves K unchanged. Mirrors eloParams.setK in the CLI.
+    """
+    params = params or load_elo_params()
+    if params["margin"] == "games":
+        margin = abs(int(s[0]) - int(s[1]))
+    elif params["margin"] == "sets":
+        scores = [g for g in (_set_games(x) for x in sets) if g]
+        won = sum(1 for a, b in scores if a > b)
+        lost = sum(1 for a, b in scores if b > a)
+        margin = abs(won - lost)
+    else:
+        return params["k"]
+    if margin <= 1:
+        return params["k"]
+    return params["k"] * (1 + math.log(margin))
+
 
-def normalize_player(name):
+@functools.lru_cache(maxsize=None)
+def load_decay(path=CONFIG_FILE):
+    """Return the inactivity decay rules from .tennis.yml.
+
+    The decay section's after_days and points_per_week mirror the CLI;
+    without them ratings don't decay.
+    """
+    rules = {"after_days": 0, "points_per_week": 0}
+    if os.path.exists(path):
+        with open(path) as f:
+            data = yaml.safe_load(f) or {}
+        for key, value in (data.get("decay") or {}).items():
+            if key in rules and value:
+                rules[key] = value
+    if rules["after_days"] < 0 or rules["points_per_week"] < 0:
+        raise ValueError("decay.after_days and decay.points_per_week can't be negative")
+    return rules
+
+
+@functools.lru_cache(maxsize=None)
+def load_handicap(path=CONFIG_FILE):
+    """Return the handicap rules from .tennis.yml.
+
+    The handicap section's games_per, max_games and score mirror the CLI,
+    which records each match's head start when it's reported. score is
+    "raw" (the default) to rank on the sets as played, or "handicap" to
+    rank on the sets with the head start added.
+    """
+    rules = {"games_per": 0, "max_games": 0, "score": "raw"}
+    if os.path.exists(path):
+        with open(path) as f:
+            data = yaml.safe_load(f) or {}
+        for key, value in (data.get("handicap") or {}).items():
+            if key in rules and value:
+                rules[key] = value
+    if rules["score"] not in ("raw", "handicap"):
+        raise ValueError(f"handicap.score must be raw or handicap, got {rules['score']!r}")
+    if rules["games_per"] < 0 or rules["max_games"] < 0:
+        raise ValueError("handicap.games_per and handicap.max_games can't be negative")
+    return rules
+
+
+@functools.lru_cache(maxsize=None)
+def load_forfeit(path=CONFIG_FILE):
+    """Return the rules.forfeit section of .tennis.yml as a dict.
+
+    result is "default" (the default) to rate a forfeit's default win like
+    any other result, or "penalty" to rate nothing and take penalty rating
+    points off each player named in the match file's forfeit list.
+    """
+    rules = {"result": "default", "penalty": 0}
+    if os.path.exists(path):
+        with open(path) as f:
+            data = yaml.safe_load(f) or {}
+        for key, value in ((data.get("rules") or {}).get("forfeit") or {}).items():
+            if key in rules and value:
+                rules[key] = value
+    if rules["result"] not in ("default", "penalty"):
+        raise ValueError(f"rules.forfeit.result must be default or penalty, got {rules['result']!r}")
+    if rules["penalty"] < 0:
+        raise ValueError("rules.forfeit.penalty can't be negative")
+    return rules
+
+
+def charge_forfeit(ratings, match, rules=None):
+    """Take the forfeit penalty off the rating of each player who didn't
+    turn up for `match`, if the league penalizes forfeits. Mirrors
+    chargeForfeit in the CLI."""
+    rules = rules or load_forfeit()
+    if rules["result"] != "penalty":
+        return
+    for p in match.get("forfeit") or []:
+        p = normalize_player(p)
+        ratings[p] = ratings.get(p, initial_rating()) - rules["penalty"]
+
+
+@functools.lru_cache(maxsize=None)
+def load_freeze(path=FREEZE_FILE):
+    """Return the rankings freeze from rankings-freeze.yml as a dict with
+    from and until dates, or None if the rankings aren't frozen. Written by
+    "tennis rankings freeze"."""
+    if not os.path.exists(path):
+        return None
+    with open(path) as f:
+        data = yaml.safe_load(f) or {}
+    held = {"from": str(data.get("from", "")), "until": str(data.get("until", ""))}
+    for key, value in held.items():
+        try:
+            date.fromisoformat(value)
+        except ValueError:
+            raise ValueError(f"rankings-freeze.yml: {key} must be YYYY-MM-DD, got {value!r}")
+    return held
+
+
+def frozen(match, held=None, today=None):
+    """Whether the rankings freeze holds `match` back from the ratings and
+    ranking points: the freeze is on today and the match was played since
+    it started. Mirrors frozen in the CLI."""
+    held = held if held is not None else load_freeze()
+    if not held:
+        return False
+    today = today or date.today().isoformat()
+    return held["from"] <= today <= held["until"] and str(match.get("date", "")) >= held["from"]
+
+
+def scored_sets(match, rules=None, forfeit=None):
+    """The sets of a match file the rankings score: none for a forfeit if
+    the league penalizes forfeits, its handicap_sets if the league ranks on
+    handicap results and the match has them, else its sets. Mirrors
+    Match.scoredSets in the CLI."""
+    rules = rules or load_handicap()
+    forfeit = forfeit or load_forfeit()
+    if match.get("forfeit") and forfeit["result"] == "penalty":
+        return []
+    if rules["score"] == "handicap" and match.get("handicap_sets") is not None:
+        return match["handicap_sets"]
+    return match.get("sets") or []
+
+
+def decayed_ratings(ratings, match_dates, rules=None, as_of=None, away=None):
+    """Return `ratings` as they stand on `as_of` after inactivity decay.
+
+    A player whose last match (per `match_dates`) was more than after_days
+    days ago loses points_per_week for every full week beyond that, but
+    never drops below the initial rating. Days in the player's away period
+    (`away`, see roster.away_periods) don't count. Mirrors decayRatings in
+    the CLI.
+    """
+    rules = rules or load_decay()
+    if not rules["points_per_week"]:
+        return dict(ratings)
+    as_of = as_of or date.today().isoformat()
+    floor = initial_rating()
+    decayed = {}
+    for p, r in ratings.items():
+        decayed[p] = r
+        played = [d for d in match_dates.get(p, []) if d <= as_of]
+        if not played or r <= floor:
+            continue
+        last, day = date.fromisoformat(max(played)), date.fromisoformat(as_of)
+        idle = (day - last).days - rules["after_days"]
+        if away and p in away:
+            start = max(date.fromisoformat(away[p][0]), last)
+            end = min(date.fromisoformat(away[p][1]), day)
+            idle -= max((end - start).days, 0)
+        if idle >= 7:
+            decayed[p] = max(floor, r - rules["points_per_week"] * (idle // 7))
+    return decayed
+
+@functools.lru_cache(maxsize=None)
+def load_aliases(path=ALIASES_FILE):
+    """Return the handle alias map (old handle -> current handle).
+
+    aliases.yml is written by `tennis player rename` so that renamed players
+    keep one identity across match files recorded under their old handle.
+    A missing file means no aliases.
+    """
+    if not os.path.exists(path):
+        return {}
+    with open(path) as f:
+        data = yaml.safe_load(f) or {}
+    return {
+        str(old).strip().lstrip("@").lower(): str(new).strip().lstrip("@").lower()
+        for old, new in data.items()
+    }
+
+def resolve_alias(handle, aliases):
+    """Follow the alias chain for a normalized handle to its current handle."""
+    for _ in range(len(aliases)):
+        nxt = aliases.get(handle)
+        if nxt is None or nxt == handle:
+            break
+        handle = nxt
+    return handle
+
+def normalize_player(name, aliases=None):
     """Canonicalize a player handle for case-insensitive identity.
 
     GitHub usernames are unique and case-insensitive, so "Johnor12" and
     "johnor12" are the same account. Lowercasing collapses casing variants
     to a single player so stats and profile pages don't split. Also strips
-    surrounding whitespace and a leading '@'.
+    surrounding whitespace and a leading '@', and maps renamed handles to
+    the player's current handle via aliases.yml.
     """
-    return name.strip().lstrip("@").lower()
+    handle = name.strip().lstrip("@").lower()
+    return resolve_alias(handle, load_aliases() if aliases is None else aliases)
 
 def expected(rA, rB):
     """
@@ -20,7 +331,7 @@ def expected(rA, rB):
     """
     return 1 / (1 + 10 ** ((rB - rA) / 400))
 
-def update_elo_ratings(ratings, winner, loser):
+def update_elo_ratings(ratings, winner, loser, k=None, games=None):
     """
     Updates the ELO ratings for a winner and loser.
 
@@ -28,17 +339,23 @@ def update_elo_ratings(ratings, winner, loser):
         ratings (dict): A dictionary of player ratings.
         winner (str): The name of the winner.
         loser (str): The name of the loser.
+        k (float): K-factor for this set (see set_k); defaults to the league's.
+        games (tuple): The set's games, for a custom rating formula.
 
     Returns:
         tuple: A tuple containing the new rating for the winner and loser.
     """
-    rW = ratings.get(winner, 1200)
-    rL = ratings.get(loser, 1200)
+    k = load_elo_params()["k"] if k is None else k
+    rW = ratings.get(winner, initial_rating())
+    rL = ratings.get(loser, initial_rating())
+    change = set_change(rW, rL, k, games or (0, 0))
+    if change is not None:
+        return rW + change, rL - change
     eW = expected(rW, rL)
     eL = expected(rL, rW)
 
-    new_rW = rW + K * (1 - eW)
-    new_rL = rL + K * (0 - eL)
+    new_rW = rW + k * (1 - eW)
+    new_rL = rL + k * (0 - eL)
 
     return new_rW, new_rL
 
@@ -50,33 +367,44 @@ def normalize_team(team_players):
     sorted_players = sorted(team_players)
     return f"{sorted_players[0]}, {sorted_players[1]}"
 
-def update_doubles_elo_ratings(team_ratings, individual_ratings, winner_team, loser_team):
+def update_doubles_elo_ratings(team_ratings, individual_ratings, winner_team, loser_team, k=None, games=None):
     """
-    Updates the ELO ratings for a doubles match.
+    Updates the ELO ratings for a doubles match. `k` is the K-factor for
+    this set (see set_k) and defaults to the league's; `games` is the set's
+    games, for a custom rating formula.
     """
+    k = load_elo_params()["k"] if k is None else k
+    initial = initial_rating()
+
     # 1. Team-based ELO
     winner_team_key = f"{sorted(winner_team)[0]}, {sorted(winner_team)[1]}"
     loser_team_key = f"{sorted(loser_team)[0]}, {sorted(loser_team)[1]}"
 
-    rW_team = team_ratings.get(winner_team_key, 1200)
-    rL_team = team_ratings.get(loser_team_key, 1200)
+    rW_team = team_ratings.get(winner_team_key, initial)
+    rL_team = team_ratings.get(loser_team_key, initial)
     eW_team = expected(rW_team, rL_team)
 
-    new_rW_team = rW_team + K * (1 - eW_team)
-    new_rL_team = rL_team + K * (0 - (1-eW_team))
+    new_rW_team = rW_team + k * (1 - eW_team)
+    new_rL_team = rL_team + k * (0 - (1-eW_team))
+    team_change = set_change(rW_team, rL_team, k, games or (0, 0), doubles=True)
+    if team_change is not None:
+        new_rW_team, new_rL_team = rW_team + team_change, rL_team - team_change
 
     # 2. Individual-based ELO
-    r_w1 = individual_ratings.get(winner_team[0], 1200)
-    r_w2 = individual_ratings.get(winner_team[1], 1200)
-    r_l1 = individual_ratings.get(loser_team[0], 1200)
-    r_l2 = individual_ratings.get(loser_team[1], 1200)
+    r_w1 = individual_ratings.get(winner_team[0], initial)
+    r_w2 = individual_ratings.get(winner_team[1], initial)
+    r_l1 = individual_ratings.get(loser_team[0], initial)
+    r_l2 = individual_ratings.get(loser_team[1], initial)
 
     r_winner_eff = (r_w1 + r_w2) / 2
     r_loser_eff = (r_l1 + r_l2) / 2
 
     e_winner = expected(r_winner_eff, r_loser_eff)
 
-    rating_change = K * (1 - e_winner)
+    rating_change = k * (1 - e_winner)
+    custom_change = set_change(r_winner_eff, r_loser_eff, k, games or (0, 0), doubles=True)
+    if custom_change is not None:
+        rating_change = custom_change
 
     new_r_w1 = r_w1 + rating_change
     new_r_w2 = r_w2 + rating_change
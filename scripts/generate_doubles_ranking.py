@@ -2,7 +2,9 @@ import glob
 import sys
 import yaml
 import pandas as pd
-from scripts.elo_utils import update_doubles_elo_ratings, normalize_team, normalize_player
+from scripts.elo_utils import charge_forfeit, decayed_ratings, frozen, initial_rating, scored_sets, set_k, update_doubles_elo_ratings, normalize_team, normalize_player
+from scripts.ranking_points import award_points
+from scripts.roster import away_periods, imported_ratings, leaderboard_players, load_qualification, load_roster, unranked_players
 
 # --- Team-based data ---
 team_ratings = {}
@@ -11,6 +13,10 @@ team_stats = {}
 # --- Individual-based data ---
 individual_ratings = {}
 individual_stats = {}
+# Dates of each player's matches, for the leaderboard qualification rules.
+match_dates = {}
+# Ranking points per player.
+points = {}
 
 
 def _ensure_team_stats(team: str) -> None:
@@ -47,9 +53,11 @@ def apply_match(match):
     # Ensure individual player stats entries exist
     for p in team1_players + team2_players:
         _ensure_player_stats(p)
+        match_dates.setdefault(p, []).append(str(match.get("date", "")))
+    award_points(points, match, team1_players, team2_players, individual_ratings, {})
 
     # --- Process sets ---
-    sets = match.get("sets") or []
+    sets = scored_sets(match)
     for s in sets:
         try:
             t1_games = int(s[0])
@@ -94,8 +102,13 @@ def apply_match(match):
                 individual_stats[p]["set_losses"] += 1
 
 
-        # --- ELO Updates ---
-        new_rW_team, new_rL_team, new_r_w1, new_r_w2, new_r_l1, new_r_l2 = update_doubles_elo_ratings(team_ratings, individual_ratings, set_winner_players, set_loser_players)
+        # --- ELO Updates, unless the rankings freeze holds the match back ---
+        if frozen(match):
+            continue
+        new_rW_team, new_rL_team, new_r_w1, new_r_w2, new_r_l1, new_r_l2 = update_doubles_elo_ratings(
+            team_ratings, individual_ratings, set_winner_players, set_loser_players,
+            k=set_k(sets, (t1_games, t2_games)), games=(t1_games, t2_games)
+        )
 
         team_ratings[set_winner_key] = new_rW_team
         team_ratings[set_loser_key] = new_rL_team
@@ -105,9 +118,16 @@ def apply_match(match):
         individual_ratings[set_loser_players[0]] = new_r_l1
         individual_ratings[set_loser_players[1]] = new_r_l2
 
+    if not frozen(match):
+        charge_forfeit(individual_ratings, match)
+
 
 def main():
     """Main function to calculate and print doubles rankings."""
+    # Newcomers with a rating imported from another league start from it
+    roster = load_roster()
+    individual_ratings.update(imported_ratings(roster, "doubles"))
+
     # Process doubles matches
     for fn in sorted(glob.glob("doubles-matches/*.yml")):
         with open(fn) as f:
@@ -137,13 +157,19 @@ def main():
         pd.DataFrame(columns=["team", "rating", "set_wins", "set_losses", "game_wins", "game_losses"]).to_csv("doubles-ranking.csv", index=False)
 
     # --- Generate and save individual rankings ---
+    listed = leaderboard_players(roster, individual_ratings)
+    unranked = unranked_players(match_dates, listed, load_qualification())
+    current = decayed_ratings(individual_ratings, match_dates, away=away_periods(roster))
     individual_data = []
-    for player, rating in sorted(individual_ratings.items(), key=lambda item: -item[1]):
+    for player in sorted(listed, key=lambda p: -current.get(p, initial_rating())):
+        rating = current.get(player, initial_rating())
         stats = individual_stats.get(player, {"set_wins": 0, "set_losses": 0, "game_wins": 0, "game_losses": 0})
         individual_data.append({
             "player": player, "rating": round(rating, 1),
             "set_wins": stats["set_wins"], "set_losses": stats["set_losses"],
-            "game_wins": stats["game_wins"], "game_losses": stats["game_losses"]
+            "game_wins": stats["game_wins"], "game_losses": stats["game_losses"],
+            "unranked": player in unranked,
+            "points": round(points.get(player, 0), 1),
         })
 
     individual_df = pd.DataFrame(individual_data)
@@ -151,7 +177,7 @@ def main():
         individual_df = individual_df.sort_values(by="rating", ascending=False).reset_index(drop=True)
         individual_df.to_csv("doubles-individual-ranking.csv", index=False)
     else:
-        pd.DataFrame(columns=["player", "rating", "set_wins", "set_losses", "game_wins", "game_losses"]).to_csv("doubles-individual-ranking.csv", index=False)
+        pd.DataFrame(columns=["player", "rating", "set_wins", "set_losses", "game_wins", "game_losses", "unranked", "points"]).to_csv("doubles-individual-ranking.csv", index=False)
 
 
 if __name__ == "__main__":
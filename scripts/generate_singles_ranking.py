@@ -2,7 +2,9 @@ import glob
 import sys
 import yaml
 import pandas as pd
-from scripts.elo_utils import normalize_player, update_elo_ratings
+from scripts.elo_utils import charge_forfeit, decayed_ratings, frozen, initial_rating, normalize_player, scored_sets, set_k, update_elo_ratings
+from scripts.ranking_points import award_points, tournament_rounds
+from scripts.roster import away_periods, imported_ratings, leaderboard_players, load_qualification, load_roster, unranked_players
 
 ratings = {}
 elo_changes = []
@@ -11,6 +13,12 @@ elo_changes = []
 # - game_wins/game_losses: total games won/lost across all recorded sets
 # - wins/losses: kept for backward compatibility (mirror set stats)
 stats = {}
+# Dates of each player's matches, for the leaderboard qualification rules.
+match_dates = {}
+# Ranking points per player, and the tournament round of each match that
+# decided a knockout slot, by issue.
+points = {}
+rounds = {}
 
 
 def _ensure_player(player: str) -> None:
@@ -27,7 +35,9 @@ def apply_match(match):
     """Update ratings and aggregates from a single match file.
 
     Elo is applied per set. Each set is an independent event that updates
-    the players' ratings based solely on who won the set (score margin ignored).
+    the players' ratings based on who won the set, weighted by the margin
+    of victory if .tennis.yml's elo.margin asks for it (see set_k), or by
+    the league's custom rating formula if elo.formula names one.
     """
     player1 = normalize_player(match["players"][0])
     player2 = normalize_player(match["players"][1])
@@ -35,9 +45,12 @@ def apply_match(match):
     # Ensure player entries exist for aggregation
     _ensure_player(player1)
     _ensure_player(player2)
+    for p in (player1, player2):
+        match_dates.setdefault(p, []).append(str(match.get("date", "")))
+    award_points(points, match, [player1], [player2], ratings, rounds)
 
     # Iterate sets: first number maps to player1's games, second to player2's
-    sets = match.get("sets") or []
+    sets = scored_sets(match)
     for s in sets:
         try:
             p1_games = int(s[0])
@@ -64,11 +77,16 @@ def apply_match(match):
         stats[set_winner]["set_wins"] += 1
         stats[set_loser]["set_losses"] += 1
 
-        # Elo update for this set (independent event)
-        old_winner_rating = ratings.get(set_winner, 1200)
-        old_loser_rating = ratings.get(set_loser, 1200)
+        # Elo update for this set (independent event), unless the rankings
+        # freeze is holding the match back
+        if frozen(match):
+            continue
+        old_winner_rating = ratings.get(set_winner, initial_rating())
+        old_loser_rating = ratings.get(set_loser, initial_rating())
 
-        new_winner_rating, new_loser_rating = update_elo_ratings(ratings, set_winner, set_loser)
+        new_winner_rating, new_loser_rating = update_elo_ratings(
+            ratings, set_winner, set_loser, k=set_k(sets, (p1_games, p2_games)), games=(p1_games, p2_games)
+        )
 
         ratings[set_winner] = new_winner_rating
         ratings[set_loser] = new_loser_rating
@@ -86,10 +104,17 @@ def apply_match(match):
             "change": new_loser_rating - old_loser_rating
         })
 
+    if not frozen(match):
+        charge_forfeit(ratings, match)
+
 
 def main():
     """Main function to calculate and print rankings."""
-    # All players start with default rating of 1200 - no CSV bootstrapping needed
+    # All players start with the initial rating - no CSV bootstrapping needed -
+    # except newcomers with a rating imported from another league
+    roster = load_roster()
+    ratings.update(imported_ratings(roster, "singles"))
+    rounds.update(tournament_rounds())
 
     # Process matches
     for fn in sorted(glob.glob("singles-matches/*.yml")):
@@ -102,8 +127,13 @@ def main():
                 print(f"Error reading {fn}: {e}", file=sys.stderr)
 
     # Create new DataFrame with updated ratings and stats
+    # The roster, when present, decides who appears on the leaderboard
+    listed = leaderboard_players(roster, ratings)
+    unranked = unranked_players(match_dates, listed, load_qualification())
+    current = decayed_ratings(ratings, match_dates, away=away_periods(roster))
     new_players_data = []
-    for p, r in sorted(ratings.items(), key=lambda item: -item[1]):
+    for p in sorted(listed, key=lambda p: -current.get(p, initial_rating())):
+        r = current.get(p, initial_rating())
         player_stats = stats.get(
             p,
             {
@@ -122,6 +152,8 @@ def main():
                 "set_losses": player_stats["set_losses"],
                 "game_wins": player_stats["game_wins"],
                 "game_losses": player_stats["game_losses"],
+                "unranked": p in unranked,
+                "points": round(points.get(p, 0), 1),
             }
         )
 
@@ -135,6 +167,8 @@ def main():
                 "set_losses",
                 "game_wins",
                 "game_losses",
+                "unranked",
+                "points",
             ]
         )
         df.to_csv(sys.stdout, index=False)
@@ -151,6 +185,8 @@ def main():
         "set_losses",
         "game_wins",
         "game_losses",
+        "unranked",
+        "points",
     ]
 
     # Ensure all desired columns exist (defensive in case of missing keys)
@@ -3,6 +3,8 @@ import re
 import sys
 import yaml
 
+from scripts.elo_utils import normalize_player
+
 
 def parse_issue_body(body):
     """Parses the issue body to extract match details."""
@@ -18,7 +20,7 @@ def parse_issue_body(body):
     players_match = re.search(r"### Players.*?\n\s*([^\n]+)", body)
     if players_match:
         players_str = players_match.group(1).strip()
-        details["players"] = [p.strip().replace("@", "").lower() for p in players_str.split(",")]
+        details["players"] = [normalize_player(p) for p in players_str.split(",")]
 
     sets_match = re.search(r"### Sets.*?\n(.*?)(?=\n###|\Z)", body, re.DOTALL)
     if sets_match:
@@ -0,0 +1,143 @@
+"""
+Ranking points, set in the points section of .tennis.yml, an alternative
+to ranking on Elo ratings.
+
+A win earns `win` points in a league match, or `rounds` points in a
+knockout tournament match by its round (final, semifinal, quarterfinal, or
+round for earlier ones), times the multiplier of the highest `opponents`
+band the beaten side's rating reached going in. A win's points drop off
+`window_days` days later, 52 weeks unless set. The points and the rules
+match pointsRules in the CLI.
+"""
+
+import functools
+import glob
+import os
+from datetime import date, timedelta
+
+import yaml
+
+from scripts.elo_utils import CONFIG_FILE, frozen, initial_rating, normalize_player, scored_sets
+
+POINTS_ROUNDS = ("final", "semifinal", "quarterfinal", "round")
+RANK_BY = ("rating", "points")
+DEFAULT_WINDOW_DAYS = 52 * 7
+
+
+@functools.lru_cache(maxsize=None)
+def load_points(path=CONFIG_FILE):
+    """Return the points rules as a dict (rank_by, win, rounds, opponents
+    as (min_rating, multiplier) pairs highest first, window_days). Without
+    a points section no wins earn points. Invalid rules raise ValueError."""
+    rules = {"rank_by": "rating", "win": 0, "rounds": {}, "opponents": [], "window_days": DEFAULT_WINDOW_DAYS}
+    if os.path.exists(path):
+        with open(path) as f:
+            data = yaml.safe_load(f) or {}
+        section = data.get("points") or {}
+        for key in ("rank_by", "win", "window_days"):
+            if section.get(key):
+                rules[key] = section[key]
+        rules["rounds"] = {str(k): float(v) for k, v in (section.get("rounds") or {}).items()}
+        rules["opponents"] = sorted(
+            ((float(b.get("min_rating") or 0), float(b.get("multiplier") or 0)) for b in section.get("opponents") or []),
+            key=lambda b: -b[0],
+        )
+    if rules["rank_by"] not in RANK_BY:
+        raise ValueError(f"points.rank_by must be rating or points, got {rules['rank_by']!r}")
+    if rules["win"] < 0 or rules["window_days"] < 0:
+        raise ValueError("points.win and points.window_days can't be negative")
+    for name, value in rules["rounds"].items():
+        if name not in POINTS_ROUNDS:
+            raise ValueError(f"points.rounds: unknown round {name!r} (use {', '.join(POINTS_ROUNDS)})")
+        if value < 0:
+            raise ValueError(f"points.rounds.{name} can't be negative")
+    mins = [m for m, _ in rules["opponents"]]
+    if len(set(mins)) != len(mins) or any(mult <= 0 for _, mult in rules["opponents"]):
+        raise ValueError("points.opponents: min_rating values must be unique and multipliers positive")
+    if rules["rank_by"] == "points" and not points_enabled(rules):
+        raise ValueError("points.rank_by: points needs points.win or points.rounds")
+    return rules
+
+
+def points_enabled(rules):
+    """Whether wins earn ranking points under `rules`."""
+    return rules["win"] > 0 or any(v > 0 for v in rules["rounds"].values())
+
+
+def points_for_win(rules, round_name, opponent_rating):
+    """Points for beating a side rated `opponent_rating` in a match of
+    `round_name` (None for a league match)."""
+    base = rules["rounds"].get(round_name, rules["win"])
+    for min_rating, multiplier in rules["opponents"]:
+        if opponent_rating >= min_rating:
+            return base * multiplier
+    return base
+
+
+def round_key(num_players, index):
+    """The POINTS_ROUNDS key of round `index` of a knockout draw of
+    `num_players`, as Tournament.RoundName names it in the CLI."""
+    total = 0
+    n = num_players
+    while n > 1:
+        total += 1
+        n = (n + 1) // 2
+    return {1: "final", 2: "semifinal", 3: "quarterfinal"}.get(total - index, "round")
+
+
+def tournament_rounds(directory="tournaments"):
+    """Map the source_issue of every match that decided a knockout
+    tournament slot to its round key."""
+    rounds = {}
+    for fn in sorted(glob.glob(os.path.join(directory, "*.yml"))):
+        try:
+            with open(fn) as f:
+                t = yaml.safe_load(f) or {}
+        except yaml.YAMLError:
+            continue
+        for i, rnd in enumerate(t.get("rounds") or []):
+            for m in rnd.get("matches") or []:
+                if m.get("result"):
+                    rounds[int(m["result"])] = round_key(len(t.get("players") or []), i)
+    return rounds
+
+
+def match_winner(sets):
+    """1 or 2 for the side that won more sets, or 0 for a draw."""
+    w1 = w2 = 0
+    for s in sets or []:
+        try:
+            a, b = int(s[0]), int(s[1])
+        except (ValueError, TypeError, IndexError):
+            continue
+        if a > b:
+            w1 += 1
+        elif b > a:
+            w2 += 1
+    return 1 if w1 > w2 else 2 if w2 > w1 else 0
+
+
+def award_points(points, match, side1, side2, ratings, rounds, rules=None, as_of=None):
+    """Add the points the winners of `match` earn to `points`, with
+    `ratings` as they stood going in. Doubles winners each earn the points
+    for beating the losing team's average rating. A penalized forfeit
+    earns nothing, nor does a match the rankings freeze holds back."""
+    rules = rules or load_points()
+    if not points_enabled(rules):
+        return
+    as_of = as_of or date.today().isoformat()
+    played = str(match.get("date", ""))
+    since = (date.fromisoformat(as_of) - timedelta(days=rules["window_days"] or DEFAULT_WINDOW_DAYS)).isoformat()
+    if not since < played <= as_of:
+        return
+    w = match_winner(match.get("sets"))
+    if w == 0 or not scored_sets(match) or frozen(match):
+        return
+    winners, losers = (side1, side2) if w == 1 else (side2, side1)
+    round_name = None
+    if not match.get("repo") and len(side1) == 1:
+        round_name = rounds.get(match.get("source_issue"))
+    opponent = sum(ratings.get(p, initial_rating()) for p in losers) / len(losers)
+    won = points_for_win(rules, round_name, opponent)
+    for p in winners:
+        points[normalize_player(p)] = points.get(normalize_player(p), 0) + won
@@ -0,0 +1,180 @@
+"""
+Custom rating formulas, named by elo.formula in .tennis.yml.
+
+A formula is one arithmetic expression giving the points the winner of a
+set gains and the loser loses, in place of K * (1 - expected). It can only
+read the variables below and call the functions below, so it can't reach
+the file system, network or engine state. The grammar and results match
+ratingFormula in the CLI, which rates matches with the same file.
+"""
+
+import ast
+import math
+import re
+
+MAX_FORMULA_SIZE = 4096
+
+# Plain decimal numbers only, as in the CLI (no hex, underscores or j).
+NUMBER = re.compile(r"(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?")
+
+# The values a formula is fed for each set.
+FORMULA_VARS = (
+    "k",               # K-factor for the set, after margin weighting
+    "expected",        # the winner's expected score
+    "winner_rating",   # the winner's rating (team average in doubles)
+    "loser_rating",    # the loser's rating (team average in doubles)
+    "games_won",       # the winner's games in the set
+    "games_lost",      # the loser's games in the set
+    "initial_rating",  # rating of a player with no matches
+    "doubles",         # 1 for a doubles set, 0 for singles
+)
+
+# The functions a formula may call, by arity (-1 for two or more arguments).
+FORMULA_FUNCS = {
+    "abs": 1, "sqrt": 1, "ln": 1, "log10": 1, "exp": 1,
+    "pow": 2, "min": -1, "max": -1, "when": 3, "clamp": 3,
+}
+
+_BINARY_OPS = {
+    ast.Add: lambda x, y: x + y,
+    ast.Sub: lambda x, y: x - y,
+    ast.Mult: lambda x, y: x * y,
+    ast.Div: lambda x, y: x / y,
+}
+
+_COMPARE_OPS = {
+    ast.Lt: lambda x, y: x < y,
+    ast.LtE: lambda x, y: x <= y,
+    ast.Gt: lambda x, y: x > y,
+    ast.GtE: lambda x, y: x >= y,
+    ast.Eq: lambda x, y: x == y,
+    ast.NotEq: lambda x, y: x != y,
+}
+
+_FUNCS = {
+    "abs": abs,
+    "sqrt": math.sqrt,
+    "ln": math.log,
+    "log10": math.log10,
+    "exp": math.exp,
+    "pow": math.pow,
+    "min": min,
+    "max": max,
+    "when": lambda c, a, b: a if c != 0 else b,
+    "clamp": lambda x, lo, hi: min(max(x, lo), hi),
+}
+
+
+class FormulaError(ValueError):
+    """A formula that is invalid, or that fails to evaluate."""
+
+
+class RatingFormula:
+    """A checked rating formula. Call evaluate() with FORMULA_VARS."""
+
+    def __init__(self, source):
+        lines = [line.split("#", 1)[0].strip() for line in source.split("\n")]
+        self.source = " ".join(lines).strip()
+        if not self.source:
+            raise FormulaError("the formula is empty")
+        try:
+            self._tree = ast.parse(self.source, mode="eval").body
+        except SyntaxError as e:
+            raise FormulaError(str(e)) from None
+        _check(self._tree, self.source)
+        sample = {
+            "k": 32, "expected": 0.5, "winner_rating": 1200, "loser_rating": 1200,
+            "games_won": 6, "games_lost": 4, "initial_rating": 1200, "doubles": 0,
+        }
+        try:
+            self.evaluate(sample)
+        except FormulaError as e:
+            raise FormulaError(f"evaluating it for an even 6-4 set: {e}") from None
+
+    def evaluate(self, variables):
+        """Evaluate the formula. Comparisons yield 1 or 0, and when(c, a, b)
+        is a if c is non-zero, else b. Any non-finite intermediate value,
+        such as a division by zero, raises FormulaError."""
+        return _eval(self._tree, variables)
+
+
+def load_formula(path):
+    """Read and check the formula file at `path`. Raises FormulaError."""
+    with open(path) as f:
+        source = f.read()
+    if len(source.encode()) > MAX_FORMULA_SIZE:
+        raise FormulaError(f"{path} is over {MAX_FORMULA_SIZE} bytes")
+    try:
+        return RatingFormula(source)
+    except FormulaError as e:
+        raise FormulaError(f"{path}: {e}") from None
+
+
+def _check(node, source):
+    """Reject anything but numbers, the formula variables, arithmetic,
+    comparisons and calls to the formula functions."""
+    if isinstance(node, ast.Constant):
+        text = ast.get_source_segment(source, node) or ""
+        if isinstance(node.value, bool) or not isinstance(node.value, (int, float)):
+            raise FormulaError(f"unsupported literal {node.value!r}")
+        if not NUMBER.fullmatch(text):
+            raise FormulaError(f"invalid number {text}")
+    elif isinstance(node, ast.Name):
+        if node.id not in FORMULA_VARS:
+            raise FormulaError(f"unknown variable {node.id!r} (available: {', '.join(FORMULA_VARS)})")
+    elif isinstance(node, ast.UnaryOp):
+        if not isinstance(node.op, (ast.UAdd, ast.USub)):
+            raise FormulaError("unsupported operator")
+        _check(node.operand, source)
+    elif isinstance(node, ast.BinOp):
+        if type(node.op) not in _BINARY_OPS:
+            raise FormulaError("unsupported operator")
+        _check(node.left, source)
+        _check(node.right, source)
+    elif isinstance(node, ast.Compare):
+        if len(node.ops) != 1 or type(node.ops[0]) not in _COMPARE_OPS:
+            raise FormulaError("unsupported comparison")
+        _check(node.left, source)
+        _check(node.comparators[0], source)
+    elif isinstance(node, ast.Call):
+        if not isinstance(node.func, ast.Name):
+            raise FormulaError("only the built-in functions can be called")
+        name = node.func.id
+        if name not in FORMULA_FUNCS:
+            raise FormulaError(f"unknown function {name!r}")
+        arity = FORMULA_FUNCS[name]
+        if node.keywords or any(isinstance(a, ast.Starred) for a in node.args) or (
+            len(node.args) < 2 if arity < 0 else len(node.args) != arity
+        ):
+            raise FormulaError(f"wrong number of arguments to {name}")
+        for a in node.args:
+            _check(a, source)
+    else:
+        raise FormulaError(f"unsupported expression {type(node).__name__}")
+
+
+def _eval(node, variables):
+    try:
+        if isinstance(node, ast.Constant):
+            value = float(node.value)
+        elif isinstance(node, ast.Name):
+            value = float(variables.get(node.id, 0))
+        elif isinstance(node, ast.UnaryOp):
+            value = _eval(node.operand, variables)
+            if isinstance(node.op, ast.USub):
+                value = -value
+        elif isinstance(node, ast.BinOp):
+            value = _BINARY_OPS[type(node.op)](_eval(node.left, variables), _eval(node.right, variables))
+        elif isinstance(node, ast.Compare):
+            holds = _COMPARE_OPS[type(node.ops[0])](_eval(node.left, variables), _eval(node.comparators[0], variables))
+            value = 1.0 if holds else 0.0
+        else:
+            args = [_eval(a, variables) for a in node.args]
+            value = float(_FUNCS[node.func.id](*args))
+    except (ArithmeticError, ValueError) as e:
+        if isinstance(e, FormulaError):
+            raise
+        raise FormulaError("result is not a finite number") from None
+    if not math.isfinite(value):
+        raise FormulaError("result is not a finite number")
+    return value
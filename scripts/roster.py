@@ -0,0 +1,213 @@
+"""
+Loads the league roster (players.yml), the source of truth for who is in the
+league. It is maintained with `tennis player add|remove`.
+
+Two formats are accepted: the original flat list of handles, and a list of
+mappings with `handle`, `name`, `joined`, `status`, `imported`, `away` and
+`profile` keys.
+"""
+
+import os
+import re
+from datetime import date, timedelta
+
+import yaml
+
+from scripts.elo_utils import CONFIG_FILE, normalize_player
+
+ROSTER_FILE = "players.yml"
+
+
+def load_roster(path=ROSTER_FILE):
+    """Return the roster as {handle: entry}, or {} if there is no roster.
+
+    Handles are normalized; entries always carry `handle`, `name` and
+    `status` keys (name defaults to the handle, status to "active").
+    """
+    if not os.path.exists(path):
+        return {}
+    with open(path) as f:
+        data = yaml.safe_load(f) or []
+
+    roster = {}
+    for item in data:
+        if isinstance(item, str):
+            item = {"handle": item}
+        handle = normalize_player(str(item.get("handle", "")))
+        if not handle:
+            continue
+        roster[handle] = {
+            "handle": handle,
+            "name": item.get("name") or handle,
+            "joined": str(item["joined"]) if item.get("joined") else None,
+            "status": item.get("status") or "active",
+        }
+        if item.get("imported"):
+            roster[handle]["imported"] = item["imported"]
+        if item.get("away"):
+            roster[handle]["away"] = {k: str(v) for k, v in item["away"].items()}
+        if item.get("profile"):
+            roster[handle]["profile"] = dict(item["profile"])
+    return roster
+
+
+def imported_ratings(roster, kind):
+    """Return {handle: rating} for players who joined with a `kind`
+    ("singles" or "doubles") rating imported from another league by
+    `tennis player import-rating`. They start from it instead of the
+    initial rating.
+    """
+    return {
+        handle: float(entry["imported"][kind])
+        for handle, entry in roster.items()
+        if (entry.get("imported") or {}).get(kind)
+    }
+
+
+def away_periods(roster):
+    """Return {handle: (from, until)} for players marked away by
+    `tennis availability away`, as YYYY-MM-DD strings. `until` is the day
+    they're back.
+    """
+    return {
+        handle: (entry["away"]["from"], entry["away"]["until"])
+        for handle, entry in roster.items()
+        if entry.get("away")
+    }
+
+
+def profile_summary(roster, handle):
+    """Return a one-line summary of a player's profile, set with `tennis
+    profile set`, e.g. "Left-handed · NTRP 4.0 · Riverside", or "" if they
+    have none. Mirrors the CLI's stats output.
+    """
+    profile = (roster.get(handle) or {}).get("profile") or {}
+    parts = []
+    if profile.get("hand"):
+        parts.append(f"{str(profile['hand']).capitalize()}-handed")
+    if profile.get("ntrp"):
+        parts.append(f"NTRP {float(profile['ntrp']):.1f}")
+    if profile.get("club"):
+        parts.append(str(profile["club"]))
+    return " · ".join(parts)
+
+
+def leaderboard_players(roster, rated_players):
+    """Return the players to list on a leaderboard.
+
+    With a roster, every active rostered player is listed (even without
+    matches yet); inactive players and players not on the roster are left
+    off, though their matches still counted towards ratings. Without a
+    roster, players are inferred from the match data as before.
+    """
+    if not roster:
+        return list(rated_players)
+    return [h for h, entry in roster.items() if entry.get("status", "active") == "active"]
+
+
+def load_qualification(path=CONFIG_FILE):
+    """Return the leaderboard qualification rules from .tennis.yml.
+
+    The leaderboard section's min_matches and window_days (0 = all time)
+    mirror the CLI; without them everyone is ranked.
+    """
+    rules = {"min_matches": 0, "window_days": 0}
+    if os.path.exists(path):
+        with open(path) as f:
+            data = yaml.safe_load(f) or {}
+        for key, value in (data.get("leaderboard") or {}).items():
+            if key in rules and value:
+                rules[key] = int(value)
+    return rules
+
+
+def load_tiers(path=CONFIG_FILE):
+    """Return the leaderboard tiers from .tennis.yml as (name, min_rating)
+    pairs, highest first, or [] if the league has none. Mirrors the CLI,
+    which rejects the same mistakes with ValueError here.
+    """
+    if not os.path.exists(path):
+        return []
+    with open(path) as f:
+        data = yaml.safe_load(f) or {}
+    tiers = []
+    for item in data.get("tiers") or []:
+        name = str(item.get("name") or "")
+        if not name:
+            raise ValueError("tiers: every tier needs a name")
+        tiers.append((name, float(item.get("min_rating") or 0)))
+    tiers.sort(key=lambda t: -t[1])
+    names = [name for name, _ in tiers]
+    mins = [m for _, m in tiers]
+    if len(set(names)) != len(names) or len(set(mins)) != len(mins):
+        raise ValueError("tiers: tier names and min_rating values must be unique")
+    return tiers
+
+
+def tier_for(rating, tiers):
+    """Return the name of the highest tier `rating` reaches, or "" if it is
+    below every tier."""
+    for name, min_rating in tiers:
+        if rating >= min_rating:
+            return name
+    return ""
+
+
+def unranked_players(match_dates, players, rules, as_of=None):
+    """Return the players who don't qualify to be ranked on `as_of`.
+
+    `match_dates` maps each player to the dates (YYYY-MM-DD) of the
+    matches they played; a player qualifies with at least min_matches of
+    them in the last window_days days.
+    """
+    if not rules.get("min_matches"):
+        return set()
+    as_of = as_of or date.today().isoformat()
+    since = ""
+    if rules.get("window_days"):
+        since = (date.fromisoformat(as_of) - timedelta(days=rules["window_days"])).isoformat()
+    unranked = set()
+    for p in players:
+        played = [d for d in match_dates.get(p, []) if since < d <= as_of]
+        if len(played) < rules["min_matches"]:
+            unranked.add(p)
+    return unranked
+
+
+def load_pseudonyms(path=CONFIG_FILE):
+    """Return {handle: pseudonym} from .tennis.yml's pseudonyms section, or
+    {} if there is none. Players with a pseudonym appear under it on the
+    Pages site; issues and match files keep their real handles.
+    """
+    if not os.path.exists(path):
+        return {}
+    with open(path) as f:
+        data = yaml.safe_load(f) or {}
+    return {
+        normalize_player(str(handle)): str(name).strip()
+        for handle, name in (data.get("pseudonyms") or {}).items()
+        if str(name).strip()
+    }
+
+
+def public_handle(pseudonyms, handle):
+    """Return the name a player appears under on the Pages site: their
+    pseudonym, or their handle if they have none."""
+    return pseudonyms.get(handle, handle)
+
+
+def public_slug(pseudonyms, handle):
+    """Return the name of a player's page on the Pages site, which mustn't
+    give away the handle of a player with a pseudonym."""
+    if handle not in pseudonyms:
+        return handle
+    return re.sub(r"[^a-z0-9]+", "-", pseudonyms[handle].lower()).strip("-")
+
+
+def display_name(roster, handle, pseudonyms=None):
+    """Return the roster display name for a handle, or the handle itself.
+    A player's pseudonym, if they have one, takes precedence."""
+    if pseudonyms and handle in pseudonyms:
+        return pseudonyms[handle]
+    entry = roster.get(handle)
+    return entry["name"] if entry else handle
@@ -0,0 +1,146 @@
+"""
+Scorecard images for individual matches.
+
+Each recorded match gets a 1200x630 SVG card (the OpenGraph image size)
+showing the players, set scores, date and rating changes, and a small
+match page that uses it as its preview image so shared links unfurl in
+chat apps. The CLI's `tennis match card` draws the same card on demand.
+"""
+
+import os
+from html import escape
+
+CARD_WIDTH = 1200
+CARD_HEIGHT = 630
+
+
+def pages_base_url(owner: str, repo: str) -> str:
+    """The default GitHub Pages address of the league site."""
+    return f"https://{owner.lower()}.github.io/{repo}/"
+
+
+def card_headline(sides: list[list[str]], sets: list[list[int]]) -> str:
+    """One-line summary, e.g. "alice beat bob 6-3 6-4"."""
+    won = [0, 0]
+    for s in sets:
+        if s[0] > s[1]:
+            won[0] += 1
+        elif s[1] > s[0]:
+            won[1] += 1
+    names = [" & ".join(side) for side in sides]
+    score = " ".join(f"{a}-{b}" for a, b in sets)
+    if won[0] == won[1]:
+        return f"{names[0]} vs {names[1]} {score}"
+    w = 0 if won[0] > won[1] else 1
+    flipped = " ".join(f"{s[w]}-{s[1 - w]}" for s in sets)
+    return f"{names[w]} beat {names[1 - w]} {flipped}"
+
+
+def _change_text(change: float) -> str:
+    return f"{change:+.1f}"
+
+
+def scorecard_svg(match_type: str, date: str, sides: list[list[str]], sets: list[list[int]], changes: dict[str, float]) -> str:
+    """
+    Render a match as an SVG scorecard. sides lists the players of each
+    side, sets the games per set as [side1, side2], and changes each
+    player's total rating change from the match.
+    """
+    winner = None
+    won = [sum(1 for s in sets if s[0] > s[1]), sum(1 for s in sets if s[1] > s[0])]
+    if won[0] != won[1]:
+        winner = 0 if won[0] > won[1] else 1
+
+    rows = []
+    for i, side in enumerate(sides):
+        y = 250 + i * 170
+        weight = "700" if winner == i else "400"
+        fill = "#1b5e20" if winner == i else "#37474f"
+        for j, player in enumerate(side):
+            py = y + j * 52 - (len(side) - 1) * 26
+            change = changes.get(player)
+            change_svg = ""
+            if change is not None:
+                colour = "#2e7d32" if change >= 0 else "#c62828"
+                change_svg = (
+                    f'<text x="600" y="{py}" font-size="30" fill="{colour}" text-anchor="end">'
+                    f"{escape(_change_text(change))}</text>"
+                )
+            rows.append(
+                f'<text x="80" y="{py}" font-size="44" font-weight="{weight}" fill="{fill}">@{escape(player)}</text>'
+                + change_svg
+            )
+        for k, s in enumerate(sets):
+            games = s[i]
+            bold = "700" if games > s[1 - i] else "400"
+            rows.append(
+                f'<text x="{700 + k * 110}" y="{y}" font-size="56" font-weight="{bold}" fill="#263238" text-anchor="middle">{games}</text>'
+            )
+        if winner == i:
+            rows.append(f'<text x="40" y="{y}" font-size="36" fill="#f9a825">●</text>')
+
+    return f"""<svg xmlns="http://www.w3.org/2000/svg" width="{CARD_WIDTH}" height="{CARD_HEIGHT}" viewBox="0 0 {CARD_WIDTH} {CARD_HEIGHT}" font-family="Helvetica, Arial, sans-serif">
+<rect width="{CARD_WIDTH}" height="{CARD_HEIGHT}" fill="#f1f8e9"/>
+<rect width="{CARD_WIDTH}" height="110" fill="#2e7d32"/>
+<text x="80" y="72" font-size="40" font-weight="700" fill="#ffffff">{escape(match_type.title())} match</text>
+<text x="{CARD_WIDTH - 80}" y="72" font-size="34" fill="#ffffff" text-anchor="end">{escape(str(date))}</text>
+<line x1="80" y1="335" x2="{CARD_WIDTH - 80}" y2="335" stroke="#c5e1a5" stroke-width="3"/>
+{chr(10).join(rows)}
+</svg>
+"""
+
+
+def match_page_html(issue_number: int, headline: str, date: str, image_url: str, repo_url: str) -> str:
+    """A minimal page for one match carrying its OpenGraph preview."""
+    title = escape(headline)
+    return f"""<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{title}</title>
+    <meta property="og:type" content="article">
+    <meta property="og:title" content="{title}">
+    <meta property="og:description" content="Match played {escape(str(date))}">
+    <meta property="og:image" content="{escape(image_url)}">
+    <meta property="og:image:width" content="{CARD_WIDTH}">
+    <meta property="og:image:height" content="{CARD_HEIGHT}">
+    <meta name="twitter:card" content="summary_large_image">
+    <meta name="twitter:image" content="{escape(image_url)}">
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0/dist/css/bootstrap.min.css" rel="stylesheet">
+    <style>
+        body {{ padding: 2rem; }}
+        img {{ max-width: 100%; height: auto; border-radius: 0.375rem; }}
+        .footer {{ margin-top: 2rem; padding-top: 2rem; border-top: 1px solid #dee2e6; font-size: 0.9rem; color: #6c757d; text-align: center; }}
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1 class="mb-4">{title}</h1>
+        <img src="scorecards/{issue_number}.svg" alt="{title}">
+        <div class="footer">
+            <p><a href="history.html">Match History</a> | <a href="index.html">Back to Leaderboards</a> | <a href="{repo_url}/issues/{issue_number}">Issue #{issue_number}</a></p>
+        </div>
+    </div>
+</body>
+</html>
+"""
+
+
+def write_scorecards(output_dir: str, matches: list[dict], owner: str, repo: str) -> None:
+    """
+    Write scorecards/<issue>.svg and match_<issue>.html for every match.
+    Each match carries "card" data as built by build_history.
+    """
+    cards_dir = os.path.join(output_dir, "scorecards")
+    os.makedirs(cards_dir, exist_ok=True)
+    base = pages_base_url(owner, repo)
+    repo_url = f"https://github.com/{owner}/{repo}"
+    for match in matches:
+        card = match["card"]
+        number = match["issue_number"]
+        with open(os.path.join(cards_dir, f"{number}.svg"), "w") as f:
+            f.write(scorecard_svg(match["type"], match["date"], card["sides"], card["sets"], card["changes"]))
+        headline = card_headline(card["sides"], card["sets"])
+        with open(os.path.join(output_dir, f"match_{number}.html"), "w") as f:
+            f.write(match_page_html(number, headline, match["date"], f"{base}scorecards/{number}.svg", repo_url))
@@ -0,0 +1,30 @@
+"""Tests for handle aliases (aliases.yml) written by `tennis player rename`."""
+
+from scripts.elo_utils import load_aliases, normalize_player
+
+
+def test_missing_aliases_file_is_empty(tmp_path):
+    assert load_aliases(str(tmp_path / "aliases.yml")) == {}
+
+
+def test_aliases_are_normalized(tmp_path):
+    path = tmp_path / "aliases.yml"
+    path.write_text("'@OldName': NewName\n")
+    assert load_aliases(str(path)) == {"oldname": "newname"}
+
+
+def test_renamed_handle_resolves_to_current():
+    aliases = {"oldname": "newname"}
+    assert normalize_player("@OldName", aliases) == "newname"
+    assert normalize_player("newname", aliases) == "newname"
+    assert normalize_player("someone", aliases) == "someone"
+
+
+def test_alias_chain_is_followed():
+    aliases = {"first": "second", "second": "third"}
+    assert normalize_player("first", aliases) == "third"
+
+
+def test_alias_cycle_terminates():
+    aliases = {"a": "b", "b": "a"}
+    assert normalize_player("a", aliases) in {"a", "b"}
@@ -0,0 +1,72 @@
+"""Tests for fetching and caching player avatars for the Pages site."""
+
+import os
+
+from scripts.avatars import avatar_img, avatar_url, fetch_avatars
+
+
+class FakeResponse:
+    def __init__(self, status_code=200, content=b"png"):
+        self.status_code = status_code
+        self.content = content
+        self.headers = {"Content-Type": "image/png"}
+
+
+class FakeFetch:
+    """Records the URLs fetched, failing those containing `fail`."""
+
+    def __init__(self, fail=None):
+        self.urls = []
+        self.fail = fail
+
+    def __call__(self, url, timeout):
+        self.urls.append(url)
+        if self.fail and self.fail in url:
+            return FakeResponse(404)
+        return FakeResponse(content=url.encode())
+
+
+def test_rostered_players_get_github_avatars_and_guests_identicons(tmp_path):
+    fetch = FakeFetch()
+    avatars = fetch_avatars(["alice", "guest"], {"alice": {}}, str(tmp_path / "site"), str(tmp_path / "cache"), fetch)
+    assert avatars == {"alice": "avatars/alice.png", "guest": "avatars/guest.png"}
+    assert fetch.urls == [avatar_url("alice", False), avatar_url("guest", True)]
+    assert "identicon" in fetch.urls[1]
+    assert (tmp_path / "site" / "avatars" / "alice.png").read_bytes() == avatar_url("alice", False).encode()
+
+
+def test_cached_avatars_are_reused_until_stale(tmp_path):
+    cache = str(tmp_path / "cache")
+    fetch_avatars(["alice"], {"alice": {}}, str(tmp_path / "a"), cache, FakeFetch())
+    fetched_at = os.path.getmtime(os.path.join(cache, "alice.png"))
+
+    fetch = FakeFetch()
+    fetch_avatars(["alice"], {"alice": {}}, str(tmp_path / "b"), cache, fetch, now=fetched_at + 86400)
+    assert fetch.urls == []
+    fetch_avatars(["alice"], {"alice": {}}, str(tmp_path / "c"), cache, fetch, now=fetched_at + 8 * 86400)
+    assert fetch.urls == [avatar_url("alice", False)]
+
+
+def test_falls_back_to_identicon_then_to_none(tmp_path):
+    fetch = FakeFetch(fail="github.com")
+    avatars = fetch_avatars(["alice"], {"alice": {}}, str(tmp_path / "site"), str(tmp_path / "cache"), fetch)
+    assert avatars == {"alice": "avatars/alice.png"}
+    assert len(fetch.urls) == 2
+
+    avatars = fetch_avatars(["bob"], {}, str(tmp_path / "site"), str(tmp_path / "cache"), FakeFetch(fail="http"))
+    assert avatars == {}
+
+
+def test_pseudonymous_players_get_an_identicon_of_their_pseudonym(tmp_path):
+    fetch = FakeFetch()
+    avatars = fetch_avatars(
+        ["alice"], {"alice": {}}, str(tmp_path / "site"), str(tmp_path / "cache"), fetch, pseudonyms={"alice": "Drop Shot"}
+    )
+    assert avatars == {"alice": "avatars/drop-shot.png"}
+    assert fetch.urls == [avatar_url("Drop Shot", True)]
+
+
+def test_avatar_img():
+    assert avatar_img({"alice": "avatars/alice.png"}, "alice").startswith('<img src="avatars/alice.png"')
+    assert avatar_img({}, "bob") == ""
+    assert avatar_img(None, "bob") == ""
@@ -0,0 +1,33 @@
+"""Tests for linking match history rows to their pull requests."""
+
+from scripts.build_history import load_matches_from_directory, pr_number_from_comments
+
+
+def test_bot_comment_links_pr():
+    comments = [
+        {"author": "alice", "body": "See #3"},
+        {"author": "github-actions", "body": "Please review and approve it here: #42"},
+    ]
+    assert pr_number_from_comments(comments) == 42
+
+
+def test_rest_bot_login_is_recognised():
+    comments = [{"author": "github-actions[bot]", "body": "here: #7"}]
+    assert pr_number_from_comments(comments) == 7
+
+
+def test_no_bot_comment():
+    assert pr_number_from_comments([{"author": "alice", "body": "#9"}]) == 0
+    assert pr_number_from_comments([]) == 0
+
+
+def test_pulled_match_keeps_its_repository(tmp_path):
+    # `tennis repos pull` names files <date>--<owner>--<repo>-<issue>.yml.
+    (tmp_path / "2026-01-01--club--north-7.yml").write_text(
+        "date: '2026-01-01'\n"
+        "players:\n- alice\n- bob\n"
+        "sets:\n- - 6\n  - 3\nsource_issue: 7\nrepo: club/north\n"
+    )
+    matches = load_matches_from_directory(str(tmp_path), "singles", {})
+    assert matches[0]["issue_number"] == 7
+    assert matches[0]["repo"] == "club/north"
@@ -11,7 +11,16 @@ import pytest
 
 from scripts.elo_utils import (
     K,
+    decayed_ratings,
     expected,
+    frozen,
+    charge_forfeit,
+    load_elo_params,
+    load_forfeit,
+    load_handicap,
+    scored_sets,
+    set_change,
+    set_k,
     normalize_team,
     update_doubles_elo_ratings,
     update_elo_ratings,
@@ -131,3 +140,149 @@ def test_doubles_elo_uses_average_of_individual_ratings():
 def test_expected_is_finite_for_extreme_gaps():
     assert math.isfinite(expected(3000, 1))
     assert math.isfinite(expected(1, 3000))
+
+
+def test_elo_params_default_without_config(tmp_path):
+    params = load_elo_params(str(tmp_path / ".tennis.yml"))
+    assert params == {"k": 32, "initial_rating": 1200, "margin": "none"}
+
+
+def test_elo_params_read_from_config(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("name: Club\nelo:\n  k: 20\n  margin: games\n")
+    params = load_elo_params(str(path))
+    assert params == {"k": 20, "initial_rating": 1200, "margin": "games"}
+
+
+def test_elo_params_reject_unknown_margin(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("elo:\n  margin: points\n")
+    with pytest.raises(ValueError):
+        load_elo_params(str(path))
+
+
+def test_elo_params_game_mode(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("elo:\n  k: 4\n  mode: games\n")
+    assert load_elo_params(str(path)) == {"k": 4, "initial_rating": 1200, "margin": "none", "mode": "games"}
+
+
+def test_elo_params_set_mode_is_the_default(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("elo:\n  mode: sets\n")
+    assert "mode" not in load_elo_params(str(path))
+
+
+@pytest.mark.parametrize("elo", ["mode: points", "mode: games\n  margin: games"])
+def test_elo_params_reject_invalid_mode(tmp_path, elo):
+    path = tmp_path / ".tennis.yml"
+    path.write_text(f"elo:\n  {elo}\n")
+    with pytest.raises(ValueError):
+        load_elo_params(str(path))
+
+
+def test_set_change_per_game(monkeypatch):
+    params = {"k": 4, "initial_rating": 1200, "margin": "none", "mode": "games"}
+    monkeypatch.setattr("scripts.elo_utils.load_elo_params", lambda path=None: params)
+    # Evenly matched, a 6-4 set is one game better than expected.
+    assert set_change(1200, 1200, 4, (6, 4)) == pytest.approx(4)
+    assert set_change(1200, 1200, 4, (4, 6)) == pytest.approx(4)
+    # A heavy favourite who only wins 7-5 loses points.
+    assert set_change(1600, 1200, 4, (7, 5)) < 0
+
+
+def test_set_change_is_none_for_plain_elo(monkeypatch):
+    params = {"k": 32, "initial_rating": 1200, "margin": "none"}
+    monkeypatch.setattr("scripts.elo_utils.load_elo_params", lambda path=None: params)
+    monkeypatch.setattr("scripts.elo_utils.load_rating_formula", lambda path=None: None)
+    assert set_change(1200, 1200, 32, (6, 4)) is None
+
+
+def test_set_k_without_margin_is_k():
+    params = {"k": 32, "initial_rating": 1200, "margin": "none"}
+    assert set_k([[6, 0], [6, 0]], [6, 0], params) == 32
+
+
+def test_set_k_games_margin():
+    params = {"k": 32, "initial_rating": 1200, "margin": "games"}
+    assert set_k([[7, 6]], [7, 6], params) == 32
+    assert set_k([[6, 0]], [6, 0], params) == pytest.approx(32 * (1 + math.log(6)))
+
+
+def test_set_k_sets_margin():
+    params = {"k": 32, "initial_rating": 1200, "margin": "sets"}
+    assert set_k([[6, 3], [3, 6], [6, 4]], [6, 3], params) == 32
+    assert set_k([[6, 3], [6, 4]], [6, 3], params) == pytest.approx(32 * (1 + math.log(2)))
+
+
+def test_decay_disabled_by_default():
+    rules = {"after_days": 0, "points_per_week": 0}
+    assert decayed_ratings({"a": 1300}, {"a": ["2020-01-01"]}, rules, "2025-01-01") == {"a": 1300}
+
+
+def test_decay_after_grace_period():
+    rules = {"after_days": 60, "points_per_week": 5}
+    dates = {"a": ["2025-01-01"], "b": ["2025-02-20"]}
+    # a is idle 90 days: 30 beyond the grace period, four full weeks.
+    got = decayed_ratings({"a": 1300, "b": 1300}, dates, rules, "2025-04-01")
+    assert got == {"a": 1280, "b": 1300}
+
+
+def test_decay_pauses_while_away():
+    rules = {"after_days": 60, "points_per_week": 5}
+    dates = {"a": ["2025-01-01"], "b": ["2025-01-01"]}
+    # a was away for 21 of their 90 idle days: 9 beyond the grace period.
+    away = {"a": ("2025-02-01", "2025-02-22")}
+    got = decayed_ratings({"a": 1300, "b": 1300}, dates, rules, "2025-04-01", away)
+    assert got == {"a": 1295, "b": 1280}
+
+
+def test_decay_stops_at_initial_rating():
+    rules = {"after_days": 0, "points_per_week": 50}
+    dates = {"a": ["2024-01-01"], "b": ["2024-01-01"]}
+    got = decayed_ratings({"a": 1250, "b": 1150}, dates, rules, "2025-01-01")
+    assert got == {"a": 1200, "b": 1150}
+
+
+def test_handicap_defaults_to_raw(tmp_path):
+    rules = load_handicap(str(tmp_path / ".tennis.yml"))
+    assert rules == {"games_per": 0, "max_games": 0, "score": "raw"}
+
+
+def test_handicap_rejects_unknown_score(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("handicap:\n  games_per: 100\n  score: points\n")
+    with pytest.raises(ValueError):
+        load_handicap(str(path))
+
+
+def test_scored_sets_pick_the_configured_result():
+    match = {"sets": [[6, 4], [3, 6]], "handicap_sets": [[6, 6], [3, 8]]}
+    assert scored_sets(match, {"score": "raw"}) == [[6, 4], [3, 6]]
+    assert scored_sets(match, {"score": "handicap"}) == [[6, 6], [3, 8]]
+    assert scored_sets({"sets": [[6, 4]]}, {"score": "handicap"}) == [[6, 4]]
+
+
+def test_forfeit_rejects_unknown_result(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("rules:\n  forfeit:\n    result: walkover\n")
+    with pytest.raises(ValueError):
+        load_forfeit(str(path))
+
+
+def test_penalized_forfeit_scores_no_sets_and_charges_the_absent_side():
+    match = {"players": ["alice", "bob"], "sets": [[6, 0], [6, 0]], "forfeit": ["bob"]}
+    penalty = {"result": "penalty", "penalty": 25}
+    assert scored_sets(match, {"score": "raw"}, {"result": "default", "penalty": 0}) == [[6, 0], [6, 0]]
+    assert scored_sets(match, {"score": "raw"}, penalty) == []
+    ratings = {"alice": 1200, "bob": 1210}
+    charge_forfeit(ratings, match, penalty)
+    assert ratings == {"alice": 1200, "bob": 1185}
+
+
+def test_freeze_holds_back_matches_only_while_it_is_on():
+    held = {"from": "2025-09-01", "until": "2025-09-30"}
+    assert frozen({"date": "2025-09-10"}, held, today="2025-09-15")
+    assert not frozen({"date": "2025-08-31"}, held, today="2025-09-15")
+    assert not frozen({"date": "2025-09-10"}, held, today="2025-10-01")
+    assert not frozen({"date": "2025-09-10"}, {}, today="2025-09-15")
@@ -17,10 +17,12 @@ def reset_module_state():
     singles.ratings.clear()
     singles.stats.clear()
     singles.elo_changes.clear()
+    singles.match_dates.clear()
     doubles.team_ratings.clear()
     doubles.team_stats.clear()
     doubles.individual_ratings.clear()
     doubles.individual_stats.clear()
+    doubles.match_dates.clear()
     yield
 
 
@@ -0,0 +1,71 @@
+"""Tests for ranking points (scripts/ranking_points.py)."""
+
+import pytest
+
+from scripts.ranking_points import award_points, load_points, match_winner, points_for_win, round_key
+
+RULES = {
+    "rank_by": "points",
+    "win": 10,
+    "rounds": {"final": 100, "quarterfinal": 35},
+    "opponents": [(1400, 2.0), (1250, 1.5)],
+    "window_days": 0,
+}
+
+
+def test_points_for_win_by_round_and_opponent():
+    assert points_for_win(RULES, None, 1200) == 10
+    assert points_for_win(RULES, "final", 1200) == 100
+    assert points_for_win(RULES, "semifinal", 1300) == 15  # no semifinal entry: the league win, x1.5
+    assert points_for_win(RULES, "quarterfinal", 1450) == 70
+
+
+def test_round_key_counts_from_the_final():
+    # Five players take three rounds: quarterfinal, semifinal, final.
+    assert [round_key(5, i) for i in range(3)] == ["quarterfinal", "semifinal", "final"]
+    assert round_key(16, 0) == "round"
+
+
+def test_match_winner():
+    assert match_winner([[6, 3], [4, 6], [6, 4]]) == 1
+    assert match_winner([[3, 6], [6, 7]]) == 2
+    assert match_winner([[6, 3], [3, 6]]) == 0
+
+
+def test_award_points_singles_and_doubles():
+    points = {}
+    match = {"date": "2025-03-01", "sets": [[6, 3], [6, 4]], "source_issue": 7}
+    award_points(points, match, ["alice"], ["bob"], {"bob": 1300}, {7: "final"}, rules=RULES, as_of="2025-03-02")
+    assert points == {"alice": 150}
+
+    doubles = {"date": "2025-03-01", "sets": [[2, 6], [3, 6]]}
+    award_points(points, doubles, ["alice", "bob"], ["carol", "dave"], {"alice": 1500, "bob": 1400}, {}, rules=RULES, as_of="2025-03-02")
+    assert points["carol"] == points["dave"] == 20
+
+
+def test_award_points_window():
+    points = {}
+    match = {"date": "2024-01-01", "sets": [[6, 0]]}
+    award_points(points, match, ["alice"], ["bob"], {}, {}, rules={**RULES, "window_days": 30}, as_of="2025-03-02")
+    assert points == {}
+
+
+def test_load_points_rejects_unknown_round(tmp_path):
+    config = tmp_path / ".tennis.yml"
+    config.write_text("points:\n  win: 10\n  rounds:\n    last16: 20\n")
+    with pytest.raises(ValueError, match="unknown round"):
+        load_points(str(config))
+
+
+def test_load_points_defaults_off(tmp_path):
+    rules = load_points(str(tmp_path / "missing.yml"))
+    assert rules["rank_by"] == "rating" and rules["win"] == 0
+
+
+def test_points_drop_off_after_52_weeks_by_default():
+    points = {}
+    match = {"date": "2024-03-01", "sets": [[6, 0]]}
+    award_points(points, match, ["alice"], ["bob"], {}, {}, rules=RULES, as_of="2025-02-27")
+    assert points == {"alice": 10}
+    award_points(points, match, ["alice"], ["bob"], {}, {}, rules=RULES, as_of="2025-02-28")
+    assert points == {"alice": 10}  # 364 days on, the win no longer counts
@@ -0,0 +1,58 @@
+"""Tests for custom rating formulas (scripts/rating_formula.py)."""
+
+import pytest
+
+from scripts.rating_formula import FormulaError, RatingFormula, load_formula
+
+SET = {
+    "k": 32, "expected": 0.75, "winner_rating": 1300, "loser_rating": 1100,
+    "games_won": 6, "games_lost": 2, "initial_rating": 1200, "doubles": 0,
+}
+
+
+def test_standard_elo_formula():
+    assert RatingFormula("k * (1 - expected)").evaluate(SET) == 8
+
+
+def test_comments_and_line_breaks():
+    formula = RatingFormula("# margin bonus\nk * (1 - expected)\n  * (1 + (games_won - games_lost) / 10)  # up to 60%\n")
+    assert formula.evaluate(SET) == pytest.approx(8 * 1.4)
+
+
+def test_functions_and_comparisons():
+    assert RatingFormula("when(doubles, 1, 2)").evaluate(SET) == 2
+    assert RatingFormula("max(1, 5, 3) + min(4, 2)").evaluate(SET) == 7
+    assert RatingFormula("clamp(games_won - games_lost, 0, 3)").evaluate(SET) == 3
+    assert RatingFormula("(winner_rating > loser_rating) * 10").evaluate(SET) == 10
+
+
+@pytest.mark.parametrize("source", [
+    "",
+    "k ** 2",
+    "open('x')",
+    "__import__('os')",
+    "k.real",
+    "[k]",
+    "unknown * 2",
+    "sqrt(1, 2)",
+    "1 < k < 40",
+    "k and expected",
+    "0x10",
+    "1_000",
+])
+def test_rejects_anything_outside_the_grammar(source):
+    with pytest.raises(FormulaError):
+        RatingFormula(source)
+
+
+def test_non_finite_result_is_an_error():
+    formula = RatingFormula("k / (games_won - 6 + 1)")
+    with pytest.raises(FormulaError):
+        formula.evaluate({**SET, "games_won": 5})
+
+
+def test_load_rejects_invalid_formula(tmp_path):
+    path = tmp_path / "rating-formula.expr"
+    path.write_text("k / 0\n")
+    with pytest.raises(FormulaError):
+        load_formula(str(path))
@@ -0,0 +1,164 @@
+"""Tests for loading the league roster (players.yml)."""
+
+import pytest
+
+from scripts.roster import (
+    away_periods,
+    display_name,
+    imported_ratings,
+    leaderboard_players,
+    load_qualification,
+    load_pseudonyms,
+    load_roster,
+    load_tiers,
+    profile_summary,
+    public_handle,
+    public_slug,
+    tier_for,
+    unranked_players,
+)
+
+
+def test_missing_roster_is_empty(tmp_path):
+    assert load_roster(str(tmp_path / "players.yml")) == {}
+
+
+def test_flat_list_of_handles(tmp_path):
+    path = tmp_path / "players.yml"
+    path.write_text("- Alice\n- '@bob'\n")
+    roster = load_roster(str(path))
+    assert list(roster) == ["alice", "bob"]
+    assert roster["alice"] == {"handle": "alice", "name": "alice", "joined": None, "status": "active"}
+
+
+def test_structured_entries(tmp_path):
+    path = tmp_path / "players.yml"
+    path.write_text(
+        "- handle: carol\n"
+        "  name: Carol C\n"
+        '  joined: "2025-01-15"\n'
+        "  status: active\n"
+    )
+    roster = load_roster(str(path))
+    assert roster["carol"]["name"] == "Carol C"
+    assert roster["carol"]["joined"] == "2025-01-15"
+    assert display_name(roster, "carol") == "Carol C"
+    assert display_name(roster, "dave") == "dave"
+
+
+def test_imported_ratings(tmp_path):
+    path = tmp_path / "players.yml"
+    path.write_text(
+        "- handle: Erin\n"
+        "  imported: {from: other/league, date: 2025-02-01, singles: 1350.5}\n"
+        "- handle: frank\n"
+    )
+    roster = load_roster(str(path))
+    assert imported_ratings(roster, "singles") == {"erin": 1350.5}
+    assert imported_ratings(roster, "doubles") == {}
+
+
+def test_away_periods(tmp_path):
+    path = tmp_path / "players.yml"
+    path.write_text(
+        "- handle: erin\n"
+        "  away: {from: 2025-02-01, until: 2025-03-01}\n"
+        "- handle: frank\n"
+    )
+    roster = load_roster(str(path))
+    assert away_periods(roster) == {"erin": ("2025-02-01", "2025-03-01")}
+
+
+def test_profile_summary(tmp_path):
+    path = tmp_path / "players.yml"
+    path.write_text(
+        "- handle: erin\n"
+        "  profile: {hand: left, ntrp: 4, club: Riverside}\n"
+        "- handle: frank\n"
+        "  profile: {club: Northside}\n"
+        "- handle: gina\n"
+    )
+    roster = load_roster(str(path))
+    assert profile_summary(roster, "erin") == "Left-handed · NTRP 4.0 · Riverside"
+    assert profile_summary(roster, "frank") == "Northside"
+    assert profile_summary(roster, "gina") == ""
+    assert profile_summary(roster, "nobody") == ""
+
+
+def test_pseudonyms_read_from_config(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("pseudonyms:\n  '@Player_One': Baseline Basher\n")
+    pseudonyms = load_pseudonyms(str(path))
+    assert pseudonyms == {"player_one": "Baseline Basher"}
+    assert public_handle(pseudonyms, "player_one") == "Baseline Basher"
+    assert public_handle(pseudonyms, "player_two") == "player_two"
+    assert public_slug(pseudonyms, "player_one") == "baseline-basher"
+    assert public_slug(pseudonyms, "player_two") == "player_two"
+    assert display_name({"player_one": {"name": "Real Name"}}, "player_one", pseudonyms) == "Baseline Basher"
+
+
+def test_pseudonyms_default_without_config(tmp_path):
+    assert load_pseudonyms(str(tmp_path / ".tennis.yml")) == {}
+
+
+def test_leaderboard_players_without_roster_uses_match_data():
+    assert leaderboard_players({}, {"alice": 1210, "bob": 1190}) == ["alice", "bob"]
+
+
+def test_leaderboard_players_with_roster_is_roster():
+    roster = {"alice": {}, "carol": {}}
+    # bob has matches but isn't rostered; carol is rostered but unrated
+    assert leaderboard_players(roster, {"alice": 1210, "bob": 1190}) == ["alice", "carol"]
+
+
+def test_leaderboard_players_skips_inactive():
+    roster = {"alice": {"status": "active"}, "bob": {"status": "inactive"}}
+    assert leaderboard_players(roster, {"alice": 1210, "bob": 1190}) == ["alice"]
+
+
+def test_qualification_defaults_without_config(tmp_path):
+    assert load_qualification(str(tmp_path / ".tennis.yml")) == {"min_matches": 0, "window_days": 0}
+
+
+def test_qualification_read_from_config(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("leaderboard:\n  min_matches: 5\n  window_days: 90\n")
+    assert load_qualification(str(path)) == {"min_matches": 5, "window_days": 90}
+
+
+def test_tiers_default_without_config(tmp_path):
+    assert load_tiers(str(tmp_path / ".tennis.yml")) == []
+
+
+def test_tiers_read_highest_first(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("tiers:\n  - name: Bronze\n    min_rating: 0\n  - name: Gold\n    min_rating: 1400\n")
+    tiers = load_tiers(str(path))
+    assert tiers == [("Gold", 1400), ("Bronze", 0)]
+    assert tier_for(1400, tiers) == "Gold"
+    assert tier_for(1399.9, tiers) == "Bronze"
+    assert tier_for(-1, tiers) == ""
+
+
+def test_tiers_reject_duplicates(tmp_path):
+    path = tmp_path / ".tennis.yml"
+    path.write_text("tiers:\n  - name: Gold\n    min_rating: 1400\n  - name: Silver\n    min_rating: 1400\n")
+    with pytest.raises(ValueError):
+        load_tiers(str(path))
+
+
+def test_unranked_players_without_rules_is_empty():
+    assert unranked_players({"a": []}, ["a"], {"min_matches": 0, "window_days": 0}) == set()
+
+
+def test_unranked_players_counts_matches_in_window():
+    dates = {
+        "a": ["2025-01-01", "2025-05-01", "2025-05-20"],
+        "b": ["2025-05-10", "2025-05-30"],
+        "c": [],
+    }
+    rules = {"min_matches": 2, "window_days": 90}
+    # a's January match is outside the window but two remain
+    assert unranked_players(dates, ["a", "b", "c"], rules, as_of="2025-06-01") == {"c"}
+    assert unranked_players(dates, ["a", "b", "c"], {**rules, "min_matches": 3}, as_of="2025-06-01") == {"a", "b", "c"}
+    assert unranked_players(dates, ["a", "b"], {"min_matches": 3, "window_days": 0}, as_of="2025-06-01") == {"b"}
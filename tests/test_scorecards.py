@@ -0,0 +1,31 @@
+"""Tests for match scorecards and their OpenGraph pages."""
+
+from scripts.scorecards import card_headline, match_page_html, scorecard_svg
+
+
+def test_headline_puts_winner_first():
+    assert card_headline([["bob"], ["alice"]], [[3, 6], [4, 6]]) == "alice beat bob 6-3 6-4"
+
+
+def test_headline_for_doubles_and_split_matches():
+    assert card_headline([["a", "b"], ["c", "d"]], [[6, 4]]) == "a & b beat c & d 6-4"
+    assert card_headline([["alice"], ["bob"]], [[6, 4], [4, 6]]) == "alice vs bob 6-4 4-6"
+
+
+def test_scorecard_shows_players_sets_and_changes():
+    svg = scorecard_svg("singles", "2025-01-20", [["alice"], ["bob"]], [[6, 3], [6, 4]], {"alice": 30.5, "bob": -30.5})
+    assert 'width="1200" height="630"' in svg
+    assert "@alice" in svg and "@bob" in svg
+    assert ">+30.5<" in svg and ">-30.5<" in svg
+    assert "2025-01-20" in svg
+
+
+def test_scorecard_escapes_handles():
+    svg = scorecard_svg("singles", "2025-01-20", [["a<b"], ["c"]], [[6, 0]], {})
+    assert "@a&lt;b" in svg
+
+
+def test_match_page_references_card_as_og_image():
+    page = match_page_html(12, "alice beat bob 6-3", "2025-01-20", "https://org.github.io/tennis/scorecards/12.svg", "https://github.com/org/tennis")
+    assert '<meta property="og:image" content="https://org.github.io/tennis/scorecards/12.svg">' in page
+    assert '<meta property="og:title" content="alice beat bob 6-3">' in page